@@ -0,0 +1,286 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package arnoldi
+
+/* -------------------------------------------------------------------------- */
+
+import   "fmt"
+import   "math"
+import   "math/cmplx"
+import   "sort"
+
+import . "github.com/pbenner/autodiff"
+import   "github.com/pbenner/autodiff/algorithm/eigensystem"
+import   "github.com/pbenner/autodiff/algorithm/givensRotation"
+import   "github.com/pbenner/autodiff/algorithm/householder"
+import   "github.com/pbenner/autodiff/algorithm/krylovBasis"
+
+/* -------------------------------------------------------------------------- */
+
+// Reference:
+// Sorensen, Danny C. "Implicit application of polynomial filters in a
+// k-step Arnoldi method." SIAM Journal on Matrix Analysis and Applications
+// 13.1 (1992): 357-385.
+//
+// Lehoucq, Richard B., Danny C. Sorensen, and Chao Yang. "ARPACK users'
+// guide: solution of large-scale eigenvalue problems with implicitly
+// restarted Arnoldi methods." SIAM (1998).
+
+/* -------------------------------------------------------------------------- */
+
+// A Ritz pair (mu, y) is accepted as soon as the norm of the residual
+// A y - mu y is smaller than Epsilon times |mu|.
+type Epsilon = krylovBasis.Epsilon
+
+// Maximum number of restarts.
+type MaxIterations = krylovBasis.MaxIterations
+
+// Dimension of the Krylov subspace, by default max(2k+1, 20) but at most
+// the dimension of the operator.
+type SubspaceDimension = krylovBasis.SubspaceDimension
+
+// Compute the eigenvalues with smallest instead of largest magnitude.
+type Smallest = krylovBasis.Smallest
+
+// Starting vector of the iteration, by default a random vector.
+type InitialVector = krylovBasis.InitialVector
+
+// Seed of the random number generator used for the starting vector.
+type Seed = krylovBasis.Seed
+
+/* -------------------------------------------------------------------------- */
+
+// Apply the shift mu to the Hessenberg matrix h, i.e. compute the QR
+// decomposition of p(h) and replace h by Q^T h Q. The transformation is
+// accumulated in q. Real shifts are applied with Givens rotations. Complex
+// shifts are applied together with their conjugate using Householder
+// reflections, such that all computations remain real.
+func applyShift(h, q Matrix, mu complex128) {
+  m, _ := h.Dims()
+  t1 := NullBareReal()
+  t2 := NullBareReal()
+  t3 := NullBareReal()
+  if imag(mu) == 0.0 {
+    // p = h - mu I
+    p := h.CloneMatrix()
+    for i := 0; i < m; i++ {
+      p.At(i, i).Sub(p.At(i, i), ConstReal(real(mu)))
+    }
+    c := NullBareReal()
+    s := NullBareReal()
+    for i := 0; i < m-1; i++ {
+      givensRotation.Run(p.At(i, i), p.At(i+1, i), c, s)
+      givensRotation.ApplyLeft (p, c, s, i, i+1, t1, t2)
+      givensRotation.ApplyLeft (h, c, s, i, i+1, t1, t2)
+      givensRotation.ApplyRight(h, c, s, i, i+1, t1, t2)
+      givensRotation.ApplyRight(q, c, s, i, i+1, t1, t2)
+    }
+  } else {
+    // p = (h - mu I)(h - conj(mu) I) = h^2 - 2 Re(mu) h + |mu|^2 I
+    p := NullDenseBareRealMatrix(m, m)
+    r := NullDenseBareRealMatrix(m, m)
+    p.MdotM(h, h)
+    r.MmulS(h, ConstReal(2.0*real(mu)))
+    p.MsubM(p, r)
+    for i := 0; i < m; i++ {
+      p.At(i, i).Add(p.At(i, i), ConstReal(real(mu)*real(mu) + imag(mu)*imag(mu)))
+    }
+    x    := NullDenseBareRealVector(m)
+    nu   := NullDenseBareRealVector(m)
+    beta := NullBareReal()
+    t    := NullDenseBareRealVector(m)
+    for j := 0; j < m-1; j++ {
+      for i := j; i < m; i++ {
+        x.At(i).Set(p.At(i, j))
+      }
+      householder.Run(x.Slice(j, m), beta, nu.Slice(j, m), t1, t2, t3)
+      householder.ApplyLeft (p.Slice(j, m, j, m), beta, nu.Slice(j, m), t.Slice(j, m), t1)
+      householder.ApplyLeft (h.Slice(j, m, 0, m), beta, nu.Slice(j, m), t, t1)
+      householder.ApplyRight(h.Slice(0, m, j, m), beta, nu.Slice(j, m), t, t1)
+      householder.ApplyRight(q.Slice(0, m, j, m), beta, nu.Slice(j, m), t, t1)
+    }
+  }
+  // restore Hessenberg structure
+  for i := 2; i < m; i++ {
+    for j := 0; j < i-1; j++ {
+      h.At(i, j).SetValue(0.0)
+    }
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Eigen-decomposition of the Hessenberg matrix. Ritz values are sorted such
+// that wanted values come first.
+func ritz(h Matrix, smallest bool) ([]complex128, Matrix, []int, error) {
+  m, _ := h.Dims()
+  lambda, y, err := eigensystem.Run(h.CloneMatrix(), eigensystem.ComplexEigensystem{true})
+  if err != nil {
+    return nil, nil, nil, err
+  }
+  mu := make([]complex128, m)
+  p  := make([]int, m)
+  for i := 0; i < m; i++ {
+    mu[i] = lambda.ConstAt(i).(ConstComplexScalar).GetComplexValue()
+    p [i] = i
+  }
+  sort.SliceStable(p, func(i, j int) bool {
+    if smallest {
+      return cmplx.Abs(mu[p[i]]) < cmplx.Abs(mu[p[j]])
+    } else {
+      return cmplx.Abs(mu[p[i]]) > cmplx.Abs(mu[p[j]])
+    }
+  })
+  return mu, y, p, nil
+}
+
+// Returns true if a and b are complex conjugates.
+func isConjugate(a, b complex128) bool {
+  return imag(a) != 0.0 && cmplx.Abs(a - cmplx.Conj(b)) <= 1e-10*cmplx.Abs(a)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func arnoldi(a LinearOperator, k int, opts krylovBasis.Options) (Vector, Matrix, error) {
+  n, _ := a.Dims()
+  m    := opts.Dimension(n, k)
+  if m <= k+1 && m < n {
+    return nil, nil, fmt.Errorf("subspace dimension must be larger than k+1")
+  }
+  basis, err := krylovBasis.New(n, m, opts)
+  if err != nil {
+    return nil, nil, err
+  }
+  v  := basis.V
+  h  := NullDenseBareRealMatrix(m, m)
+  j0 := 0
+  for iter := 0; ; iter++ {
+    b := basis.Expand(a, h, j0, m)
+    mu, y, p, err := ritz(h, opts.Smallest)
+    if err != nil {
+      return nil, nil, err
+    }
+    // check convergence of the wanted Ritz pairs
+    converged := 0
+    for i := 0; i < k; i++ {
+      s := 0.0
+      for j := 0; j < m; j++ {
+        c := y.ConstAt(j, p[i]).(ConstComplexScalar).GetComplexValue()
+        s += real(c)*real(c) + imag(c)*imag(c)
+      }
+      r := b*cmplx.Abs(y.ConstAt(m-1, p[i]).(ConstComplexScalar).GetComplexValue())
+      if r <= opts.Epsilon*math.Max(cmplx.Abs(mu[p[i]]), 1e-300)*math.Sqrt(s) {
+        converged++
+      }
+    }
+    if converged == k {
+      eigenvalues  := NullDenseComplexVector(k)
+      eigenvectors := NullDenseComplexMatrix(n, k)
+      cr := make([]float64, m)
+      ci := make([]float64, m)
+      xr := NullDenseBareRealVector(n)
+      xi := NullDenseBareRealVector(n)
+      for i := 0; i < k; i++ {
+        eigenvalues.AT(i).SetComplexValue(mu[p[i]])
+        // x = V y
+        for j := 0; j < m; j++ {
+          c := y.ConstAt(j, p[i]).(ConstComplexScalar).GetComplexValue()
+          cr[j] = real(c)
+          ci[j] = imag(c)
+        }
+        basis.Combine(xr, cr)
+        basis.Combine(xi, ci)
+        s := 0.0
+        for l := 0; l < n; l++ {
+          s += xr.ValueAt(l)*xr.ValueAt(l) + xi.ValueAt(l)*xi.ValueAt(l)
+        }
+        s = math.Sqrt(s)
+        for l := 0; l < n; l++ {
+          eigenvectors.AT(l, i).SetComplexValue(complex(xr.ValueAt(l), xi.ValueAt(l))/complex(s, 0.0))
+        }
+      }
+      return eigenvalues, eigenvectors, nil
+    }
+    if iter+1 >= opts.MaxIterations {
+      return nil, nil, fmt.Errorf("maximum number of iterations reached")
+    }
+    // number of Ritz values to keep, complex conjugate pairs must not be
+    // separated
+    l := k + (m-k)/2
+    if l >= m {
+      l = m-1
+    }
+    if isConjugate(mu[p[l-1]], mu[p[l]]) {
+      l++
+    }
+    if l >= m {
+      l -= 2
+    }
+    // apply unwanted Ritz values as exact shifts
+    q := NullDenseBareRealMatrix(m, m)
+    q.SetIdentity()
+    for i := l; i < m; i++ {
+      s := mu[p[i]]
+      if math.Abs(imag(s)) <= 1e-14*cmplx.Abs(s) {
+        s = complex(real(s), 0.0)
+      } else if imag(s) < 0.0 {
+        // conjugate is applied together with the shift with positive
+        // imaginary part
+        continue
+      }
+      applyShift(h, q, s)
+    }
+    // f = V q_l h[l][l-1] + b q[m-1][l-1] v_m
+    c := make([]float64, m+1)
+    for j := 0; j < m; j++ {
+      c[j] = q.ValueAt(j, l)*h.ValueAt(l, l-1)
+    }
+    c[m] = b*q.ValueAt(m-1, l-1)
+    f := NullDenseBareRealVector(n)
+    basis.Combine(f, c)
+    // V = V Q
+    basis.Rotate(q, nil, l, m)
+    for i := 0; i < m; i++ {
+      for j := 0; j < m; j++ {
+        if i >= l || j >= l {
+          h.AT(i, j).SetValue(0.0)
+        }
+      }
+    }
+    copy(v[l], f)
+    h.AT(l, l-1).SetValue(basis.Normalize(l))
+    j0 = l
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the k eigenvalues with largest (or smallest) magnitude and
+// corresponding eigenvectors of the linear operator a. Eigenvalues and
+// eigenvectors are returned as DenseComplexVector and DenseComplexMatrix,
+// sorted by decreasing (increasing) magnitude. Eigenvectors are stored as
+// columns and normalized to unit length.
+func Run(a LinearOperator, k int, args ...interface{}) (Vector, Matrix, error) {
+  opts := krylovBasis.ParseOptions("Arnoldi", args)
+  if n, m := a.Dims(); n != m {
+    return nil, nil, fmt.Errorf("linear operator must be square")
+  } else if k <= 0 || k > n {
+    return nil, nil, fmt.Errorf("invalid number of eigenvalues")
+  }
+  return arnoldi(a, k, opts)
+}
@@ -0,0 +1,141 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package arnoldi
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math"
+import   "math/cmplx"
+import   "math/rand"
+import   "sort"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+import   "github.com/pbenner/autodiff/algorithm/eigensystem"
+
+/* -------------------------------------------------------------------------- */
+
+func complexAt(x ConstVector, i int) complex128 {
+  return x.ConstAt(i).(ConstComplexScalar).GetComplexValue()
+}
+
+// Check that A x_i = lambda_i x_i for all returned eigenpairs.
+func checkEigenpairs(a LinearOperator, lambda Vector, x Matrix, epsilon float64) bool {
+  n, k := x.Dims()
+  r1 := NullDenseBareRealVector(n)
+  r2 := NullDenseBareRealVector(n)
+  x1 := NullDenseBareRealVector(n)
+  x2 := NullDenseBareRealVector(n)
+  for i := 0; i < k; i++ {
+    xi := x.ConstCol(i)
+    for j := 0; j < n; j++ {
+      x1[j] = BareReal(real(complexAt(xi, j)))
+      x2[j] = BareReal(imag(complexAt(xi, j)))
+    }
+    a.MdotV(r1, x1)
+    a.MdotV(r2, x2)
+    for j := 0; j < n; j++ {
+      if cmplx.Abs(complex(r1.ValueAt(j), r2.ValueAt(j)) - complexAt(lambda, i)*complexAt(xi, j)) > epsilon {
+        return false
+      }
+    }
+  }
+  return true
+}
+
+/* -------------------------------------------------------------------------- */
+
+func TestArnoldi1(t *testing.T) {
+  // non-symmetric tridiagonal Toeplitz operator
+  n := 50
+  a := NewLinearOperator(n, n, func(r Vector, b ConstVector) {
+    for i := 0; i < n; i++ {
+      s := 2.0*b.ValueAt(i)
+      if i > 0 {
+        s += 0.8*b.ValueAt(i-1)
+      }
+      if i < n-1 {
+        s += 1.0*b.ValueAt(i+1)
+      }
+      r.At(i).SetValue(s)
+    }
+  })
+  lambda := func(j int) float64 {
+    return 2.0 + 2.0*math.Sqrt(0.8)*math.Cos(float64(j)*math.Pi/float64(n+1))
+  }
+  if l, x, err := Run(a, 3); err != nil {
+    t.Error(err)
+  } else {
+    for i := 0; i < 3; i++ {
+      if cmplx.Abs(complexAt(l, i) - complex(lambda(i+1), 0.0)) > 1e-8 {
+        t.Error("test failed")
+      }
+    }
+    if !checkEigenpairs(a, l, x, 1e-8) {
+      t.Error("test failed")
+    }
+  }
+  if l, x, err := Run(a, 3, Smallest{true}); err != nil {
+    t.Error(err)
+  } else {
+    for i := 0; i < 3; i++ {
+      if cmplx.Abs(complexAt(l, i) - complex(lambda(n-i), 0.0)) > 1e-8 {
+        t.Error("test failed")
+      }
+    }
+    if !checkEigenpairs(a, l, x, 1e-8) {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestArnoldi2(t *testing.T) {
+  // random non-symmetric matrix with complex eigenvalues
+  n := 40
+  g := rand.New(rand.NewSource(42))
+  a := NullMatrix(BareRealType, n, n)
+  for i := 0; i < n; i++ {
+    for j := 0; j < n; j++ {
+      a.At(i, j).SetValue(g.NormFloat64())
+    }
+  }
+  r, _, err := eigensystem.Run(a, eigensystem.ComplexEigensystem{true}, eigensystem.ComputeEigenvectors{false})
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  lambda := make([]float64, n)
+  for i := 0; i < n; i++ {
+    lambda[i] = cmplx.Abs(complexAt(r, i))
+  }
+  sort.Float64s(lambda)
+
+  if l, x, err := Run(AsLinearOperator(a), 4); err != nil {
+    t.Error(err)
+  } else {
+    for i := 0; i < 4; i++ {
+      if math.Abs(cmplx.Abs(complexAt(l, i)) - lambda[n-1-i]) > 1e-8 {
+        t.Error("test failed")
+      }
+    }
+    if !checkEigenpairs(AsLinearOperator(a), l, x, 1e-8) {
+      t.Error("test failed")
+    }
+  }
+}
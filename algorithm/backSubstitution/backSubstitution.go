@@ -30,9 +30,15 @@ type InSitu struct {
   T Scalar
 }
 
+// Solve A^T x = b instead of A x = b, i.e. perform a forward substitution
+// with the lower triangular matrix A^T.
+type Transposed struct {
+  Value bool
+}
+
 /* -------------------------------------------------------------------------- */
 
-func backSubstitution(inSitu *InSitu, b Vector) (Vector, error) {
+func backSubstitution(inSitu *InSitu, b Vector, transposed bool) (Vector, error) {
 
   A := inSitu.A
   x := inSitu.X
@@ -40,21 +46,64 @@ func backSubstitution(inSitu *InSitu, b Vector) (Vector, error) {
 
   _, n := A.Dims()
 
-  for i := n-1; i >= 0; i-- {
-    if b == nil {
-      x.At(i).SetValue(0.0)
-    } else {
-      x.At(i).Set(b.At(i))
+  if transposed {
+    for i := 0; i < n; i++ {
+      if b == nil {
+        x.At(i).SetValue(0.0)
+      } else {
+        x.At(i).Set(b.At(i))
+      }
+      for j := 0; j < i; j++ {
+        t.Mul(A.At(j,i), x.At(j))
+        x.At(i).Sub(x.At(i), t)
+      }
+      x.At(i).Div(x.At(i), A.At(i,i))
     }
-    for j := i+1; j < n; j++ {
-      t.Mul(A.At(i,j), x.At(j))
-      x.At(i).Sub(x.At(i), t)
+  } else {
+    for i := n-1; i >= 0; i-- {
+      if b == nil {
+        x.At(i).SetValue(0.0)
+      } else {
+        x.At(i).Set(b.At(i))
+      }
+      for j := i+1; j < n; j++ {
+        t.Mul(A.At(i,j), x.At(j))
+        x.At(i).Sub(x.At(i), t)
+      }
+      x.At(i).Div(x.At(i), A.At(i,i))
     }
-    x.At(i).Div(x.At(i), A.At(i,i))
   }
   return x, nil
 }
 
+func backSubstitutionMatrix(A Matrix, X Matrix, B ConstMatrix, t Scalar, transposed bool) (Matrix, error) {
+
+  n, m := B.Dims()
+
+  for k := 0; k < m; k++ {
+    if transposed {
+      for i := 0; i < n; i++ {
+        X.At(i,k).Set(B.ConstAt(i,k))
+        for j := 0; j < i; j++ {
+          t.Mul(A.At(j,i), X.At(j,k))
+          X.At(i,k).Sub(X.At(i,k), t)
+        }
+        X.At(i,k).Div(X.At(i,k), A.At(i,i))
+      }
+    } else {
+      for i := n-1; i >= 0; i-- {
+        X.At(i,k).Set(B.ConstAt(i,k))
+        for j := i+1; j < n; j++ {
+          t.Mul(A.At(i,j), X.At(j,k))
+          X.At(i,k).Sub(X.At(i,k), t)
+        }
+        X.At(i,k).Div(X.At(i,k), A.At(i,i))
+      }
+    }
+  }
+  return X, nil
+}
+
 /* -------------------------------------------------------------------------- */
 
 func Run(A Matrix, b Vector, args ...interface{}) (Vector, error) {
@@ -67,7 +116,8 @@ func Run(A Matrix, b Vector, args ...interface{}) (Vector, error) {
   if b != nil && m != b.Dim() {
     return nil, fmt.Errorf("matrix vector dimensions do not match")
   }
-  inSitu := &InSitu{}
+  inSitu     := &InSitu{}
+  transposed := false
 
   // loop over optional arguments
   for _, arg := range args {
@@ -76,6 +126,8 @@ func Run(A Matrix, b Vector, args ...interface{}) (Vector, error) {
       inSitu = tmp
     case InSitu:
       panic("InSitu must be passed by reference")
+    case Transposed:
+      transposed = tmp.Value
     }
   }
   if inSitu.A == nil {
@@ -95,5 +147,31 @@ func Run(A Matrix, b Vector, args ...interface{}) (Vector, error) {
   if inSitu.T == nil {
     inSitu.T = NullScalar(t)
   }
-  return backSubstitution(inSitu, b)
+  return backSubstitution(inSitu, b, transposed)
+}
+
+// Solve A X = B for X, where A is upper triangular and the columns of B
+// are the right-hand sides. A is not modified.
+func RunMatrix(A Matrix, B ConstMatrix, args ...interface{}) (Matrix, error) {
+  m, n := A.Dims()
+  t    := A.ElementType()
+
+  if m != n {
+    return nil, fmt.Errorf("matrix must be square")
+  }
+  if k, _ := B.Dims(); k != n {
+    return nil, fmt.Errorf("matrix dimensions do not match")
+  }
+  transposed := false
+
+  // loop over optional arguments
+  for _, arg := range args {
+    switch tmp := arg.(type) {
+    case Transposed:
+      transposed = tmp.Value
+    }
+  }
+  _, k := B.Dims()
+
+  return backSubstitutionMatrix(A, NullMatrix(t, n, k), B, NullScalar(t), transposed)
 }
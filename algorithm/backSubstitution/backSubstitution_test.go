@@ -43,3 +43,40 @@ func Test1(t *testing.T) {
     t.Error("test failed")
   }
 }
+
+func Test2(t *testing.T) {
+  a := NewMatrix(RealType, 3, 3, []float64{
+    1, -2,  1,
+    0,  1,  6,
+    0,  0,  1 })
+  b := NewMatrix(RealType, 3, 2, []float64{
+     4, 1,
+    -1, 0,
+     2, 3 })
+  // A X = B
+  r1 := NewMatrix(RealType, 3, 2, []float64{
+    -24, -38,
+    -13, -18,
+      2,   3 })
+  // A^T X = B
+  r2 := NewMatrix(RealType, 3, 2, []float64{
+      4,  1,
+      7,  2,
+    -44, -10 })
+
+  if x, err := RunMatrix(a, b); err != nil {
+    t.Error(err)
+  } else if Mnorm(MsubM(r1, x)).GetValue() > 1e-8 {
+    t.Error("test failed")
+  }
+  if x, err := RunMatrix(a, b, Transposed{true}); err != nil {
+    t.Error(err)
+  } else if Mnorm(MsubM(r2, x)).GetValue() > 1e-8 {
+    t.Error("test failed")
+  }
+  if x, err := Run(a, b.Col(0), Transposed{true}); err != nil {
+    t.Error(err)
+  } else if Vnorm(VsubV(r2.Col(0), x)).GetValue() > 1e-8 {
+    t.Error("test failed")
+  }
+}
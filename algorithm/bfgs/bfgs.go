@@ -81,7 +81,12 @@ func newObjectiveInSitu(f Objective) ObjectiveInSitu {
 
 func (f ObjectiveInSitu) Differentiate(x, g Vector, y Scalar) error {
   if f.X == nil {
-    f.X = NullVector(RealType, x.Dim())
+    t := x.ElementType()
+    // BareReal cannot carry derivatives
+    if t == BareRealType {
+      t = RealType
+    }
+    f.X = NullVector(t, x.Dim())
   }
   f.X.Set(x)
   f.X.Variables(1)
@@ -186,13 +191,20 @@ func bfgs(f_ Objective, f ObjectiveInSitu, x0 Vector, H0 Matrix, epsilon Epsilon
   // H: inverse Hessian
   n := x0.Dim()
   t := BareRealType
+  // scalar type used for computing derivatives
+  T := x0.ElementType()
+  // BareReal cannot carry derivatives
+  if T == BareRealType {
+    T = RealType
+  }
 
   p1 := NullVector(t, n)
   p2 := NullVector(t, n)
-  P2 := NullVector(RealType, n)
+  A2 := NullScalar(T)
+  P2 := NullVector(T, n)
   x1 := x0.CloneVector()
   x2 := x1.CloneVector()
-  X2 := x1.CloneVector()
+  X2 := NullVector(T, n)
   y1 := NullScalar(t)
   y2 := NullScalar(t)
   g1 := NullVector(t, n)
@@ -239,7 +251,13 @@ func bfgs(f_ Objective, f ObjectiveInSitu, x0 Vector, H0 Matrix, epsilon Epsilon
     bgfs_computeDirection(x1, y1, g1, H1, p1)
     // line search objective
     phi := func(alpha Scalar) (Scalar, error) {
-      P2.VmulS(p1, alpha)
+      // alpha might be of a different type, use
+      // a copy of type T as variable instead
+      A2.SetValue(alpha.GetValue())
+      if err := Variables(1, A2); err != nil {
+        return nil, err
+      }
+      P2.VmulS(p1, A2)
       X2.VaddV(x1, P2)
       return f_(X2)
     }
@@ -104,3 +104,29 @@ func TestBfgsRosenbrock(t *testing.T) {
     t.Error("BFGS Rosenbrock test failed!")
   }
 }
+
+func TestBfgsRosenbrockReverse(t *testing.T) {
+
+  f := func(x Vector) (Scalar, error) {
+    // f(x1, x2) = (a - x1)^2 + b(x2 - x1^2)^2
+    // a = 1
+    // b = 100
+    // minimum: (x1,x2) = (a, a^2)
+    a := NewReverseReal(  1.0)
+    b := NewReverseReal(100.0)
+    s := Pow(Sub(a, x.At(0)), NewReverseReal(2.0))
+    t := Mul(b, Pow(Sub(x.At(1), Mul(x.At(0), x.At(0))), NewReverseReal(2.0)))
+    return Add(s, t), nil
+  }
+
+  x0 := NewVector(ReverseRealType, []float64{-0.5, 2})
+  xr := NewVector(ReverseRealType, []float64{   1, 1})
+  xn, err := Run(f, x0,
+    Epsilon{1e-10})
+  if err != nil {
+    t.Error(err)
+  }
+  if Vnorm(VsubV(xn, xr)).GetValue() > 1e-8 {
+    t.Error("BFGS Rosenbrock test failed!")
+  }
+}
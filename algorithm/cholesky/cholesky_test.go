@@ -93,6 +93,58 @@ func TestCholesky3(t *testing.T) {
 }
 
 
+func TestCholeskyUpdate1(t *testing.T) {
+  n := 4
+  for _, e := range []ScalarType{RealType, BareRealType} {
+    a := NewMatrix(e, n, n, []float64{
+      18, 22,  54,  42,
+      22, 70,  86,  62,
+      54, 86, 174, 134,
+      42, 62, 134, 106 })
+    x := NewVector(e, []float64{1, -2, 3, 0.5})
+    // A + x x^T
+    b := MaddM(a, Outer(x, x))
+
+    l1, _, _ := Run(a)
+    l2, _, _ := Run(b)
+
+    l := l1.CloneMatrix()
+    if err := Update(l, x); err != nil {
+      t.Error(err)
+      return
+    }
+    if Mnorm(MsubM(l, l2)).GetValue() > 1e-16 {
+      t.Error("test failed")
+    }
+    if err := Downdate(l, x); err != nil {
+      t.Error(err)
+      return
+    }
+    if Mnorm(MsubM(l, l1)).GetValue() > 1e-16 {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestCholeskyUpdate2(t *testing.T) {
+  // downdate would result in a singular matrix
+  l := NewMatrix(RealType, 3, 3, []float64{
+    2, 0, 0,
+    1, 1, 0,
+    0, 1, 1 })
+  r := l.CloneMatrix()
+  x := NewVector(RealType, []float64{2, 1, 0})
+  if err := Downdate(l, x); err == nil {
+    t.Error("test failed")
+  }
+  if !l.Equals(r, 1e-12) {
+    t.Error("test failed")
+  }
+  if err := Downdate(l, NewVector(RealType, []float64{1, 0})); err == nil {
+    t.Error("test failed")
+  }
+}
+
 func TestPerformance(t *testing.T) {
   n := 100
 
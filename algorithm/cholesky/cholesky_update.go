@@ -0,0 +1,139 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package cholesky
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "errors"
+
+import . "github.com/pbenner/autodiff"
+import   "github.com/pbenner/autodiff/algorithm/givensRotation"
+
+/* -------------------------------------------------------------------------- */
+
+// Reference:
+// Golub, G. H., & Van Loan, C. F. (2013). Matrix Computations (4th ed.),
+// Section 6.5.4.
+//
+// Dongarra, J. J., Moler, C. B., Bunch, J. R., & Stewart, G. W. (1979).
+// LINPACK Users' Guide, Chapter 10.
+
+/* -------------------------------------------------------------------------- */
+
+func checkUpdateArguments(name string, l Matrix, x ConstVector) error {
+  n, m := l.Dims()
+  if n != m {
+    return errors.New(name + "(): Not a square matrix!")
+  }
+  if x.Dim() != n {
+    return errors.New(name + "(): Matrix and vector dimensions do not match!")
+  }
+  return nil
+}
+
+// Make all diagonal elements of L positive by changing the sign of columns.
+func normalizeSigns(l Matrix) {
+  n, _ := l.Dims()
+  for j := 0; j < n; j++ {
+    if l.At(j, j).GetValue() < 0.0 {
+      for i := j; i < n; i++ {
+        l.At(i, j).Neg(l.At(i, j))
+      }
+    }
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Given the Cholesky factor L of A = L L^T, compute the Cholesky factor of
+// the rank-one update A + x x^T. L is modified in-place with O(n^2)
+// operations.
+func Update(l Matrix, x ConstVector) error {
+  if err := checkUpdateArguments("Update", l, x); err != nil {
+    return err
+  }
+  n, _ := l.Dims()
+  t  := l.ElementType()
+  c  := NullScalar(t)
+  s  := NullScalar(t)
+  t1 := NullScalar(t)
+  t2 := NullScalar(t)
+  w  := NullVector(t, n)
+  w.Set(x)
+  // apply Givens rotations to the columns of [L x] such that x vanishes
+  for k := 0; k < n; k++ {
+    givensRotation.Run(l.At(k, k), w.At(k), c, s)
+    for i := k; i < n; i++ {
+      givensRotation.Apply(l.At(i, k), w.At(i), c, s, t1, t2)
+    }
+  }
+  normalizeSigns(l)
+  return nil
+}
+
+// Given the Cholesky factor L of A = L L^T, compute the Cholesky factor of
+// the rank-one downdate A - x x^T. L is modified in-place with O(n^2)
+// operations. An error is returned and L remains unchanged if A - x x^T is
+// not positive definite.
+func Downdate(l Matrix, x ConstVector) error {
+  if err := checkUpdateArguments("Downdate", l, x); err != nil {
+    return err
+  }
+  n, _ := l.Dims()
+  t  := l.ElementType()
+  c  := make([]Scalar, n)
+  s  := make([]Scalar, n)
+  t1 := NullScalar(t)
+  t2 := NullScalar(t)
+  // solve L p = x
+  p := NullVector(t, n)
+  for i := 0; i < n; i++ {
+    p.At(i).Set(x.ConstAt(i))
+    for j := 0; j < i; j++ {
+      t1.Mul(l.At(i, j), p.At(j))
+      p.At(i).Sub(p.At(i), t1)
+    }
+    p.At(i).Div(p.At(i), l.At(i, i))
+  }
+  // alpha^2 = 1 - p^T p must be positive
+  alpha := NullScalar(t)
+  alpha.VdotV(p, p)
+  alpha.Neg(alpha)
+  alpha.Add(alpha, ConstReal(1.0))
+  if alpha.GetValue() <= 0.0 {
+    return errors.New("Downdate(): Matrix is not positive definite!")
+  }
+  alpha.Sqrt(alpha)
+  // compute rotations that eliminate p from the bottom
+  for i := n-1; i >= 0; i-- {
+    c[i] = NullScalar(t)
+    s[i] = NullScalar(t)
+    givensRotation.Run(alpha, p.At(i), c[i], s[i])
+    givensRotation.Apply(alpha, p.At(i), c[i], s[i], t1, t2)
+  }
+  // apply rotations to the columns of L, which recovers x in w
+  w := NullVector(t, n)
+  for i := n-1; i >= 0; i-- {
+    for j := i; j < n; j++ {
+      givensRotation.Apply(w.At(j), l.At(j, i), c[i], s[i], t1, t2)
+    }
+  }
+  normalizeSigns(l)
+  return nil
+}
@@ -72,3 +72,20 @@ func TestDeterminant4(t *testing.T) {
   }
 
 }
+
+func TestDeterminant5(t *testing.T) {
+
+  m := NewMatrix(IntervalType, 3, 3, []float64{0.1, -1, 0, -1, 2.3, -1, 0, -1, 2})
+  n := NewMatrix(RealType,     3, 3, []float64{0.1, -1, 0, -1, 2.3, -1, 0, -1, 2})
+
+  r1, _ := Run(m)
+  r2, _ := Run(n)
+
+  if r, ok := r1.(*Interval); !ok {
+    t.Error("Matrix determinant failed!")
+  } else {
+    if !r.Contains(r2.GetValue()) || r.GetWidth() > 1e-14 {
+      t.Error("Matrix determinant failed!")
+    }
+  }
+}
@@ -20,6 +20,7 @@ package eigensystem
 
 //import   "fmt"
 import   "math"
+import   "math/cmplx"
 import   "sort"
 
 import . "github.com/pbenner/autodiff"
@@ -36,6 +37,20 @@ type ComputeEigenvectors struct {
   Value bool
 }
 
+// Return complex eigenvalues and eigenvectors of non-symmetric matrices
+// as DenseComplexVector and DenseComplexMatrix.
+type ComplexEigensystem struct {
+  Value bool
+}
+
+// Compute derivatives of symmetric eigensystems with closed-form
+// perturbation formulas instead of propagating them through the QR
+// iterations. The option is ignored if the matrix does not carry first
+// order derivatives. Analytic derivatives require distinct eigenvalues.
+type AnalyticDerivatives struct {
+  Value bool
+}
+
 type InSitu struct {
   QrAlgorithm  qrAlgorithm.InSitu
   Eigenvalues  Vector
@@ -54,7 +69,14 @@ func (v sortEigenvaluesType) Len() int {
 }
 
 func (v sortEigenvaluesType) Less(i, j int) bool {
-  return math.Abs(v.At(i).GetValue()) < math.Abs(v.At(j).GetValue())
+  return eigenvalueAbs(v.At(i)) < eigenvalueAbs(v.At(j))
+}
+
+func eigenvalueAbs(a ConstScalar) float64 {
+  if c, ok := a.(ConstComplexScalar); ok {
+    return cmplx.Abs(c.GetComplexValue())
+  }
+  return math.Abs(a.GetValue())
 }
 
 func sortEigenvalues(v Vector) {
@@ -126,24 +148,22 @@ func sortEigensystem(eigenvectors Matrix, eigenvalues Vector) {
   if eigenvectors == nil {
     sortEigenvalues(eigenvalues)
   } else {
-    m := make(map[Scalar]int)
-    // permutation
+    // permutation that sorts eigenvalues by decreasing absolute value
     p := make([]int, eigenvalues.Dim())
     for i := 0; i < eigenvalues.Dim(); i++ {
-      m[eigenvalues.At(i)] = i
-    }
-    sortEigenvalues(eigenvalues)
-
-    for i := 0; i < eigenvalues.Dim(); i++ {
-      p[i] = m[eigenvalues.At(i)]
+      p[i] = i
     }
+    sort.SliceStable(p, func(i, j int) bool {
+      return eigenvalueAbs(eigenvalues.At(p[i])) > eigenvalueAbs(eigenvalues.At(p[j]))
+    })
+    eigenvalues .Permute(p)
     eigenvectors.PermuteColumns(p)
   }
 }
 
 /* -------------------------------------------------------------------------- */
 
-func eigensystem(a Matrix, inSitu *InSitu, computeEigenvectors, symmetric bool, args ...interface{}) (Vector, Matrix, error) {
+func eigensystem(a Matrix, inSitu *InSitu, computeEigenvectors, symmetric, complexEigensystem bool, args ...interface{}) (Vector, Matrix, error) {
   eigenvalues  := inSitu.Eigenvalues
   eigenvectors := inSitu.Eigenvectors
 
@@ -163,6 +183,10 @@ func eigensystem(a Matrix, inSitu *InSitu, computeEigenvectors, symmetric bool,
     }
     // no need to copy eigenvectors in this case
 
+    sortEigensystem(eigenvectors, eigenvalues)
+  } else
+  if complexEigensystem {
+    eigensystemComplex(eigenvalues, eigenvectors, h, u)
     sortEigensystem(eigenvectors, eigenvalues)
   } else {
     getEigenvalues (eigenvalues, h)
@@ -180,6 +204,8 @@ func Run(a Matrix, args_ ...interface{}) (Vector, Matrix, error) {
   // default values for optional arguments
   computeEigenvectors := true
   symmetric           := false
+  complexEigensystem  := false
+  analyticDerivatives := false
   inSitu              := &InSitu{}
   // arguments passed on to the qrAlgorithm
   var args []interface{}
@@ -190,6 +216,10 @@ func Run(a Matrix, args_ ...interface{}) (Vector, Matrix, error) {
       computeEigenvectors = tmp.Value
     case Symmetric:
       symmetric = tmp.Value
+    case ComplexEigensystem:
+      complexEigensystem = tmp.Value
+    case AnalyticDerivatives:
+      analyticDerivatives = tmp.Value
     case qrAlgorithm.ComputeU:
       // drop this option
     case *InSitu:
@@ -198,6 +228,12 @@ func Run(a Matrix, args_ ...interface{}) (Vector, Matrix, error) {
       args = append(args, arg)
     }
   }
+  if symmetric {
+    complexEigensystem = false
+  }
+  if complexEigensystem {
+    t = ComplexType
+  }
   if inSitu.Eigenvalues == nil {
     inSitu.Eigenvalues = NullVector(t, n)
   }
@@ -207,5 +243,8 @@ func Run(a Matrix, args_ ...interface{}) (Vector, Matrix, error) {
       inSitu.QrAlgorithm.U = inSitu.Eigenvectors
     }
   }
-  return eigensystem(a, inSitu, computeEigenvectors, symmetric, args)
+  if symmetric && analyticDerivatives && HasFirstOrderDerivatives(a) {
+    return eigensystemDerivatives(a, inSitu, computeEigenvectors, args...)
+  }
+  return eigensystem(a, inSitu, computeEigenvectors, symmetric, complexEigensystem, args)
 }
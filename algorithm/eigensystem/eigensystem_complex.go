@@ -0,0 +1,197 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package eigensystem
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math"
+import   "math/cmplx"
+
+import . "github.com/pbenner/autodiff"
+
+/* complex Schur form
+ * -------------------------------------------------------------------------- */
+
+// Reduce the 2x2 block of the real Schur form t at position i to upper
+// triangular form with a unitary transform q, which is applied to t and u.
+func triangularizeBlock(t, u *DenseComplexMatrix, i int) {
+  n, _ := t.Dims()
+  a := t.AT(i  ,i  )
+  b := t.AT(i  ,i+1)
+  c := t.AT(i+1,i  )
+  d := t.AT(i+1,i+1)
+  // eigenvalue of the block:
+  // lambda = (a+d)/2 + sqrt(((a-d)/2)^2 + bc)
+  p := NullComplex()
+  q := NullComplex()
+  s := NullComplex()
+  p.Add(a, d)
+  p.Div(p, ConstReal(2.0))
+  q.Sub(a, d)
+  q.Div(q, ConstReal(2.0))
+  q.Mul(q, q)
+  s.Mul(b, c)
+  q.Add(q, s)
+  q.Sqrt(q)
+  p.Add(p, q)
+  // eigenvector (x1, x2) of the block
+  x1 := NullComplex()
+  x2 := NullComplex()
+  if cmplx.Abs(b.GetComplexValue()) >= cmplx.Abs(c.GetComplexValue()) {
+    x1.Set(b)
+    x2.Sub(p, a)
+  } else {
+    x1.Sub(p, d)
+    x2.Set(c)
+  }
+  // normalize eigenvector
+  s.Conj(x1)
+  s.Mul(s, x1)
+  q.Conj(x2)
+  q.Mul(q, x2)
+  s.Add(s, q)
+  s.Sqrt(s)
+  x1.Div(x1, s)
+  x2.Div(x2, s)
+  // q = [x1 -conj(x2); x2 conj(x1)]
+  y1 := NullComplex()
+  y2 := NullComplex()
+  y1.Conj(x1)
+  y2.Conj(x2)
+  t1 := NullComplex()
+  t2 := NullComplex()
+  // t = q^H t
+  for j := i; j < n; j++ {
+    r1 := t.AT(i  ,j)
+    r2 := t.AT(i+1,j)
+    t1.Mul(y1, r1)
+    s .Mul(y2, r2)
+    t1.Add(t1, s)
+    t2.Mul(x1, r2)
+    s .Mul(x2, r1)
+    t2.Sub(t2, s)
+    r1.Set(t1)
+    r2.Set(t2)
+  }
+  // t = t q
+  for k := 0; k <= i+1; k++ {
+    c1 := t.AT(k,i  )
+    c2 := t.AT(k,i+1)
+    t1.Mul(c1, x1)
+    s .Mul(c2, x2)
+    t1.Add(t1, s)
+    t2.Mul(c2, y1)
+    s .Mul(c1, y2)
+    t2.Sub(t2, s)
+    c1.Set(t1)
+    c2.Set(t2)
+  }
+  t.AT(i+1,i).Reset()
+  // u = u q
+  if u != nil {
+    for k := 0; k < n; k++ {
+      c1 := u.AT(k,i  )
+      c2 := u.AT(k,i+1)
+      t1.Mul(c1, x1)
+      s .Mul(c2, x2)
+      t1.Add(t1, s)
+      t2.Mul(c2, y1)
+      s .Mul(c1, y2)
+      t2.Sub(t2, s)
+      c1.Set(t1)
+      c2.Set(t2)
+    }
+  }
+}
+
+// Compute the kth eigenvector of the upper triangular matrix t by back
+// substitution and transform it with u.
+func getComplexEigenvector(eigenvector Vector, t, u *DenseComplexMatrix, y DenseComplexVector, k int) {
+  n, _ := t.Dims()
+  // small denominators are replaced to avoid divisions by zero
+  // in case of repeated eigenvalues
+  smin := 1e-300
+  if v := cmplx.Abs(t.AT(k,k).GetComplexValue()); v > 0.0 {
+    smin = math.Max(smin, 1e-16*v)
+  }
+  s := NullComplex()
+  d := NullComplex()
+  y.Reset()
+  y.AT(k).SetValue(1.0)
+  for i := k-1; i >= 0; i-- {
+    for j := i+1; j <= k; j++ {
+      s.Mul(t.AT(i,j), y.AT(j))
+      y.AT(i).Add(y.AT(i), s)
+    }
+    d.Sub(t.AT(i,i), t.AT(k,k))
+    if cmplx.Abs(d.GetComplexValue()) < smin {
+      d.SetValue(smin)
+    }
+    y.AT(i).Div(y.AT(i), d)
+    y.AT(i).Neg(y.AT(i))
+  }
+  // eigenvector = u y
+  for i := 0; i < n; i++ {
+    r := eigenvector.At(i)
+    r.Reset()
+    for j := 0; j <= k; j++ {
+      s.Mul(u.AT(i,j), y.AT(j))
+      r.Add(r, s)
+    }
+  }
+  s.Vnorm(eigenvector)
+  eigenvector.VdivS(eigenvector, s)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func eigensystemComplex(eigenvalues Vector, eigenvectors Matrix, h, u Matrix) {
+  n, _ := h.Dims()
+  // copy real Schur form
+  t := NullDenseComplexMatrix(n, n)
+  for i := 0; i < n; i++ {
+    for j := 0; j < n; j++ {
+      t.AT(i,j).Set(h.ConstAt(i,j))
+    }
+  }
+  var v *DenseComplexMatrix
+  if eigenvectors != nil {
+    v = NullDenseComplexMatrix(n, n)
+    for i := 0; i < n; i++ {
+      for j := 0; j < n; j++ {
+        v.AT(i,j).Set(u.ConstAt(i,j))
+      }
+    }
+  }
+  // compute complex Schur form
+  for i := 0; i < n-1; i++ {
+    if h.At(i+1,i).GetValue() != 0.0 {
+      triangularizeBlock(t, v, i)
+      i++
+    }
+  }
+  for i := 0; i < n; i++ {
+    eigenvalues.At(i).Set(t.AT(i,i))
+  }
+  if eigenvectors != nil {
+    y := NullDenseComplexVector(n)
+    for i := 0; i < n; i++ {
+      getComplexEigenvector(eigenvectors.Col(i), t, v, y, i)
+    }
+  }
+}
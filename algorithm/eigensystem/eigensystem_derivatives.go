@@ -0,0 +1,106 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package eigensystem
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "errors"
+import   "math"
+
+import . "github.com/pbenner/autodiff"
+
+/* -------------------------------------------------------------------------- */
+
+// Reference:
+// Magnus, J.~R. (1985). On Differentiating Eigenvalues and Eigenvectors.
+// Econometric Theory, 1(2), 179-191.
+
+/* -------------------------------------------------------------------------- */
+
+// eigenvalues closer than this (relative to the largest eigenvalue) are
+// considered to be degenerate
+const degeneracyTolerance = 1e-10
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the eigensystem of a symmetric matrix in BareReal and attach
+// first order derivatives to eigenvalues and eigenvectors. With dA the
+// derivative of A and P = U^T dA U, the derivatives are given by
+//
+//   dlambda_i = P_ii
+//   du_i      = sum_{j != i} u_j P_ji / (lambda_i - lambda_j)
+//
+// Both are computed with the element type of A as first order expansions
+// around the BareReal solution, so that derivatives are propagated by the
+// usual scalar operations.
+func eigensystemDerivatives(a Matrix, inSitu *InSitu, computeEigenvectors bool, args ...interface{}) (Vector, Matrix, error) {
+  n, _ := a.Dims()
+  t    := a.ElementType()
+  a0   := AsDenseBareRealMatrix(a)
+
+  args = append(args, Symmetric{true}, ComputeEigenvectors{true}, AnalyticDerivatives{false})
+  l0, u0, err := Run(a0, args...)
+  if err != nil {
+    return nil, nil, err
+  }
+  // check for degenerate eigenvalues, which only matter for the derivatives
+  // of eigenvectors
+  if computeEigenvectors {
+    scale := 0.0
+    for i := 0; i < n; i++ {
+      scale = math.Max(scale, math.Abs(l0.ValueAt(i)))
+    }
+    for i := 0; i < n; i++ {
+      for j := i+1; j < n; j++ {
+        if math.Abs(l0.ValueAt(i) - l0.ValueAt(j)) <= degeneracyTolerance*scale {
+          return nil, nil, errors.New("Eigensystem(): derivatives of eigenvectors are not defined for degenerate eigenvalues")
+        }
+      }
+    }
+  }
+  // P = U^T dA U, where dA = A - A0 has zero value
+  dA := NullMatrix(t, n, n)
+  dA.MsubM(a, a0)
+  T  := NullMatrix(t, n, n)
+  T.MdotM(dA, u0)
+  P  := dA
+  P.MdotM(u0.T(), T)
+
+  eigenvalues := inSitu.Eigenvalues
+  for i := 0; i < n; i++ {
+    eigenvalues.At(i).Add(l0.ConstAt(i), P.ConstAt(i, i))
+  }
+  if !computeEigenvectors {
+    return eigenvalues, nil, nil
+  }
+  // Omega_ji = P_ji / (lambda_i - lambda_j)
+  W := T
+  for i := 0; i < n; i++ {
+    for j := 0; j < n; j++ {
+      if i == j {
+        W.At(j, i).Reset()
+      } else {
+        W.At(j, i).Div(P.ConstAt(j, i), ConstReal(l0.ValueAt(i) - l0.ValueAt(j)))
+      }
+    }
+  }
+  eigenvectors := inSitu.Eigenvectors
+  eigenvectors.MdotM(u0, W)
+  eigenvectors.MaddM(eigenvectors, u0)
+  return eigenvalues, eigenvectors, nil
+}
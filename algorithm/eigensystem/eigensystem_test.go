@@ -20,6 +20,7 @@ package eigensystem
 
 //import   "fmt"
 import   "math"
+import   "math/cmplx"
 import   "testing"
 
 import . "github.com/pbenner/autodiff"
@@ -78,3 +79,186 @@ func Test2(t *testing.T) {
     }
   }
 }
+
+func Test3(t *testing.T) {
+  a := NewMatrix(RealType, 4, 4, []float64{
+    1, -2,  3, 0.5,
+    2,  1, -1, 2.0,
+    0,  3,  2, 1.0,
+    1,  0, -2, 4.0 })
+
+  e, v, err := Run(a, ComplexEigensystem{true})
+  if err != nil {
+    t.Error(err); return
+  }
+  if e.ElementType() != ComplexType || v.ElementType() != ComplexType {
+    t.Error("test failed"); return
+  }
+  n, _ := a.Dims()
+  // check a x = lambda x
+  for k := 0; k < n; k++ {
+    lambda := e.At(k).(*Complex).GetComplexValue()
+    for i := 0; i < n; i++ {
+      s := complex(0.0, 0.0)
+      for j := 0; j < n; j++ {
+        s += complex(a.At(i,j).GetValue(), 0.0)*v.At(j,k).(*Complex).GetComplexValue()
+      }
+      if cmplx.Abs(s - lambda*v.At(i,k).(*Complex).GetComplexValue()) > 1e-8 {
+        t.Errorf("test failed for eigenvector `%d'", k)
+      }
+    }
+  }
+  // eigenvalues are sorted by modulus and complex eigenvalues appear
+  // as conjugate pairs
+  for k := 1; k < n; k++ {
+    if cmplx.Abs(e.At(k).(*Complex).GetComplexValue()) > cmplx.Abs(e.At(k-1).(*Complex).GetComplexValue()) + 1e-12 {
+      t.Error("test failed")
+    }
+  }
+  s := complex(0.0, 0.0)
+  for k := 0; k < n; k++ {
+    s += e.At(k).(*Complex).GetComplexValue()
+  }
+  // trace
+  if cmplx.Abs(s - 8.0) > 1e-8 {
+    t.Error("test failed")
+  }
+}
+
+func Test4(t *testing.T) {
+  // eigenvalues 1 +/- 2i and 3
+  f := func(x float64) Vector {
+    a := NewMatrix(RealType, 3, 3, []float64{
+      1, -2, 0,
+      2,  1, 0,
+      0,  0, 3})
+    a.At(0,1).SetValue(-2.0*x)
+    a.Variables(1)
+    e, _, err := Run(a, ComplexEigensystem{true}, ComputeEigenvectors{false})
+    if err != nil {
+      t.Error(err)
+    }
+    return e
+  }
+  e := f(1.0)
+
+  r := []complex128{3, complex(1, 2), complex(1, -2)}
+  for k := 0; k < 3; k++ {
+    if cmplx.Abs(e.At(k).(*Complex).GetComplexValue() - r[k]) > 1e-8 &&
+      (k == 0 || cmplx.Abs(e.At(k).(*Complex).GetComplexValue() - cmplx.Conj(r[k])) > 1e-8) {
+      t.Errorf("test failed for eigenvalue `%d'", k)
+    }
+  }
+  // derivative of the eigenvalue 1 + 2i sqrt(x) with respect
+  // to the element a_12 = -2x at x = 1 is -i/2
+  for k := 1; k < 3; k++ {
+    z := e.At(k).(*Complex).GetComplexDerivative(1)
+    if cmplx.Abs(z - complex(0, -0.5*math.Copysign(1.0, imag(e.At(k).(*Complex).GetComplexValue())))) > 1e-8 {
+      t.Errorf("test failed for eigenvalue `%d'", k)
+    }
+  }
+}
+
+func newSymmetricMatrix(x, y Scalar) Matrix {
+  // A(x, y) = A0 + x S + y R with symmetric S and R
+  a0 := []float64{
+    4, 1, 0, 2,
+    1, 3, 1, 0,
+    0, 1, 5, 1,
+    2, 0, 1, 1 }
+  s  := []float64{
+    1, 0, 1, 0,
+    0, 2, 0, 1,
+    1, 0, 0, 0,
+    0, 1, 0, 3 }
+  r  := []float64{
+    0, 1, 0, 0,
+    1, 0, 0, 2,
+    0, 0, 1, 0,
+    0, 2, 0, 0 }
+  a := NullMatrix(RealType, 4, 4)
+  t := NullReal()
+  for i := 0; i < 4; i++ {
+    for j := 0; j < 4; j++ {
+      a.At(i, j).Mul(x, ConstReal(s[4*i+j]))
+      t         .Mul(y, ConstReal(r[4*i+j]))
+      a.At(i, j).Add(a.At(i, j), t)
+      a.At(i, j).Add(a.At(i, j), ConstReal(a0[4*i+j]))
+    }
+  }
+  return a
+}
+
+func Test5(t *testing.T) {
+  x := NewReal(0.5)
+  y := NewReal(0.2)
+  Variables(1, x, y)
+
+  a := newSymmetricMatrix(x, y)
+
+  l1, u1, err1 := Run(a, Symmetric{true}, AnalyticDerivatives{true})
+  l2, u2, err2 := Run(a, Symmetric{true})
+  if err1 != nil || err2 != nil {
+    t.Error("test failed")
+    return
+  }
+  for i := 0; i < 4; i++ {
+    for k := 0; k < 2; k++ {
+      if math.Abs(l1.At(i).GetValue() - l2.At(i).GetValue()) > 1e-10 {
+        t.Error("test failed")
+      }
+      if math.Abs(l1.At(i).GetDerivative(k) - l2.At(i).GetDerivative(k)) > 1e-8 {
+        t.Error("test failed")
+      }
+    }
+    for j := 0; j < 4; j++ {
+      for k := 0; k < 2; k++ {
+        if math.Abs(u1.At(i, j).GetValue() - u2.At(i, j).GetValue()) > 1e-10 {
+          t.Error("test failed")
+        }
+        if math.Abs(u1.At(i, j).GetDerivative(k) - u2.At(i, j).GetDerivative(k)) > 1e-8 {
+          t.Error("test failed")
+        }
+      }
+    }
+  }
+}
+
+func Test6(t *testing.T) {
+  // degenerate eigenvalues
+  a := NewMatrix(RealType, 3, 3, []float64{
+    2, 0, 0,
+    0, 2, 0,
+    0, 0, 1 })
+  a.Variables(1)
+
+  if _, _, err := Run(a, Symmetric{true}, AnalyticDerivatives{true}); err == nil {
+    t.Error("test failed")
+  }
+  if _, _, err := Run(a, Symmetric{true}); err != nil {
+    t.Error("test failed")
+  }
+  // derivatives of eigenvalues are defined, their sum is the derivative
+  // of the trace
+  if l, _, err := Run(a, Symmetric{true}, AnalyticDerivatives{true}, ComputeEigenvectors{false}); err != nil {
+    t.Error(err)
+  } else {
+    for k := 0; k < 9; k++ {
+      r := 0.0
+      for i := 0; i < 3; i++ {
+        r += l.ConstAt(i).GetDerivative(k)
+      }
+      if k % 4 == 0 && math.Abs(r - 1.0) > 1e-10 || k % 4 != 0 && math.Abs(r) > 1e-10 {
+        t.Error("test failed")
+      }
+    }
+  }
+  // the option is ignored for matrices without derivatives
+  b := NewMatrix(RealType, 3, 3, []float64{
+    2, 0, 0,
+    0, 2, 0,
+    0, 0, 1 })
+  if _, _, err := Run(b, Symmetric{true}, AnalyticDerivatives{true}); err != nil {
+    t.Error("test failed")
+  }
+}
@@ -67,3 +67,28 @@ func TestGaussJordan2(t *testing.T) {
     }
   }
 }
+
+func TestGaussJordan4(t *testing.T) {
+  // Hilbert matrix, which is ill-conditioned
+  n := 4
+  a := NullMatrix(BigRealType, n, n)
+  for i := 0; i < n; i++ {
+    for j := 0; j < n; j++ {
+      a.At(i, j).Div(ConstReal(1.0), ConstReal(float64(i+j+1)))
+    }
+  }
+  x := IdentityMatrix(BigRealType, n)
+  b := NullVector(BigRealType, n)
+  c := a.CloneMatrix()
+
+  if err := Run(a, x, b); err != nil {
+    t.Error(err)
+  } else {
+    r := NullMatrix(BigRealType, n, n)
+    r.MdotM(c, x)
+    r.MsubM(r, IdentityMatrix(BigRealType, n))
+    if Mnorm(r).GetValue() > 1e-100 {
+      t.Error("Gauss-Jordan method failed!")
+    }
+  }
+}
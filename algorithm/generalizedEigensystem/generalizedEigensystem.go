@@ -0,0 +1,100 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package generalizedEigensystem
+
+/* -------------------------------------------------------------------------- */
+
+import   "fmt"
+import   "errors"
+
+import . "github.com/pbenner/autodiff"
+import   "github.com/pbenner/autodiff/algorithm/backSubstitution"
+import   "github.com/pbenner/autodiff/algorithm/cholesky"
+import   "github.com/pbenner/autodiff/algorithm/eigensystem"
+
+/* -------------------------------------------------------------------------- */
+
+// Reference:
+// Golub, G. H., & Van Loan, C. F. (2013). Matrix Computations (4th ed.),
+// Section 8.7.2.
+
+/* -------------------------------------------------------------------------- */
+
+// Compute eigenvalues and eigenvectors of the generalized symmetric-definite
+// eigenproblem A x = lambda B x, where A is symmetric and B is symmetric
+// positive definite. With the Cholesky factorization B = L L^T the problem
+// is reduced to the symmetric eigenproblem C y = lambda y with
+// C = L^-1 A L^-T and x = L^-T y. Eigenvalues are sorted as in
+// eigensystem.Run and eigenvectors are B-orthonormal, i.e. X^T B X = I.
+// Optional arguments are passed on to eigensystem.Run.
+func Run(a, b Matrix, args ...interface{}) (Vector, Matrix, error) {
+  n1, m1 := a.Dims()
+  n2, m2 := b.Dims()
+  if n1 != m1 || n2 != m2 {
+    return nil, nil, errors.New("GeneralizedEigensystem(): Not a square matrix!")
+  }
+  if n1 != n2 {
+    return nil, nil, errors.New("GeneralizedEigensystem(): Matrix dimensions do not match!")
+  }
+  if n1 == 0 {
+    return nil, nil, errors.New("GeneralizedEigensystem(): Empty matrix!")
+  }
+  n := n1
+  l, _, err := cholesky.Run(b)
+  if err != nil {
+    return nil, nil, fmt.Errorf("GeneralizedEigensystem(): B is not positive definite: %w", err)
+  }
+  // computations are carried out in the element type of A
+  e := a.ElementType()
+  t := NullScalar(e)
+  if l.ElementType() != e {
+    l = AsMatrix(e, l)
+  }
+  // C = L^-1 (L^-1 A)^T, where systems with L = (L^T)^T are solved by
+  // forward substitution
+  w, err := backSubstitution.RunMatrix(l.T(), a, backSubstitution.Transposed{true})
+  if err != nil {
+    return nil, nil, err
+  }
+  c, err := backSubstitution.RunMatrix(l.T(), w.T(), backSubstitution.Transposed{true})
+  if err != nil {
+    return nil, nil, err
+  }
+  // remove asymmetry caused by rounding errors
+  for i := 0; i < n; i++ {
+    for j := i+1; j < n; j++ {
+      t.Add(c.At(i, j), c.At(j, i))
+      t.Div(t, ConstReal(2.0))
+      c.At(i, j).Set(t)
+      c.At(j, i).Set(t)
+    }
+  }
+  lambda, y, err := eigensystem.Run(c, append(args, eigensystem.Symmetric{true})...)
+  if err != nil {
+    return nil, nil, err
+  }
+  if y == nil {
+    return lambda, nil, nil
+  }
+  // X = L^-T Y
+  x, err := backSubstitution.RunMatrix(l.T(), y)
+  if err != nil {
+    return nil, nil, err
+  }
+  return lambda, x, nil
+}
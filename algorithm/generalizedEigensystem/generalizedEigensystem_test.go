@@ -0,0 +1,149 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package generalizedEigensystem
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "errors"
+import   "math"
+import   "math/rand"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+import . "github.com/pbenner/autodiff/simple"
+
+/* -------------------------------------------------------------------------- */
+
+func Test1(t *testing.T) {
+  a := NewMatrix(RealType, 2, 2, []float64{
+    2, 0,
+    0, 6 })
+  b := NewMatrix(RealType, 2, 2, []float64{
+    1, 0,
+    0, 2 })
+  lambda, x, err := Run(a, b)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  if math.Abs(lambda.At(0).GetValue() - 3.0) > 1e-12 || math.Abs(lambda.At(1).GetValue() - 2.0) > 1e-12 {
+    t.Error("test failed")
+  }
+  if !MdotM(MdotM(x.T(), b), x).Equals(IdentityMatrix(RealType, 2), 1e-12) {
+    t.Error("test failed")
+  }
+}
+
+func Test2(t *testing.T) {
+  n := 6
+  g := rand.New(rand.NewSource(1))
+  a := NullMatrix(BareRealType, n, n)
+  z := NullMatrix(BareRealType, n, n)
+  for i := 0; i < n; i++ {
+    for j := 0; j < n; j++ {
+      z.At(i, j).SetValue(g.NormFloat64())
+    }
+    for j := i; j < n; j++ {
+      v := g.NormFloat64()
+      a.At(i, j).SetValue(v)
+      a.At(j, i).SetValue(v)
+    }
+  }
+  // positive definite B = Z Z^T + I
+  b := MdotM(z, z.T())
+  for i := 0; i < n; i++ {
+    b.At(i, i).Add(b.At(i, i), ConstReal(1.0))
+  }
+  lambda, x, err := Run(a, b)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  // A X = B X diag(lambda)
+  for i := 0; i < n; i++ {
+    r1 := MdotV(a, x.Col(i))
+    r2 := MdotV(b, x.Col(i))
+    for j := 0; j < n; j++ {
+      if math.Abs(r1.At(j).GetValue() - lambda.At(i).GetValue()*r2.At(j).GetValue()) > 1e-10 {
+        t.Error("test failed")
+      }
+    }
+  }
+  if !MdotM(MdotM(x.T(), b), x).Equals(IdentityMatrix(BareRealType, n), 1e-10) {
+    t.Error("test failed")
+  }
+}
+
+func Test3(t *testing.T) {
+  a := NewMatrix(BareRealType, 2, 2, []float64{
+    1, 0,
+    0, 1 })
+  b := NewMatrix(BareRealType, 2, 2, []float64{
+    1, 2,
+    2, 1 })
+  if _, _, err := Run(a, b); err == nil {
+    t.Error("test failed")
+  } else if errors.Unwrap(err) == nil {
+    t.Error("test failed")
+  }
+}
+
+func Test4(t *testing.T) {
+  // A = 2B - B v v^T B with v^T B v = 1 has the generalized eigenvalue 2
+  // with multiplicity two and the eigenvalue 1 with eigenvector v
+  a := NewMatrix(RealType, 3, 3, []float64{
+    4, 1,    0,
+    1, 5.75, 2,
+    0, 2,    4 })
+  b := NewMatrix(RealType, 3, 3, []float64{
+    4, 1, 0,
+    1, 3, 1,
+    0, 1, 2 })
+  a.Variables(1)
+
+  lambda, x, err := Run(a, b)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  n := 0
+  for i := 0; i < 3; i++ {
+    if math.Abs(lambda.At(i).GetValue() - 2.0) < 1e-10 {
+      n++
+    } else if math.Abs(lambda.At(i).GetValue() - 1.0) > 1e-10 {
+      t.Error("test failed")
+    }
+  }
+  if n != 2 {
+    t.Error("test failed")
+  }
+  // A X = B X diag(lambda)
+  for i := 0; i < 3; i++ {
+    r1 := MdotV(a, x.Col(i))
+    r2 := MdotV(b, x.Col(i))
+    for j := 0; j < 3; j++ {
+      if math.Abs(r1.At(j).GetValue() - lambda.At(i).GetValue()*r2.At(j).GetValue()) > 1e-10 {
+        t.Error("test failed")
+      }
+    }
+  }
+  if !MdotM(MdotM(x.T(), b), x).Equals(IdentityMatrix(RealType, 3), 1e-10) {
+    t.Error("test failed")
+  }
+}
@@ -37,6 +37,12 @@ func apply(a1, a2, c, s Scalar, t1, t2 Scalar) {
   a2.Add(a2, t2)
 }
 
+// Apply the rotation to the pair (a1, a2), i.e. a1 = c a1 - s a2 and
+// a2 = s a1 + c a2. The scalars t1 and t2 are used as temporary memory.
+func Apply(a1, a2, c, s Scalar, t1, t2 Scalar) {
+  apply(a1, a2, c, s, t1, t2)
+}
+
 /* -------------------------------------------------------------------------- */
 
 func ApplyBidiagLeft(A Matrix, c, s Scalar, i, k int, t1, t2 Scalar) {
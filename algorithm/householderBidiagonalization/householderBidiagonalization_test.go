@@ -50,3 +50,38 @@ func Test1(t *testing.T) {
     t.Error("test failed")
   }
 }
+
+func Test2(t *testing.T) {
+  a := NewMatrix(RealType, 6, 5, []float64{
+    1,  2, 3,  4,  5,
+    2, -1, 0,  3,  1,
+    4,  4, 1, -2,  0,
+    1,  0, 0,  7,  3,
+    2,  2, 2,  1, -1,
+    0,  3, 1,  1,  2 })
+
+  b, u, v, _ := Run(a, ComputeU{true}, ComputeV{true})
+
+  r := MdotM(u.T(),MdotM(a,v))
+
+  if Mnorm(MsubM(r, b)).GetValue() > 1e-8 {
+    t.Error("test failed")
+  }
+}
+
+func Test3(t *testing.T) {
+  a := NewMatrix(RealType, 4, 4, []float64{
+     3, 1, -2,  5,
+     0, 4,  1, -1,
+     2, 7,  1,  0,
+    -1, 2,  6,  3 })
+
+  b, u, v, _ := Run(a, ComputeU{true}, ComputeV{true})
+
+  // A = U B V^T
+  r := MdotM(u, MdotM(b, v.T()))
+
+  if Mnorm(MsubM(r, a)).GetValue() > 1e-8 {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,197 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package implicit
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "errors"
+
+import . "github.com/pbenner/autodiff"
+import   "github.com/pbenner/autodiff/algorithm/lu"
+
+/* -------------------------------------------------------------------------- */
+
+// Objective function f(x, theta) with parameters x and hyperparameters
+// theta
+type Objective func(x, theta Vector) (Scalar, error)
+
+/* -------------------------------------------------------------------------- */
+
+func checkArguments(name string, args []interface{}) {
+  for _, arg := range args {
+    switch arg.(type) {
+    default:
+      panic(name + "(): Invalid optional argument!")
+    }
+  }
+}
+
+// BareReal cannot carry derivatives
+func derivativeType(types ...ScalarType) ScalarType {
+  for _, t := range types {
+    if t != BareRealType {
+      return t
+    }
+  }
+  return RealType
+}
+
+// solve A X = B for all columns of B
+func solve(a, b ConstMatrix) (Matrix, error) {
+  n, m := b.Dims()
+  p, l, u, err := lu.Run(a)
+  if err != nil {
+    return nil, err
+  }
+  x := NullMatrix(BareRealType, n, m)
+  for j := 0; j < m; j++ {
+    r, err := lu.Solve(p, l, u, b.ConstCol(j))
+    if err != nil {
+      return nil, err
+    }
+    for i := 0; i < n; i++ {
+      x.At(i, j).Set(r.ConstAt(i))
+    }
+  }
+  return x, nil
+}
+
+// r_i = x_i + sum_k J_ik (theta_k - value(theta_k)), i.e. the value of r
+// is x and its derivatives are J times the derivatives of theta
+func attach(x ConstVector, j ConstMatrix, theta ConstVector, t ScalarType) Vector {
+  d := NullVector(t, theta.Dim())
+  for k := 0; k < theta.Dim(); k++ {
+    d.At(k).Sub(theta.ConstAt(k), ConstReal(theta.ValueAt(k)))
+  }
+  r := NullVector(t, x.Dim())
+  r.MdotV(j, d)
+  for i := 0; i < x.Dim(); i++ {
+    r.At(i).Add(r.At(i), ConstReal(x.ValueAt(i)))
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the derivatives dx/dtheta of a stationary point x of f(x, theta)
+// with respect to theta. By the implicit function theorem, the gradient
+// condition df/dx (x, theta) = 0 implies
+//
+//   d^2f/dx^2 dx/dtheta = -d^2f/dx dtheta
+//
+// The result is an n x p matrix with n the dimension of x and p the
+// dimension of theta. The objective function must be twice differentiable
+// at x and is evaluated once with second order derivatives.
+func Jacobian(f Objective, x, theta ConstVector, args ...interface{}) (Matrix, error) {
+  checkArguments("Jacobian", args)
+  n := x.Dim()
+  p := theta.Dim()
+  if n == 0 {
+    return nil, errors.New("Jacobian(): Empty parameter vector!")
+  }
+  // evaluate f with second order derivatives with respect to x and theta
+  z := NullVector(RealType, n+p)
+  for i := 0; i < n; i++ {
+    z.At(i).SetValue(x.ValueAt(i))
+  }
+  for k := 0; k < p; k++ {
+    z.At(n+k).SetValue(theta.ValueAt(k))
+  }
+  if err := z.Variables(2); err != nil {
+    return nil, err
+  }
+  y, err := f(z.Slice(0, n), z.Slice(n, n+p))
+  if err != nil {
+    return nil, err
+  }
+  if y.GetOrder() < 2 {
+    return nil, errors.New("Jacobian(): Objective function does not provide second order derivatives!")
+  }
+  hxx := NullMatrix(BareRealType, n, n)
+  hxt := NullMatrix(BareRealType, n, p)
+  for i := 0; i < n; i++ {
+    for j := 0; j < n; j++ {
+      hxx.At(i, j).SetValue(y.GetHessian(i, j))
+    }
+    for k := 0; k < p; k++ {
+      hxt.At(i, k).SetValue(-y.GetHessian(i, n+k))
+    }
+  }
+  r, err := solve(hxx, hxt)
+  if err != nil {
+    return nil, errors.New("Jacobian(): Hessian is singular!")
+  }
+  return r, nil
+}
+
+// Attach derivatives to a stationary point x of f(x, theta), which was
+// computed for instance with newton.RunMin or bfgs.Run on a copy of the
+// hyperparameters. The returned vector has the same value as x and
+// derivatives dx/dtheta times the derivatives of theta, i.e. derivatives
+// with respect to all variables upstream of theta.
+func Minimum(f Objective, x, theta ConstVector, args ...interface{}) (Vector, error) {
+  checkArguments("Minimum", args)
+  j, err := Jacobian(f, x, theta)
+  if err != nil {
+    return nil, err
+  }
+  return attach(x, j, theta, derivativeType(theta.ElementType())), nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Attach derivatives to the solution x of the linear system A x = b, which
+// was computed for instance with gaussJordan.Run on copies of A and b. The
+// returned vector has the same value as x and derivatives
+//
+//   dx = A^-1 (db - dA x)
+//
+// with respect to all variables of A and b.
+func Solve(a ConstMatrix, b, x ConstVector, args ...interface{}) (Vector, error) {
+  checkArguments("Solve", args)
+  n, m := a.Dims()
+  if n != m {
+    return nil, errors.New("Solve(): Not a square matrix!")
+  }
+  if b.Dim() != n || x.Dim() != n {
+    return nil, errors.New("Solve(): Vector has invalid dimension!")
+  }
+  t  := derivativeType(a.ElementType(), b.ElementType())
+  a0 := AsDenseBareRealMatrix(a)
+  x0 := AsDenseBareRealVector(x)
+  // A^-1
+  ai, err := solve(a0, IdentityMatrix(BareRealType, n))
+  if err != nil {
+    return nil, errors.New("Solve(): Matrix is singular!")
+  }
+  // r = db - dA x, where db = b - b0 and dA = A - A0 have zero value
+  dA := NullMatrix(t, n, n)
+  dA.MsubM(a, a0)
+  r  := NullVector(t, n)
+  r.MdotV(dA, x0)
+  for i := 0; i < n; i++ {
+    r.At(i).Sub(b.ConstAt(i), r.At(i))
+    r.At(i).Sub(r.At(i), ConstReal(b.ValueAt(i)))
+  }
+  s := NullVector(t, n)
+  s.MdotV(ai, r)
+  for i := 0; i < n; i++ {
+    s.At(i).Add(s.At(i), ConstReal(x0.ValueAt(i)))
+  }
+  return s, nil
+}
@@ -0,0 +1,129 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package implicit
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+import . "github.com/pbenner/autodiff/simple"
+import   "github.com/pbenner/autodiff/algorithm/gaussJordan"
+import   "github.com/pbenner/autodiff/algorithm/matrixInverse"
+import   "github.com/pbenner/autodiff/algorithm/newton"
+
+/* -------------------------------------------------------------------------- */
+
+func TestMinimum(t *testing.T) {
+  a := NewMatrix(BareRealType, 5, 2, []float64{
+    1.0, -1.0,
+    1.0, -0.5,
+    1.0,  0.0,
+    1.0,  0.5,
+    1.0,  1.0 })
+  b := NewVector(BareRealType, []float64{0.3, 0.8, 1.1, 1.4, 2.1})
+  // ridge regression 1/2 ||A x - b||^2 + 1/2 lambda ||x||^2
+  f := func(x, theta Vector) (Scalar, error) {
+    r := NullVector(x.ElementType(), 5)
+    r.MdotV(a, x)
+    r.VsubV(r, b)
+    s := NullScalar(x.ElementType())
+    t := NullScalar(x.ElementType())
+    s.VdotV(r, r)
+    t.VdotV(x, x)
+    t.Mul(t, theta.At(0))
+    s.Add(s, t)
+    s.Div(s, ConstReal(2.0))
+    return s, nil
+  }
+  // upstream variable
+  lambda := NewReal(0.7)
+  Variables(1, lambda)
+  theta  := NewVector(RealType, []float64{0.0})
+  theta.At(0).Set(lambda)
+
+  // minimize on a copy of the hyperparameters
+  g := func(x Vector) (Scalar, error) {
+    return f(x, NewVector(RealType, []float64{0.7}))
+  }
+  x, err := newton.RunMin(g, NewVector(RealType, []float64{0.0, 0.0}), newton.Epsilon{1e-12})
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  r, err := Minimum(f, x, theta)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  // dx/dlambda = -(A^T A + lambda I)^-1 x
+  h := MdotM(a.T(), a)
+  h.At(0, 0).Add(h.At(0, 0), ConstReal(0.7))
+  h.At(1, 1).Add(h.At(1, 1), ConstReal(0.7))
+  hi, _ := matrixInverse.Run(h)
+  d := MdotV(hi, AsDenseBareRealVector(x))
+
+  for i := 0; i < 2; i++ {
+    if math.Abs(r.At(i).GetValue() - x.At(i).GetValue()) > 1e-12 {
+      t.Error("test failed")
+    }
+    if math.Abs(r.At(i).GetDerivative(0) + d.At(i).GetValue()) > 1e-8 {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestSolve(t *testing.T) {
+  a := NewMatrix(RealType, 3, 3, []float64{
+    4, 1, 2,
+    1, 5, 1,
+    2, 0, 3 })
+  b := NewVector(RealType, []float64{1, 2, 3})
+  // derivatives with respect to a_01 and b_2
+  Variables(1, a.At(0, 1), b.At(2))
+
+  // solve system on copies, the solution is stored in b0
+  a0 := AsDenseBareRealMatrix(a)
+  b0 := AsDenseBareRealVector(b)
+  x0 := NullDenseBareRealMatrix(3, 3)
+  x0.SetIdentity()
+  if err := gaussJordan.Run(a0, x0, b0); err != nil {
+    t.Error(err)
+    return
+  }
+  x, err := Solve(a, b, b0)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  // solution with derivatives
+  ai, _ := matrixInverse.Run(a)
+  r := MdotV(ai, b)
+
+  for i := 0; i < 3; i++ {
+    if math.Abs(x.At(i).GetValue() - r.At(i).GetValue()) > 1e-12 {
+      t.Error("test failed")
+    }
+    for k := 0; k < 2; k++ {
+      if math.Abs(x.At(i).GetDerivative(k) - r.At(i).GetDerivative(k)) > 1e-12 {
+        t.Error("test failed")
+      }
+    }
+  }
+}
@@ -0,0 +1,108 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package krylov
+
+/* -------------------------------------------------------------------------- */
+
+import   "fmt"
+
+import . "github.com/pbenner/autodiff"
+
+/* -------------------------------------------------------------------------- */
+
+func pcg(a, m LinearOperator, b ConstVector, opts options) (Vector, error) {
+  x, r, tol, err := initialize(a, b, opts)
+  if err != nil {
+    return nil, err
+  }
+  n := b.Dim()
+  t := b.ElementType()
+  z := NullVector(t, n)
+  p := NullVector(t, n)
+  q := NullVector(t, n)
+  alpha := NullScalar(t)
+  beta  := NullScalar(t)
+  rz1   := NullScalar(t)
+  rz2   := NullScalar(t)
+  rnorm := NullScalar(t)
+  // z = M r
+  if m != nil {
+    m.MdotV(z, r)
+  } else {
+    z.Set(r)
+  }
+  p.Set(z)
+  rz1.VdotV(r, z)
+
+  for i := 0; i < opts.maxIterations.Value; i++ {
+    rnorm.Vnorm(r)
+    // execute hook if available
+    if opts.hook.Value != nil && opts.hook.Value(x, rnorm, i) {
+      break
+    }
+    // evaluate stop criterion
+    if rnorm.GetValue() <= tol {
+      break
+    }
+    // alpha = r^T z / p^T A p
+    a.MdotV(q, p)
+    alpha.VdotV(p, q)
+    if alpha.GetValue() <= 0.0 {
+      return x, fmt.Errorf("linear operator is not positive definite")
+    }
+    alpha.Div(rz1, alpha)
+    // x = x + alpha p
+    z.VmulS(p, alpha)
+    x.VaddV(x, z)
+    // r = r - alpha A p
+    q.VmulS(q, alpha)
+    r.VsubV(r, q)
+    // z = M r
+    if m != nil {
+      m.MdotV(z, r)
+    } else {
+      z.Set(r)
+    }
+    rz2.VdotV(r, z)
+    // p = z + beta p
+    beta.Div(rz2, rz1)
+    p.VmulS(p, beta)
+    p.VaddV(z, p)
+    rz1.Set(rz2)
+  }
+  return x, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Solve the linear system A x = b with the conjugate gradient method,
+// where A must be symmetric positive definite.
+func CG(a LinearOperator, b ConstVector, args ...interface{}) (Vector, error) {
+  return pcg(a, nil, b, parseOptions("CG", args))
+}
+
+// Solve the linear system A x = b with the preconditioned conjugate
+// gradient method, where A must be symmetric positive definite. The
+// linear operator m is the inverse of the preconditioner, which should
+// approximate the inverse of A and must also be symmetric positive
+// definite.
+func PCG(a, m LinearOperator, b ConstVector, args ...interface{}) (Vector, error) {
+  if n1, m1 := m.Dims(); n1 != b.Dim() || m1 != b.Dim() {
+    return nil, fmt.Errorf("preconditioner has invalid dimensions")
+  }
+  return pcg(a, m, b, parseOptions("PCG", args))
+}
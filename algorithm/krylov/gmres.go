@@ -0,0 +1,170 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Reference:
+// Saad, Youcef, and Martin H. Schultz. GMRES: A generalized minimal
+// residual algorithm for solving nonsymmetric linear systems. SIAM Journal
+// on Scientific and Statistical Computing 7.3 (1986): 856-869.
+
+/* -------------------------------------------------------------------------- */
+
+package krylov
+
+/* -------------------------------------------------------------------------- */
+
+import   "fmt"
+
+import . "github.com/pbenner/autodiff"
+import   "github.com/pbenner/autodiff/algorithm/backSubstitution"
+
+/* -------------------------------------------------------------------------- */
+
+// compute x + V y, where y solves the triangular system H y = g
+func gmres_update(z, x Vector, h Matrix, g Vector, v []Vector, k int, t1 Vector) error {
+  y, err := backSubstitution.Run(h.Slice(0,k,0,k), g.Slice(0,k))
+  if err != nil {
+    return err
+  }
+  z.Set(x)
+  for i := 0; i < k; i++ {
+    t1.VmulS(v[i], y.At(i))
+    z .VaddV(z, t1)
+  }
+  return nil
+}
+
+func gmres(a LinearOperator, b ConstVector, opts options) (Vector, error) {
+  x, r, tol, err := initialize(a, b, opts)
+  if err != nil {
+    return nil, err
+  }
+  n := b.Dim()
+  t := b.ElementType()
+  m := opts.restart.Value
+  if m > n {
+    m = n
+  }
+  // orthonormal basis of the Krylov space
+  v := make([]Vector, m+1)
+  for i := 0; i <= m; i++ {
+    v[i] = NullVector(t, n)
+  }
+  // Hessenberg matrix, reduced to upper triangular form by Givens
+  // rotations
+  h  := NullMatrix(t, m+1, m)
+  g  := NullVector(t, m+1)
+  c  := NullVector(t, m)
+  s  := NullVector(t, m)
+  w  := NullVector(t, n)
+  z  := NullVector(t, n)
+  t1 := NullScalar(t)
+  t2 := NullScalar(t)
+  t3 := NullScalar(t)
+  tv := NullVector(t, n)
+  rnorm := NullScalar(t)
+
+  for i := 0; i < opts.maxIterations.Value; {
+    // compute residual
+    a.MdotV(r, x)
+    r.VsubV(b, r)
+    rnorm.Vnorm(r)
+    if opts.hook.Value != nil && opts.hook.Value(x, rnorm, i) {
+      break
+    }
+    if rnorm.GetValue() <= tol {
+      break
+    }
+    v[0].VdivS(r, rnorm)
+    g.Reset()
+    g.At(0).Set(rnorm)
+    h.Reset()
+    // Arnoldi process
+    k := 0
+    for j := 0; j < m && i < opts.maxIterations.Value; j++ {
+      a.MdotV(w, v[j])
+      // modified Gram-Schmidt
+      for l := 0; l <= j; l++ {
+        h.At(l, j).VdotV(w, v[l])
+        tv.VmulS(v[l], h.At(l, j))
+        w .VsubV(w, tv)
+      }
+      h.At(j+1, j).Vnorm(w)
+      // the Krylov space is invariant under A if h(j+1,j) is zero, in
+      // which case the exact solution is found
+      breakdown := h.At(j+1, j).GetValue() == 0.0
+      if !breakdown {
+        v[j+1].VdivS(w, h.At(j+1, j))
+      }
+      // apply previous rotations to the new column
+      for l := 0; l < j; l++ {
+        t1.Mul(c.At(l), h.At(l  , j))
+        t2.Mul(s.At(l), h.At(l+1, j))
+        t3.Mul(s.At(l), h.At(l  , j))
+        t3.Neg(t3)
+        h.At(l+1, j).Mul(c.At(l), h.At(l+1, j))
+        h.At(l+1, j).Add(h.At(l+1, j), t3)
+        h.At(l  , j).Add(t1, t2)
+      }
+      // compute new rotation that eliminates h(j+1,j)
+      t1.Mul(h.At(j, j), h.At(j, j))
+      t2.Mul(h.At(j+1, j), h.At(j+1, j))
+      t1.Add(t1, t2)
+      t1.Sqrt(t1)
+      if t1.GetValue() == 0.0 {
+        return x, fmt.Errorf("linear operator is singular")
+      }
+      c.At(j).Div(h.At(j  , j), t1)
+      s.At(j).Div(h.At(j+1, j), t1)
+      h.At(j  , j).Set(t1)
+      h.At(j+1, j).Reset()
+      // apply rotation to the right-hand side
+      g.At(j+1).Mul(s.At(j), g.At(j))
+      g.At(j+1).Neg(g.At(j+1))
+      g.At(j  ).Mul(c.At(j), g.At(j))
+
+      i++; k = j+1
+      // |g(j+1)| is the norm of the residual
+      rnorm.Abs(g.At(j+1))
+      if rnorm.GetValue() <= tol || breakdown {
+        break
+      }
+      // execute hook if available (at the end of the cycle the hook is
+      // called after restarting)
+      if opts.hook.Value != nil && j+1 < m && i < opts.maxIterations.Value {
+        if err := gmres_update(z, x, h, g, v, k, tv); err != nil {
+          return x, err
+        }
+        if opts.hook.Value(z, rnorm, i) {
+          return z, nil
+        }
+      }
+    }
+    if err := gmres_update(z, x, h, g, v, k, tv); err != nil {
+      return x, err
+    }
+    x.Set(z)
+  }
+  return x, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Solve the linear system A x = b with the generalized minimal residual
+// method, where A is an arbitrary non-singular matrix. The method is
+// restarted after a fixed number of iterations (see Restart).
+func GMRES(a LinearOperator, b ConstVector, args ...interface{}) (Vector, error) {
+  return gmres(a, b, parseOptions("GMRES", args))
+}
@@ -0,0 +1,118 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package krylov
+
+/* -------------------------------------------------------------------------- */
+
+import   "fmt"
+
+import . "github.com/pbenner/autodiff"
+
+/* -------------------------------------------------------------------------- */
+
+// Iterations stop as soon as the norm of the residual b - A x is smaller
+// than Epsilon times the norm of b.
+type Epsilon struct {
+  Value float64
+}
+
+type MaxIterations struct {
+  Value int
+}
+
+// The hook is called in every iteration with the current estimate x, the
+// norm of the residual and the iteration number. Iterations stop if the
+// hook returns true.
+type Hook struct {
+  Value func(x ConstVector, r ConstScalar, i int) bool
+}
+
+// Initial value of the iteration, by default zero.
+type InitialGuess struct {
+  Value ConstVector
+}
+
+// Number of iterations after which GMRES is restarted.
+type Restart struct {
+  Value int
+}
+
+/* -------------------------------------------------------------------------- */
+
+type options struct {
+  epsilon       Epsilon
+  maxIterations MaxIterations
+  hook          Hook
+  initialGuess  InitialGuess
+  restart       Restart
+}
+
+func parseOptions(name string, args []interface{}) options {
+  opts := options{
+    epsilon      : Epsilon      {1e-8},
+    maxIterations: MaxIterations{int(^uint(0) >> 1)},
+    restart      : Restart      {30} }
+  // loop over optional arguments
+  for _, arg := range args {
+    switch a := arg.(type) {
+    case Epsilon:
+      opts.epsilon = a
+    case MaxIterations:
+      opts.maxIterations = a
+    case Hook:
+      opts.hook = a
+    case InitialGuess:
+      opts.initialGuess = a
+    case Restart:
+      opts.restart = a
+    default:
+      panic(fmt.Sprintf("%s(): Invalid optional argument!", name))
+    }
+  }
+  return opts
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Check dimensions and compute the initial residual r = b - A x0. Returns
+// the initial value, the residual and the convergence threshold.
+func initialize(a LinearOperator, b ConstVector, opts options) (Vector, Vector, float64, error) {
+  n, m := a.Dims()
+  if n != m {
+    return nil, nil, 0, fmt.Errorf("linear operator must be square")
+  }
+  if b.Dim() != n {
+    return nil, nil, 0, fmt.Errorf("linear operator and vector dimensions do not match")
+  }
+  if opts.restart.Value <= 0 {
+    return nil, nil, 0, fmt.Errorf("invalid restart parameter")
+  }
+  t := b.ElementType()
+  x := NullVector(t, n)
+  r := NullVector(t, n)
+  if opts.initialGuess.Value != nil {
+    if opts.initialGuess.Value.Dim() != n {
+      return nil, nil, 0, fmt.Errorf("initial guess has invalid dimension")
+    }
+    x.Set(opts.initialGuess.Value)
+    a.MdotV(r, x)
+    r.VsubV(b, r)
+  } else {
+    r.Set(b)
+  }
+  return x, r, opts.epsilon.Value*NullScalar(t).Vnorm(b).GetValue(), nil
+}
@@ -0,0 +1,193 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package krylov
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+import . "github.com/pbenner/autodiff/simple"
+import   "github.com/pbenner/autodiff/algorithm/matrixInverse"
+
+/* -------------------------------------------------------------------------- */
+
+func solve(a Matrix, b Vector) Vector {
+  ainv, _ := matrixInverse.Run(a)
+  return MdotV(ainv, b)
+}
+
+func vnorm(a Vector) float64 {
+  return math.Sqrt(VdotV(a, a).GetValue())
+}
+
+/* -------------------------------------------------------------------------- */
+
+func TestCG(t *testing.T) {
+  a := NewMatrix(RealType, 4, 4, []float64{
+    4, 1, 0, 0,
+    1, 3, 1, 0,
+    0, 1, 2, 1,
+    0, 0, 1, 5 })
+  b := NewVector(RealType, []float64{1, 2, 3, 4})
+  r := solve(a, b)
+
+  if x, err := CG(AsLinearOperator(a), b, Epsilon{1e-12}); err != nil {
+    t.Error(err)
+  } else if vnorm(VsubV(x, r)) > 1e-10 {
+    t.Error("test failed")
+  }
+  // sparse matrix
+  s := NewSparseRealMatrix(4, 4,
+    []int{0, 0, 1, 1, 1, 2, 2, 2, 3, 3},
+    []int{0, 1, 0, 1, 2, 1, 2, 3, 2, 3},
+    []float64{4, 1, 1, 3, 1, 1, 2, 1, 1, 5})
+  if x, err := CG(AsLinearOperator(s), b, Epsilon{1e-12}); err != nil {
+    t.Error(err)
+  } else if vnorm(VsubV(x, r)) > 1e-10 {
+    t.Error("test failed")
+  }
+  // Jacobi preconditioner
+  m := NewLinearOperator(4, 4, func(r Vector, b ConstVector) {
+    for i := 0; i < 4; i++ {
+      r.At(i).Div(b.ConstAt(i), a.ConstAt(i, i))
+    }
+  })
+  if x, err := PCG(AsLinearOperator(a), m, b, Epsilon{1e-12}, InitialGuess{NewVector(RealType, []float64{1, 1, 1, 1})}); err != nil {
+    t.Error(err)
+  } else if vnorm(VsubV(x, r)) > 1e-10 {
+    t.Error("test failed")
+  }
+}
+
+func TestCG2(t *testing.T) {
+  // indefinite matrix
+  a := NewMatrix(RealType, 2, 2, []float64{
+    1,  0,
+    0, -1 })
+  b := NewVector(RealType, []float64{1, 1})
+
+  if _, err := CG(AsLinearOperator(a), b); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestMINRES(t *testing.T) {
+  a := NewMatrix(RealType, 4, 4, []float64{
+    2,  1, 0,  0,
+    1, -3, 1,  0,
+    0,  1, 1,  2,
+    0,  0, 2, -1 })
+  b := NewVector(RealType, []float64{1, -1, 2, 0.5})
+  r := solve(a, b)
+
+  if x, err := MINRES(AsLinearOperator(a), b, Epsilon{1e-12}); err != nil {
+    t.Error(err)
+  } else if vnorm(VsubV(x, r)) > 1e-10 {
+    t.Error("test failed")
+  }
+}
+
+func TestGMRES(t *testing.T) {
+  a := NewMatrix(RealType, 5, 5, []float64{
+    4, 1, 0, 2, 0,
+    0, 3, 1, 0, 1,
+    1, 0, 5, 1, 0,
+    0, 2, 0, 4, 1,
+    1, 0, 1, 0, 3 })
+  b := NewVector(RealType, []float64{1, 2, 3, 4, 5})
+  r := solve(a, b)
+
+  if x, err := GMRES(AsLinearOperator(a), b, Epsilon{1e-12}); err != nil {
+    t.Error(err)
+  } else if vnorm(VsubV(x, r)) > 1e-10 {
+    t.Error("test failed")
+  }
+  // restarted GMRES
+  if x, err := GMRES(AsLinearOperator(a), b, Epsilon{1e-12}, Restart{2}); err != nil {
+    t.Error(err)
+  } else if vnorm(VsubV(x, r)) > 1e-10 {
+    t.Error("test failed")
+  }
+}
+
+func TestHook(t *testing.T) {
+  a := NewMatrix(RealType, 5, 5, []float64{
+    4, 1, 0, 2, 0,
+    0, 3, 1, 0, 1,
+    1, 0, 5, 1, 0,
+    0, 2, 0, 4, 1,
+    1, 0, 1, 0, 3 })
+  b := NewVector(RealType, []float64{1, 2, 3, 4, 5})
+
+  for _, restart := range []int{2, 30} {
+    n := 0
+    r := math.Inf(1)
+    hook := Hook{func(x ConstVector, rnorm ConstScalar, i int) bool {
+      if i != n {
+        t.Error("test failed")
+      }
+      // residual norm is not increasing
+      if rnorm.GetValue() > r + 1e-12 {
+        t.Error("test failed")
+      }
+      // check residual norm
+      y := NullVector(RealType, 5)
+      y.MdotV(a, x)
+      if math.Abs(rnorm.GetValue() - vnorm(VsubV(b, y))) > 1e-10 {
+        t.Error("test failed")
+      }
+      n++
+      r = rnorm.GetValue()
+      return false
+    }}
+    if _, err := GMRES(AsLinearOperator(a), b, hook, Restart{restart}, MaxIterations{4}); err != nil {
+      t.Error(err)
+    }
+    if n != 4 {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestDerivatives(t *testing.T) {
+  a := NewMatrix(RealType, 3, 3, []float64{
+    4, 1, 0,
+    1, 3, 1,
+    0, 1, 2 })
+  b := NewVector(RealType, []float64{1, 2, 3})
+  b.Variables(1)
+
+  ainv, _ := matrixInverse.Run(a)
+
+  for _, solver := range []func(LinearOperator, ConstVector, ...interface{}) (Vector, error){CG, MINRES, GMRES} {
+    x, err := solver(AsLinearOperator(a), b, Epsilon{1e-14})
+    if err != nil {
+      t.Error(err); continue
+    }
+    // dx/db = A^-1
+    for i := 0; i < 3; i++ {
+      for j := 0; j < 3; j++ {
+        if math.Abs(x.At(i).GetDerivative(j) - ainv.At(i, j).GetValue()) > 1e-8 {
+          t.Error("test failed")
+        }
+      }
+    }
+  }
+}
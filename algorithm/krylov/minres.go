@@ -0,0 +1,145 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Reference:
+// Paige, Christopher C., and Michael A. Saunders. Solution of sparse
+// indefinite systems of linear equations. SIAM Journal on Numerical
+// Analysis 12.4 (1975): 617-629.
+
+/* -------------------------------------------------------------------------- */
+
+package krylov
+
+/* -------------------------------------------------------------------------- */
+
+import   "fmt"
+
+import . "github.com/pbenner/autodiff"
+
+/* -------------------------------------------------------------------------- */
+
+func minres(a LinearOperator, b ConstVector, opts options) (Vector, error) {
+  x, r, tol, err := initialize(a, b, opts)
+  if err != nil {
+    return nil, err
+  }
+  n := b.Dim()
+  t := b.ElementType()
+  // Lanczos vectors
+  v  := NullVector(t, n)
+  y  := NullVector(t, n)
+  r1 := r.CloneVector()
+  r2 := r
+  // search directions
+  w  := NullVector(t, n)
+  w1 := NullVector(t, n)
+  w2 := NullVector(t, n)
+  tv := NullVector(t, n)
+  // scalars of the Lanczos process and the QR factorization
+  alpha  := NullScalar(t)
+  beta   := NullScalar(t)
+  betaO  := NullScalar(t)
+  delta  := NullScalar(t)
+  gamma  := NullScalar(t)
+  gbar   := NullScalar(t)
+  dbar   := NullScalar(t)
+  eps    := NullScalar(t)
+  epsO   := NullScalar(t)
+  phi    := NullScalar(t)
+  phibar := NullScalar(t)
+  cs     := NewScalar(t, -1.0)
+  sn     := NullScalar(t)
+  t1     := NullScalar(t)
+  t2     := NullScalar(t)
+
+  y.Set(r)
+  beta  .Vnorm(r)
+  phibar.Set(beta)
+
+  for i := 0; i < opts.maxIterations.Value; i++ {
+    // execute hook if available
+    if opts.hook.Value != nil && opts.hook.Value(x, phibar, i) {
+      break
+    }
+    // evaluate stop criterion
+    if phibar.GetValue() <= tol {
+      break
+    }
+    // Lanczos step
+    v.VdivS(y, beta)
+    a.MdotV(y, v)
+    if i > 0 {
+      t1.Div(beta, betaO)
+      tv.VmulS(r1, t1)
+      y .VsubV(y, tv)
+    }
+    alpha.VdotV(v, y)
+    t1.Div(alpha, beta)
+    tv.VmulS(r2, t1)
+    y .VsubV(y, tv)
+    r1.Set(r2)
+    r2.Set(y)
+    betaO.Set(beta)
+    beta .Vnorm(y)
+    // apply previous rotation
+    epsO .Set(eps)
+    delta.Mul(cs, dbar)
+    t1   .Mul(sn, alpha)
+    delta.Add(delta, t1)
+    gbar .Mul(sn, dbar)
+    t1   .Mul(cs, alpha)
+    gbar .Sub(gbar, t1)
+    eps  .Mul(sn, beta)
+    dbar .Mul(cs, beta)
+    dbar .Neg(dbar)
+    // compute next rotation
+    t1   .Mul(gbar, gbar)
+    t2   .Mul(beta, beta)
+    gamma.Add(t1, t2)
+    gamma.Sqrt(gamma)
+    if gamma.GetValue() == 0.0 {
+      return x, fmt.Errorf("linear operator is singular")
+    }
+    cs.Div(gbar, gamma)
+    sn.Div(beta, gamma)
+    phi   .Mul(cs, phibar)
+    phibar.Mul(sn, phibar)
+    // w = (v - epsO w1 - delta w2)/gamma
+    w1.Set(w2)
+    w2.Set(w)
+    tv.VmulS(w1, epsO)
+    w .VsubV(v, tv)
+    tv.VmulS(w2, delta)
+    w .VsubV(w, tv)
+    w .VdivS(w, gamma)
+    // x = x + phi w
+    tv.VmulS(w, phi)
+    x .VaddV(x, tv)
+    if beta.GetValue() == 0.0 {
+      // Krylov space is exhausted, x is the exact solution
+      break
+    }
+  }
+  return x, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Solve the linear system A x = b with the minimum residual method, where
+// A must be symmetric but may be indefinite.
+func MINRES(a LinearOperator, b ConstVector, args ...interface{}) (Vector, error) {
+  return minres(a, b, parseOptions("MINRES", args))
+}
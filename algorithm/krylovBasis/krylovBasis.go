@@ -0,0 +1,394 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package krylovBasis
+
+/* -------------------------------------------------------------------------- */
+
+import   "fmt"
+import   "math"
+import   "math/rand"
+
+import . "github.com/pbenner/autodiff"
+
+/* -------------------------------------------------------------------------- */
+
+// A Ritz pair (theta, y) is accepted as soon as the norm of the residual
+// A y - theta y is smaller than Epsilon times |theta|.
+type Epsilon struct {
+  Value float64
+}
+
+// Maximum number of restarts.
+type MaxIterations struct {
+  Value int
+}
+
+// Dimension of the Krylov subspace, by default max(2k+1, 20) but at most
+// the dimension of the operator.
+type SubspaceDimension struct {
+  Value int
+}
+
+// Compute the smallest instead of the largest eigenvalues.
+type Smallest struct {
+  Value bool
+}
+
+// Starting vector of the iteration, by default a random vector.
+type InitialVector struct {
+  Value ConstVector
+}
+
+// Seed of the random number generator used for the starting vector.
+type Seed struct {
+  Value int64
+}
+
+/* -------------------------------------------------------------------------- */
+
+type Options struct {
+  Epsilon           float64
+  MaxIterations     int
+  SubspaceDimension int
+  Smallest          bool
+  InitialVector     ConstVector
+  Seed              int64
+}
+
+// Parse optional arguments of the eigensolver with given name.
+func ParseOptions(name string, args []interface{}) Options {
+  opts := Options{
+    Epsilon      : 1e-10,
+    MaxIterations: 1000,
+    Seed         : 1 }
+  // loop over optional arguments
+  for _, arg := range args {
+    switch a := arg.(type) {
+    case Epsilon:
+      opts.Epsilon = a.Value
+    case MaxIterations:
+      opts.MaxIterations = a.Value
+    case SubspaceDimension:
+      opts.SubspaceDimension = a.Value
+    case Smallest:
+      opts.Smallest = a.Value
+    case InitialVector:
+      opts.InitialVector = a.Value
+    case Seed:
+      opts.Seed = a.Value
+    default:
+      panic(fmt.Sprintf("%s(): Invalid optional argument!", name))
+    }
+  }
+  return opts
+}
+
+// Dimension of the Krylov subspace for computing k eigenvalues of an
+// operator of dimension n.
+func (opts Options) Dimension(n, k int) int {
+  m := opts.SubspaceDimension
+  if m == 0 {
+    m = 2*k+1
+    if m < 20 {
+      m = 20
+    }
+  }
+  if m > n {
+    m = n
+  }
+  return m
+}
+
+/* -------------------------------------------------------------------------- */
+
+func dot(a, b DenseBareRealVector) float64 {
+  r := 0.0
+  for i := 0; i < len(a); i++ {
+    r += float64(a[i])*float64(b[i])
+  }
+  return r
+}
+
+func axpy(r DenseBareRealVector, alpha float64, x DenseBareRealVector) {
+  for i := 0; i < len(r); i++ {
+    r[i] += BareReal(alpha*float64(x[i]))
+  }
+}
+
+func scale(r DenseBareRealVector, alpha float64) {
+  for i := 0; i < len(r); i++ {
+    r[i] = BareReal(alpha*float64(r[i]))
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Orthonormal basis V = [v_0, ..., v_m] of a Krylov subspace.
+type Basis struct {
+  V []DenseBareRealVector
+  g *rand.Rand
+}
+
+// Allocate a basis with m+1 vectors of dimension n. The first basis vector
+// is set to the normalized initial vector or to a random unit vector.
+func New(n, m int, opts Options) (*Basis, error) {
+  b := Basis{}
+  b.V = make([]DenseBareRealVector, m+1)
+  b.g = rand.New(rand.NewSource(opts.Seed))
+  for i := 0; i <= m; i++ {
+    b.V[i] = NullDenseBareRealVector(n)
+  }
+  if x := opts.InitialVector; x != nil {
+    if x.Dim() != n {
+      return nil, fmt.Errorf("initial vector has invalid dimension")
+    }
+    for i := 0; i < n; i++ {
+      b.V[0][i] = BareReal(x.ValueAt(i))
+    }
+    if s := math.Sqrt(dot(b.V[0], b.V[0])); s == 0.0 {
+      return nil, fmt.Errorf("initial vector is zero")
+    } else {
+      scale(b.V[0], 1.0/s)
+    }
+  } else {
+    b.RandomVector(0)
+  }
+  return &b, nil
+}
+
+// Orthogonalize w against the first j basis vectors. The projection is
+// repeated once to compensate for the loss of orthogonality in finite
+// precision. Coefficients are added to h.
+func (b *Basis) Orthogonalize(w DenseBareRealVector, h []float64, j int) {
+  for pass := 0; pass < 2; pass++ {
+    for i := 0; i < j; i++ {
+      c := dot(b.V[i], w)
+      axpy(w, -c, b.V[i])
+      if h != nil {
+        h[i] += c
+      }
+    }
+  }
+}
+
+// Set v_j to a random unit vector orthogonal to the first j basis vectors.
+// If the basis already spans the whole space, v_j is set to zero and false
+// is returned.
+func (b *Basis) RandomVector(j int) bool {
+  w := b.V[j]
+  for i := 0; i < len(w); i++ {
+    w[i] = BareReal(b.g.NormFloat64())
+  }
+  r := math.Sqrt(dot(w, w))
+  b.Orthogonalize(w, nil, j)
+  if s := math.Sqrt(dot(w, w)); s <= 1e-8*r {
+    for i := 0; i < len(w); i++ {
+      w[i] = 0.0
+    }
+    return false
+  } else {
+    scale(w, 1.0/s)
+  }
+  return true
+}
+
+// Orthogonalize v_j against the first j basis vectors and normalize it.
+// Returns the norm of v_j after orthogonalization. If v_j vanishes, it is
+// replaced by a random vector and zero is returned.
+func (b *Basis) Normalize(j int) float64 {
+  b.Orthogonalize(b.V[j], nil, j)
+  if s := math.Sqrt(dot(b.V[j], b.V[j])); s == 0.0 {
+    b.RandomVector(j)
+    return 0.0
+  } else {
+    scale(b.V[j], 1.0/s)
+    return s
+  }
+}
+
+// Extend the Arnoldi factorization A V_j = V_j H_j + f e_j^T from step j0
+// to m, where H is stored in the upper Hessenberg matrix h. The new residual
+// is normalized and stored in v_m. Returns the norm of the residual.
+func (b *Basis) Expand(a LinearOperator, h Matrix, j0, m int) float64 {
+  c := make([]float64, m)
+  r := 0.0
+  for j := j0; j < m; j++ {
+    for i := 0; i <= j; i++ {
+      c[i] = 0.0
+    }
+    a.MdotV(b.V[j+1], b.V[j])
+    b.Orthogonalize(b.V[j+1], c, j+1)
+    for i := 0; i < m; i++ {
+      if i <= j {
+        h.At(i, j).SetValue(c[i])
+      } else {
+        h.At(i, j).SetValue(0.0)
+      }
+    }
+    r = math.Sqrt(dot(b.V[j+1], b.V[j+1]))
+    if r <= 1e-14*math.Abs(c[j]) || r == 0.0 {
+      // invariant subspace found, continue with a random vector
+      r = 0.0
+      b.RandomVector(j+1)
+    } else {
+      scale(b.V[j+1], 1.0/r)
+    }
+    if j+1 < m {
+      h.At(j+1, j).SetValue(r)
+    }
+  }
+  return r
+}
+
+// Extend the Lanczos factorization A V_j = V_j T_j + f e_j^T of a symmetric
+// operator from step j0 to m, where the upper triangular part of T is stored
+// in h. The first step is orthogonalized against the whole basis, which
+// recovers the coupling to the Ritz vectors kept by a thick restart. All
+// further steps use the three-term recurrence. The loss of orthogonality is
+// estimated with the recurrence of Simon and the basis is re-orthogonalized
+// only when the estimate exceeds the square root of the machine precision
+// (partial re-orthogonalization). The new residual is normalized and stored
+// in v_m. Returns the norm of the residual.
+//
+// Reference:
+// Simon, Horst D. "The Lanczos algorithm with partial reorthogonalization."
+// Mathematics of Computation 42.165 (1984): 115-142.
+func (b *Basis) ExpandSymmetric(a LinearOperator, h Matrix, j0, m int) float64 {
+  eps := math.Nextafter(1.0, 2.0) - 1.0
+  n   := len(b.V[0])
+  c   := make([]float64, m)
+  // estimates of v_k^T v_{j-1}, v_k^T v_j and v_k^T v_{j+1}
+  w0 := make([]float64, m+1)
+  w1 := make([]float64, m+1)
+  w2 := make([]float64, m+1)
+  for k := 0; k < j0; k++ {
+    w1[k] = eps
+  }
+  w1[j0] = 1.0
+  // symmetric access to T
+  t := func(i, k int) float64 {
+    if i > k {
+      i, k = k, i
+    }
+    return h.ValueAt(i, k)
+  }
+  tnorm := 0.0
+  force := false
+  r     := 0.0
+  for j := j0; j < m; j++ {
+    w := b.V[j+1]
+    for i := 0; i < m; i++ {
+      c[i] = 0.0
+    }
+    a.MdotV(w, b.V[j])
+    if j == j0 {
+      b.Orthogonalize(w, c, j+1)
+    } else {
+      c[j-1] = h.ValueAt(j, j-1)
+      axpy(w, -c[j-1], b.V[j-1])
+      c[j]   = dot(b.V[j], w)
+      axpy(w, -c[j], b.V[j])
+    }
+    s := 0.0
+    for i := 0; i < m; i++ {
+      if i <= j {
+        h.At(i, j).SetValue(c[i])
+      } else {
+        h.At(i, j).SetValue(0.0)
+      }
+      s += math.Abs(c[i])
+    }
+    r = math.Sqrt(dot(w, w))
+    tnorm = math.Max(tnorm, s + r)
+    // estimate v_k^T v_{j+1} from the recurrence of T
+    triggered := false
+    if j > j0 && r > 0.0 {
+      for k := 0; k < j; k++ {
+        x := -c[j]*w1[k] - c[j-1]*w0[k]
+        for i := 0; i <= j; i++ {
+          x += t(i, k)*w1[i]
+        }
+        x /= r
+        x += math.Copysign(eps*math.Sqrt(float64(n))*tnorm/r, x)
+        w2[k] = x
+        if math.Abs(x) > math.Sqrt(eps) {
+          triggered = true
+        }
+      }
+    }
+    if r <= 1e-14*math.Abs(c[j]) || r == 0.0 {
+      // invariant subspace found, continue with a random vector
+      r = 0.0
+      b.RandomVector(j+1)
+      triggered = false
+    } else {
+      // the vector following a re-orthogonalization is also
+      // re-orthogonalized
+      if triggered || force {
+        b.Orthogonalize(w, nil, j+1)
+        r = math.Sqrt(dot(w, w))
+      }
+      scale(w, 1.0/r)
+    }
+    if j == j0 || r == 0.0 || triggered || force {
+      for k := 0; k < j; k++ {
+        w2[k] = eps
+      }
+    }
+    force = triggered
+    w2[j]   = eps
+    w2[j+1] = 1.0
+    if j+1 < m {
+      h.At(j+1, j).SetValue(r)
+    }
+    w0, w1, w2 = w1, w2, w0
+  }
+  return r
+}
+
+// Compute r = sum_j c_j v_j.
+func (b *Basis) Combine(r DenseBareRealVector, c []float64) {
+  for i := 0; i < len(r); i++ {
+    r[i] = 0.0
+  }
+  for j := 0; j < len(c); j++ {
+    axpy(r, c[j], b.V[j])
+  }
+}
+
+// Replace the first l basis vectors by v_i = sum_j v_j y_{j,p_i} with j < m.
+// The identity permutation is used if p is nil.
+func (b *Basis) Rotate(y ConstMatrix, p []int, l, m int) {
+  n := len(b.V[0])
+  w := make([]DenseBareRealVector, l)
+  c := make([]float64, m)
+  for i := 0; i < l; i++ {
+    k := i
+    if p != nil {
+      k = p[i]
+    }
+    for j := 0; j < m; j++ {
+      c[j] = y.ValueAt(j, k)
+    }
+    w[i] = NullDenseBareRealVector(n)
+    b.Combine(w[i], c)
+  }
+  for i := 0; i < l; i++ {
+    copy(b.V[i], w[i])
+  }
+}
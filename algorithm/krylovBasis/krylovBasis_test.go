@@ -0,0 +1,134 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package krylovBasis
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+
+/* -------------------------------------------------------------------------- */
+
+func TestBasis1(t *testing.T) {
+  n := 10
+  m := 6
+  a := NullDenseBareRealMatrix(n, n)
+  for i := 0; i < n; i++ {
+    for j := 0; j < n; j++ {
+      a.AT(i, j).SetValue(math.Sin(float64(i*n + j + 1)))
+    }
+  }
+  opts := ParseOptions("Test", nil)
+  b, err := New(n, m, opts)
+  if err != nil {
+    t.Fatal(err)
+  }
+  h := NullDenseBareRealMatrix(m, m)
+  r := b.Expand(AsLinearOperator(a), h, 0, m)
+  // check orthonormality of the basis
+  for i := 0; i <= m; i++ {
+    for j := 0; j <= m; j++ {
+      s := dot(b.V[i], b.V[j])
+      if i == j && math.Abs(s - 1.0) > 1e-10 || i != j && math.Abs(s) > 1e-10 {
+        t.Error("test failed")
+      }
+    }
+  }
+  // check A V = V H + r v_m e_m^T
+  w := NullDenseBareRealVector(n)
+  c := make([]float64, m+1)
+  for j := 0; j < m; j++ {
+    for i := 0; i < m; i++ {
+      c[i] = h.ValueAt(i, j)
+    }
+    c[m] = 0.0
+    if j == m-1 {
+      c[m] = r
+    }
+    b.Combine(w, c)
+    x := NullDenseBareRealVector(n)
+    x.MdotV(a, b.V[j])
+    for i := 0; i < n; i++ {
+      if math.Abs(x.ValueAt(i) - w.ValueAt(i)) > 1e-10 {
+        t.Error("test failed")
+      }
+    }
+  }
+}
+
+func TestBasis2(t *testing.T) {
+  // diagonal operator with well separated largest eigenvalues, such that
+  // Ritz values converge quickly and the three-term recurrence alone would
+  // lose orthogonality
+  n := 400
+  m := 80
+  d := make([]float64, n)
+  for i := 0; i < n; i++ {
+    d[i] = float64(i)/float64(n)
+  }
+  d[0], d[1], d[2] = 100.0, 50.0, 20.0
+  a := NewLinearOperator(n, n, func(r Vector, b ConstVector) {
+    for i := 0; i < n; i++ {
+      r.At(i).SetValue(d[i]*b.ValueAt(i))
+    }
+  })
+  opts := ParseOptions("Test", nil)
+  b, err := New(n, m, opts)
+  if err != nil {
+    t.Fatal(err)
+  }
+  h := NullDenseBareRealMatrix(m, m)
+  r := b.ExpandSymmetric(a, h, 0, m)
+  // check semi-orthogonality of the basis
+  for i := 0; i <= m; i++ {
+    for j := 0; j <= m; j++ {
+      s := dot(b.V[i], b.V[j])
+      if i == j && math.Abs(s - 1.0) > 1e-10 || i != j && math.Abs(s) > 1e-7 {
+        t.Error("test failed"); return
+      }
+    }
+  }
+  // check A V = V T + r v_m e_m^T, where only the upper triangular part
+  // of T is stored in h
+  w := NullDenseBareRealVector(n)
+  x := NullDenseBareRealVector(n)
+  c := make([]float64, m+1)
+  for j := 0; j < m; j++ {
+    for i := 0; i < m; i++ {
+      if i <= j {
+        c[i] = h.ValueAt(i, j)
+      } else {
+        c[i] = h.ValueAt(j, i)
+      }
+    }
+    c[m] = 0.0
+    if j == m-1 {
+      c[m] = r
+    }
+    b.Combine(w, c)
+    a.MdotV(x, b.V[j])
+    for i := 0; i < n; i++ {
+      if math.Abs(x.ValueAt(i) - w.ValueAt(i)) > 1e-6 {
+        t.Error("test failed"); return
+      }
+    }
+  }
+}
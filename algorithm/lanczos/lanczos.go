@@ -0,0 +1,169 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package lanczos
+
+/* -------------------------------------------------------------------------- */
+
+import   "fmt"
+import   "math"
+import   "sort"
+
+import . "github.com/pbenner/autodiff"
+import   "github.com/pbenner/autodiff/algorithm/eigensystem"
+import   "github.com/pbenner/autodiff/algorithm/krylovBasis"
+
+/* -------------------------------------------------------------------------- */
+
+// Reference:
+// Wu, Kesheng, and Horst Simon. "Thick-restart Lanczos method for large
+// symmetric eigenvalue problems." SIAM Journal on Matrix Analysis and
+// Applications 22.2 (2000): 602-616.
+//
+// Simon, Horst D. "The Lanczos algorithm with partial reorthogonalization."
+// Mathematics of Computation 42.165 (1984): 115-142.
+
+/* -------------------------------------------------------------------------- */
+
+// A Ritz pair (theta, y) is accepted as soon as the norm of the residual
+// A y - theta y is smaller than Epsilon times |theta|.
+type Epsilon = krylovBasis.Epsilon
+
+// Maximum number of restarts.
+type MaxIterations = krylovBasis.MaxIterations
+
+// Dimension of the Krylov subspace, by default max(2k+1, 20) but at most
+// the dimension of the operator.
+type SubspaceDimension = krylovBasis.SubspaceDimension
+
+// Compute the smallest instead of the largest eigenvalues.
+type Smallest = krylovBasis.Smallest
+
+// Starting vector of the iteration, by default a random vector.
+type InitialVector = krylovBasis.InitialVector
+
+// Seed of the random number generator used for the starting vector.
+type Seed = krylovBasis.Seed
+
+/* -------------------------------------------------------------------------- */
+
+// Eigen-decomposition of the projected matrix. The Lanczos factorization
+// only computes the upper triangular part of h. Ritz values are sorted such
+// that wanted values come first.
+func ritz(h Matrix, smallest bool) ([]float64, Matrix, []int, error) {
+  m, _ := h.Dims()
+  t := NullDenseBareRealMatrix(m, m)
+  for i := 0; i < m; i++ {
+    for j := i; j < m; j++ {
+      t.At(i, j).Set(h.At(i, j))
+      t.At(j, i).Set(h.At(i, j))
+    }
+  }
+  lambda, y, err := eigensystem.Run(t, eigensystem.Symmetric{true})
+  if err != nil {
+    return nil, nil, nil, err
+  }
+  theta := make([]float64, m)
+  p     := make([]int, m)
+  for i := 0; i < m; i++ {
+    theta[i] = lambda.ValueAt(i)
+    p    [i] = i
+  }
+  sort.SliceStable(p, func(i, j int) bool {
+    if smallest {
+      return theta[p[i]] < theta[p[j]]
+    } else {
+      return theta[p[i]] > theta[p[j]]
+    }
+  })
+  return theta, y, p, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+func lanczos(a LinearOperator, k int, opts krylovBasis.Options) (Vector, Matrix, error) {
+  n, _ := a.Dims()
+  m    := opts.Dimension(n, k)
+  if m <= k && m < n {
+    return nil, nil, fmt.Errorf("subspace dimension must be larger than k")
+  }
+  basis, err := krylovBasis.New(n, m, opts)
+  if err != nil {
+    return nil, nil, err
+  }
+  v  := basis.V
+  h  := NullDenseBareRealMatrix(m, m)
+  j0 := 0
+  for iter := 0; ; iter++ {
+    b := basis.ExpandSymmetric(a, h, j0, m)
+    theta, y, p, err := ritz(h, opts.Smallest)
+    if err != nil {
+      return nil, nil, err
+    }
+    // check convergence of the wanted Ritz pairs
+    converged := 0
+    for i := 0; i < k; i++ {
+      if math.Abs(b*y.ValueAt(m-1, p[i])) <= opts.Epsilon*math.Max(math.Abs(theta[p[i]]), 1e-300) {
+        converged++
+      }
+    }
+    if converged == k {
+      basis.Rotate(y, p, k, m)
+      eigenvalues  := NullDenseBareRealVector(k)
+      eigenvectors := NullDenseBareRealMatrix(n, k)
+      for i := 0; i < k; i++ {
+        eigenvalues.AT(i).SetValue(theta[p[i]])
+        for j := 0; j < n; j++ {
+          eigenvectors.AT(j, i).SetValue(float64(v[i][j]))
+        }
+      }
+      return eigenvalues, eigenvectors, nil
+    }
+    if iter+1 >= opts.MaxIterations {
+      return nil, nil, fmt.Errorf("maximum number of iterations reached")
+    }
+    // thick restart: keep the l best Ritz vectors together with the last
+    // residual vector
+    l := k + (m-k)/2
+    if l >= m {
+      l = m-1
+    }
+    basis.Rotate(y, p, l, m)
+    copy(v[l], v[m])
+    basis.Normalize(l)
+    h.Reset()
+    for i := 0; i < l; i++ {
+      h.AT(i, i).SetValue(theta[p[i]])
+    }
+    j0 = l
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the k largest (or smallest) eigenvalues and corresponding
+// eigenvectors of the symmetric linear operator a. Eigenvalues are sorted
+// in decreasing (increasing) order and eigenvectors are stored as columns.
+func Run(a LinearOperator, k int, args ...interface{}) (Vector, Matrix, error) {
+  opts := krylovBasis.ParseOptions("Lanczos", args)
+  if n, m := a.Dims(); n != m {
+    return nil, nil, fmt.Errorf("linear operator must be square")
+  } else if k <= 0 || k > n {
+    return nil, nil, fmt.Errorf("invalid number of eigenvalues")
+  }
+  return lanczos(a, k, opts)
+}
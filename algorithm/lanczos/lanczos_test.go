@@ -0,0 +1,159 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package lanczos
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math"
+import   "math/rand"
+import   "sort"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+import   "github.com/pbenner/autodiff/algorithm/eigensystem"
+
+/* -------------------------------------------------------------------------- */
+
+// Check that A x_i = lambda_i x_i for all returned eigenpairs.
+func checkEigenpairs(a LinearOperator, lambda Vector, x Matrix, epsilon float64) bool {
+  n, k := x.Dims()
+  r := NullDenseBareRealVector(n)
+  for i := 0; i < k; i++ {
+    a.MdotV(r, x.ConstCol(i))
+    for j := 0; j < n; j++ {
+      if math.Abs(r.ValueAt(j) - lambda.ValueAt(i)*x.ValueAt(j, i)) > epsilon {
+        return false
+      }
+    }
+  }
+  return true
+}
+
+/* -------------------------------------------------------------------------- */
+
+func TestLanczos1(t *testing.T) {
+  // one-dimensional Laplacian as matrix-free operator
+  n := 30
+  a := NewLinearOperator(n, n, func(r Vector, b ConstVector) {
+    for i := 0; i < n; i++ {
+      s := 2.0*b.ValueAt(i)
+      if i > 0 {
+        s -= b.ValueAt(i-1)
+      }
+      if i < n-1 {
+        s -= b.ValueAt(i+1)
+      }
+      r.At(i).SetValue(s)
+    }
+  })
+  lambda := func(j int) float64 {
+    return 2.0 - 2.0*math.Cos(float64(j)*math.Pi/float64(n+1))
+  }
+  if l, x, err := Run(a, 4, SubspaceDimension{12}); err != nil {
+    t.Error(err)
+  } else {
+    for i := 0; i < 4; i++ {
+      if math.Abs(l.ValueAt(i) - lambda(n-i)) > 1e-8 {
+        t.Error("test failed")
+      }
+    }
+    if !checkEigenpairs(a, l, x, 1e-8) {
+      t.Error("test failed")
+    }
+  }
+  if l, x, err := Run(a, 3, Smallest{true}, SubspaceDimension{12}); err != nil {
+    t.Error(err)
+  } else {
+    for i := 0; i < 3; i++ {
+      if math.Abs(l.ValueAt(i) - lambda(i+1)) > 1e-8 {
+        t.Error("test failed")
+      }
+    }
+    if !checkEigenpairs(a, l, x, 1e-8) {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestLanczos2(t *testing.T) {
+  // random symmetric matrix
+  n := 30
+  g := rand.New(rand.NewSource(42))
+  a := NullMatrix(BareRealType, n, n)
+  for i := 0; i < n; i++ {
+    for j := i; j < n; j++ {
+      v := g.NormFloat64()
+      a.At(i, j).SetValue(v)
+      a.At(j, i).SetValue(v)
+    }
+  }
+  r, _, err := eigensystem.Run(a, eigensystem.Symmetric{true}, eigensystem.ComputeEigenvectors{false})
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  lambda := r.GetValues()
+  sort.Float64s(lambda)
+
+  if l, x, err := Run(AsLinearOperator(a), 5, SubspaceDimension{12}); err != nil {
+    t.Error(err)
+  } else {
+    for i := 0; i < 5; i++ {
+      if math.Abs(l.ValueAt(i) - lambda[n-1-i]) > 1e-8 {
+        t.Error("test failed")
+      }
+    }
+    if !checkEigenpairs(AsLinearOperator(a), l, x, 1e-8) {
+      t.Error("test failed")
+    }
+  }
+  if l, x, err := Run(AsLinearOperator(a), 5, Smallest{true}, SubspaceDimension{12}); err != nil {
+    t.Error(err)
+  } else {
+    for i := 0; i < 5; i++ {
+      if math.Abs(l.ValueAt(i) - lambda[i]) > 1e-8 {
+        t.Error("test failed")
+      }
+    }
+    if !checkEigenpairs(AsLinearOperator(a), l, x, 1e-8) {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestLanczos3(t *testing.T) {
+  // subspace spans the whole space
+  a := NewMatrix(BareRealType, 3, 3, []float64{
+    2, 1, 0,
+    1, 2, 1,
+    0, 1, 2 })
+  l, x, err := Run(AsLinearOperator(a), 3)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  if math.Abs(l.ValueAt(0) - 2.0 - math.Sqrt(2.0)) > 1e-10 ||
+    (math.Abs(l.ValueAt(1) - 2.0)                 > 1e-10) ||
+    (math.Abs(l.ValueAt(2) - 2.0 + math.Sqrt(2.0)) > 1e-10) {
+    t.Error("test failed")
+  }
+  if !checkEigenpairs(AsLinearOperator(a), l, x, 1e-10) {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,305 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package leastSquares
+
+/* -------------------------------------------------------------------------- */
+
+import   "fmt"
+import   "math"
+
+import . "github.com/pbenner/autodiff"
+import   "github.com/pbenner/autodiff/algorithm/backSubstitution"
+import   "github.com/pbenner/autodiff/algorithm/householder"
+import   "github.com/pbenner/autodiff/algorithm/svd"
+
+/* -------------------------------------------------------------------------- */
+
+// Weights of the observations, i.e. the weighted sum of squared
+// residuals sum_i w_i (b_i - (A x)_i)^2 is minimized.
+type Weights struct {
+  Value ConstVector
+}
+
+// Tikhonov damping, which adds lambda |x|^2 to the objective function.
+type Tikhonov struct {
+  Value float64
+}
+
+// Use the singular value decomposition instead of Householder QR. This is
+// slower but allows to solve rank deficient systems, in which case the
+// solution with minimal norm is returned.
+type SVD struct {
+  Value bool
+}
+
+// Singular values and diagonal elements of R smaller than Epsilon times
+// the largest one are considered zero.
+type Epsilon struct {
+  Value float64
+}
+
+type ComputeCovariance struct {
+  Value bool
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Construct the augmented system [W^1/2 A, W^1/2 b; lambda^1/2 I, 0], where the last
+// column contains the right-hand side.
+func augmentedSystem(a ConstMatrix, b, w ConstVector, lambda float64) Matrix {
+  m, n := a.Dims()
+  t    := a.ElementType()
+  k    := m
+  if lambda > 0.0 {
+    k += n
+  }
+  r := NullMatrix(t, k, n+1)
+  s := NullScalar(t)
+  for i := 0; i < m; i++ {
+    if w != nil {
+      s.Sqrt(w.ConstAt(i))
+    } else {
+      s.SetValue(1.0)
+    }
+    for j := 0; j < n; j++ {
+      r.At(i, j).Mul(a.ConstAt(i, j), s)
+    }
+    r.At(i, n).Mul(b.ConstAt(i), s)
+  }
+  if lambda > 0.0 {
+    for j := 0; j < n; j++ {
+      r.At(m+j, j).SetValue(math.Sqrt(lambda))
+    }
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Reduce the augmented system [A, b] to [R, Q^T b] using Householder
+// reflections, where R is an upper triangular matrix.
+func triangularize(a Matrix) (Matrix, Vector) {
+  k, n1 := a.Dims()
+  n     := n1-1
+  t     := a.ElementType()
+  x     := NullVector(t, k)
+  nu    := NullVector(t, k)
+  t4    := NullVector(t, n1)
+  beta  := NullScalar(t)
+  t1    := NullScalar(t)
+  t2    := NullScalar(t)
+  t3    := NullScalar(t)
+  for j := 0; j < n; j++ {
+    for i := j; i < k; i++ {
+      x.At(i).Set(a.At(i, j))
+    }
+    householder.Run(x.Slice(j,k), beta, nu.Slice(j,k), t1, t2, t3)
+    householder.ApplyLeft(a.Slice(j,k,j,n1), beta, nu.Slice(j,k), t4.Slice(j,n1), t1)
+  }
+  c := NullVector(t, n)
+  for i := 0; i < n; i++ {
+    c.At(i).Set(a.At(i, n))
+  }
+  return a.Slice(0,n,0,n), c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func solveQR(a Matrix, epsilon float64, computeCovariance bool) (Vector, Matrix, int, error) {
+  _, n1 := a.Dims()
+  n     := n1-1
+  t     := a.ElementType()
+  r, c  := triangularize(a)
+  // check rank
+  rmax := 0.0
+  for i := 0; i < n; i++ {
+    rmax = math.Max(rmax, math.Abs(r.At(i, i).GetValue()))
+  }
+  for i := 0; i < n; i++ {
+    if math.Abs(r.At(i, i).GetValue()) <= epsilon*rmax {
+      return nil, nil, 0, fmt.Errorf("matrix is rank deficient")
+    }
+  }
+  z, err := backSubstitution.Run(r, c)
+  if err != nil {
+    return nil, nil, 0, err
+  }
+  if !computeCovariance {
+    return z, nil, n, nil
+  }
+  // (A^T A)^-1 = R^-1 R^-T
+  rinv := NullMatrix(t, n, n)
+  e    := NullVector(t, n)
+  for j := 0; j < n; j++ {
+    e.At(j).SetValue(1.0)
+    y, err := backSubstitution.Run(r, e)
+    if err != nil {
+      return nil, nil, 0, err
+    }
+    for i := 0; i < n; i++ {
+      rinv.At(i, j).Set(y.At(i))
+    }
+    e.At(j).SetValue(0.0)
+  }
+  s := NullMatrix(t, n, n)
+  s.MdotM(rinv, rinv.T())
+  return z, s, n, nil
+}
+
+func solveSVD(a Matrix, epsilon float64, computeCovariance bool) (Vector, Matrix, int, error) {
+  _, n1 := a.Dims()
+  n     := n1-1
+  t     := a.ElementType()
+  // reduce the system to the square matrix R, for which R = U H V^T
+  r, b  := triangularize(a)
+  // the pseudo-inverse is differentiable also for repeated singular values,
+  // for which analytic derivatives of U and V are not defined
+  h, u, v, err := svd.Run(r, svd.ComputeU{true}, svd.ComputeV{true}, svd.AnalyticDerivatives{false})
+  if err != nil {
+    return nil, nil, 0, err
+  }
+  hmax := 0.0
+  for i := 0; i < n; i++ {
+    hmax = math.Max(hmax, math.Abs(h.At(i, i).GetValue()))
+  }
+  z    := NullVector(t, n)
+  s    := NullMatrix(t, n, n)
+  c    := NullScalar(t)
+  t1   := NullScalar(t)
+  rank := 0
+  for i := 0; i < n; i++ {
+    hi := h.At(i, i)
+    if math.Abs(hi.GetValue()) <= epsilon*hmax {
+      continue
+    }
+    rank++
+    // c = u_i^T b / h_i
+    c.Reset()
+    for j := 0; j < n; j++ {
+      t1.Mul(u.At(j, i), b.At(j))
+      c.Add(c, t1)
+    }
+    c.Div(c, hi)
+    // z = z + c v_i
+    for j := 0; j < n; j++ {
+      t1.Mul(c, v.At(j, i))
+      z.At(j).Add(z.At(j), t1)
+    }
+    if computeCovariance {
+      // s = s + v_i v_i^T / h_i^2
+      for j1 := 0; j1 < n; j1++ {
+        for j2 := 0; j2 < n; j2++ {
+          t1.Mul(v.At(j1, i), v.At(j2, i))
+          t1.Div(t1, hi)
+          t1.Div(t1, hi)
+          s.At(j1, j2).Add(s.At(j1, j2), t1)
+        }
+      }
+    }
+  }
+  if !computeCovariance {
+    s = nil
+  }
+  return z, s, rank, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Solve the linear least squares problem min_x |W^1/2 (A x - b)|^2 +
+// lambda |x|^2. Returns the estimate x, the residuals b - A x and, if
+// requested, the covariance of the estimate s^2 (A^T W A + lambda I)^-1,
+// where s^2 is the weighted sum of squared residuals divided by the number
+// of degrees of freedom. If elements are of type Real, derivatives with
+// respect to A, b and the weights are propagated to all results.
+func Run(a ConstMatrix, b ConstVector, args ...interface{}) (Vector, Vector, Matrix, error) {
+  m, n := a.Dims()
+  if b.Dim() != m {
+    return nil, nil, nil, fmt.Errorf("matrix vector dimensions do not match")
+  }
+  var weights ConstVector
+  lambda            := 0.0
+  useSVD            := false
+  epsilon           := math.NaN()
+  computeCovariance := false
+
+  // loop over optional arguments
+  for _, arg := range args {
+    switch tmp := arg.(type) {
+    case Weights:
+      weights = tmp.Value
+    case Tikhonov:
+      lambda = tmp.Value
+    case SVD:
+      useSVD = tmp.Value
+    case Epsilon:
+      epsilon = tmp.Value
+    case ComputeCovariance:
+      computeCovariance = tmp.Value
+    default:
+      panic("LeastSquares(): Invalid optional argument!")
+    }
+  }
+  if weights != nil && weights.Dim() != m {
+    return nil, nil, nil, fmt.Errorf("weights have invalid dimension")
+  }
+  if lambda < 0.0 {
+    return nil, nil, nil, fmt.Errorf("invalid Tikhonov damping")
+  }
+  if lambda == 0.0 && m < n {
+    return nil, nil, nil, fmt.Errorf("underdetermined system requires Tikhonov damping")
+  }
+  A := augmentedSystem(a, b, weights, lambda)
+  if math.IsNaN(epsilon) {
+    k, _ := A.Dims()
+    epsilon = float64(k)*2.220446e-16
+  }
+  var x    Vector
+  var s    Matrix
+  var rank int
+  var err  error
+  if useSVD {
+    x, s, rank, err = solveSVD(A, epsilon, computeCovariance)
+  } else {
+    x, s, rank, err = solveQR(A, epsilon, computeCovariance)
+  }
+  if err != nil {
+    return nil, nil, nil, err
+  }
+  // residuals r = b - A x
+  r := NullVector(a.ElementType(), m)
+  r.MdotV(a, x)
+  r.VsubV(b, r)
+
+  if computeCovariance {
+    if m <= rank {
+      return nil, nil, nil, fmt.Errorf("not enough observations to estimate the covariance")
+    }
+    // weighted sum of squared residuals
+    c := NullScalar(a.ElementType())
+    t := NullScalar(a.ElementType())
+    for i := 0; i < m; i++ {
+      t.Mul(r.At(i), r.At(i))
+      if weights != nil {
+        t.Mul(t, weights.ConstAt(i))
+      }
+      c.Add(c, t)
+    }
+    c.Div(c, ConstReal(float64(m-rank)))
+    s.MmulS(s, c)
+  }
+  return x, r, s, nil
+}
@@ -0,0 +1,170 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package leastSquares
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+import . "github.com/pbenner/autodiff/simple"
+import   "github.com/pbenner/autodiff/algorithm/matrixInverse"
+
+/* -------------------------------------------------------------------------- */
+
+func newDesign(t ScalarType, x []float64) Matrix {
+  a := NullMatrix(t, len(x), 3)
+  for i := 0; i < len(x); i++ {
+    a.At(i, 0).SetValue(1.0)
+    a.At(i, 1).SetValue(x[i])
+    a.At(i, 2).SetValue(x[i]*x[i])
+  }
+  return a
+}
+
+// (A^T A + lambda I)^-1 A^T b
+func normalEquations(a Matrix, b Vector, lambda float64) (Vector, Matrix) {
+  _, n := a.Dims()
+  h := MdotM(a.T(), a)
+  for i := 0; i < n; i++ {
+    h.At(i, i).Add(h.At(i, i), ConstReal(lambda))
+  }
+  hinv, _ := matrixInverse.Run(h)
+  return MdotV(hinv, MdotV(a.T(), b)), hinv
+}
+
+func vnorm(a Vector) float64 {
+  return math.Sqrt(VdotV(a, a).GetValue())
+}
+
+/* -------------------------------------------------------------------------- */
+
+func TestLeastSquares1(t *testing.T) {
+  a := newDesign(RealType, []float64{-1.0, -0.5, 0.0, 0.5, 1.0, 1.5})
+  b := NewVector(RealType, []float64{2.1, 1.2, 0.9, 1.3, 2.2, 3.4})
+
+  x1, r1, s1, err := Run(a, b, ComputeCovariance{true})
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  x2, r2, s2, err := Run(a, b, ComputeCovariance{true}, SVD{true})
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  x3, h := normalEquations(a, b, 0.0)
+
+  if vnorm(VsubV(x1, x3)) > 1e-10 || vnorm(VsubV(x2, x3)) > 1e-10 {
+    t.Error("test failed")
+  }
+  if vnorm(VsubV(r1, VsubV(b, MdotV(a, x3)))) > 1e-10 || vnorm(VsubV(r1, r2)) > 1e-10 {
+    t.Error("test failed")
+  }
+  // residuals are orthogonal to the columns of A
+  if vnorm(MdotV(a.T(), r1)) > 1e-10 {
+    t.Error("test failed")
+  }
+  // covariance s^2 (A^T A)^-1
+  s := VdotV(r1, r1).GetValue()/3.0
+  if Mnorm(MsubM(s1, MmulS(h, NewReal(s)))).GetValue() > 1e-20 {
+    t.Error("test failed")
+  }
+  if Mnorm(MsubM(s2, MmulS(h, NewReal(s)))).GetValue() > 1e-20 {
+    t.Error("test failed")
+  }
+}
+
+func TestLeastSquares2(t *testing.T) {
+  // a weight of two is equivalent to a duplicated observation
+  a1 := newDesign(BareRealType, []float64{-1.0, -0.5, 0.0, 0.5, 1.0})
+  b1 := NewVector(BareRealType, []float64{2.1, 1.2, 0.9, 1.3, 2.2})
+  w1 := NewVector(BareRealType, []float64{1.0, 2.0, 1.0, 1.0, 1.0})
+  a2 := newDesign(BareRealType, []float64{-1.0, -0.5, -0.5, 0.0, 0.5, 1.0})
+  b2 := NewVector(BareRealType, []float64{2.1, 1.2, 1.2, 0.9, 1.3, 2.2})
+
+  x1, _, _, err1 := Run(a1, b1, Weights{w1})
+  x2, _, _, err2 := Run(a2, b2)
+  if err1 != nil || err2 != nil {
+    t.Error("test failed")
+    return
+  }
+  if vnorm(VsubV(x1, x2)) > 1e-10 {
+    t.Error("test failed")
+  }
+  // Tikhonov damping
+  x3, _, _, err := Run(a2, b2, Tikhonov{0.5})
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  x4, _ := normalEquations(a2, b2, 0.5)
+  if vnorm(VsubV(x3, x4)) > 1e-10 {
+    t.Error("test failed")
+  }
+}
+
+func TestLeastSquares3(t *testing.T) {
+  // rank deficient system with two identical columns
+  a := NewMatrix(RealType, 4, 2, []float64{
+    1, 1,
+    2, 2,
+    3, 3,
+    4, 4 })
+  b := NewVector(RealType, []float64{1, 2, 3, 4})
+
+  if _, _, _, err := Run(a, b); err == nil {
+    t.Error("test failed")
+  }
+  x, r, _, err := Run(a, b, SVD{true})
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  // minimum norm solution
+  if math.Abs(x.At(0).GetValue() - 0.5) > 1e-10 || math.Abs(x.At(1).GetValue() - 0.5) > 1e-10 {
+    t.Error("test failed")
+  }
+  if vnorm(r) > 1e-10 {
+    t.Error("test failed")
+  }
+}
+
+func TestLeastSquares4(t *testing.T) {
+  // derivatives of x with respect to b
+  a := newDesign(RealType, []float64{-1.0, -0.5, 0.0, 0.5, 1.0})
+  b := NewVector(RealType, []float64{2.1, 1.2, 0.9, 1.3, 2.2})
+  b.Variables(1)
+
+  x, _, _, err := Run(a, b)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  // dx/db = (A^T A)^-1 A^T
+  _, h := normalEquations(a, NullVector(RealType, 5), 0.0)
+  p := MdotM(h, a.T())
+  for i := 0; i < 3; i++ {
+    for j := 0; j < 5; j++ {
+      if math.Abs(x.At(i).GetDerivative(j) - p.At(i, j).GetValue()) > 1e-10 {
+        t.Error("test failed")
+      }
+    }
+  }
+}
@@ -0,0 +1,159 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package lu
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+
+import . "github.com/pbenner/autodiff"
+
+/* -------------------------------------------------------------------------- */
+
+type InSitu struct {
+  P Matrix
+  L Matrix
+  U Matrix
+  X Vector
+  S Scalar
+  T Scalar
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the LU decomposition P A = L U with partial pivoting, where P is
+// a permutation matrix, L is unit lower triangular and U is upper
+// triangular. Returns P, L and U.
+func Run(a ConstMatrix, args ...interface{}) (Matrix, Matrix, Matrix, error) {
+  n, m := a.Dims()
+  if n != m {
+    panic("LU(): Not a square matrix!")
+  }
+  if n == 0 {
+    panic("LU(): Empty matrix!")
+  }
+  t      := a.ElementType()
+  inSitu := &InSitu{}
+
+  for _, arg := range args {
+    switch a := arg.(type) {
+    case *InSitu:
+      inSitu = a
+    case InSitu:
+      panic("InSitu must be passed by reference")
+    default:
+      panic("LU(): Invalid optional argument!")
+    }
+  }
+  // allocate memory
+  if inSitu.P == nil {
+    inSitu.P = NullMatrix(t, n, n)
+  }
+  if inSitu.L == nil {
+    inSitu.L = NullMatrix(t, n, n)
+  }
+  if inSitu.U == nil {
+    inSitu.U = NullMatrix(t, n, n)
+  }
+  if inSitu.S == nil {
+    inSitu.S = NewScalar(t, 0.0)
+  }
+  { // Real
+    A, ok1 :=        a.(*DenseRealMatrix)
+    P, ok2 := inSitu.P.(*DenseRealMatrix)
+    L, ok3 := inSitu.L.(*DenseRealMatrix)
+    U, ok4 := inSitu.U.(*DenseRealMatrix)
+    s, ok5 := inSitu.S.(*Real)
+    if ok1 && ok2 && ok3 && ok4 && ok5 {
+      return lu_real(A, P, L, U, s)
+    }
+  }
+  { // BareReal
+    A, ok1 :=        a.(*DenseBareRealMatrix)
+    P, ok2 := inSitu.P.(*DenseBareRealMatrix)
+    L, ok3 := inSitu.L.(*DenseBareRealMatrix)
+    U, ok4 := inSitu.U.(*DenseBareRealMatrix)
+    s, ok5 := inSitu.S.(*BareReal)
+    if ok1 && ok2 && ok3 && ok4 && ok5 {
+      return lu_barereal(A, P, L, U, s)
+    }
+  }
+  return lu(a, inSitu.P, inSitu.L, inSitu.U, inSitu.S)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Solve A x = b given the LU decomposition P A = L U computed by Run. The
+// decomposition is not modified and can be reused for many right-hand
+// sides.
+func Solve(p, l, u ConstMatrix, b ConstVector, args ...interface{}) (Vector, error) {
+  n, m := l.Dims()
+  if n != m {
+    panic("LU(): Not a square matrix!")
+  }
+  if b.Dim() != n {
+    panic("LU(): Vector has invalid dimension!")
+  }
+  t      := u.ElementType()
+  inSitu := &InSitu{}
+
+  for _, arg := range args {
+    switch a := arg.(type) {
+    case *InSitu:
+      inSitu = a
+    case InSitu:
+      panic("InSitu must be passed by reference")
+    default:
+      panic("LU(): Invalid optional argument!")
+    }
+  }
+  // allocate memory
+  if inSitu.X == nil {
+    inSitu.X = NullVector(t, n)
+  }
+  if inSitu.S == nil {
+    inSitu.S = NewScalar(t, 0.0)
+  }
+  if inSitu.T == nil {
+    inSitu.T = NewScalar(t, 0.0)
+  }
+  { // Real
+    P, ok1 :=        p.(*DenseRealMatrix)
+    L, ok2 :=        l.(*DenseRealMatrix)
+    U, ok3 :=        u.(*DenseRealMatrix)
+    B, ok4 :=        b.(DenseRealVector)
+    x, ok5 := inSitu.X.(DenseRealVector)
+    s, ok6 := inSitu.S.(*Real)
+    t, ok7 := inSitu.T.(*Real)
+    if ok1 && ok2 && ok3 && ok4 && ok5 && ok6 && ok7 {
+      return lu_solve_real(P, L, U, B, x, s, t)
+    }
+  }
+  { // BareReal
+    P, ok1 :=        p.(*DenseBareRealMatrix)
+    L, ok2 :=        l.(*DenseBareRealMatrix)
+    U, ok3 :=        u.(*DenseBareRealMatrix)
+    B, ok4 :=        b.(DenseBareRealVector)
+    x, ok5 := inSitu.X.(DenseBareRealVector)
+    s, ok6 := inSitu.S.(*BareReal)
+    t, ok7 := inSitu.T.(*BareReal)
+    if ok1 && ok2 && ok3 && ok4 && ok5 && ok6 && ok7 {
+      return lu_solve_barereal(P, L, U, B, x, s, t)
+    }
+  }
+  return lu_solve(p, l, u, b, inSitu.X, inSitu.S, inSitu.T)
+}
@@ -0,0 +1,11 @@
+
+#define SCALAR_TYPE *BareReal
+#define MATRIX_TYPE *DenseBareRealMatrix
+#define VECTOR_TYPE DenseBareRealVector
+#define CONST_MATRIX_TYPE MATRIX_TYPE
+#define CONST_VECTOR_TYPE VECTOR_TYPE
+
+#define LU       lu_barereal
+#define LU_SOLVE lu_solve_barereal
+
+#define CONSTAT AT
@@ -0,0 +1,111 @@
+/* -*- mode: go; -*-
+ *
+ * Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package lu
+/* -------------------------------------------------------------------------- */
+import "fmt"
+import "math"
+import . "github.com/pbenner/autodiff"
+/* -------------------------------------------------------------------------- */
+func lu(A ConstMatrix, P, L, U Matrix, s Scalar) (Matrix, Matrix, Matrix, error) {
+  n, _ := A.Dims()
+  // permutation of rows, i.e. row i of P*A is row perm[i] of A
+  perm := make([]int, n)
+  for i := 0; i < n; i++ {
+    perm[i] = i
+    for j := 0; j < n; j++ {
+      U.At(i, j).Set(A.ConstAt(i, j))
+      L.At(i, j).SetValue(0.0)
+      P.At(i, j).SetValue(0.0)
+    }
+  }
+  for k := 0; k < n; k++ {
+    // find pivot element
+    p := k
+    for i := k+1; i < n; i++ {
+      if math.Abs(U.At(i, k).GetValue()) > math.Abs(U.At(p, k).GetValue()) {
+        p = i
+      }
+    }
+    if U.At(p, k).GetValue() == 0.0 {
+      return nil, nil, nil, fmt.Errorf("matrix is singular")
+    }
+    // swap rows k and p
+    if p != k {
+      for j := 0; j < n; j++ {
+        s.Set(U.At(k, j))
+        U.At(k, j).Set(U.At(p, j))
+        U.At(p, j).Set(s)
+      }
+      for j := 0; j < k; j++ {
+        s.Set(L.At(k, j))
+        L.At(k, j).Set(L.At(p, j))
+        L.At(p, j).Set(s)
+      }
+      perm[k], perm[p] = perm[p], perm[k]
+    }
+    // eliminate entries below the pivot
+    for i := k+1; i < n; i++ {
+      L.At(i, k).Div(U.At(i, k), U.At(k, k))
+      for j := k+1; j < n; j++ {
+        s.Mul(L.At(i, k), U.At(k, j))
+        U.At(i, j).Sub(U.At(i, j), s)
+      }
+      U.At(i, k).SetValue(0.0)
+    }
+  }
+  for i := 0; i < n; i++ {
+    L.At(i, i).SetValue(1.0)
+    P.At(i, perm[i]).SetValue(1.0)
+  }
+  return P, L, U, nil
+}
+/* -------------------------------------------------------------------------- */
+func lu_solve(P, L, U ConstMatrix, b ConstVector, x Vector, s, t Scalar) (Vector, error) {
+  n, _ := L.Dims()
+  // x = P*b
+  for i := 0; i < n; i++ {
+    for j := 0; j < n; j++ {
+      if P.ConstAt(i, j).GetValue() != 0.0 {
+        x.At(i).Set(b.ConstAt(j))
+        break
+      }
+    }
+  }
+  // solve L*y = P*b
+  for i := 0; i < n; i++ {
+    s.Set(x.At(i))
+    for k := 0; k < i; k++ {
+      t.Mul(L.ConstAt(i, k), x.At(k))
+      s.Sub(s, t)
+    }
+    x.At(i).Div(s, L.ConstAt(i, i))
+  }
+  // solve U*x = y
+  for i := n-1; i >= 0; i-- {
+    if U.ConstAt(i, i).GetValue() == 0.0 {
+      return x, fmt.Errorf("matrix is singular")
+    }
+    s.Set(x.At(i))
+    for k := i+1; k < n; k++ {
+      t.Mul(U.ConstAt(i, k), x.At(k))
+      s.Sub(s, t)
+    }
+    x.At(i).Div(s, U.ConstAt(i, i))
+  }
+  return x, nil
+}
@@ -0,0 +1,17 @@
+
+#define SCALAR_TYPE Scalar
+#define MATRIX_TYPE Matrix
+#define VECTOR_TYPE Vector
+#define CONST_MATRIX_TYPE ConstMatrix
+#define CONST_VECTOR_TYPE ConstVector
+
+#define LU       lu
+#define LU_SOLVE lu_solve
+
+#define AT      At
+#define CONSTAT ConstAt
+#define SET     Set
+#define ADD     Add
+#define SUB     Sub
+#define MUL     Mul
+#define DIV     Div
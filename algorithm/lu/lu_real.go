@@ -0,0 +1,111 @@
+/* -*- mode: go; -*-
+ *
+ * Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package lu
+/* -------------------------------------------------------------------------- */
+import "fmt"
+import "math"
+import . "github.com/pbenner/autodiff"
+/* -------------------------------------------------------------------------- */
+func lu_real(A *DenseRealMatrix, P, L, U *DenseRealMatrix, s *Real) (*DenseRealMatrix, *DenseRealMatrix, *DenseRealMatrix, error) {
+  n, _ := A.Dims()
+  // permutation of rows, i.e. row i of P*A is row perm[i] of A
+  perm := make([]int, n)
+  for i := 0; i < n; i++ {
+    perm[i] = i
+    for j := 0; j < n; j++ {
+      U.AT(i, j).SET(A.AT(i, j))
+      L.AT(i, j).SetValue(0.0)
+      P.AT(i, j).SetValue(0.0)
+    }
+  }
+  for k := 0; k < n; k++ {
+    // find pivot element
+    p := k
+    for i := k+1; i < n; i++ {
+      if math.Abs(U.AT(i, k).GetValue()) > math.Abs(U.AT(p, k).GetValue()) {
+        p = i
+      }
+    }
+    if U.AT(p, k).GetValue() == 0.0 {
+      return nil, nil, nil, fmt.Errorf("matrix is singular")
+    }
+    // swap rows k and p
+    if p != k {
+      for j := 0; j < n; j++ {
+        s.SET(U.AT(k, j))
+        U.AT(k, j).SET(U.AT(p, j))
+        U.AT(p, j).SET(s)
+      }
+      for j := 0; j < k; j++ {
+        s.SET(L.AT(k, j))
+        L.AT(k, j).SET(L.AT(p, j))
+        L.AT(p, j).SET(s)
+      }
+      perm[k], perm[p] = perm[p], perm[k]
+    }
+    // eliminate entries below the pivot
+    for i := k+1; i < n; i++ {
+      L.AT(i, k).DIV(U.AT(i, k), U.AT(k, k))
+      for j := k+1; j < n; j++ {
+        s.MUL(L.AT(i, k), U.AT(k, j))
+        U.AT(i, j).SUB(U.AT(i, j), s)
+      }
+      U.AT(i, k).SetValue(0.0)
+    }
+  }
+  for i := 0; i < n; i++ {
+    L.AT(i, i).SetValue(1.0)
+    P.AT(i, perm[i]).SetValue(1.0)
+  }
+  return P, L, U, nil
+}
+/* -------------------------------------------------------------------------- */
+func lu_solve_real(P, L, U *DenseRealMatrix, b DenseRealVector, x DenseRealVector, s, t *Real) (DenseRealVector, error) {
+  n, _ := L.Dims()
+  // x = P*b
+  for i := 0; i < n; i++ {
+    for j := 0; j < n; j++ {
+      if P.AT(i, j).GetValue() != 0.0 {
+        x.AT(i).SET(b.AT(j))
+        break
+      }
+    }
+  }
+  // solve L*y = P*b
+  for i := 0; i < n; i++ {
+    s.SET(x.AT(i))
+    for k := 0; k < i; k++ {
+      t.MUL(L.AT(i, k), x.AT(k))
+      s.SUB(s, t)
+    }
+    x.AT(i).DIV(s, L.AT(i, i))
+  }
+  // solve U*x = y
+  for i := n-1; i >= 0; i-- {
+    if U.AT(i, i).GetValue() == 0.0 {
+      return x, fmt.Errorf("matrix is singular")
+    }
+    s.SET(x.AT(i))
+    for k := i+1; k < n; k++ {
+      t.MUL(U.AT(i, k), x.AT(k))
+      s.SUB(s, t)
+    }
+    x.AT(i).DIV(s, U.AT(i, i))
+  }
+  return x, nil
+}
@@ -0,0 +1,11 @@
+
+#define SCALAR_TYPE *Real
+#define MATRIX_TYPE *DenseRealMatrix
+#define VECTOR_TYPE DenseRealVector
+#define CONST_MATRIX_TYPE MATRIX_TYPE
+#define CONST_VECTOR_TYPE VECTOR_TYPE
+
+#define LU       lu_real
+#define LU_SOLVE lu_solve_real
+
+#define CONSTAT AT
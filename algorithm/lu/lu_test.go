@@ -0,0 +1,129 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package lu
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+import . "github.com/pbenner/autodiff/simple"
+
+/* -------------------------------------------------------------------------- */
+
+func TestLU1(t *testing.T) {
+  n := 4
+  a := NewMatrix(RealType, n, n, []float64{
+     1, 2, 3, 4,
+     2, 4, 7, 1,
+    -3, 1, 2, 5,
+     6, 2, 1, 0 })
+  p, l, u, err := Run(a)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  if Mnorm(MsubM(MdotM(p, a), MdotM(l, u))).GetValue() > 1e-20 {
+    t.Error("test failed")
+  }
+  for i := 0; i < n; i++ {
+    if l.At(i, i).GetValue() != 1.0 {
+      t.Error("test failed")
+    }
+    for j := i+1; j < n; j++ {
+      if l.At(i, j).GetValue() != 0.0 || u.At(j, i).GetValue() != 0.0 {
+        t.Error("test failed")
+      }
+    }
+  }
+}
+
+func TestLU2(t *testing.T) {
+  n := 6
+  for _, st := range []ScalarType{RealType, BareRealType, BigRealType} {
+    // Hilbert matrix
+    a := NullMatrix(st, n, n)
+    for i := 0; i < n; i++ {
+      for j := 0; j < n; j++ {
+        a.At(i, j).SetValue(1.0/float64(i+j+1))
+      }
+    }
+    inSitu := InSitu{}
+    p, l, u, err := Run(a, &inSitu)
+    if err != nil {
+      t.Error(err)
+      return
+    }
+    // solve for several right-hand sides
+    for k := 0; k < n; k++ {
+      b := NullVector(st, n)
+      b.At(k).SetValue(1.0)
+      x, err := Solve(p, l, u, b)
+      if err != nil {
+        t.Error(err)
+        return
+      }
+      r := VsubV(MdotV(a, x), b)
+      if math.Sqrt(VdotV(r, r).GetValue()) > 1e-8 {
+        t.Error("test failed")
+      }
+    }
+  }
+}
+
+func TestLU3(t *testing.T) {
+  // x solves [[theta, 1], [1, 2]] x = [1, 0]
+  theta := NewReal(3.0)
+  Variables(1, theta)
+
+  a := NullMatrix(RealType, 2, 2)
+  a.At(0, 0).Set(theta)
+  a.At(0, 1).SetValue(1.0)
+  a.At(1, 0).SetValue(1.0)
+  a.At(1, 1).SetValue(2.0)
+  b := NewVector(RealType, []float64{1.0, 0.0})
+
+  p, l, u, err := Run(a)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  x, err := Solve(p, l, u, b)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  d := 2.0*theta.GetValue() - 1.0
+  if math.Abs(x.At(0).GetValue() - 2.0/d) > 1e-12 || math.Abs(x.At(1).GetValue() + 1.0/d) > 1e-12 {
+    t.Error("test failed")
+  }
+  if math.Abs(x.At(0).GetDerivative(0) + 4.0/(d*d)) > 1e-12 || math.Abs(x.At(1).GetDerivative(0) - 2.0/(d*d)) > 1e-12 {
+    t.Error("test failed")
+  }
+}
+
+func TestLU4(t *testing.T) {
+  a := NewMatrix(BareRealType, 3, 3, []float64{
+    1, 2, 3,
+    2, 4, 6,
+    1, 0, 1 })
+  if _, _, _, err := Run(a); err == nil {
+    t.Error("test failed")
+  }
+}
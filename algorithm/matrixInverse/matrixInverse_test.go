@@ -41,6 +41,17 @@ func TestMatrixInverse(t *testing.T) {
   }
 }
 
+func TestMatrixInverse2(t *testing.T) {
+
+  // row pivoting results in a permutation that is not an involution
+  m1 := NewMatrix(RealType, 3, 3, []float64{1,2,3,4,5,6,7,8,10})
+  m2, _ := Run(m1)
+
+  if Mnorm(MsubM(MdotM(m1, m2), IdentityMatrix(RealType, 3))).GetValue() > 1e-8 {
+    t.Error("Inverting matrix failed!")
+  }
+}
+
 func TestSubmatrixInverse(t *testing.T) {
 
   // exclude the third row/column
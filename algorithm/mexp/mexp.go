@@ -0,0 +1,193 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mexp
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "errors"
+import   "math"
+
+import . "github.com/pbenner/autodiff"
+import   "github.com/pbenner/autodiff/algorithm/matrixInverse"
+
+/* -------------------------------------------------------------------------- */
+
+// Reference:
+// Higham, N.~J. (2005). The Scaling and Squaring Method for the Matrix
+// Exponential Revisited. SIAM J. Matrix Anal. Appl., 26(4), 1179-1193.
+
+/* -------------------------------------------------------------------------- */
+
+// maximal 1-norms for which the [m/m] Pade approximant has a backward
+// error smaller than the unit roundoff
+var theta = map[int]float64{
+   3: 1.495585217958292e-2,
+   5: 2.539398330063230e-1,
+   7: 9.504178996162932e-1,
+   9: 2.097847961257068e+0,
+  13: 5.371920351148152e+0 }
+
+// coefficients of the numerator polynomials
+var coefficients = map[int][]float64{
+   3: {120, 60, 12, 1},
+   5: {30240, 15120, 3360, 420, 30, 1},
+   7: {17297280, 8648640, 1995840, 277200, 25200, 1512, 56, 1},
+   9: {17643225600, 8821612800, 2075673600, 302702400, 30270240, 2162160, 110880, 3960, 90, 1},
+  13: {64764752532480000, 32382376266240000, 7771770303897600, 1187353796428800, 129060195264000,
+    10559470521600, 670442572800, 33522128640, 1323241920, 40840800, 960960, 16380, 182, 1} }
+
+/* -------------------------------------------------------------------------- */
+
+func norm1(a ConstMatrix) float64 {
+  n, m := a.Dims()
+  r := 0.0
+  for j := 0; j < m; j++ {
+    s := 0.0
+    for i := 0; i < n; i++ {
+      s += math.Abs(a.ValueAt(i, j))
+    }
+    r = math.Max(r, s)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+// evaluate the [m/m] Pade approximant r(A) = (V - U)^-1 (V + U), where U
+// contains the odd and V the even powers of A
+func pade(a ConstMatrix, m int) (Matrix, error) {
+  n, _ := a.Dims()
+  t    := a.ElementType()
+  b    := coefficients[m]
+  s    := NewScalar(t, 0.0)
+  w    := NullMatrix(t, n, n)
+  // r = r + c x
+  axpy := func(r Matrix, c float64, x ConstMatrix) {
+    s.SetValue(c)
+    w.MmulS(x, s)
+    r.MaddM(r, w)
+  }
+  I  := IdentityMatrix(t, n)
+  U  := NullMatrix(t, n, n)
+  V  := NullMatrix(t, n, n)
+  A2 := NullMatrix(t, n, n)
+  A2.MdotM(a, a)
+  if m < 13 {
+    p := I
+    for k := 0; 2*k <= m; k++ {
+      if k > 0 {
+        q := NullMatrix(t, n, n)
+        q.MdotM(p, A2)
+        p = q
+      }
+      axpy(V, b[2*k], p)
+      if 2*k+1 <= m {
+        axpy(U, b[2*k+1], p)
+      }
+    }
+  } else {
+    A4 := NullMatrix(t, n, n)
+    A4.MdotM(A2, A2)
+    A6 := NullMatrix(t, n, n)
+    A6.MdotM(A4, A2)
+    // U = A [A6 (b13 A6 + b11 A4 + b9 A2) + b7 A6 + b5 A4 + b3 A2 + b1 I]
+    axpy(U, b[13], A6)
+    axpy(U, b[11], A4)
+    axpy(U, b[ 9], A2)
+    U.MdotM(A6, U)
+    axpy(U, b[ 7], A6)
+    axpy(U, b[ 5], A4)
+    axpy(U, b[ 3], A2)
+    axpy(U, b[ 1], I)
+    // V = A6 (b12 A6 + b10 A4 + b8 A2) + b6 A6 + b4 A4 + b2 A2 + b0 I
+    axpy(V, b[12], A6)
+    axpy(V, b[10], A4)
+    axpy(V, b[ 8], A2)
+    V.MdotM(A6, V)
+    axpy(V, b[ 6], A6)
+    axpy(V, b[ 4], A4)
+    axpy(V, b[ 2], A2)
+    axpy(V, b[ 0], I)
+  }
+  U.MdotM(a, U)
+  P := NullMatrix(t, n, n)
+  P.MaddM(V, U)
+  Q := NullMatrix(t, n, n)
+  Q.MsubM(V, U)
+  Qi, err := matrixInverse.Run(Q)
+  if err != nil {
+    return nil, err
+  }
+  return P.MdotM(Qi, P), nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+func mExp(a Matrix) (Matrix, error) {
+  n, _ := a.Dims()
+  t    := a.ElementType()
+  norm := norm1(a)
+  if math.IsNaN(norm) || math.IsInf(norm, 0) {
+    return nil, errors.New("MExp(): Matrix has non-finite entries!")
+  }
+  for _, m := range []int{3, 5, 7, 9} {
+    if norm <= theta[m] {
+      return pade(a, m)
+    }
+  }
+  // scale A such that its norm is smaller than theta_13, the
+  // scaling factor is not differentiated
+  s := 0
+  if norm > theta[13] {
+    s = int(math.Ceil(math.Log2(norm/theta[13])))
+  }
+  b := NullMatrix(t, n, n)
+  b.MmulS(a, NewScalar(t, math.Pow(2.0, -float64(s))))
+  r, err := pade(b, 13)
+  if err != nil {
+    return nil, err
+  }
+  // undo scaling by repeated squaring
+  for i := 0; i < s; i++ {
+    b.MdotM(r, r)
+    r, b = b, r
+  }
+  return r, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the matrix exponential exp(A) using the scaling and squaring
+// algorithm with Pade approximants. All operations are carried out in the
+// element type of A, so that derivatives are propagated.
+func Run(matrix Matrix, args ...interface{}) (Matrix, error) {
+  for _, arg := range args {
+    switch arg.(type) {
+    default:
+      panic("MExp(): Invalid optional argument!")
+    }
+  }
+  rows, cols := matrix.Dims()
+  if rows != cols {
+    return nil, errors.New("MExp(): Not a square matrix!")
+  }
+  if rows == 0 {
+    return nil, errors.New("MExp(): Empty matrix!")
+  }
+  return mExp(matrix)
+}
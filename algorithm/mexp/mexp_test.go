@@ -0,0 +1,94 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mexp
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+import . "github.com/pbenner/autodiff/simple"
+
+/* -------------------------------------------------------------------------- */
+
+func TestMExp1(t *testing.T) {
+  // diagonal and nilpotent matrices
+  a1 := NewMatrix(RealType, 2, 2, []float64{1, 0, 0, -2})
+  r1 := NewMatrix(RealType, 2, 2, []float64{math.Exp(1), 0, 0, math.Exp(-2)})
+  a2 := NewMatrix(RealType, 3, 3, []float64{0, 1, 0, 0, 0, 1, 0, 0, 0})
+  r2 := NewMatrix(RealType, 3, 3, []float64{1, 1, 0.5, 0, 1, 1, 0, 0, 1})
+
+  if x, err := Run(a1); err != nil {
+    t.Error(err)
+  } else if Mnorm(MsubM(x, r1)).GetValue() > 1e-20 {
+    t.Error("test failed")
+  }
+  if x, err := Run(a2); err != nil {
+    t.Error(err)
+  } else if Mnorm(MsubM(x, r2)).GetValue() > 1e-20 {
+    t.Error("test failed")
+  }
+}
+
+func TestMExp2(t *testing.T) {
+  // rotations for all Pade degrees and several scaling steps
+  for _, phi := range []float64{0.001, 0.1, 0.5, 1.0, 2.0, 30.0} {
+    a := NewMatrix(BareRealType, 2, 2, []float64{0, -phi, phi, 0})
+    r := NewMatrix(BareRealType, 2, 2, []float64{
+      math.Cos(phi), -math.Sin(phi),
+      math.Sin(phi),  math.Cos(phi) })
+    if x, err := Run(a); err != nil {
+      t.Error(err)
+    } else if Mnorm(MsubM(x, r)).GetValue() > 1e-24 {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestMExp3(t *testing.T) {
+  // transition probabilities of a continuous-time Markov chain
+  q := NewMatrix(RealType, 3, 3, []float64{
+    -3.0,  2.0,  1.0,
+     0.5, -1.0,  0.5,
+     1.0,  4.0, -5.0 })
+  s := NewReal(1.7)
+  Variables(1, s)
+
+  a := MmulS(q, s)
+  p, err := Run(a)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  // rows of P(t) sum to one
+  for i := 0; i < 3; i++ {
+    if r := p.At(i, 0).GetValue() + p.At(i, 1).GetValue() + p.At(i, 2).GetValue(); math.Abs(r - 1.0) > 1e-12 {
+      t.Error("test failed")
+    }
+  }
+  // dP(t)/dt = Q P(t)
+  d := MdotM(q, p)
+  for i := 0; i < 3; i++ {
+    for j := 0; j < 3; j++ {
+      if math.Abs(p.At(i, j).GetDerivative(0) - d.At(i, j).GetValue()) > 1e-10 {
+        t.Error("test failed")
+      }
+    }
+  }
+}
@@ -0,0 +1,153 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mlog
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "errors"
+import   "math"
+
+import . "github.com/pbenner/autodiff"
+import   "github.com/pbenner/autodiff/algorithm/matrixInverse"
+import   "github.com/pbenner/autodiff/algorithm/msqrt"
+
+/* -------------------------------------------------------------------------- */
+
+// Reference:
+// Higham, N.~J. (2001). Evaluating Pade Approximants of the Matrix
+// Logarithm. SIAM J. Matrix Anal. Appl., 22(4), 1126-1135.
+// Higham, N.~J. (2008). Functions of Matrices: Theory and Computation;
+// Society for Industrial and Applied Mathematics, Philadelphia, PA, USA.
+
+/* -------------------------------------------------------------------------- */
+
+// square roots are taken until ||A - I|| <= theta, for which the [8/8] Pade
+// approximant of log(I + X) is accurate to unit roundoff
+const theta = 0.25
+const degree = 8
+
+const maxSquareRoots = 64
+
+/* -------------------------------------------------------------------------- */
+
+func norm1(a ConstMatrix) float64 {
+  n, m := a.Dims()
+  r := 0.0
+  for j := 0; j < m; j++ {
+    s := 0.0
+    for i := 0; i < n; i++ {
+      s += math.Abs(a.ValueAt(i, j))
+    }
+    r = math.Max(r, s)
+  }
+  return r
+}
+
+// Gauss-Legendre nodes and weights on the interval [0,1]
+func gaussLegendre(m int) ([]float64, []float64) {
+  x := make([]float64, m)
+  w := make([]float64, m)
+  for i := 0; i < m; i++ {
+    z  := math.Cos(math.Pi*(float64(i)+0.75)/(float64(m)+0.5))
+    dp := 0.0
+    for k := 0; k < 100; k++ {
+      // evaluate Legendre polynomial P_m(z) by recurrence
+      p0, p1 := 1.0, z
+      for j := 2; j <= m; j++ {
+        p0, p1 = p1, ((2.0*float64(j)-1.0)*z*p1 - (float64(j)-1.0)*p0)/float64(j)
+      }
+      dp  = float64(m)*(z*p1 - p0)/(z*z - 1.0)
+      dz := p1/dp
+      z  -= dz
+      if math.Abs(dz) < 1e-16 {
+        break
+      }
+    }
+    x[i] = (1.0 - z)/2.0
+    w[i] = 1.0/((1.0 - z*z)*dp*dp)
+  }
+  return x, w
+}
+
+/* -------------------------------------------------------------------------- */
+
+func mLog(a Matrix) (Matrix, error) {
+  n, _ := a.Dims()
+  t    := a.ElementType()
+  I    := IdentityMatrix(t, n)
+  X    := NullMatrix(t, n, n)
+  X.MsubM(a, I)
+  // inverse scaling: A^(1/2^k) is close to the identity
+  k := 0
+  for ; norm1(X) > theta; k++ {
+    if k == maxSquareRoots {
+      return nil, errors.New("MLog(): Matrix has eigenvalues on the closed negative real axis!")
+    }
+    r, err := msqrt.Run(X.MaddM(X, I), msqrt.Epsilon{1e-20})
+    if err != nil {
+      return nil, err
+    }
+    X.MsubM(r, I)
+  }
+  // partial fraction form of the [m/m] Pade approximant
+  // log(I + X) = sum_j w_j X (I + x_j X)^-1
+  x, w := gaussLegendre(degree)
+  s := NewScalar(t, 0.0)
+  T := NullMatrix(t, n, n)
+  R := NullMatrix(t, n, n)
+  for j := 0; j < degree; j++ {
+    s.SetValue(x[j])
+    T.MmulS(X, s)
+    T.MaddM(T, I)
+    Ti, err := matrixInverse.Run(T)
+    if err != nil {
+      return nil, err
+    }
+    s.SetValue(w[j])
+    T.MdotM(X, Ti)
+    T.MmulS(T, s)
+    R.MaddM(R, T)
+  }
+  // undo scaling
+  s.SetValue(math.Pow(2.0, float64(k)))
+  R.MmulS(R, s)
+  return R, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the principal matrix logarithm log(A) using the inverse scaling
+// and squaring algorithm. A must not have eigenvalues on the closed negative
+// real axis. All operations are carried out in the element type of A, so
+// that derivatives are propagated.
+func Run(matrix Matrix, args ...interface{}) (Matrix, error) {
+  for _, arg := range args {
+    switch arg.(type) {
+    default:
+      panic("MLog(): Invalid optional argument!")
+    }
+  }
+  rows, cols := matrix.Dims()
+  if rows != cols {
+    return nil, errors.New("MLog(): Not a square matrix!")
+  }
+  if rows == 0 {
+    return nil, errors.New("MLog(): Empty matrix!")
+  }
+  return mLog(matrix)
+}
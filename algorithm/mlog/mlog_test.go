@@ -0,0 +1,111 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mlog
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+import . "github.com/pbenner/autodiff/simple"
+import   "github.com/pbenner/autodiff/algorithm/mexp"
+
+/* -------------------------------------------------------------------------- */
+
+func TestMLog1(t *testing.T) {
+  a1 := NewMatrix(RealType, 2, 2, []float64{3, 0, 0, 0.2})
+  r1 := NewMatrix(RealType, 2, 2, []float64{math.Log(3), 0, 0, math.Log(0.2)})
+  // rotation by phi
+  phi := 2.5
+  a2  := NewMatrix(RealType, 2, 2, []float64{
+    math.Cos(phi), -math.Sin(phi),
+    math.Sin(phi),  math.Cos(phi) })
+  r2  := NewMatrix(RealType, 2, 2, []float64{0, -phi, phi, 0})
+
+  if x, err := Run(a1); err != nil {
+    t.Error(err)
+  } else if Mnorm(MsubM(x, r1)).GetValue() > 1e-20 {
+    t.Error("test failed")
+  }
+  if x, err := Run(a2); err != nil {
+    t.Error(err)
+  } else if Mnorm(MsubM(x, r2)).GetValue() > 1e-20 {
+    t.Error("test failed")
+  }
+}
+
+func TestMLog2(t *testing.T) {
+  // exp(log(A)) = A
+  a := NewMatrix(BareRealType, 3, 3, []float64{
+    40.0,  2.0,  3.0,
+     1.0, 20.0, -4.0,
+     0.5,  0.0,  0.1 })
+  l, err := Run(a)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  x, err := mexp.Run(l)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  if Mnorm(MsubM(x, a)).GetValue() > 1e-20 {
+    t.Error("test failed")
+  }
+}
+
+func TestMLog3(t *testing.T) {
+  // d/ds log(exp(s B)) = B
+  b := NewMatrix(RealType, 3, 3, []float64{
+    -1.0,  0.5,  0.5,
+     0.2, -0.4,  0.2,
+     1.0,  2.0, -3.0 })
+  s := NewReal(0.8)
+  Variables(1, s)
+
+  a, err := mexp.Run(MmulS(b, s))
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  l, err := Run(a)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  for i := 0; i < 3; i++ {
+    for j := 0; j < 3; j++ {
+      if math.Abs(l.At(i, j).GetValue() - 0.8*b.At(i, j).GetValue()) > 1e-10 {
+        t.Error("test failed")
+      }
+      if math.Abs(l.At(i, j).GetDerivative(0) - b.At(i, j).GetValue()) > 1e-10 {
+        t.Error("test failed")
+      }
+    }
+  }
+}
+
+func TestMLog4(t *testing.T) {
+  // negative eigenvalues
+  a := NewMatrix(RealType, 2, 2, []float64{-2, 0, 0, 2})
+  if _, err := Run(a); err == nil {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,96 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mpow
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "errors"
+import   "math"
+
+import . "github.com/pbenner/autodiff"
+import   "github.com/pbenner/autodiff/algorithm/matrixInverse"
+import   "github.com/pbenner/autodiff/algorithm/mexp"
+import   "github.com/pbenner/autodiff/algorithm/mlog"
+
+/* -------------------------------------------------------------------------- */
+
+// A^k by repeated squaring
+func mPowInt(a Matrix, k int) Matrix {
+  n, _ := a.Dims()
+  t    := a.ElementType()
+  r    := IdentityMatrix(t, n)
+  s    := a.CloneMatrix()
+  w    := NullMatrix(t, n, n)
+  for ; k > 0; k >>= 1 {
+    if k & 1 == 1 {
+      w.MdotM(r, s)
+      r, w = w, r
+    }
+    if k > 1 {
+      w.MdotM(s, s)
+      s, w = w, s
+    }
+  }
+  return r
+}
+
+func mPow(a Matrix, p float64) (Matrix, error) {
+  if p == math.Trunc(p) && math.Abs(p) <= math.MaxInt32 {
+    if p < 0 {
+      b, err := matrixInverse.Run(a)
+      if err != nil {
+        return nil, err
+      }
+      return mPowInt(b, int(-p)), nil
+    } else {
+      return mPowInt(a, int(p)), nil
+    }
+  }
+  // A^p = exp(p log(A))
+  b, err := mlog.Run(a)
+  if err != nil {
+    return nil, err
+  }
+  b.MmulS(b, NewScalar(b.ElementType(), p))
+  return mexp.Run(b)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the matrix power A^p. Integer powers are computed by repeated
+// squaring (of the inverse if p is negative), whereas for real p the
+// principal power exp(p log(A)) is returned.
+func Run(matrix Matrix, p float64, args ...interface{}) (Matrix, error) {
+  for _, arg := range args {
+    switch arg.(type) {
+    default:
+      panic("MPow(): Invalid optional argument!")
+    }
+  }
+  rows, cols := matrix.Dims()
+  if rows != cols {
+    return nil, errors.New("MPow(): Not a square matrix!")
+  }
+  if rows == 0 {
+    return nil, errors.New("MPow(): Empty matrix!")
+  }
+  if math.IsNaN(p) || math.IsInf(p, 0) {
+    return nil, errors.New("MPow(): Invalid exponent!")
+  }
+  return mPow(matrix, p)
+}
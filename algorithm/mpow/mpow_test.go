@@ -0,0 +1,107 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mpow
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+import . "github.com/pbenner/autodiff/simple"
+
+/* -------------------------------------------------------------------------- */
+
+func TestMPow1(t *testing.T) {
+  a := NewMatrix(RealType, 3, 3, []float64{
+    2.0, 1.0, 0.0,
+    0.5, 3.0, 1.0,
+    0.0, 1.0, 4.0 })
+  // A^5
+  r := a.CloneMatrix()
+  for i := 1; i < 5; i++ {
+    r = MdotM(r, a)
+  }
+  if x, err := Run(a, 5); err != nil {
+    t.Error(err)
+  } else if Mnorm(MsubM(x, r)).GetValue() > 1e-16 {
+    t.Error("test failed")
+  }
+  // A^-2 A^2 = I
+  x1, err1 := Run(a, -2)
+  x2, err2 := Run(a,  2)
+  if err1 != nil || err2 != nil {
+    t.Error("test failed")
+  } else if Mnorm(MsubM(MdotM(x1, x2), IdentityMatrix(RealType, 3))).GetValue() > 1e-20 {
+    t.Error("test failed")
+  }
+  // A^0 = I
+  if x, err := Run(a, 0); err != nil {
+    t.Error(err)
+  } else if Mnorm(MsubM(x, IdentityMatrix(RealType, 3))).GetValue() > 0.0 {
+    t.Error("test failed")
+  }
+}
+
+func TestMPow2(t *testing.T) {
+  a := NewMatrix(RealType, 2, 2, []float64{4, 1, 2, 3})
+  // (A^1/3)^3 = A
+  x, err := Run(a, 1.0/3.0)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  if Mnorm(MsubM(MdotM(MdotM(x, x), x), a)).GetValue() > 1e-20 {
+    t.Error("test failed")
+  }
+  // A^1.5 = A A^0.5
+  x1, err1 := Run(a, 1.5)
+  x2, err2 := Run(a, 0.5)
+  if err1 != nil || err2 != nil {
+    t.Error("test failed")
+  } else if Mnorm(MsubM(x1, MdotM(a, x2))).GetValue() > 1e-20 {
+    t.Error("test failed")
+  }
+}
+
+func TestMPow3(t *testing.T) {
+  // d/ds A(s)^3 = A'(s) A^2 + A A'(s) A + A^2 A'(s)
+  s := NewReal(0.5)
+  Variables(1, s)
+  a := NullMatrix(RealType, 2, 2)
+  a.At(0, 0).SetValue(1.0)
+  a.At(0, 1).Set(s)
+  a.At(1, 0).Mul(s, s)
+  a.At(1, 1).SetValue(2.0)
+
+  x, err := Run(a, 3)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  b := NewMatrix(RealType, 2, 2, []float64{1.0, 0.5, 0.25, 2.0})
+  d := NewMatrix(RealType, 2, 2, []float64{0.0, 1.0, 1.0, 0.0})
+  r := MaddM(MaddM(MdotM(d, MdotM(b, b)), MdotM(b, MdotM(d, b))), MdotM(MdotM(b, b), d))
+  for i := 0; i < 2; i++ {
+    for j := 0; j < 2; j++ {
+      if math.Abs(x.At(i, j).GetDerivative(0) - r.At(i, j).GetValue()) > 1e-12 {
+        t.Error("test failed")
+      }
+    }
+  }
+}
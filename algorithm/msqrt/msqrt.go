@@ -31,11 +31,26 @@ import   "github.com/pbenner/autodiff/algorithm/matrixInverse"
 // Higham, N.~J. (2008). Functions of Matrices: Theory and Computation;
 // Society for Industrial and Applied Mathematics, Philadelphia, PA, USA.
 
-func mSqrt(matrix Matrix) (Matrix, error) {
+// The iteration stops as soon as the squared Frobenius norm of the
+// difference between two successive iterates is smaller than Epsilon times
+// the squared Frobenius norm of the current iterate.
+type Epsilon struct {
+  Value float64
+}
+
+// Maximum number of iterations before an error is returned.
+type MaxIterations struct {
+  Value int
+}
+
+/* -------------------------------------------------------------------------- */
+
+func mSqrt(matrix Matrix, epsilon float64, maxIterations int) (Matrix, error) {
   n, _ := matrix.Dims()
   c  := NewScalar(matrix.ElementType(), 0.5)
   t0 := NewScalar(matrix.ElementType(), 0.0)
-  Y0 := matrix
+  t3 := NewScalar(matrix.ElementType(), 0.0)
+  Y0 := matrix.CloneMatrix()
   Z0 := IdentityMatrix(matrix.ElementType(), n)
   t1, err := matrixInverse.Run(Z0)
   if err != nil {
@@ -50,7 +65,10 @@ func mSqrt(matrix Matrix) (Matrix, error) {
   Y1.MmulS(Y1.MaddM(Y0, t1), c)
   Z1 := Z0.CloneMatrix()
   Z1.MmulS(Z1.MaddM(Z0, t2), c)
-  for t0.Mnorm(S.MsubM(Y0, Y1)).GetValue() > 1e-8 {
+  for i := 1; t0.Mnorm(S.MsubM(Y0, Y1)).GetValue() > epsilon*t3.Mnorm(Y1).GetValue(); i++ {
+    if i >= maxIterations {
+      return nil, errors.New("MSqrt(): Maximum number of iterations reached!")
+    }
     Y0, Y1 = Y1, Y0
     Z0, Z1 = Z1, Z0
     t1, err := matrixInverse.Run(Z0)
@@ -70,6 +88,18 @@ func mSqrt(matrix Matrix) (Matrix, error) {
 /* -------------------------------------------------------------------------- */
 
 func Run(matrix Matrix, args ...interface{}) (Matrix, error) {
+  epsilon       := 1e-8
+  maxIterations := 100
+  for _, arg := range args {
+    switch tmp := arg.(type) {
+    case Epsilon:
+      epsilon = tmp.Value
+    case MaxIterations:
+      maxIterations = tmp.Value
+    default:
+      panic("MSqrt(): Invalid optional argument!")
+    }
+  }
   rows, cols := matrix.Dims()
   if rows != cols {
     return nil, errors.New("MSqrt(): Not a square matrix!")
@@ -77,5 +107,5 @@ func Run(matrix Matrix, args ...interface{}) (Matrix, error) {
   if rows == 0 {
     return nil, errors.New("MSqrt(): Empty matrix!")
   }
-  return mSqrt(matrix)
+  return mSqrt(matrix, epsilon, maxIterations)
 }
@@ -34,3 +34,23 @@ func TestMSqrt(t *testing.T) {
     t.Error("MSqrt failed!")
   }
 }
+
+func TestMSqrt2(t *testing.T) {
+  n := 3
+  a := NewMatrix(RealType, n, n, []float64{4, 1, 0, 1, 3, 1, 0, 1, 2})
+  b := a.CloneMatrix()
+  x, err := Run(a, Epsilon{1e-20})
+  if err != nil {
+    t.Error(err); return
+  }
+  // argument is not modified
+  if Mnorm(MsubM(a, b)).GetValue() != 0.0 {
+    t.Error("test failed")
+  }
+  if Mnorm(MsubM(MdotM(x, x), a)).GetValue() > 1e-20 {
+    t.Error("test failed")
+  }
+  if _, err := Run(a, MaxIterations{1}); err == nil {
+    t.Error("test failed")
+  }
+}
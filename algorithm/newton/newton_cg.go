@@ -0,0 +1,262 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package newton
+
+/* -------------------------------------------------------------------------- */
+
+import   "fmt"
+import   "math"
+
+import . "github.com/pbenner/autodiff"
+import   "github.com/pbenner/autodiff/algorithm/lineSearch"
+
+/* -------------------------------------------------------------------------- */
+
+type objective_hvp func(x, v Vector, g, Hv Vector) (Scalar, error)
+
+/* Newton-CG (truncated Newton) method for optimization
+ * -------------------------------------------------------------------------- */
+
+// Approximately solve H s = g with conjugate gradients, where only
+// Hessian-vector products are available. The iteration stops early
+// if a direction of negative curvature is detected [Nocedal and Wright,
+// Numerical Optimization, Algorithm 7.1]. The vector v is used as
+// temporary memory.
+func newton_cg_direction(f objective_hvp, x, s, g Vector, r, d, Hd, v Vector, t Scalar) error {
+  n := x.Dim()
+  // tolerance of the inner iteration
+  gnorm := t.Vnorm(g).GetValue()
+  eta   := math.Min(0.5, math.Sqrt(gnorm))*gnorm
+  s.Reset()
+  r.Set(g)
+  d.Set(g)
+  rr := t.VdotV(r, r).GetValue()
+  for j := 0; j < n; j++ {
+    if _, err := f(x, d, v, Hd); err != nil {
+      return err
+    }
+    dHd := t.VdotV(d, Hd).GetValue()
+    if dHd <= 0.0 {
+      if j == 0 {
+        // use steepest descent
+        s.Set(g)
+      }
+      return nil
+    }
+    alpha := NewBareReal(rr/dHd)
+    // s = s + alpha d
+    v.VmulS(d, alpha)
+    s.VaddV(s, v)
+    // r = r - alpha H d
+    Hd.VmulS(Hd, alpha)
+    r.VsubV(r, Hd)
+    if t.Vnorm(r).GetValue() < eta {
+      return nil
+    }
+    rr_new := t.VdotV(r, r).GetValue()
+    // d = r + beta d
+    d.VmulS(d, NewBareReal(rr_new/rr))
+    d.VaddV(d, r)
+    rr = rr_new
+  }
+  return nil
+}
+
+// nomenclature:
+// f(x) = y
+// g: gradient
+// s: approximate Newton step
+func newton_min_cg(
+  f objective_hvp,
+  x Vector,
+  getPhi func(x, p Vector) objective_line,
+  epsilon Epsilon,
+  maxIterations MaxIterations,
+  hook HookMin,
+  constraints Constraints,
+  inSitu *InSitu,
+  options []interface{}) (Vector, error) {
+  n  := x.Dim()
+  x1 := x.CloneVector()
+  x2 := x.CloneVector()
+  // allocate temporary memory
+  if inSitu.T1 == nil {
+    inSitu.T1 = NullVector(BareRealType, n)
+  }
+  if inSitu.T2 == nil {
+    inSitu.T2 = NullScalar(BareRealType)
+  }
+  // temporary variables
+  t1 := inSitu.T1
+  t2 := inSitu.T2
+  g  := NullVector(BareRealType, n)
+  r  := NullVector(BareRealType, n)
+  d  := NullVector(BareRealType, n)
+  Hd := NullVector(BareRealType, n)
+  v  := NullVector(BareRealType, n)
+  z  := NullVector(BareRealType, n)
+  s  := NullVector(BareRealType, n)
+
+  // check initial value
+  if constraints.Value != nil && !constraints.Value(x1) {
+    return x1, fmt.Errorf("invalid initial value: %v", x1)
+  }
+  // evaluate objective function
+  y, err := f(x1, z, g, Hd)
+  if err != nil {
+    return nil, err
+  }
+  y1 := y.CloneScalar()
+  // constraints function for the line search algorithm
+  var constraints_line func(alpha Scalar) bool
+
+  if constraints.Value != nil {
+    constraints_line = func(alpha Scalar) bool {
+      // do not modify the search direction t1
+      s.VmulS(t1, alpha)
+      x2.VsubV(x1, s)
+      return constraints.Value(x2)
+    }
+  }
+
+  for i := 0; i < maxIterations.Value; i++ {
+    // execute hook if available
+    if hook.Value != nil && hook.Value(x1, g, nil, y1) {
+      break
+    }
+    // evaluate stop criterion
+    t2.Vnorm(g)
+    if t2.GetValue() < epsilon.Value {
+      break
+    }
+    if math.IsNaN(t2.GetValue()) {
+      return x1, fmt.Errorf("NaN value detected")
+    }
+    if err := newton_cg_direction(f, x1, t1, g, r, d, Hd, v, t2); err != nil {
+      return nil, err
+    }
+    // get line search objective function
+    phi := getPhi(x1, t1)
+    // execute line search and update x
+    if alpha, err := lineSearch.Run(phi, BareRealType,
+        lineSearch.Constraints{constraints_line},
+        lineSearch.Parameters {1, 20}); err != nil {
+      return x1, err
+    } else {
+      t1.VmulS(t1, alpha)
+      x2.VsubV(x1, t1)
+    }
+    // evaluate objective function
+    y, err = f(x2, z, g, Hd)
+    if err != nil {
+      return nil, err
+    }
+    y1.Set(y)
+    x1, x2 = x2, x1
+  }
+  return x1, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+func run_min_cg(f objective_hvp, x Vector, getPhi func(x, p Vector) objective_line, args ...interface{}) (Vector, error) {
+
+  hook          := HookMin      {nil}
+  epsilon       := Epsilon      {1e-8}
+  constraints   := Constraints  {nil}
+  maxIterations := MaxIterations{int(^uint(0) >> 1)}
+  inSitu        := &InSitu      {}
+  options       := make([]interface{}, 0)
+
+  for _, arg := range args {
+    switch a := arg.(type) {
+    case HookMin:
+      hook = a
+    case Epsilon:
+      epsilon = a
+    case Constraints:
+      constraints = a
+    case MaxIterations:
+      maxIterations = a
+    case *InSitu:
+      inSitu = a
+    case InSitu:
+      panic("InSitu must be passed by reference")
+    default:
+      options = append(options, a)
+    }
+  }
+
+  return newton_min_cg(f, x, getPhi, epsilon, maxIterations, hook, constraints, inSitu, options)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Minimize f with the Newton-CG (truncated Newton) method. Newton steps
+// are computed with conjugate gradients from Hessian-vector products, which
+// are obtained by evaluating f on DualReal scalars. The Hessian is never
+// computed explicitly. The Hessian matrix passed to the hook is always nil.
+// The function f must not mix its argument with scalars of other types that
+// carry derivatives.
+func RunMinCG(f_ func(Vector) (Scalar, error), x Vector, args ...interface{}) (Vector, error) {
+
+  n := x.Dim()
+  y := NullBareReal()
+  // copy of x for computing derivatives
+  X := NullDenseDualRealVector(n)
+  // copy of x for line search
+  Z := x.CloneVector()
+  P := x.CloneVector()
+  // objective function, computes the gradient g and the
+  // Hessian-vector product Hv
+  f := func(x, v Vector, g, Hv Vector) (Scalar, error) {
+    X.Set(x)
+    if err := X.Variables(1); err != nil {
+      return nil, err
+    }
+    for i := 0; i < n; i++ {
+      X.AT(i).SetTangent(v.ValueAt(i))
+    }
+    // evaluate objective function
+    Y, err := f_(X)
+    if err != nil {
+      return nil, err
+    }
+    r, ok := Y.(*DualReal)
+    if !ok {
+      return nil, fmt.Errorf("objective function must return a scalar of type `%v'", DualRealType)
+    }
+    // copy function value to y
+    y.SetValue(r.GetValue())
+    // copy derivatives to g and Hv
+    for i := 0; i < n; i++ {
+      g .At(i).SetValue(r.GetDerivative(i))
+      Hv.At(i).SetValue(r.GetTangentDerivative(i))
+    }
+    return y, nil
+  }
+  // objective function for line-search
+  getPhi := func(x, p Vector) objective_line {
+    phi := func(alpha Scalar) (Scalar, error) {
+      P.VmulS(p, alpha)
+      Z.VsubV(x, P)
+      return f_(Z)
+    }
+    return phi
+  }
+  return run_min_cg(f, x, getPhi, args...)
+}
@@ -90,3 +90,49 @@ func TestNewtonCrit2(t *testing.T) {
     }
   }
 }
+
+func TestNewtonMinCG(t *testing.T) {
+  f := func(x Vector) (Scalar, error) {
+    // f(x1, x2) = (x1 - a)^2 + b(x2 - x1^2)^2
+    // a = 1
+    // b = 100
+    // minimum: (x1,x2) = (a, a^2)
+    s := Pow(Sub(x.At(0), NewReal(1.0)), NewReal(2.0))
+    t := Mul(Pow(Sub(x.At(1), Mul(x.At(0), x.At(0))), NewReal(2.0)), NewReal(100.0))
+    return Add(s, t), nil
+  }
+  v1 := NewVector(RealType, []float64{-0.5, 2})
+  v2 := NewVector(RealType, []float64{   1, 1})
+  v3, err := RunMinCG(f, v1, Epsilon{1e-10})
+  if err != nil {
+    t.Error(err)
+  } else {
+    if Vnorm(VsubV(v2, v3)).GetValue() > 1e-6  {
+      t.Error("Newton method failed!")
+    }
+  }
+}
+
+func TestNewtonMinCGConstraints(t *testing.T) {
+  f := func(x Vector) (Scalar, error) {
+    // f(x1, x2) = (x1 - 1)^2 + (x2 - 1)^2
+    s := Pow(Sub(x.At(0), NewReal(1.0)), NewReal(2.0))
+    t := Pow(Sub(x.At(1), NewReal(1.0)), NewReal(2.0))
+    return Add(s, t), nil
+  }
+  // the full Newton step to (1, 1) violates the constraint, so that the
+  // line search must halve the step size
+  c := func(x Vector) bool {
+    return x.ValueAt(0) < 0.75
+  }
+  v1 := NewVector(RealType, []float64{  0,   0})
+  v2 := NewVector(RealType, []float64{0.5, 0.5})
+  v3, err := RunMinCG(f, v1, Constraints{c}, MaxIterations{1})
+  if err != nil {
+    t.Error(err)
+  } else {
+    if Vnorm(VsubV(v2, v3)).GetValue() > 1e-8  {
+      t.Error("Newton method failed!")
+    }
+  }
+}
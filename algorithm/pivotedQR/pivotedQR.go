@@ -0,0 +1,166 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package pivotedQR
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "errors"
+import   "math"
+
+import . "github.com/pbenner/autodiff"
+import   "github.com/pbenner/autodiff/algorithm/householder"
+
+/* -------------------------------------------------------------------------- */
+
+// Reference:
+// Businger, P., & Golub, G. H. (1965). Linear least squares solutions by
+// Householder transformations. Numerische Mathematik, 7(3), 269-276.
+//
+// Golub, G. H., & Van Loan, C. F. (2013). Matrix Computations (4th ed.),
+// Section 5.4.2.
+
+/* -------------------------------------------------------------------------- */
+
+// Diagonal elements of R with |R_ii| <= Epsilon |R_00| are considered to be
+// zero when estimating the numerical rank. The default is max(m, n) times
+// the machine epsilon.
+type Epsilon struct {
+  Value float64
+}
+
+type ComputeQ struct {
+  Value bool
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Squared norm of column j of a restricted to rows k to m-1.
+func columnNorm(a Matrix, j, k, m int) float64 {
+  r := 0.0
+  for i := k; i < m; i++ {
+    v := a.At(i, j).GetValue()
+    r += v*v
+  }
+  return r
+}
+
+func pivotedQR(a Matrix, computeQ bool, epsilon float64) (Matrix, Matrix, []int, int, error) {
+  m, n := a.Dims()
+  p    := m
+  if n < p {
+    p = n
+  }
+  t    := a.ElementType()
+  r    := a.CloneMatrix()
+  x    := NullVector(t, m)
+  t4   := NullVector(t, n)
+  t1   := NullScalar(t)
+  t2   := NullScalar(t)
+  t3   := NullScalar(t)
+  perm := make([]int, n)
+  for j := 0; j < n; j++ {
+    perm[j] = j
+  }
+  // Householder vectors and coefficients
+  nu   := make([]Vector, p)
+  beta := make([]Scalar, p)
+  for k := 0; k < p; k++ {
+    // select column with largest remaining norm
+    jmax := k
+    cmax := columnNorm(r, k, k, m)
+    for j := k+1; j < n; j++ {
+      if c := columnNorm(r, j, k, m); c > cmax {
+        jmax, cmax = j, c
+      }
+    }
+    if jmax != k {
+      r.SwapColumns(k, jmax)
+      perm[k], perm[jmax] = perm[jmax], perm[k]
+    }
+    nu  [k] = NullVector(t, m-k)
+    beta[k] = NullScalar(t)
+    for i := k; i < m; i++ {
+      x.At(i).Set(r.At(i, k))
+    }
+    householder.Run(x.Slice(k,m), beta[k], nu[k], t1, t2, t3)
+    householder.ApplyLeft(r.Slice(k,m,k,n), beta[k], nu[k], t4.Slice(k,n), t1)
+    for i := k+1; i < m; i++ {
+      r.At(i, k).SetValue(0.0)
+    }
+  }
+  // numerical rank
+  rank := 0
+  if p > 0 {
+    r00 := math.Abs(r.At(0, 0).GetValue())
+    for k := 0; k < p; k++ {
+      if math.Abs(r.At(k, k).GetValue()) > epsilon*r00 {
+        rank++
+      }
+    }
+  }
+  R := NullMatrix(t, p, n)
+  R.Set(r.Slice(0,p,0,n))
+  if !computeQ {
+    return nil, R, perm, rank, nil
+  }
+  // accumulate Q = H_0 H_1 ... H_{p-1} in backward order
+  q  := NullMatrix(t, m, p)
+  t5 := NullVector(t, p)
+  for i := 0; i < p; i++ {
+    q.At(i, i).SetValue(1.0)
+  }
+  for k := p-1; k >= 0; k-- {
+    householder.ApplyLeft(q.Slice(k,m,k,p), beta[k], nu[k], t5.Slice(k,p), t1)
+  }
+  return q, R, perm, rank, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the QR decomposition A P = Q R with column pivoting, where Q is
+// an m x p matrix with orthonormal columns, R is a p x n upper triangular
+// matrix and p = min(m, n). Columns are permuted such that the magnitudes
+// of the diagonal elements of R are non-increasing. The permutation is
+// returned as a slice perm, where column j of A P is column perm[j] of A,
+// i.e. A.PermuteColumns(perm) yields A P. The last return value is the
+// numerical rank of A, where columns perm[rank:] of A are numerically
+// linear combinations of the columns perm[:rank].
+func Run(a Matrix, args ...interface{}) (Matrix, Matrix, []int, int, error) {
+  m, n := a.Dims()
+  if m == 0 || n == 0 {
+    return nil, nil, nil, 0, errors.New("PivotedQR(): Empty matrix!")
+  }
+  computeQ := true
+  epsilon  := float64(m)*2.220446049250313e-16
+  if n > m {
+    epsilon = float64(n)*2.220446049250313e-16
+  }
+  // loop over optional arguments
+  for _, arg := range args {
+    switch tmp := arg.(type) {
+    case ComputeQ:
+      computeQ = tmp.Value
+    case Epsilon:
+      epsilon = tmp.Value
+    default:
+      panic("PivotedQR(): Invalid optional argument!")
+    }
+  }
+  return pivotedQR(a, computeQ, epsilon)
+}
@@ -0,0 +1,126 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package pivotedQR
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+import . "github.com/pbenner/autodiff/simple"
+
+/* -------------------------------------------------------------------------- */
+
+func checkQR(a, q, r Matrix, perm []int, t *testing.T) {
+  m, n := a.Dims()
+  p, _ := r.Dims()
+  // A P = Q R
+  b := a.CloneMatrix()
+  if err := b.PermuteColumns(perm); err != nil {
+    t.Error(err)
+    return
+  }
+  if !MdotM(q, r).Equals(b, 1e-10) {
+    t.Error("test failed")
+  }
+  // Q has orthonormal columns
+  if !MdotM(q.T(), q).Equals(IdentityMatrix(a.ElementType(), p), 1e-10) {
+    t.Error("test failed")
+  }
+  // R is upper triangular with non-increasing diagonal
+  for i := 0; i < p; i++ {
+    for j := 0; j < i && j < n; j++ {
+      if r.At(i, j).GetValue() != 0.0 {
+        t.Error("test failed")
+      }
+    }
+    if i > 0 && math.Abs(r.At(i, i).GetValue()) > math.Abs(r.At(i-1, i-1).GetValue()) + 1e-10 {
+      t.Error("test failed")
+    }
+  }
+  if q1, q2 := q.Dims(); q1 != m || q2 != p {
+    t.Error("test failed")
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+func Test1(t *testing.T) {
+  a := NewMatrix(RealType, 5, 3, []float64{
+    1, 2, 0,
+    0, 1, 4,
+    3, 1, 1,
+    1, 0, 2,
+    2, 5, 1 })
+  q, r, perm, rank, err := Run(a)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  checkQR(a, q, r, perm, t)
+  if rank != 3 {
+    t.Error("test failed")
+  }
+  // the second column has the largest norm
+  if perm[0] != 1 {
+    t.Error("test failed")
+  }
+}
+
+func Test2(t *testing.T) {
+  // the third column is the sum of the first two columns
+  a := NewMatrix(BareRealType, 4, 3, []float64{
+    1, 2, 3,
+    0, 1, 1,
+    3, 1, 4,
+    1, 0, 1 })
+  q, r, perm, rank, err := Run(a)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  checkQR(a, q, r, perm, t)
+  if rank != 2 {
+    t.Error("test failed")
+  }
+  if math.Abs(r.At(2, 2).GetValue()) > 1e-10 {
+    t.Error("test failed")
+  }
+  // a larger tolerance ignores the second direction
+  if _, _, _, rank, _ := Run(a, Epsilon{0.9}, ComputeQ{false}); rank != 1 {
+    t.Error("test failed")
+  }
+}
+
+func Test3(t *testing.T) {
+  // wide matrix
+  a := NewMatrix(RealType, 2, 4, []float64{
+    1, 2, 0, 4,
+    0, 1, 3, 1 })
+  q, r, perm, rank, err := Run(a)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  checkQR(a, q, r, perm, t)
+  if rank != 2 || perm[0] != 3 {
+    t.Error("test failed")
+  }
+}
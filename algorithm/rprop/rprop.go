@@ -61,9 +61,17 @@ func rprop(f func(Vector) (Scalar, error), x0 ConstVector, step_init float64 , e
 
   n := x0.Dim()
   t := x0.ElementType()
+  // scalar type used for computing derivatives, BareReal
+  // cannot carry derivatives
+  v := t
+  if v == BareRealType {
+    v = RealType
+  }
   // copy variables
-  x1 := AsDenseRealVector(x0)
-  x2 := AsDenseRealVector(x0)
+  x1 := NullVector(v, n)
+  x2 := NullVector(v, n)
+  x1.Set(x0)
+  x2.Set(x0)
   // step size for each variable
   step := make([]float64, n)
   // gradients
@@ -156,6 +164,11 @@ func rprop(f func(Vector) (Scalar, error), x0 ConstVector, step_init float64 , e
       }
     }
     x1.Set(x2)
+    // x1 depends on the variables of the previous iteration, reset
+    // them so that the history does not accumulate
+    if err := x1.Variables(1); err != nil {
+      return nil, err
+    }
   }
   return x1, nil
 }
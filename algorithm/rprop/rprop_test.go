@@ -120,3 +120,29 @@ func TestRPropRosenbrockGradient(t *testing.T) {
     t.Error("Rosenbrock test failed!")
   }
 }
+
+/* -------------------------------------------------------------------------- */
+
+func TestRPropRosenbrockReverse(t *testing.T) {
+
+  f := func(x Vector) (Scalar, error) {
+    // f(x1, x2) = (a - x1)^2 + b(x2 - x1^2)^2
+    // a = 1
+    // b = 100
+    // minimum: (x1,x2) = (a, a^2)
+    a := NewReverseReal(  1.0)
+    b := NewReverseReal(100.0)
+    s := Pow(Sub(a, x.At(0)), NewReverseReal(2.0))
+    t := Mul(b, Pow(Sub(x.At(1), Mul(x.At(0), x.At(0))), NewReverseReal(2.0)))
+    return Add(s, t), nil
+  }
+
+  x0 := NewVector(ReverseRealType, []float64{-10,10})
+  xr := NewVector(ReverseRealType, []float64{  1, 1})
+  xn, _ := Run(f, x0, 0.01, []float64{1.2, 0.8},
+    Epsilon{1e-10})
+
+  if Vnorm(xr.VsubV(xr, xn)).GetValue() > 1e-8 {
+    t.Error("Rosenbrock test failed!")
+  }
+}
@@ -0,0 +1,207 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sparseCholesky
+
+/* -------------------------------------------------------------------------- */
+
+import   "container/heap"
+import   "sort"
+
+/* -------------------------------------------------------------------------- */
+
+type degreeItem struct {
+  degree int
+  node   int
+}
+
+type degreeHeap []degreeItem
+
+func (h degreeHeap) Len() int {
+  return len(h)
+}
+
+func (h degreeHeap) Less(i, j int) bool {
+  if h[i].degree != h[j].degree {
+    return h[i].degree < h[j].degree
+  }
+  return h[i].node < h[j].node
+}
+
+func (h degreeHeap) Swap(i, j int) {
+  h[i], h[j] = h[j], h[i]
+}
+
+func (h *degreeHeap) Push(x interface{}) {
+  *h = append(*h, x.(degreeItem))
+}
+
+func (h *degreeHeap) Pop() interface{} {
+  n    := len(*h)
+  item := (*h)[n-1]
+  *h    = (*h)[0:n-1]
+  return item
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute a fill-reducing ordering with the approximate minimum degree
+// heuristic. The elimination graph is represented implicitly by a quotient
+// graph, where each eliminated node becomes an element that stores the
+// clique formed by its neighbors. Elements adjacent to the pivot are
+// absorbed into the new element, and elements that are contained in the new
+// element are absorbed aggressively. Instead of exact external degrees, the
+// upper bounds of Amestoy, Davis and Duff are used. Ties are broken by node
+// index. Returns the permutation p, where p[k] is the k-th node to be
+// eliminated.
+//
+// Reference:
+// Amestoy, Patrick R., Timothy A. Davis, and Iain S. Duff. "An approximate
+// minimum degree ordering algorithm." SIAM Journal on Matrix Analysis and
+// Applications 17.4 (1996): 886-905.
+func approximateMinimumDegree(adj [][]int) []int {
+  n := len(adj)
+  // variables adjacent to variable i
+  a := make([][]int, n)
+  // elements adjacent to variable i
+  e := make([][]int, n)
+  // variables contained in element i
+  l := make([][]int, n)
+  // approximate external degrees
+  d := make([]int, n)
+  // |L_e \ L_p| for elements adjacent to the current pivot p
+  w := make([]int, n)
+  // eliminated[i]: node i is an element
+  // absorbed[i]  : element i was absorbed by another element
+  eliminated := make([]bool, n)
+  absorbed   := make([]bool, n)
+  // marks for set operations
+  mark  := make([]int, n)
+  wmark := make([]int, n)
+  tag   := 0
+
+  h := make(degreeHeap, n)
+  for i := 0; i < n; i++ {
+    tag++
+    mark[i] = tag
+    for _, j := range adj[i] {
+      if mark[j] != tag {
+        mark[j] = tag
+        a[i]    = append(a[i], j)
+      }
+    }
+    d[i] = len(a[i])
+    h[i] = degreeItem{d[i], i}
+  }
+  heap.Init(&h)
+
+  p := make([]int, 0, n)
+  for h.Len() > 0 {
+    item := heap.Pop(&h).(degreeItem)
+    k    := item.node
+    // skip outdated entries
+    if eliminated[k] || item.degree != d[k] {
+      continue
+    }
+    p = append(p, k)
+    eliminated[k] = true
+    // construct the new element L_k from the adjacent variables and the
+    // variables of all adjacent elements, which are absorbed
+    tag++
+    mark[k] = tag
+    lk := []int{}
+    for _, j := range a[k] {
+      if !eliminated[j] && mark[j] != tag {
+        mark[j] = tag
+        lk      = append(lk, j)
+      }
+    }
+    for _, el := range e[k] {
+      if absorbed[el] {
+        continue
+      }
+      for _, j := range l[el] {
+        if !eliminated[j] && mark[j] != tag {
+          mark[j] = tag
+          lk      = append(lk, j)
+        }
+      }
+      absorbed[el] = true
+      l[el]        = nil
+    }
+    sort.Ints(lk)
+    l[k] = lk
+    a[k] = nil
+    e[k] = nil
+    // compute |L_e \ L_k| for all elements e adjacent to variables in L_k
+    for _, i := range lk {
+      for _, el := range e[i] {
+        if absorbed[el] {
+          continue
+        }
+        if wmark[el] != tag {
+          wmark[el] = tag
+          w[el]     = 0
+          for _, j := range l[el] {
+            if !eliminated[j] {
+              w[el]++
+            }
+          }
+        }
+        w[el]--
+      }
+    }
+    // update variables in L_k
+    remaining := n - len(p)
+    for _, i := range lk {
+      // prune elements, absorb elements that are subsets of L_k
+      ei  := e[i][:0]
+      deg := len(lk) - 1
+      for _, el := range e[i] {
+        if absorbed[el] {
+          continue
+        }
+        if w[el] == 0 {
+          absorbed[el] = true
+          l[el]        = nil
+          continue
+        }
+        ei   = append(ei, el)
+        deg += w[el]
+      }
+      e[i] = append(ei, k)
+      // prune variables that are reachable through element k
+      ai := a[i][:0]
+      for _, j := range a[i] {
+        if !eliminated[j] && mark[j] != tag {
+          ai = append(ai, j)
+        }
+      }
+      a[i] = ai
+      deg += len(ai)
+      // approximate degree
+      if t := d[i] + len(lk) - 1; t < deg {
+        deg = t
+      }
+      if t := remaining - 1; t < deg {
+        deg = t
+      }
+      d[i] = deg
+      heap.Push(&h, degreeItem{d[i], i})
+    }
+  }
+  return p
+}
@@ -0,0 +1,480 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Reference:
+// Davis, Timothy A. Direct methods for sparse linear systems. Society for
+// Industrial and Applied Mathematics, 2006.
+
+/* -------------------------------------------------------------------------- */
+
+package sparseCholesky
+
+/* -------------------------------------------------------------------------- */
+
+import   "fmt"
+import   "math"
+import   "sort"
+
+import . "github.com/pbenner/autodiff"
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the factorization A = L D L^T with unit lower triangular L
+// instead of A = L L^T.
+type LDL struct {
+  Value bool
+}
+
+// Do not reorder rows and columns of A.
+type NaturalOrdering struct {
+  Value bool
+}
+
+// Reuse the result of a symbolic analysis, which requires that the
+// sparsity pattern of A is a subset of the analyzed pattern.
+type Symbolic struct {
+  Value *SymbolicFactor
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Result of the symbolic analysis, which depends only on the sparsity
+// pattern of A. The analysis can be reused for the factorization of all
+// matrices with the same pattern, e.g. across Newton iterations.
+type SymbolicFactor struct {
+  n      int
+  // fill-reducing permutation and its inverse
+  p      []int
+  pinv   []int
+  // elimination tree
+  parent []int
+  // column pointers of L
+  lp     []int
+  // upper triangular part of C = P A P^T in compressed column format,
+  // where ai and aj give the position of each element in A
+  cp     []int
+  ci     []int
+  ai     []int
+  aj     []int
+}
+
+// Numerical factorization P A P^T = L L^T or P A P^T = L D L^T.
+type Factor struct {
+  symbolic *SymbolicFactor
+  ldl      bool
+  // row indices and values of L in compressed column format, the diagonal
+  // element is stored first in each column
+  li       []int
+  lx       Vector
+  d        Vector
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Positions of all non-zero elements of a matrix.
+func nonZeros(a ConstMatrix) ([]int, []int) {
+  n, m := a.Dims()
+  rows := []int{}
+  cols := []int{}
+  switch a.(type) {
+  case CSRConstRealMatrix, CSCConstRealMatrix, *SparseRealMatrix, *SparseBareRealMatrix:
+    for it := a.ConstIterator(); it.Ok(); it.Next() {
+      i, j := it.Index()
+      // skip elements outside of slices
+      if i < 0 || i >= n || j < 0 || j >= m {
+        continue
+      }
+      if it.GetValue() != 0.0 {
+        rows = append(rows, i)
+        cols = append(cols, j)
+      }
+    }
+  default:
+    for i := 0; i < n; i++ {
+      for j := 0; j < m; j++ {
+        if a.ValueAt(i, j) != 0.0 {
+          rows = append(rows, i)
+          cols = append(cols, j)
+        }
+      }
+    }
+  }
+  return rows, cols
+}
+
+// Compute the pattern of row k of L, which is given by the reach of the
+// non-zero elements in column k of C in the elimination tree. The pattern
+// is stored in s[top:n] in topological order.
+func ereach(s *SymbolicFactor, k int, stack, w []int, mark int) int {
+  top := s.n
+  w[k] = mark
+  for q := s.cp[k]; q < s.cp[k+1]; q++ {
+    i := s.ci[q]
+    if i > k {
+      continue
+    }
+    // traverse up the elimination tree
+    l := 0
+    for ; w[i] != mark; i = s.parent[i] {
+      stack[l] = i; l++
+      w[i] = mark
+    }
+    // push path onto stack
+    for l > 0 {
+      l--; top--
+      stack[top] = stack[l]
+    }
+  }
+  return top
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Symbolic analysis of a symmetric matrix A, which computes a fill-reducing
+// ordering and the sparsity pattern of the Cholesky factor.
+func Analyze(a ConstMatrix, args ...interface{}) (*SymbolicFactor, error) {
+  n, m := a.Dims()
+  if n != m {
+    return nil, fmt.Errorf("matrix is not square")
+  }
+  natural := false
+  for _, arg := range args {
+    switch tmp := arg.(type) {
+    case NaturalOrdering:
+      natural = tmp.Value
+    case LDL, Symbolic:
+    default:
+      panic("Analyze(): Invalid optional argument!")
+    }
+  }
+  rows, cols := nonZeros(a)
+  // adjacency lists of the graph of A
+  adj := make([][]int, n)
+  for k := 0; k < len(rows); k++ {
+    if i, j := rows[k], cols[k]; i != j {
+      adj[i] = append(adj[i], j)
+      adj[j] = append(adj[j], i)
+    }
+  }
+  s := SymbolicFactor{n: n}
+  if natural {
+    s.p = make([]int, n)
+    for i := 0; i < n; i++ {
+      s.p[i] = i
+    }
+  } else {
+    s.p = approximateMinimumDegree(adj)
+  }
+  s.pinv = make([]int, n)
+  for k, i := range s.p {
+    s.pinv[i] = k
+  }
+  // upper triangular part of C = P A P^T, duplicates (i.e. elements
+  // in the upper and lower triangular part of A) are stored only once
+  type entry struct {
+    i, j   int
+    ai, aj int
+  }
+  entries := make([]entry, 0, len(rows))
+  for k := 0; k < len(rows); k++ {
+    i, j := s.pinv[rows[k]], s.pinv[cols[k]]
+    if i > j {
+      i, j = j, i
+    }
+    entries = append(entries, entry{i, j, rows[k], cols[k]})
+  }
+  sort.SliceStable(entries, func(k1, k2 int) bool {
+    if entries[k1].j != entries[k2].j {
+      return entries[k1].j < entries[k2].j
+    }
+    return entries[k1].i < entries[k2].i
+  })
+  s.cp = make([]int, n+1)
+  for k, e := range entries {
+    if k > 0 && entries[k-1].i == e.i && entries[k-1].j == e.j {
+      continue
+    }
+    s.ci = append(s.ci, e.i)
+    s.ai = append(s.ai, e.ai)
+    s.aj = append(s.aj, e.aj)
+    s.cp[e.j+1]++
+  }
+  for j := 0; j < n; j++ {
+    s.cp[j+1] += s.cp[j]
+  }
+  // elimination tree
+  s.parent   = make([]int, n)
+  ancestor  := make([]int, n)
+  for k := 0; k < n; k++ {
+    s.parent[k] = -1
+    ancestor[k] = -1
+    for q := s.cp[k]; q < s.cp[k+1]; q++ {
+      // traverse from i to the root, compress path
+      for i := s.ci[q]; i != -1 && i < k; {
+        next := ancestor[i]
+        ancestor[i] = k
+        if next == -1 {
+          s.parent[i] = k
+        }
+        i = next
+      }
+    }
+  }
+  // column counts of L
+  stack  := make([]int, n)
+  w      := make([]int, n)
+  counts := make([]int, n)
+  for k := 0; k < n; k++ {
+    w[k] = -1
+  }
+  for k := 0; k < n; k++ {
+    top := ereach(&s, k, stack, w, k)
+    for _, i := range stack[top:n] {
+      counts[i]++
+    }
+    counts[k]++
+  }
+  s.lp = make([]int, n+1)
+  for j := 0; j < n; j++ {
+    s.lp[j+1] = s.lp[j] + counts[j]
+  }
+  return &s, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Numerical factorization of the symmetric matrix A. A symbolic analysis
+// is performed unless it is given as optional argument.
+func Run(a ConstMatrix, args ...interface{}) (*Factor, error) {
+  n, m := a.Dims()
+  if n != m {
+    return nil, fmt.Errorf("matrix is not square")
+  }
+  var s *SymbolicFactor
+  ldl := false
+  for _, arg := range args {
+    switch tmp := arg.(type) {
+    case LDL:
+      ldl = tmp.Value
+    case Symbolic:
+      s = tmp.Value
+    case NaturalOrdering:
+    default:
+      panic("Run(): Invalid optional argument!")
+    }
+  }
+  if s == nil {
+    if r, err := Analyze(a, args...); err != nil {
+      return nil, err
+    } else {
+      s = r
+    }
+  }
+  if s.n != n {
+    return nil, fmt.Errorf("symbolic analysis has invalid dimension")
+  }
+  t  := a.ElementType()
+  f  := Factor{symbolic: s, ldl: ldl}
+  f.li = make([]int, s.lp[n])
+  f.lx = NullVector(t, s.lp[n])
+  if ldl {
+    f.d = NullVector(t, n)
+  }
+  // c[j] is the next free position in column j of L
+  c     := make([]int, n)
+  stack := make([]int, n)
+  w     := make([]int, n)
+  x     := NullVector(t, n)
+  d     := NullScalar(t)
+  lki   := NullScalar(t)
+  t1    := NullScalar(t)
+  copy(c, s.lp[0:n])
+  for k := 0; k < n; k++ {
+    w[k] = -1
+  }
+  for k := 0; k < n; k++ {
+    // pattern of row k of L
+    top := ereach(s, k, stack, w, k)
+    // scatter column k of C into x
+    for q := s.cp[k]; q < s.cp[k+1]; q++ {
+      x.At(s.ci[q]).Set(a.ConstAt(s.ai[q], s.aj[q]))
+    }
+    d.Set(x.At(k))
+    x.At(k).Reset()
+    // solve L(0:k-1,0:k-1) y = C(0:k-1,k)
+    for _, i := range stack[top:n] {
+      // the diagonal element of column i is stored first
+      if ldl {
+        // y_i
+        lki.Set(x.At(i))
+      } else {
+        // l_ki = y_i / l_ii
+        lki.Div(x.At(i), f.lx.At(s.lp[i]))
+      }
+      x.At(i).Reset()
+      for q := s.lp[i]+1; q < c[i]; q++ {
+        t1.Mul(f.lx.At(q), lki)
+        x.At(f.li[q]).Sub(x.At(f.li[q]), t1)
+      }
+      if ldl {
+        // l_ki = y_i / d_i, d_k = d_k - l_ki y_i
+        t1 .Mul(lki, lki)
+        t1 .Div(t1, f.d.At(i))
+        d  .Sub(d, t1)
+        lki.Div(lki, f.d.At(i))
+      } else {
+        t1 .Mul(lki, lki)
+        d  .Sub(d, t1)
+      }
+      q := c[i]; c[i]++
+      f.li[q] = k
+      f.lx.At(q).Set(lki)
+    }
+    q := c[k]; c[k]++
+    f.li[q] = k
+    if ldl {
+      if d.GetValue() == 0.0 || math.IsNaN(d.GetValue()) {
+        return nil, fmt.Errorf("matrix is singular")
+      }
+      f.d.At(k).Set(d)
+      f.lx.At(q).SetValue(1.0)
+    } else {
+      if d.GetValue() <= 0.0 || math.IsNaN(d.GetValue()) {
+        return nil, fmt.Errorf("matrix is not positive definite")
+      }
+      f.lx.At(q).Sqrt(d)
+    }
+  }
+  return &f, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Solve the linear system A x = b.
+func (f *Factor) Solve(b ConstVector) (Vector, error) {
+  s := f.symbolic
+  n := s.n
+  if b.Dim() != n {
+    return nil, fmt.Errorf("matrix vector dimensions do not match")
+  }
+  t := f.lx.ElementType()
+  if t == BareRealType {
+    t = b.ElementType()
+  }
+  y  := NullVector(t, n)
+  t1 := NullScalar(t)
+  // y = P b
+  for k := 0; k < n; k++ {
+    y.At(k).Set(b.ConstAt(s.p[k]))
+  }
+  // solve L y = P b
+  for j := 0; j < n; j++ {
+    y.At(j).Div(y.At(j), f.lx.At(s.lp[j]))
+    for q := s.lp[j]+1; q < s.lp[j+1]; q++ {
+      t1.Mul(f.lx.At(q), y.At(j))
+      y.At(f.li[q]).Sub(y.At(f.li[q]), t1)
+    }
+  }
+  if f.ldl {
+    for j := 0; j < n; j++ {
+      y.At(j).Div(y.At(j), f.d.At(j))
+    }
+  }
+  // solve L^T y = y
+  for j := n-1; j >= 0; j-- {
+    for q := s.lp[j]+1; q < s.lp[j+1]; q++ {
+      t1.Mul(f.lx.At(q), y.At(f.li[q]))
+      y.At(j).Sub(y.At(j), t1)
+    }
+    y.At(j).Div(y.At(j), f.lx.At(s.lp[j]))
+  }
+  // x = P^T y
+  x := NullVector(t, n)
+  for k := 0; k < n; k++ {
+    x.At(s.p[k]).Set(y.At(k))
+  }
+  return x, nil
+}
+
+// Logarithm of the absolute value of the determinant of A, which is for
+// instance required to evaluate the density of a Gaussian Markov random
+// field. For LDL factorizations of indefinite matrices the sign of the
+// determinant is returned by DeterminantSign.
+func (f *Factor) LogDeterminant() Scalar {
+  s := f.symbolic
+  r := NullScalar(f.lx.ElementType())
+  t := NullScalar(f.lx.ElementType())
+  for j := 0; j < s.n; j++ {
+    if f.ldl {
+      t.Abs(f.d.At(j))
+      t.Log(t)
+    } else {
+      t.Log(f.lx.At(s.lp[j]))
+      t.Add(t, t)
+    }
+    r.Add(r, t)
+  }
+  return r
+}
+
+// Sign of the determinant of A, which is always positive for Cholesky
+// factorizations.
+func (f *Factor) DeterminantSign() float64 {
+  r := 1.0
+  if f.ldl {
+    for j := 0; j < f.symbolic.n; j++ {
+      if f.d.ValueAt(j) < 0.0 {
+        r = -r
+      }
+    }
+  }
+  return r
+}
+
+// Returns the Cholesky factor L as dense matrix, where rows and columns
+// are permuted according to Permutation().
+func (f *Factor) L() Matrix {
+  s := f.symbolic
+  rows := make([]int, len(f.li))
+  cols := make([]int, len(f.li))
+  for j := 0; j < s.n; j++ {
+    for q := s.lp[j]; q < s.lp[j+1]; q++ {
+      rows[q] = f.li[q]
+      cols[q] = j
+    }
+  }
+  r := NullMatrix(f.lx.ElementType(), s.n, s.n)
+  for q := 0; q < len(rows); q++ {
+    r.At(rows[q], cols[q]).Set(f.lx.At(q))
+  }
+  return r
+}
+
+// Returns the diagonal of D for LDL factorizations.
+func (f *Factor) D() Vector {
+  return f.d
+}
+
+// Returns the fill-reducing permutation p, i.e. row k of L corresponds
+// to row p[k] of A.
+func (f *Factor) Permutation() []int {
+  return f.symbolic.p
+}
+
+// Number of non-zero elements of L.
+func (f *Factor) NNZ() int {
+  return len(f.li)
+}
@@ -0,0 +1,283 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sparseCholesky
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+import . "github.com/pbenner/autodiff/simple"
+import   "github.com/pbenner/autodiff/algorithm/determinant"
+import   "github.com/pbenner/autodiff/algorithm/matrixInverse"
+
+/* -------------------------------------------------------------------------- */
+
+// precision matrix of a Gaussian Markov random field on a n x n grid
+func gridPrecision(n int, tau float64) CSCConstRealMatrix {
+  rows   := []int{}
+  cols   := []int{}
+  values := []float64{}
+  add := func(i, j int, v float64) {
+    rows   = append(rows, i)
+    cols   = append(cols, j)
+    values = append(values, v)
+  }
+  for x := 0; x < n; x++ {
+    for y := 0; y < n; y++ {
+      i := x*n+y
+      add(i, i, tau)
+      if x+1 < n {
+        add(i, i, 1); add(i+n, i+n, 1); add(i, i+n, -1); add(i+n, i, -1)
+      }
+      if y+1 < n {
+        add(i, i, 1); add(i+1, i+1, 1); add(i, i+1, -1); add(i+1, i, -1)
+      }
+    }
+  }
+  return NewCSCConstRealMatrix(n*n, n*n, rows, cols, values)
+}
+
+func vnorm(a Vector) float64 {
+  return math.Sqrt(VdotV(a, a).GetValue())
+}
+
+/* -------------------------------------------------------------------------- */
+
+func TestSparseCholesky1(t *testing.T) {
+  a := gridPrecision(5, 0.5)
+  d := NullMatrix(BareRealType, 25, 25)
+  d.Set(a)
+  b := NullVector(BareRealType, 25)
+  for i := 0; i < 25; i++ {
+    b.At(i).SetValue(float64(i%7) - 3.0)
+  }
+  ainv, _ := matrixInverse.Run(d)
+  r := MdotV(ainv, b)
+
+  for _, ldl := range []bool{false, true} {
+    f, err := Run(a, LDL{ldl})
+    if err != nil {
+      t.Error(err); continue
+    }
+    x, err := f.Solve(b)
+    if err != nil {
+      t.Error(err); continue
+    }
+    if vnorm(VsubV(x, r)) > 1e-10 {
+      t.Error("test failed")
+    }
+    det, _ := determinant.Run(d, determinant.PositiveDefinite{true}, determinant.LogScale{true})
+    if math.Abs(f.LogDeterminant().GetValue() - det.GetValue()) > 1e-8 {
+      t.Error("test failed")
+    }
+  }
+  // P A P^T = L L^T
+  f, _ := Run(a)
+  p    := f.Permutation()
+  l    := f.L()
+  c    := MdotM(l, l.T())
+  for i := 0; i < 25; i++ {
+    for j := 0; j < 25; j++ {
+      if math.Abs(c.At(i, j).GetValue() - a.ValueAt(p[i], p[j])) > 1e-10 {
+        t.Error("test failed")
+      }
+    }
+  }
+}
+
+func TestSparseCholesky2(t *testing.T) {
+  // arrow matrix with a dense first row and column, which leads to a
+  // dense factor with the natural ordering
+  n      := 10
+  rows   := []int{}
+  cols   := []int{}
+  values := []float64{}
+  for i := 0; i < n; i++ {
+    rows   = append(rows, i)
+    cols   = append(cols, i)
+    values = append(values, float64(n))
+    if i > 0 {
+      rows   = append(rows, 0, i)
+      cols   = append(cols, i, 0)
+      values = append(values, 1, 1)
+    }
+  }
+  a := NewCSRConstRealMatrix(n, n, rows, cols, values)
+
+  f1, err1 := Run(a, NaturalOrdering{true})
+  f2, err2 := Run(a)
+  if err1 != nil || err2 != nil {
+    t.Error("test failed"); return
+  }
+  if f1.NNZ() != n*(n+1)/2 {
+    t.Error("test failed")
+  }
+  // no fill-in with approximate minimum degree ordering
+  if f2.NNZ() != 2*n-1 {
+    t.Error("test failed")
+  }
+  b  := NewVector(BareRealType, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+  x1, _ := f1.Solve(b)
+  x2, _ := f2.Solve(b)
+  if vnorm(VsubV(x1, x2)) > 1e-12 {
+    t.Error("test failed")
+  }
+  z := NullVector(BareRealType, n)
+  if vnorm(VsubV(z.MdotV(a, x2), b)) > 1e-12 {
+    t.Error("test failed")
+  }
+}
+
+func TestSparseCholesky3(t *testing.T) {
+  // reuse symbolic analysis
+  a1 := gridPrecision(4, 0.5)
+  a2 := gridPrecision(4, 2.0)
+  s, err := Analyze(a1)
+  if err != nil {
+    t.Error(err); return
+  }
+  b := NullVector(BareRealType, 16)
+  b.At(3).SetValue(1.0)
+  for _, a := range []CSCConstRealMatrix{a1, a2} {
+    f, err := Run(a, Symbolic{s})
+    if err != nil {
+      t.Error(err); continue
+    }
+    x, _ := f.Solve(b)
+    z := NullVector(BareRealType, 16)
+    if vnorm(VsubV(z.MdotV(a, x), b)) > 1e-12 {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestSparseCholesky4(t *testing.T) {
+  // symmetric indefinite matrix
+  a := NewCSRConstRealMatrix(3, 3,
+    []int{0, 0, 1, 1, 1, 2, 2},
+    []int{0, 1, 0, 1, 2, 1, 2},
+    []float64{2, 1, 1, -3, 1, 1, 1})
+  if _, err := Run(a); err == nil {
+    t.Error("test failed")
+  }
+  f, err := Run(a, LDL{true})
+  if err != nil {
+    t.Error(err); return
+  }
+  b := NewVector(BareRealType, []float64{1, 2, 3})
+  x, _ := f.Solve(b)
+  z := NullVector(BareRealType, 3)
+  if vnorm(VsubV(z.MdotV(a, x), b)) > 1e-12 {
+    t.Error("test failed")
+  }
+}
+
+func TestSparseCholesky5(t *testing.T) {
+  // derivatives with respect to the elements of A
+  a := NewSparseRealMatrix(3, 3,
+    []int{0, 0, 1, 1, 1, 2, 2},
+    []int{0, 1, 0, 1, 2, 1, 2},
+    []float64{4, 1, 1, 3, 1, 1, 2})
+  Variables(1, a.At(2, 2))
+
+  f, err := Run(a)
+  if err != nil {
+    t.Error(err); return
+  }
+  // d/dx log det A = (A^-1)_22
+  ainv, _ := matrixInverse.Run(a)
+  if math.Abs(f.LogDeterminant().GetDerivative(0) - ainv.At(2, 2).GetValue()) > 1e-10 {
+    t.Error("test failed")
+  }
+  // d/dx A^-1 b = -A^-1 dA A^-1 b
+  b := NewVector(RealType, []float64{1, 2, 3})
+  x, _ := f.Solve(b)
+  for i := 0; i < 3; i++ {
+    r := -ainv.At(i, 2).GetValue()*x.At(2).GetValue()
+    if math.Abs(x.At(i).GetDerivative(0) - r) > 1e-10 {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestSparseCholesky6(t *testing.T) {
+  // fill-in on a grid
+  a := gridPrecision(12, 0.5)
+  n := 144
+  f1, err1 := Run(a, NaturalOrdering{true})
+  f2, err2 := Run(a)
+  if err1 != nil || err2 != nil {
+    t.Error("test failed"); return
+  }
+  if f2.NNZ() >= f1.NNZ() {
+    t.Error("test failed")
+  }
+  // result must be a permutation
+  m := make([]bool, n)
+  for _, i := range f2.Permutation() {
+    if i < 0 || i >= n || m[i] {
+      t.Error("test failed"); return
+    }
+    m[i] = true
+  }
+  b := NullVector(BareRealType, n)
+  b.At(7).SetValue(1.0)
+  x, _ := f2.Solve(b)
+  z := NullVector(BareRealType, n)
+  if vnorm(VsubV(z.MdotV(a, x), b)) > 1e-12 {
+    t.Error("test failed")
+  }
+}
+
+func TestSparseCholesky7(t *testing.T) {
+  // indefinite matrix with determinant -15
+  a := NewCSCConstRealMatrix(4, 4,
+    []int{0, 1, 0, 1, 2, 3, 2, 3},
+    []int{0, 0, 1, 1, 2, 2, 3, 3},
+    []float64{1, 2, 2, 1, 3, 1, 1, 2})
+  f, err := Run(a, LDL{true})
+  if err != nil {
+    t.Error(err); return
+  }
+  if math.Abs(f.LogDeterminant().GetValue() - math.Log(15.0)) > 1e-10 {
+    t.Error("test failed")
+  }
+  if f.DeterminantSign() != -1.0 {
+    t.Error("test failed")
+  }
+}
+
+func TestOrdering(t *testing.T) {
+  // path graph 0 - 1 - 2 - 3 - 4 with duplicate edges, eliminating nodes
+  // from the ends does not create any fill-in
+  adj := [][]int{{1, 1}, {0, 2, 0}, {1, 3}, {2, 4, 4}, {3}}
+  p   := approximateMinimumDegree(adj)
+  if len(p) != 5 || p[0] != 0 || p[1] != 1 {
+    t.Error("test failed")
+  }
+  // star graph, the center is eliminated last
+  adj = [][]int{{1, 2, 3, 4}, {0}, {0}, {0}, {0}}
+  p   = approximateMinimumDegree(adj)
+  if len(p) != 5 {
+    t.Error("test failed")
+  } else if p[3] != 0 && p[4] != 0 {
+    t.Error("test failed")
+  }
+}
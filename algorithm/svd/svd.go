@@ -41,6 +41,14 @@ type Epsilon struct {
   Value float64
 }
 
+// Compute derivatives of H, U and V with closed-form perturbation formulas
+// instead of propagating them through the Golub-Kahan iterations. The
+// option is ignored if the matrix does not carry first order derivatives.
+// Analytic derivatives require distinct singular values.
+type AnalyticDerivatives struct {
+  Value bool
+}
+
 type InSitu struct {
   HouseholderBidiagonalization householderBidiagonalization.InSitu
   A  Matrix
@@ -207,6 +215,10 @@ func golubKahanSVD(inSitu *InSitu, epsilon float64) (Matrix, Matrix, Matrix, err
 
   H, U, V, _ := householderBidiagonalization.Run(A, computeU, computeV, &inSitu.HouseholderBidiagonalization)
   B := H.Slice(0,n,0,n)
+  // Givens rotations are accumulated in U^T
+  if U != nil {
+    U = U.T()
+  }
 
   for p, q := 0, 0; q < n; {
 
@@ -256,6 +268,7 @@ func Run(a Matrix, args ...interface{}) (Matrix, Matrix, Matrix, error) {
   computeU := false
   computeV := false
   epsilon  := 1.11e-16
+  analytic := false
 
   // loop over optional arguments
   for _, arg := range args {
@@ -266,6 +279,8 @@ func Run(a Matrix, args ...interface{}) (Matrix, Matrix, Matrix, error) {
       computeV = tmp.Value
     case Epsilon:
       epsilon = tmp.Value
+    case AnalyticDerivatives:
+      analytic = tmp.Value
     case *InSitu:
       inSitu = tmp
     case InSitu:
@@ -295,6 +310,9 @@ func Run(a Matrix, args ...interface{}) (Matrix, Matrix, Matrix, error) {
   } else {
     inSitu.V = nil
   }
+  if analytic && HasFirstOrderDerivatives(a) {
+    return svdDerivatives(a, inSitu, epsilon)
+  }
   if inSitu.Mu == nil {
     inSitu.Mu = NullScalar(t)
   }
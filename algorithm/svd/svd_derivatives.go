@@ -0,0 +1,134 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package svd
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "errors"
+import   "math"
+
+import . "github.com/pbenner/autodiff"
+
+/* -------------------------------------------------------------------------- */
+
+// Reference:
+// Townsend, J. (2016). Differentiating the Singular Value Decomposition.
+// Technical Report.
+
+/* -------------------------------------------------------------------------- */
+
+// singular values closer than this (relative to the largest singular value)
+// are considered to be degenerate
+const degeneracyTolerance = 1e-10
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the SVD in BareReal and attach first order derivatives to H, U
+// and V. With dA the derivative of A and P = U^T dA V, the derivatives of
+// the singular values are given by P_ii, and the derivatives of U and V by
+// U Omega_U and V Omega_V, where
+//
+//   (Omega_U)_ij = (s_j P_ij + s_i P_ji) / (s_j^2 - s_i^2)
+//   (Omega_V)_ij = (s_i P_ij + s_j P_ji) / (s_j^2 - s_i^2)
+//
+// for i != j < n, and (Omega_U)_ij = -(Omega_U)_ji = P_ij / s_j for i >= n.
+// The derivative of the last m-n columns of U is not unique and chosen
+// orthogonal to the span of these columns.
+func svdDerivatives(a Matrix, inSitu *InSitu, epsilon float64) (Matrix, Matrix, Matrix, error) {
+  m, n := a.Dims()
+  t    := a.ElementType()
+  a0   := AsDenseBareRealMatrix(a)
+
+  h0, u0, v0, err := Run(a0, ComputeU{true}, ComputeV{true}, Epsilon{epsilon}, AnalyticDerivatives{false})
+  if err != nil {
+    return nil, nil, nil, err
+  }
+  s := make([]float64, n)
+  scale := 0.0
+  for i := 0; i < n; i++ {
+    s[i] = h0.ValueAt(i, i)
+    scale = math.Max(scale, math.Abs(s[i]))
+  }
+  // check for degenerate singular values, which only matter for the
+  // derivatives of U and V
+  for i := 0; i < n; i++ {
+    for j := i+1; j < n && (inSitu.U != nil || inSitu.V != nil); j++ {
+      if math.Abs(s[i]*s[i] - s[j]*s[j]) <= degeneracyTolerance*scale*scale {
+        return nil, nil, nil, errors.New("SVD(): derivatives of U and V are not defined for degenerate singular values")
+      }
+    }
+    if inSitu.U != nil && m > n && math.Abs(s[i]) <= degeneracyTolerance*scale {
+      return nil, nil, nil, errors.New("SVD(): derivatives of U are not defined for zero singular values")
+    }
+  }
+  // P = U^T dA V, where dA = A - A0 has zero value
+  dA := NullMatrix(t, m, n)
+  dA.MsubM(a, a0)
+  T  := NullMatrix(t, m, n)
+  T.MdotM(dA, v0)
+  P  := dA
+  P.MdotM(u0.T(), T)
+
+  t1 := NullScalar(t)
+  // omega = c1 P_ij + c2 P_ji
+  omega := func(r Scalar, i, j int, c1, c2 float64) {
+    r .Mul(P.ConstAt(i, j), ConstReal(c1))
+    t1.Mul(P.ConstAt(j, i), ConstReal(c2))
+    r .Add(r, t1)
+  }
+  H := inSitu.A
+  H.Reset()
+  for i := 0; i < n; i++ {
+    H.At(i, i).Add(h0.ConstAt(i, i), P.ConstAt(i, i))
+  }
+  U := inSitu.U
+  if U != nil {
+    W := NullMatrix(t, m, m)
+    for i := 0; i < n; i++ {
+      for j := 0; j < n; j++ {
+        if i != j {
+          d := s[j]*s[j] - s[i]*s[i]
+          omega(W.At(i, j), i, j, s[j]/d, s[i]/d)
+        }
+      }
+    }
+    for i := n; i < m; i++ {
+      for j := 0; j < n; j++ {
+        W.At(i, j).Div(P.ConstAt(i, j), ConstReal(s[j]))
+        W.At(j, i).Neg(W.At(i, j))
+      }
+    }
+    U.MdotM(u0, W)
+    U.MaddM(U, u0)
+  }
+  V := inSitu.V
+  if V != nil {
+    W := NullMatrix(t, n, n)
+    for i := 0; i < n; i++ {
+      for j := 0; j < n; j++ {
+        if i != j {
+          d := s[j]*s[j] - s[i]*s[i]
+          omega(W.At(i, j), i, j, s[i]/d, s[j]/d)
+        }
+      }
+    }
+    V.MdotM(v0, W)
+    V.MaddM(V, v0)
+  }
+  return H, U, V, nil
+}
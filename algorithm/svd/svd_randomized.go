@@ -0,0 +1,198 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package svd
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "errors"
+import   "math"
+import   "math/rand"
+import   "sort"
+
+import . "github.com/pbenner/autodiff"
+import   "github.com/pbenner/autodiff/algorithm/householder"
+
+/* -------------------------------------------------------------------------- */
+
+// Reference:
+// Halko, N., Martinsson, P. G., & Tropp, J. A. (2011). Finding structure
+// with randomness: Probabilistic algorithms for constructing approximate
+// matrix decompositions. SIAM Review, 53(2), 217-288.
+
+/* -------------------------------------------------------------------------- */
+
+// Linear operator of the transposed matrix. Products with the transpose are
+// computed from the transposed matrix if available.
+func transposedOperator(a ConstMatrix) LinearOperator {
+  switch a := a.(type) {
+  case CSRConstRealMatrix:
+    return AsLinearOperator(a.T())
+  case CSCConstRealMatrix:
+    return AsLinearOperator(a.T())
+  case LowRankConstRealMatrix:
+    return AsLinearOperator(a.T())
+  case Matrix:
+    return AsLinearOperator(a.T())
+  default:
+    n, m := a.Dims()
+    return NewLinearOperator(m, n, func(r Vector, b ConstVector) {
+      r.VdotM(b, a)
+    })
+  }
+}
+
+// Compute r = A x column by column. Matrix vector products are computed with
+// AsLinearOperator, such that for sparse matrices only non-zero elements are
+// visited.
+func randomizedMdotM(r *DenseBareRealMatrix, a LinearOperator, x *DenseBareRealMatrix) {
+  n, _ := r.Dims()
+  _, l := x.Dims()
+  t := NullDenseBareRealVector(n)
+  for j := 0; j < l; j++ {
+    a.MdotV(t, x.COL(j))
+    for i := 0; i < n; i++ {
+      r.AT(i, j).SetValue(t.ValueAt(i))
+    }
+  }
+}
+
+// Compute the thin QR decomposition x = Q R of the n x l matrix x with
+// Householder reflections. The matrix x is overwritten by Q and the upper
+// triangular l x l matrix R is returned.
+func randomizedQR(x *DenseBareRealMatrix) *DenseBareRealMatrix {
+  n, l := x.Dims()
+  r    := x.Clone()
+  nu   := make([]DenseBareRealVector, l)
+  beta := make([]*BareReal, l)
+  y    := NullDenseBareRealVector(n)
+  t    := NullDenseBareRealVector(l)
+  t1   := NullBareReal()
+  t2   := NullBareReal()
+  t3   := NullBareReal()
+  for j := 0; j < l; j++ {
+    nu  [j] = NullDenseBareRealVector(n)
+    beta[j] = NullBareReal()
+    for i := j; i < n; i++ {
+      y.At(i).Set(r.At(i, j))
+    }
+    householder.Run(y.Slice(j, n), beta[j], nu[j].Slice(j, n), t1, t2, t3)
+    householder.ApplyLeft(r.Slice(j, n, j, l), beta[j], nu[j].Slice(j, n), t.Slice(j, l), t1)
+  }
+  // accumulate Q = H_0 ... H_{l-1} [I 0]^T in backward order
+  x.Reset()
+  for j := 0; j < l; j++ {
+    x.AT(j, j).SetValue(1.0)
+  }
+  for j := l-1; j >= 0; j-- {
+    householder.ApplyLeft(x.Slice(j, n, j, l), beta[j], nu[j].Slice(j, n), t.Slice(j, l), t1)
+  }
+  s := NullDenseBareRealMatrix(l, l)
+  for i := 0; i < l; i++ {
+    for j := i; j < l; j++ {
+      s.At(i, j).Set(r.At(i, j))
+    }
+  }
+  return s
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute a truncated singular value decomposition A ~ U diag(s) V^T with
+// k singular values using a randomized range finder. The range of A is
+// sampled with k+oversample random vectors and improved with powerIters
+// power iterations. The seed initializes the random number generator.
+// Returns U (m x k), the singular values s in decreasing order and V
+// (n x k). The matrix is accessed only through matrix vector products
+// computed with AsLinearOperator. Derivatives are not propagated.
+func RunRandomized(a ConstMatrix, k, oversample, powerIters int, seed int64) (Matrix, Vector, Matrix, error) {
+  m, n := a.Dims()
+  p := m
+  if n < p {
+    p = n
+  }
+  if k <= 0 || k > p {
+    return nil, nil, nil, errors.New("RunRandomized(): Invalid number of singular values!")
+  }
+  if oversample < 0 || powerIters < 0 {
+    return nil, nil, nil, errors.New("RunRandomized(): Invalid oversampling or number of power iterations!")
+  }
+  l := k + oversample
+  if l > p {
+    l = p
+  }
+  g := rand.New(rand.NewSource(seed))
+  // random test matrix
+  omega := NullDenseBareRealMatrix(n, l)
+  for i := 0; i < n; i++ {
+    for j := 0; j < l; j++ {
+      omega.AT(i, j).SetValue(g.NormFloat64())
+    }
+  }
+  f := AsLinearOperator(a)
+  t := transposedOperator(a)
+  // range finder Q with A ~ Q Q^T A
+  q := NullDenseBareRealMatrix(m, l)
+  z := NullDenseBareRealMatrix(n, l)
+  randomizedMdotM(q, f, omega)
+  randomizedQR(q)
+  for iter := 0; iter < powerIters; iter++ {
+    randomizedMdotM(z, t, q)
+    randomizedQR(z)
+    randomizedMdotM(q, f, z)
+    randomizedQR(q)
+  }
+  // B^T = A^T Q = P R, i.e. B = R^T P^T
+  randomizedMdotM(z, t, q)
+  r := randomizedQR(z)
+  // SVD of the small matrix R^T = X H Y^T
+  h, x, y, err := Run(r.T(), ComputeU{true}, ComputeV{true}, AnalyticDerivatives{false})
+  if err != nil {
+    return nil, nil, nil, err
+  }
+  // sort singular values in decreasing order
+  idx := make([]int, l)
+  for i := 0; i < l; i++ {
+    idx[i] = i
+  }
+  sort.SliceStable(idx, func(i, j int) bool {
+    return math.Abs(h.ValueAt(idx[i], idx[i])) > math.Abs(h.ValueAt(idx[j], idx[j]))
+  })
+  // A ~ Q X H Y^T P^T
+  s  := NullDenseBareRealVector(k)
+  xk := NullDenseBareRealMatrix(l, k)
+  yk := NullDenseBareRealMatrix(l, k)
+  for c := 0; c < k; c++ {
+    j     := idx[c]
+    sigma := h.ValueAt(j, j)
+    sign  := 1.0
+    if sigma < 0.0 {
+      sign = -1.0
+    }
+    s.AT(c).SetValue(sign*sigma)
+    for d := 0; d < l; d++ {
+      xk.AT(d, c).SetValue(sign*x.ValueAt(d, j))
+      yk.AT(d, c).SetValue(y.ValueAt(d, j))
+    }
+  }
+  u := NullDenseBareRealMatrix(m, k)
+  v := NullDenseBareRealMatrix(n, k)
+  u.MdotM(q, xk)
+  v.MdotM(z, yk)
+  return u, s, v, nil
+}
@@ -20,6 +20,7 @@ package svd
 
 //import   "fmt"
 import   "math"
+import   "math/rand"
 import   "sort"
 import   "testing"
 
@@ -165,3 +166,230 @@ func Test6(t *testing.T) {
     t.Error("test failed")
   }
 }
+
+func Test7(t *testing.T) {
+  a := NewMatrix(RealType, 6, 5, []float64{
+    1,  2, 3,  4,  5,
+    2, -1, 0,  3,  1,
+    4,  4, 1, -2,  0,
+    1,  0, 0,  7,  3,
+    2,  2, 2,  1, -1,
+    0,  3, 1,  1,  2 })
+
+  h, u, v, _ := Run(a, ComputeU{true}, ComputeV{true})
+
+  d := MdotM(MdotM(u.T(), a), v)
+  b := MdotM(MdotM(u, h), v.T())
+
+  if Mnorm(MsubM(d, h)).GetValue() > 1e-8 {
+    t.Error("test failed")
+  }
+  if Mnorm(MsubM(a, b)).GetValue() > 1e-8 {
+    t.Error("test failed")
+  }
+}
+
+func Test8(t *testing.T) {
+  x := NewReal(0.5)
+  y := NewReal(-0.3)
+  Variables(1, x, y)
+  // A(x, y) = A0 + x S + y R
+  a0 := []float64{
+    1,  2, 3,  4,
+    2, -1, 0,  3,
+    4,  4, 1, -2,
+    1,  0, 0,  7,
+    2,  2, 2,  1,
+    0,  3, 1,  1 }
+  s  := []float64{
+    1,  0, 0,  1,
+    0,  0, 2,  0,
+    0,  1, 0,  0,
+    3,  0, 0,  0,
+    0,  0, 0,  1,
+    1,  0, 1,  0 }
+  r  := []float64{
+    0,  1, 0,  0,
+    0,  0, 0,  1,
+    2,  0, 0,  0,
+    0,  0, 1,  0,
+    1,  0, 0,  0,
+    0,  1, 0,  2 }
+  a := NullMatrix(RealType, 6, 4)
+  for i := 0; i < 6; i++ {
+    for j := 0; j < 4; j++ {
+      t := NullReal()
+      a.At(i, j).Mul(x, ConstReal(s[4*i+j]))
+      t         .Mul(y, ConstReal(r[4*i+j]))
+      a.At(i, j).Add(a.At(i, j), t)
+      a.At(i, j).Add(a.At(i, j), ConstReal(a0[4*i+j]))
+    }
+  }
+  h1, u1, v1, err1 := Run(a, ComputeU{true}, ComputeV{true}, AnalyticDerivatives{true})
+  h2, u2, v2, err2 := Run(a, ComputeU{true}, ComputeV{true})
+  if err1 != nil || err2 != nil {
+    t.Error("test failed")
+    return
+  }
+  equal := func(a, b ConstScalar) bool {
+    if math.Abs(a.GetValue() - b.GetValue()) > 1e-10 {
+      return false
+    }
+    for k := 0; k < 2; k++ {
+      if math.Abs(a.GetDerivative(k) - b.GetDerivative(k)) > 1e-8 {
+        return false
+      }
+    }
+    return true
+  }
+  for i := 0; i < 4; i++ {
+    if !equal(h1.At(i, i), h2.At(i, i)) {
+      t.Error("test failed")
+    }
+    for j := 0; j < 4; j++ {
+      if !equal(v1.At(i, j), v2.At(i, j)) {
+        t.Error("test failed")
+      }
+    }
+  }
+  // the last two columns of U are not unique
+  for i := 0; i < 6; i++ {
+    for j := 0; j < 4; j++ {
+      if !equal(u1.At(i, j), u2.At(i, j)) {
+        t.Error("test failed")
+      }
+    }
+  }
+}
+
+func Test9(t *testing.T) {
+  // degenerate singular values
+  a := NewMatrix(RealType, 3, 3, []float64{
+    1, 0, 0,
+    0, 2, 0,
+    0, 0, 1 })
+  a.Variables(1)
+
+  if _, _, _, err := Run(a, ComputeU{true}, ComputeV{true}, AnalyticDerivatives{true}); err == nil {
+    t.Error("test failed")
+  }
+  if _, _, _, err := Run(a, ComputeU{true}, ComputeV{true}); err != nil {
+    t.Error("test failed")
+  }
+  // derivatives of singular values are defined, their sum is the nuclear
+  // norm with derivative U V^T = I
+  if h, _, _, err := Run(a, AnalyticDerivatives{true}); err != nil {
+    t.Error(err)
+  } else {
+    for k := 0; k < 9; k++ {
+      r := 0.0
+      for i := 0; i < 3; i++ {
+        r += h.ConstAt(i, i).GetDerivative(k)
+      }
+      if k % 4 == 0 && math.Abs(r - 1.0) > 1e-10 || k % 4 != 0 && math.Abs(r) > 1e-10 {
+        t.Error("test failed")
+      }
+    }
+  }
+  // the option is ignored for matrices without derivatives
+  b := NewMatrix(RealType, 3, 3, []float64{
+    1, 0, 0,
+    0, 2, 0,
+    0, 0, 1 })
+  if _, _, _, err := Run(b, ComputeU{true}, ComputeV{true}, AnalyticDerivatives{true}); err != nil {
+    t.Error("test failed")
+  }
+}
+
+func Test10(t *testing.T) {
+  // randomized SVD of a matrix with rank five
+  g := rand.New(rand.NewSource(1))
+  m := 60
+  n := 20
+  x := NullMatrix(BareRealType, m, 5)
+  y := NullMatrix(BareRealType, n, 5)
+  for i := 0; i < 5; i++ {
+    for j := 0; j < m; j++ {
+      x.At(j, i).SetValue(g.NormFloat64())
+    }
+    for j := 0; j < n; j++ {
+      y.At(j, i).SetValue(g.NormFloat64())
+    }
+  }
+  a := MdotM(x, y.T())
+
+  h, _, _, err := Run(a)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  r := []float64{}
+  for i := 0; i < n; i++ {
+    r = append(r, math.Abs(h.At(i, i).GetValue()))
+  }
+  sort.Sort(sort.Reverse(sort.Float64Slice(r)))
+
+  for _, b := range []ConstMatrix{a, AsCSRConstRealMatrix(a), AsSparseBareRealMatrix(a)} {
+    u, s, v, err := RunRandomized(b, 5, 5, 1, 42)
+    if err != nil {
+      t.Error(err)
+      return
+    }
+    for i := 0; i < 5; i++ {
+      if math.Abs(s.At(i).GetValue() - r[i]) > 1e-8 {
+        t.Error("test failed")
+      }
+    }
+    // U and V have orthonormal columns
+    if !MdotM(u.T(), u).Equals(IdentityMatrix(BareRealType, 5), 1e-10) {
+      t.Error("test failed")
+    }
+    if !MdotM(v.T(), v).Equals(IdentityMatrix(BareRealType, 5), 1e-10) {
+      t.Error("test failed")
+    }
+    if !NewLowRankConstRealMatrix(u, s, v).Equals(a, 1e-8) {
+      t.Error("test failed")
+    }
+  }
+  // truncation to the three largest singular values
+  u, s, v, err := RunRandomized(a, 3, 10, 2, 42)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  for i := 0; i < 3; i++ {
+    if math.Abs(s.At(i).GetValue() - r[i]) > 1e-8 {
+      t.Error("test failed")
+    }
+  }
+  d := MsubM(a, NewDenseBareRealMatrix(m, n, NewLowRankConstRealMatrix(u, s, v).GetValues()))
+  if math.Abs(Mnorm(d).GetValue() - (r[3]*r[3] + r[4]*r[4])) > 1e-8 {
+    t.Error("test failed")
+  }
+  if _, _, _, err := RunRandomized(a, 21, 0, 0, 42); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func Test11(t *testing.T) {
+  a := NewMatrix(RealType, 4, 4, []float64{
+     3, 1, -2,  5,
+     0, 4,  1, -1,
+     2, 7,  1,  0,
+    -1, 2,  6,  3 })
+
+  h, u, v, _ := Run(a, ComputeU{true}, ComputeV{true})
+
+  i := IdentityMatrix(RealType, 4)
+  b := MdotM(MdotM(u, h), v.T())
+
+  if Mnorm(MsubM(MdotM(u.T(), u), i)).GetValue() > 1e-8 {
+    t.Error("test failed")
+  }
+  if Mnorm(MsubM(MdotM(v.T(), v), i)).GetValue() > 1e-8 {
+    t.Error("test failed")
+  }
+  if Mnorm(MsubM(a, b)).GetValue() > 1e-8 {
+    t.Error("test failed")
+  }
+}
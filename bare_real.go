@@ -70,6 +70,24 @@ func (a *BareReal) ConvertType(t ScalarType) Scalar {
     return NewReal(a.GetValue())
   case BareRealType:
     return a
+  case ReverseRealType:
+    return NewReverseReal(a.GetValue())
+  case SparseRealType:
+    return NewSparseReal(a.GetValue())
+  case DualRealType:
+    return NewDualReal(a.GetValue())
+  case TaylorRealType:
+    return NewTaylorReal(a.GetValue())
+  case ComplexType:
+    return NewComplex(a.GetValue())
+  case BareReal32Type:
+    return NewBareReal32(a.GetValue())
+  case IntervalType:
+    return NewInterval(a.GetValue())
+  case BigRealType:
+    return NewBigReal(a.GetValue())
+  case BatchRealType:
+    return NewBatchReal(a.GetValue())
   default:
     panic(fmt.Sprintf("cannot convert `BareReal' to type `%v'", t))
   }
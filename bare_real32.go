@@ -0,0 +1,192 @@
+/* Copyright (C) 2015 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "encoding/json"
+import "reflect"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// BareReal32 is a single precision scalar without derivatives. It halves
+// the memory footprint of BareReal, while all computations are carried out
+// in double precision and rounded to single precision afterwards.
+type BareReal32 float32
+
+/* register scalar type
+ * -------------------------------------------------------------------------- */
+
+var BareReal32Type ScalarType = NewBareReal32(0.0).Type()
+
+func init() {
+  f := func(value float64) Scalar { return NewBareReal32(value) }
+  RegisterScalar(BareReal32Type, f)
+}
+
+/* constructors
+ * -------------------------------------------------------------------------- */
+
+func NewBareReal32(v float64) *BareReal32 {
+  r := BareReal32(v)
+  return &r
+}
+
+func NullBareReal32() *BareReal32 {
+  r := BareReal32(0.0)
+  return &r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BareReal32) Clone() *BareReal32 {
+  return NewBareReal32(float64(*a))
+}
+
+func (a *BareReal32) CloneScalar() Scalar {
+  return a.Clone()
+}
+
+func (a *BareReal32) Type() ScalarType {
+  return reflect.TypeOf(a)
+}
+
+func (a *BareReal32) ConvertType(t ScalarType) Scalar {
+  switch t {
+  case RealType:
+    return NewReal(a.GetValue())
+  case BareRealType:
+    return NewBareReal(a.GetValue())
+  case BareReal32Type:
+    return a
+  case ReverseRealType:
+    return NewReverseReal(a.GetValue())
+  case SparseRealType:
+    return NewSparseReal(a.GetValue())
+  case DualRealType:
+    return NewDualReal(a.GetValue())
+  case TaylorRealType:
+    return NewTaylorReal(a.GetValue())
+  case ComplexType:
+    return NewComplex(a.GetValue())
+  case IntervalType:
+    return NewInterval(a.GetValue())
+  case BigRealType:
+    return NewBigReal(a.GetValue())
+  case BatchRealType:
+    return NewBatchReal(a.GetValue())
+  default:
+    panic(fmt.Sprintf("cannot convert `BareReal32' to type `%v'", t))
+  }
+}
+
+/* type conversion
+ * -------------------------------------------------------------------------- */
+
+func (a  BareReal32) String() string {
+  return fmt.Sprintf("%e", a.GetValue())
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BareReal32) Alloc(n, order int) {
+}
+
+func (c *BareReal32) AllocForOne(a ConstScalar) {
+}
+
+func (c *BareReal32) AllocForTwo(a, b ConstScalar) {
+}
+
+/* read access
+ * -------------------------------------------------------------------------- */
+
+func (a  BareReal32) GetOrder() int {
+  return 0
+}
+
+func (a  BareReal32) GetValue() float64 {
+  return float64(a)
+}
+
+func (a  BareReal32) GetLogValue() float64 {
+  return math.Log(a.GetValue())
+}
+
+func (a  BareReal32) GetDerivative(i int) float64 {
+  return 0.0
+}
+
+func (a  BareReal32) GetHessian(i, j int) float64 {
+  return 0.0
+}
+
+func (a  BareReal32) GetN() int {
+  return 0
+}
+
+/* write access
+ * -------------------------------------------------------------------------- */
+
+func (a *BareReal32) Reset() {
+  *a = 0.0
+}
+
+func (a *BareReal32) ResetDerivatives() {
+}
+
+func (a *BareReal32) Set(b ConstScalar) {
+  *a = BareReal32(b.GetValue())
+}
+
+func (a *BareReal32) SET(b *BareReal32) {
+  *a = *b
+}
+
+func (a *BareReal32) SetValue(v float64) {
+  *a = BareReal32(v)
+}
+
+func (a *BareReal32) setValue(v float64) {
+  *a = BareReal32(v)
+}
+
+func (a *BareReal32) SetDerivative(i int, v float64) {
+}
+
+func (a *BareReal32) SetHessian(i, j int, v float64) {
+}
+
+func (a *BareReal32) SetVariable(i, n, order int) error {
+  return fmt.Errorf("BareReal32 cannot be used as a variable")
+}
+
+func (a *BareReal32) SetN(n int) {
+}
+
+/* json
+ * -------------------------------------------------------------------------- */
+
+func (obj *BareReal32) MarshalJSON() ([]byte, error) {
+  return json.Marshal(*obj)
+}
+
+func (obj *BareReal32) UnmarshalJSON(data []byte) error {
+  return json.Unmarshal(data, (*float32)(obj))
+}
@@ -0,0 +1,187 @@
+/* Copyright (C) 2015 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+import "math"
+
+//import "github.com/pbenner/autodiff/special"
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BareReal32) EQUALS(b ConstScalar, epsilon float64) bool {
+  return math.Abs(a.GetValue() - b.GetValue()) < epsilon
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BareReal32) GREATER(b *BareReal32) bool {
+  return a.GetValue() > b.GetValue()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BareReal32) SMALLER(b *BareReal32) bool {
+  return a.GetValue() < b.GetValue()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *BareReal32) MIN(a, b *BareReal32) Scalar {
+  if a.GetValue() < b.GetValue() {
+    r.SET(a)
+  } else {
+    r.SET(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *BareReal32) MAX(a, b *BareReal32) Scalar {
+  if a.GetValue() > b.GetValue() {
+    r.SET(a)
+  } else {
+    r.SET(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BareReal32) SIGN() int {
+  if a.GetValue() < 0.0 {
+    return -1
+  }
+  if a.GetValue() > 0.0 {
+    return  1
+  }
+  return 0
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BareReal32) ABS(a *BareReal32) Scalar {
+  if c.Sign() == -1 {
+    c.NEG(a)
+  } else {
+    c.SET(a)
+  }
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BareReal32) NEG(a *BareReal32) *BareReal32 {
+  *c = BareReal32(-a.GetValue())
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BareReal32) ADD(a, b *BareReal32) *BareReal32 {
+  *c = *a + *b
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BareReal32) SUB(a, b *BareReal32) *BareReal32 {
+  *c = *a - *b
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BareReal32) MUL(a, b *BareReal32) *BareReal32 {
+  *c = *a * *b
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BareReal32) DIV(a, b *BareReal32) *BareReal32 {
+  *c = *a / *b
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BareReal32) LOGADD(a, b, t *BareReal32) *BareReal32 {
+  if a.GREATER(b) {
+    // swap
+    a, b = b, a
+  }
+  if math.IsInf(a.GetValue(), 0) {
+    // cases:
+    //  i) a = -Inf and b >= a    => c = b
+    // ii) a =  Inf and b  = Inf  => c = Inf
+    c.SET(b)
+    return c
+  }
+  t.SUB(a, b)
+  t.EXP(t)
+  t.LOG1P(t)
+  c.ADD(t, b)
+  return c
+}
+
+func (c *BareReal32) LOGSUB(a, b, t *BareReal32) *BareReal32 {
+  if math.IsInf(b.GetValue(), -1) {
+    c.SET(a)
+    return c
+  }
+  t.SUB(b, a)
+  t.EXP(t)
+  t.NEG(t)
+  t.LOG1P(t)
+  c.ADD(t, a)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BareReal32) POW(a, k *BareReal32) *BareReal32 {
+  *c = BareReal32(math.Pow(a.GetValue(), k.GetValue()))
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BareReal32) SQRT(a *BareReal32) *BareReal32 {
+  return c.POW(a, NewBareReal32(1.0/2.0))
+}
+
+
+func (c *BareReal32) EXP(a *BareReal32) *BareReal32 {
+  checkBare32(a)
+  *c = BareReal32(math.Exp(a.GetValue()))
+  return c
+}
+
+func (c *BareReal32) LOG(a *BareReal32) *BareReal32 {
+  checkBare32(a)
+  *c = BareReal32(math.Log(a.GetValue()))
+  return c
+}
+
+func (c *BareReal32) LOG1P(a *BareReal32) *BareReal32 {
+  checkBare32(a)
+  *c = BareReal32(math.Log1p(a.GetValue()))
+  return c
+}
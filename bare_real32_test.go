@@ -0,0 +1,77 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "math"
+import "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestBareReal32(t *testing.T) {
+
+  a := NewBareReal32(1.0/3.0)
+  b := NewBareReal32(2.0)
+
+  if a.GetValue() != float64(float32(1.0/3.0)) {
+    t.Error("test failed")
+  }
+  c := NullBareReal32()
+  c.Mul(a, b)
+  if math.Abs(c.GetValue() - 2.0/3.0) > 1e-7 {
+    t.Error("test failed")
+  }
+  if err := a.SetVariable(0, 1, 1); err == nil {
+    t.Error("test failed")
+  }
+  r := a.ConvertType(RealType)
+  if r.Type() != RealType || r.GetValue() != a.GetValue() {
+    t.Error("test failed")
+  }
+  s := NewReal(0.25).ConvertType(BareReal32Type)
+  if s.Type() != BareReal32Type || s.GetValue() != 0.25 {
+    t.Error("test failed")
+  }
+}
+
+func TestBareReal32Matrix(t *testing.T) {
+
+  a := NewMatrix(BareReal32Type, 2, 2, []float64{1, 2, 3, 4})
+  b := NewMatrix(RealType,       2, 2, []float64{1, 2, 3, 4})
+  x := NewVector(BareReal32Type, []float64{1, 2})
+
+  if _, ok := a.(*DenseBareReal32Matrix); !ok {
+    t.Error("test failed")
+  }
+  if _, ok := x.(DenseBareReal32Vector); !ok {
+    t.Error("test failed")
+  }
+  r := NullVector(BareReal32Type, 2)
+  r.MdotV(a, x)
+
+  if r.ValueAt(0) != 5.0 || r.ValueAt(1) != 11.0 {
+    t.Error("test failed")
+  }
+  if !AsMatrix(BareReal32Type, b).Equals(a, 1e-12) {
+    t.Error("test failed")
+  }
+  if s := NullMatrix(BareReal32Type, 3, 3); s.ElementType() != BareReal32Type {
+    t.Error("test failed")
+  }
+}
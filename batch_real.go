@@ -0,0 +1,392 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "bytes"
+import "encoding/json"
+import "reflect"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// BatchReal is a scalar that holds a batch of Real values, each with its
+// own derivatives. All operations are applied elementwise, where batches
+// of size one and scalars of other types are broadcast to the size of the
+// other arguments. This allows to evaluate a function on many inputs at
+// once, e.g. a density on a batch of observations with parameters that
+// are BatchReals of size one.
+//
+// GetValue, GetDerivative and GetHessian return the respective value of
+// the single element if the batch has size one and NaN otherwise. Use
+// GetBatchValue, GetBatchDerivative and GetBatchHessian to access
+// individual elements. Comparisons are defined as follows:
+//   a.Greater(b)       is true if every element of a is greater than the corresponding element of b
+//   a.Smaller(b)       is true if every element of a is smaller than the corresponding element of b
+//   a.Equals(b, eps)   is true if all elements are equal up to eps
+//   a.Sign()           is the sign of all elements if they have the same sign and zero otherwise
+type BatchReal struct {
+  Batch []Real
+}
+
+/* register scalar type
+ * -------------------------------------------------------------------------- */
+
+var BatchRealType ScalarType = NewBatchReal(0.0).Type()
+
+func init() {
+  f := func(value float64) Scalar { return NewBatchReal(value) }
+  RegisterScalar(BatchRealType, f)
+}
+
+/* constructors
+ * -------------------------------------------------------------------------- */
+
+// Create a new batch of size one with value v.
+func NewBatchReal(v float64) *BatchReal {
+  return NewBatchRealFromSlice([]float64{v})
+}
+
+// Create a new batch that contains the given values.
+func NewBatchRealFromSlice(values []float64) *BatchReal {
+  r := &BatchReal{}
+  r.Batch = make([]Real, len(values))
+  for i, v := range values {
+    r.Batch[i] = *NewReal(v)
+  }
+  return r
+}
+
+func NullBatchReal() *BatchReal {
+  return NewBatchReal(0.0)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BatchReal) Clone() *BatchReal {
+  r := &BatchReal{}
+  r.SET(a)
+  return r
+}
+
+func (a *BatchReal) CloneScalar() Scalar {
+  return a.Clone()
+}
+
+func (a *BatchReal) Type() ScalarType {
+  return reflect.TypeOf(a)
+}
+
+func (a *BatchReal) ConvertType(t ScalarType) Scalar {
+  switch t {
+  case BatchRealType:
+    return a
+  case RealType:
+    return NewReal(a.GetValue())
+  case BareRealType:
+    return NewBareReal(a.GetValue())
+  case ReverseRealType:
+    return NewReverseReal(a.GetValue())
+  case SparseRealType:
+    return NewSparseReal(a.GetValue())
+  case DualRealType:
+    return NewDualReal(a.GetValue())
+  case TaylorRealType:
+    return NewTaylorReal(a.GetValue())
+  case ComplexType:
+    return NewComplex(a.GetValue())
+  case BareReal32Type:
+    return NewBareReal32(a.GetValue())
+  case IntervalType:
+    return NewInterval(a.GetValue())
+  case BigRealType:
+    return NewBigReal(a.GetValue())
+  default:
+    panic(fmt.Sprintf("cannot convert `BatchReal' to type `%v'", t))
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Returns the size of the batch if a is a BatchReal and one otherwise.
+func batchSize(a ConstScalar) int {
+  if b, ok := a.(*BatchReal); ok {
+    return len(b.Batch)
+  }
+  return 1
+}
+
+// Returns the common batch size of a and b.
+func batchSize2(a, b ConstScalar) int {
+  n1 := batchSize(a)
+  n2 := batchSize(b)
+  switch {
+  case n1 == n2: return n1
+  case n1 == 1 : return n2
+  case n2 == 1 : return n1
+  }
+  panic(fmt.Sprintf("batch sizes `%d' and `%d' do not match", n1, n2))
+}
+
+// Returns the i-th element of a, where batches of size one and scalars of
+// other types are broadcast.
+func batchAt(a ConstScalar, i int) ConstScalar {
+  if b, ok := a.(*BatchReal); ok {
+    if len(b.Batch) == 1 {
+      return &b.Batch[0]
+    }
+    return &b.Batch[i]
+  }
+  return a
+}
+
+// Change the size of the batch. New elements are set to zero.
+func (a *BatchReal) resize(n int) {
+  if n <= cap(a.Batch) {
+    m := len(a.Batch)
+    a.Batch = a.Batch[0:n]
+    for i := m; i < n; i++ {
+      a.Batch[i].Reset()
+    }
+  } else {
+    for len(a.Batch) < n {
+      a.Batch = append(a.Batch, *NullReal())
+    }
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BatchReal) Alloc(n, order int) {
+  for i := 0; i < len(a.Batch); i++ {
+    a.Batch[i].Alloc(n, order)
+  }
+}
+
+func (c *BatchReal) AllocForOne(a ConstScalar) {
+  c.resize(batchSize(a))
+  for i := 0; i < len(c.Batch); i++ {
+    c.Batch[i].AllocForOne(batchAt(a, i))
+  }
+}
+
+func (c *BatchReal) AllocForTwo(a, b ConstScalar) {
+  c.resize(batchSize2(a, b))
+  for i := 0; i < len(c.Batch); i++ {
+    c.Batch[i].AllocForTwo(batchAt(a, i), batchAt(b, i))
+  }
+}
+
+/* read access
+ * -------------------------------------------------------------------------- */
+
+func (a *BatchReal) BatchSize() int {
+  return len(a.Batch)
+}
+
+func (a *BatchReal) GetOrder() int {
+  if len(a.Batch) == 0 {
+    return 0
+  }
+  return a.Batch[0].GetOrder()
+}
+
+func (a *BatchReal) GetValue() float64 {
+  if len(a.Batch) != 1 {
+    return math.NaN()
+  }
+  return a.Batch[0].GetValue()
+}
+
+func (a *BatchReal) GetLogValue() float64 {
+  return math.Log(a.GetValue())
+}
+
+func (a *BatchReal) GetDerivative(i int) float64 {
+  if len(a.Batch) != 1 {
+    return math.NaN()
+  }
+  return a.Batch[0].GetDerivative(i)
+}
+
+func (a *BatchReal) GetHessian(i, j int) float64 {
+  if len(a.Batch) != 1 {
+    return math.NaN()
+  }
+  return a.Batch[0].GetHessian(i, j)
+}
+
+func (a *BatchReal) GetN() int {
+  if len(a.Batch) == 0 {
+    return 0
+  }
+  return a.Batch[0].GetN()
+}
+
+// Returns the value of the k-th element.
+func (a *BatchReal) GetBatchValue(k int) float64 {
+  return a.Batch[k].GetValue()
+}
+
+// Returns the values of all elements.
+func (a *BatchReal) GetBatchValues() []float64 {
+  r := make([]float64, len(a.Batch))
+  for k := 0; k < len(a.Batch); k++ {
+    r[k] = a.Batch[k].GetValue()
+  }
+  return r
+}
+
+// Returns the derivative of the k-th element with respect to the i-th
+// variable.
+func (a *BatchReal) GetBatchDerivative(k, i int) float64 {
+  return a.Batch[k].GetDerivative(i)
+}
+
+func (a *BatchReal) GetBatchHessian(k, i, j int) float64 {
+  return a.Batch[k].GetHessian(i, j)
+}
+
+/* write access
+ * -------------------------------------------------------------------------- */
+
+func (a *BatchReal) Reset() {
+  for i := 0; i < len(a.Batch); i++ {
+    a.Batch[i].Reset()
+  }
+}
+
+func (a *BatchReal) ResetDerivatives() {
+  for i := 0; i < len(a.Batch); i++ {
+    a.Batch[i].ResetDerivatives()
+  }
+}
+
+// Set the state to b. The batch size of a is changed to the batch size of
+// b, which is one if b is not a BatchReal.
+func (a *BatchReal) Set(b ConstScalar) {
+  if r, ok := b.(*BatchReal); ok {
+    a.SET(r)
+  } else {
+    a.resize(1)
+    a.Batch[0].Set(b)
+  }
+}
+
+func (a *BatchReal) SET(b *BatchReal) {
+  if a == b {
+    return
+  }
+  a.resize(len(b.Batch))
+  for i := 0; i < len(b.Batch); i++ {
+    a.Batch[i].Set(&b.Batch[i])
+  }
+}
+
+// Set the value of all elements to v. All derivatives are reset to zero.
+func (a *BatchReal) SetValue(v float64) {
+  if len(a.Batch) == 0 {
+    a.resize(1)
+  }
+  for i := 0; i < len(a.Batch); i++ {
+    a.Batch[i].SetValue(v)
+  }
+}
+
+func (a *BatchReal) setValue(v float64) {
+  if len(a.Batch) == 0 {
+    a.resize(1)
+  }
+  for i := 0; i < len(a.Batch); i++ {
+    a.Batch[i].setValue(v)
+  }
+}
+
+// Set the values of all elements. The batch size is changed to the
+// length of values.
+func (a *BatchReal) SetBatchValues(values []float64) {
+  a.resize(len(values))
+  for i, v := range values {
+    a.Batch[i].SetValue(v)
+  }
+}
+
+// Set the derivative of all elements.
+func (a *BatchReal) SetDerivative(i int, v float64) {
+  for k := 0; k < len(a.Batch); k++ {
+    a.Batch[k].SetDerivative(i, v)
+  }
+}
+
+func (a *BatchReal) SetHessian(i, j int, v float64) {
+  for k := 0; k < len(a.Batch); k++ {
+    a.Batch[k].SetHessian(i, j, v)
+  }
+}
+
+// Every element of the batch becomes the i-th variable.
+func (a *BatchReal) SetVariable(i, n, order int) error {
+  for k := 0; k < len(a.Batch); k++ {
+    if err := a.Batch[k].SetVariable(i, n, order); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+/* type conversion
+ * -------------------------------------------------------------------------- */
+
+func (a *BatchReal) String() string {
+  if len(a.Batch) == 1 {
+    return a.Batch[0].String()
+  }
+  var buffer bytes.Buffer
+  buffer.WriteString("[")
+  for i := 0; i < len(a.Batch); i++ {
+    if i != 0 {
+      buffer.WriteString(", ")
+    }
+    buffer.WriteString(a.Batch[i].String())
+  }
+  buffer.WriteString("]")
+  return buffer.String()
+}
+
+/* json
+ * -------------------------------------------------------------------------- */
+
+func (obj *BatchReal) MarshalJSON() ([]byte, error) {
+  if len(obj.Batch) == 1 {
+    return obj.Batch[0].MarshalJSON()
+  } else {
+    return json.Marshal(obj.GetBatchValues())
+  }
+}
+
+func (obj *BatchReal) UnmarshalJSON(data []byte) error {
+  r := []float64{}
+  if err := json.Unmarshal(data, &r); err == nil {
+    obj.SetBatchValues(r)
+    return nil
+  } else {
+    obj.resize(1)
+    return obj.Batch[0].UnmarshalJSON(data)
+  }
+}
@@ -0,0 +1,390 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) monadic(a ConstScalar, f func(r *Real, a ConstScalar)) *BatchReal {
+  c.resize(batchSize(a))
+  for i := 0; i < len(c.Batch); i++ {
+    f(&c.Batch[i], batchAt(a, i))
+  }
+  return c
+}
+
+func (c *BatchReal) dyadic(a, b ConstScalar, f func(r *Real, a, b ConstScalar)) *BatchReal {
+  n := batchSize2(a, b)
+  // c is broadcast and must be copied before it is resized
+  if a == ConstScalar(c) && len(c.Batch) != n {
+    a = c.Clone()
+  }
+  if b == ConstScalar(c) && len(c.Batch) != n {
+    b = c.Clone()
+  }
+  c.resize(n)
+  for i := 0; i < n; i++ {
+    f(&c.Batch[i], batchAt(a, i), batchAt(b, i))
+  }
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BatchReal) Equals(b ConstScalar, epsilon float64) bool {
+  for i := 0; i < batchSize2(a, b); i++ {
+    if !batchAt(a, i).Equals(batchAt(b, i), epsilon) {
+      return false
+    }
+  }
+  return true
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BatchReal) Greater(b ConstScalar) bool {
+  for i := 0; i < batchSize2(a, b); i++ {
+    if !(batchAt(a, i).GetValue() > batchAt(b, i).GetValue()) {
+      return false
+    }
+  }
+  return true
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BatchReal) Smaller(b ConstScalar) bool {
+  for i := 0; i < batchSize2(a, b); i++ {
+    if !(batchAt(a, i).GetValue() < batchAt(b, i).GetValue()) {
+      return false
+    }
+  }
+  return true
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BatchReal) Sign() int {
+  if len(a.Batch) == 0 {
+    return 0
+  }
+  s := a.Batch[0].Sign()
+  for i := 1; i < len(a.Batch); i++ {
+    if a.Batch[i].Sign() != s {
+      return 0
+    }
+  }
+  return s
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *BatchReal) Min(a, b ConstScalar) Scalar {
+  return r.dyadic(a, b, func(r *Real, a, b ConstScalar) { r.Min(a, b) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *BatchReal) Max(a, b ConstScalar) Scalar {
+  return r.dyadic(a, b, func(r *Real, a, b ConstScalar) { r.Max(a, b) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Abs(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Abs(a) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Neg(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Neg(a) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Add(a, b ConstScalar) Scalar {
+  return c.dyadic(a, b, func(r *Real, a, b ConstScalar) { r.Add(a, b) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Sub(a, b ConstScalar) Scalar {
+  return c.dyadic(a, b, func(r *Real, a, b ConstScalar) { r.Sub(a, b) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Mul(a, b ConstScalar) Scalar {
+  return c.dyadic(a, b, func(r *Real, a, b ConstScalar) { r.Mul(a, b) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Div(a, b ConstScalar) Scalar {
+  return c.dyadic(a, b, func(r *Real, a, b ConstScalar) { r.Div(a, b) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+// The temporary t is not used, since every element requires its own.
+func (c *BatchReal) LogAdd(a, b ConstScalar, t Scalar) Scalar {
+  s := NullReal()
+  return c.dyadic(a, b, func(r *Real, a, b ConstScalar) { r.LogAdd(a, b, s) })
+}
+
+func (c *BatchReal) LogSub(a, b ConstScalar, t Scalar) Scalar {
+  s := NullReal()
+  return c.dyadic(a, b, func(r *Real, a, b ConstScalar) { r.LogSub(a, b, s) })
+}
+
+func (c *BatchReal) Log1pExp(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Log1pExp(a) })
+}
+
+func (c *BatchReal) Sigmoid(a ConstScalar, t Scalar) Scalar {
+  s := NullReal()
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Sigmoid(a, s) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Pow(a, k ConstScalar) Scalar {
+  return c.dyadic(a, k, func(r *Real, a, k ConstScalar) { r.Pow(a, k) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Sqrt(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Sqrt(a) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Sin(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Sin(a) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Sinh(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Sinh(a) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Cos(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Cos(a) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Cosh(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Cosh(a) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Tan(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Tan(a) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Tanh(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Tanh(a) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Exp(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Exp(a) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Log(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Log(a) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Log1p(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Log1p(a) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Logistic(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Logistic(a) })
+}
+
+/* special functions
+ * -------------------------------------------------------------------------- */
+
+func (c *BatchReal) Erf(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Erf(a) })
+}
+
+func (c *BatchReal) Erfc(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Erfc(a) })
+}
+
+func (c *BatchReal) LogErfc(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.LogErfc(a) })
+}
+
+func (c *BatchReal) Gamma(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Gamma(a) })
+}
+
+func (c *BatchReal) Lgamma(a ConstScalar) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Lgamma(a) })
+}
+
+func (c *BatchReal) Mlgamma(a ConstScalar, k int) Scalar {
+  return c.monadic(a, func(r *Real, a ConstScalar) { r.Mlgamma(a, k) })
+}
+
+func (c *BatchReal) GammaP(a float64, b ConstScalar) Scalar {
+  return c.monadic(b, func(r *Real, b ConstScalar) { r.GammaP(a, b) })
+}
+
+func (c *BatchReal) BesselI(v float64, b ConstScalar) Scalar {
+  return c.monadic(b, func(r *Real, b ConstScalar) { r.BesselI(v, b) })
+}
+
+func (c *BatchReal) LogBesselI(v float64, b ConstScalar) Scalar {
+  return c.monadic(b, func(r *Real, b ConstScalar) { r.LogBesselI(v, b) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Sum of all elements of a. The result is a batch of size one.
+func (c *BatchReal) BatchSum(a ConstScalar) Scalar {
+  b, ok := a.(*BatchReal)
+  if !ok {
+    c.Set(a)
+    return c
+  }
+  if b == c {
+    b = b.Clone()
+  }
+  c.resize(1)
+  c.Batch[0].Reset()
+  for i := 0; i < len(b.Batch); i++ {
+    c.Batch[0].Add(&c.Batch[0], &b.Batch[i])
+  }
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *BatchReal) SmoothMax(x ConstVector, alpha ConstReal, t [2]Scalar) Scalar {
+  r   .Set(ConstReal(0.0))
+  t[1].Set(ConstReal(0.0))
+  for i := 0; i < x.Dim(); i++ {
+    t[0].Mul(alpha, x.ConstAt(i))
+    t[0].Exp(t[0])
+    t[1].Add(t[1], t[0])
+    t[0].Mul(t[0], x.ConstAt(i))
+    r .Add(r , t[0])
+  }
+  r.Div(r, t[1])
+  return r
+}
+
+func (r *BatchReal) LogSmoothMax(x ConstVector, alpha ConstReal, t [3]Scalar) Scalar {
+  r   .Set(ConstReal(0.0))
+  t[2].Set(ConstReal(math.Inf(-1)))
+  for i := 0; i < x.Dim(); i++ {
+    t[0].Mul(x.ConstAt(i), alpha)
+    t[2].LogAdd(t[2], t[0], t[1])
+    t[1].Log(x.ConstAt(i))
+    t[0].Add(t[0], t[1])
+    r.LogAdd(r, t[0], t[1])
+  }
+  r.Sub(r, t[2])
+  r.Exp(r)
+  return r
+}
+
+func (r *BatchReal) Vmean(a ConstVector) Scalar {
+  r.Set(ConstReal(0.0))
+  for i := 0; i < a.Dim(); i++ {
+    r.Add(r, a.ConstAt(i))
+  }
+  return r.Div(r, ConstReal(float64(a.Dim())))
+}
+
+func (r *BatchReal) VdotV(a, b ConstVector) Scalar {
+  if a.Dim() != b.Dim() {
+    panic("vector dimensions do not match")
+  }
+  r.Set(ConstReal(0.0))
+  t := NullBatchReal()
+  for i := 0; i < a.Dim(); i++ {
+    t.Mul(a.ConstAt(i), b.ConstAt(i))
+    r.Add(r, t)
+  }
+  return r
+}
+
+func (r *BatchReal) Vnorm(a ConstVector) Scalar {
+  r.Set(ConstReal(0.0))
+  t := NullBatchReal()
+  for it := a.ConstIterator(); it.Ok(); it.Next() {
+    t.Mul(it.GetConst(), it.GetConst())
+    r.Add(r, t)
+  }
+  r.Sqrt(r)
+  return r
+}
+
+func (r *BatchReal) Mtrace(a ConstMatrix) Scalar {
+  n, m := a.Dims()
+  if n != m {
+    panic("not a square matrix")
+  }
+  if n == 0 {
+    return nil
+  }
+  r.Set(ConstReal(0.0))
+  for i := 0; i < n; i++ {
+    r.Add(r, a.ConstAt(i,i))
+  }
+  return r
+}
+
+func (r *BatchReal) Mnorm(a ConstMatrix) Scalar {
+  n, m := a.Dims()
+  if n == 0 || m == 0 {
+    return nil
+  }
+  t := NullBatchReal()
+  v := a.AsConstVector()
+  r.Set(ConstReal(0.0))
+  for i := 0; i < v.Dim(); i++ {
+    t.Mul(v.ConstAt(i), v.ConstAt(i))
+    r.Add(r, t)
+  }
+  return r
+}
@@ -0,0 +1,141 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BatchReal) EQUALS(b *BatchReal, epsilon float64) bool {
+  return a.Equals(b, epsilon)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BatchReal) GREATER(b *BatchReal) bool {
+  return a.Greater(b)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BatchReal) SMALLER(b *BatchReal) bool {
+  return a.Smaller(b)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BatchReal) SIGN() int {
+  return a.Sign()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *BatchReal) MIN(a, b *BatchReal) Scalar {
+  return r.Min(a, b)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *BatchReal) MAX(a, b *BatchReal) Scalar {
+  return r.Max(a, b)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) ABS(a *BatchReal) Scalar {
+  return c.Abs(a)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) NEG(a *BatchReal) *BatchReal {
+  c.Neg(a)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) ADD(a, b *BatchReal) *BatchReal {
+  c.Add(a, b)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) SUB(a, b *BatchReal) *BatchReal {
+  c.Sub(a, b)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) MUL(a, b *BatchReal) *BatchReal {
+  c.Mul(a, b)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) DIV(a, b *BatchReal) *BatchReal {
+  c.Div(a, b)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) LOGADD(a, b, t *BatchReal) *BatchReal {
+  c.LogAdd(a, b, t)
+  return c
+}
+
+func (c *BatchReal) LOGSUB(a, b, t *BatchReal) *BatchReal {
+  c.LogSub(a, b, t)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) POW(a, k *BatchReal) *BatchReal {
+  c.Pow(a, k)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) SQRT(a *BatchReal) *BatchReal {
+  c.Sqrt(a)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BatchReal) EXP(a *BatchReal) *BatchReal {
+  c.Exp(a)
+  return c
+}
+
+func (c *BatchReal) LOG(a *BatchReal) *BatchReal {
+  c.Log(a)
+  return c
+}
+
+func (c *BatchReal) LOG1P(a *BatchReal) *BatchReal {
+  c.Log1p(a)
+  return c
+}
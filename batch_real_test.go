@@ -0,0 +1,129 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "math"
+import "encoding/json"
+import "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestBatchReal1(t *testing.T) {
+  x := []float64{0.5, 1.0, 2.5}
+  a := NewBatchRealFromSlice(x)
+  b := NewBatchReal(2.0)
+  c := NullBatchReal()
+
+  Variables(2, b)
+
+  // c = exp(a*b) + b
+  c.Mul(a, b)
+  c.Exp(c)
+  c.Add(c, b)
+
+  if c.BatchSize() != 3 {
+    t.Error("test failed")
+  }
+  for i := 0; i < len(x); i++ {
+    r := NewReal(2.0)
+    s := NullReal()
+    Variables(2, r)
+    s.Mul(ConstReal(x[i]), r)
+    s.Exp(s)
+    s.Add(s, r)
+    if math.Abs(c.GetBatchValue(i) - s.GetValue()) > 1e-12 {
+      t.Error("test failed")
+    }
+    if math.Abs(c.GetBatchDerivative(i, 0) - s.GetDerivative(0)) > 1e-12 {
+      t.Error("test failed")
+    }
+    if math.Abs(c.GetBatchHessian(i, 0, 0) - s.GetHessian(0, 0)) > 1e-12 {
+      t.Error("test failed")
+    }
+  }
+  // broadcasting of the receiver
+  d := NewBatchReal(1.0)
+  d.Add(d, a)
+  if d.BatchSize() != 3 || d.GetBatchValue(2) != 3.5 {
+    t.Error("test failed")
+  }
+  // sum of all elements
+  s := NullBatchReal()
+  s.BatchSum(c)
+  if math.Abs(s.GetValue() - (c.GetBatchValue(0) + c.GetBatchValue(1) + c.GetBatchValue(2))) > 1e-12 {
+    t.Error("test failed")
+  }
+  if math.Abs(s.GetDerivative(0) - (c.GetBatchDerivative(0, 0) + c.GetBatchDerivative(1, 0) + c.GetBatchDerivative(2, 0))) > 1e-12 {
+    t.Error("test failed")
+  }
+}
+
+func TestBatchReal2(t *testing.T) {
+  a := NewBatchRealFromSlice([]float64{1.0, 2.0, 3.0})
+  b := NewBatchRealFromSlice([]float64{0.0, 1.0, 2.0})
+
+  if !a.Greater(b) || a.Smaller(b) || !b.Smaller(a) {
+    t.Error("test failed")
+  }
+  if a.Greater(ConstReal(1.5)) || !a.Greater(ConstReal(0.5)) {
+    t.Error("test failed")
+  }
+  if a.Sign() != 1 || b.Sign() != 0 {
+    t.Error("test failed")
+  }
+  if !math.IsNaN(a.GetValue()) || NewBatchReal(2.0).GetValue() != 2.0 {
+    t.Error("test failed")
+  }
+  if !a.Equals(NewBatchRealFromSlice([]float64{1.0, 2.0, 3.0 + 1e-14}), 1e-12) {
+    t.Error("test failed")
+  }
+  func() {
+    defer func() {
+      if recover() == nil {
+        t.Error("test failed")
+      }
+    }()
+    a.Add(a, NewBatchRealFromSlice([]float64{1.0, 2.0}))
+  }()
+  r := NullBatchReal()
+  if data, err := json.Marshal(a); err != nil {
+    t.Error(err)
+  } else {
+    if err := json.Unmarshal(data, r); err != nil {
+      t.Error(err)
+    }
+    if !r.Equals(a, 1e-12) {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestBatchReal3(t *testing.T) {
+  v := NullVector(BatchRealType, 2)
+  v.At(0).Set(NewBatchRealFromSlice([]float64{1.0, 2.0}))
+  v.At(1).Set(NewBatchRealFromSlice([]float64{3.0, 4.0}))
+
+  r := NullBatchReal()
+  r.VdotV(v, v)
+
+  if r.GetBatchValue(0) != 10.0 || r.GetBatchValue(1) != 20.0 {
+    t.Error("test failed")
+  }
+}
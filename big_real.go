@@ -0,0 +1,279 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "bytes"
+import "reflect"
+import "math"
+import "math/big"
+
+/* -------------------------------------------------------------------------- */
+
+// Precision in bits of new BigReal scalars.
+var BigRealPrecision uint = 256
+
+/* -------------------------------------------------------------------------- */
+
+// BigReal is an arbitrary precision scalar without derivatives, which
+// is backed by a big.Float. All operations are carried out with the
+// precision of the receiver, i.e. c.Add(a, b) rounds the sum of a and b
+// to the precision of c. Since big.Float cannot represent NaN, results
+// of invalid operations are flagged separately.
+type BigReal struct {
+  Value big.Float
+  nan   bool
+}
+
+/* register scalar type
+ * -------------------------------------------------------------------------- */
+
+var BigRealType ScalarType = NewBigReal(0.0).Type()
+
+func init() {
+  f := func(value float64) Scalar { return NewBigReal(value) }
+  RegisterScalar(BigRealType, f)
+}
+
+/* constructors
+ * -------------------------------------------------------------------------- */
+
+// Create a new scalar with value v and precision BigRealPrecision.
+func NewBigReal(v float64) *BigReal {
+  return NewBigRealWithPrecision(v, BigRealPrecision)
+}
+
+// Create a new scalar with value v and the given precision in bits.
+func NewBigRealWithPrecision(v float64, prec uint) *BigReal {
+  r := &BigReal{}
+  r.Value.SetPrec(prec)
+  r.SetValue(v)
+  return r
+}
+
+// Create a new scalar from a big.Float. The precision of v is retained.
+func NewBigRealFromFloat(v *big.Float) *BigReal {
+  r := &BigReal{}
+  r.Value.Copy(v)
+  return r
+}
+
+func NullBigReal() *BigReal {
+  return NewBigReal(0.0)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BigReal) Clone() *BigReal {
+  r := &BigReal{nan: a.nan}
+  r.Value.Copy(&a.Value)
+  return r
+}
+
+func (a *BigReal) CloneScalar() Scalar {
+  return a.Clone()
+}
+
+func (a *BigReal) Type() ScalarType {
+  return reflect.TypeOf(a)
+}
+
+func (a *BigReal) ConvertType(t ScalarType) Scalar {
+  switch t {
+  case BigRealType:
+    return a
+  case RealType:
+    return NewReal(a.GetValue())
+  case BareRealType:
+    return NewBareReal(a.GetValue())
+  case ReverseRealType:
+    return NewReverseReal(a.GetValue())
+  case SparseRealType:
+    return NewSparseReal(a.GetValue())
+  case DualRealType:
+    return NewDualReal(a.GetValue())
+  case TaylorRealType:
+    return NewTaylorReal(a.GetValue())
+  case ComplexType:
+    return NewComplex(a.GetValue())
+  case BareReal32Type:
+    return NewBareReal32(a.GetValue())
+  case IntervalType:
+    return NewInterval(a.GetValue())
+  case BatchRealType:
+    return NewBatchReal(a.GetValue())
+  default:
+    panic(fmt.Sprintf("cannot convert `BigReal' to type `%v'", t))
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BigReal) Alloc(n, order int) {
+}
+
+func (c *BigReal) AllocForOne(a ConstScalar) {
+}
+
+func (c *BigReal) AllocForTwo(a, b ConstScalar) {
+}
+
+/* read access
+ * -------------------------------------------------------------------------- */
+
+func (a *BigReal) GetOrder() int {
+  return 0
+}
+
+// Returns the value rounded to the nearest float64.
+func (a *BigReal) GetValue() float64 {
+  if a.nan {
+    return math.NaN()
+  }
+  v, _ := a.Value.Float64()
+  return v
+}
+
+func (a *BigReal) GetLogValue() float64 {
+  return math.Log(a.GetValue())
+}
+
+func (a *BigReal) GetDerivative(i int) float64 {
+  return 0.0
+}
+
+func (a *BigReal) GetHessian(i, j int) float64 {
+  return 0.0
+}
+
+func (a *BigReal) GetN() int {
+  return 0
+}
+
+// Returns the value of the scalar, which must not be modified. The result
+// is nil if the value is NaN.
+func (a *BigReal) GetBigValue() *big.Float {
+  if a.nan {
+    return nil
+  }
+  return &a.Value
+}
+
+// Precision of the scalar in bits.
+func (a *BigReal) GetPrecision() uint {
+  if p := a.Value.Prec(); p != 0 {
+    return p
+  }
+  return BigRealPrecision
+}
+
+func (a *BigReal) IsNaN() bool {
+  return a.nan
+}
+
+/* write access
+ * -------------------------------------------------------------------------- */
+
+func (a *BigReal) Reset() {
+  a.SetValue(0.0)
+}
+
+func (a *BigReal) ResetDerivatives() {
+}
+
+// Set the value to b, which is rounded to the precision of a.
+func (a *BigReal) Set(b ConstScalar) {
+  if r, ok := b.(*BigReal); ok {
+    a.SET(r)
+  } else {
+    a.SetValue(b.GetValue())
+  }
+}
+
+func (a *BigReal) SET(b *BigReal) {
+  a.Value.SetPrec(a.GetPrecision())
+  a.Value.Set(&b.Value)
+  a.nan = b.nan
+}
+
+func (a *BigReal) SetValue(v float64) {
+  a.Value.SetPrec(a.GetPrecision())
+  if math.IsNaN(v) {
+    a.Value.SetInt64(0)
+    a.nan = true
+  } else {
+    a.Value.SetFloat64(v)
+    a.nan = false
+  }
+}
+
+func (a *BigReal) setValue(v float64) {
+  a.SetValue(v)
+}
+
+// Set the value to v, which is rounded to the precision of a.
+func (a *BigReal) SetBigValue(v *big.Float) {
+  a.Value.SetPrec(a.GetPrecision())
+  a.Value.Set(v)
+  a.nan = false
+}
+
+// Change the precision of the scalar. The value is rounded if necessary.
+func (a *BigReal) SetPrecision(prec uint) {
+  a.Value.SetPrec(prec)
+}
+
+func (a *BigReal) SetDerivative(i int, v float64) {
+}
+
+func (a *BigReal) SetHessian(i, j int, v float64) {
+}
+
+func (a *BigReal) SetVariable(i, n, order int) error {
+  return fmt.Errorf("BigReal cannot be used as a variable")
+}
+
+/* type conversion
+ * -------------------------------------------------------------------------- */
+
+func (a *BigReal) String() string {
+  if a.nan {
+    return "NaN"
+  }
+  return a.Value.Text('e', -1)
+}
+
+/* json
+ * -------------------------------------------------------------------------- */
+
+func (obj *BigReal) MarshalJSON() ([]byte, error) {
+  if obj.nan || obj.Value.IsInf() {
+    return nil, fmt.Errorf("json: unsupported value: %v", obj)
+  }
+  return []byte(obj.Value.Text('e', -1)), nil
+}
+
+func (obj *BigReal) UnmarshalJSON(data []byte) error {
+  obj.Value.SetPrec(obj.GetPrecision())
+  if _, _, err := obj.Value.Parse(string(bytes.Trim(data, "\"")), 10); err != nil {
+    return err
+  }
+  obj.nan = false
+  return nil
+}
@@ -0,0 +1,474 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+import "math"
+import "math/big"
+
+/* -------------------------------------------------------------------------- */
+
+// Returns the value of a as big.Float, which must not be modified. Panics
+// with bigRealNaN if a is NaN.
+func bigValueOf(a ConstScalar) *big.Float {
+  if r, ok := a.(*BigReal); ok {
+    if r.nan {
+      panic(bigRealNaN{})
+    }
+    return &r.Value
+  }
+  v := a.GetValue()
+  if math.IsNaN(v) {
+    panic(bigRealNaN{})
+  }
+  return new(big.Float).SetFloat64(v)
+}
+
+// Evaluate f at the precision of c and store the result in c. Invalid
+// operations set c to NaN.
+func (c *BigReal) eval(f func(p uint) *big.Float) *BigReal {
+  defer func() {
+    if r := recover(); r != nil {
+      switch r.(type) {
+      case bigRealNaN:
+      case big.ErrNaN:
+      default:
+        panic(r)
+      }
+      c.SetValue(math.NaN())
+    }
+  }()
+  v := f(c.GetPrecision())
+  c.SetBigValue(v)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BigReal) Equals(b ConstScalar, epsilon float64) bool {
+  if a.nan || math.IsNaN(b.GetValue()) {
+    return false
+  }
+  p := a.GetPrecision()
+  d := bigNew(p).Sub(&a.Value, bigValueOf(b))
+  return bigCmpAbs(d, big.NewFloat(epsilon)) < 0
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BigReal) Greater(b ConstScalar) bool {
+  if a.nan || math.IsNaN(b.GetValue()) {
+    return false
+  }
+  return a.Value.Cmp(bigValueOf(b)) > 0
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BigReal) Smaller(b ConstScalar) bool {
+  if a.nan || math.IsNaN(b.GetValue()) {
+    return false
+  }
+  return a.Value.Cmp(bigValueOf(b)) < 0
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BigReal) Sign() int {
+  if a.nan {
+    return 0
+  }
+  return a.Value.Sign()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *BigReal) Min(a, b ConstScalar) Scalar {
+  if a.Smaller(b) {
+    r.Set(a)
+  } else {
+    r.Set(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *BigReal) Max(a, b ConstScalar) Scalar {
+  if a.Greater(b) {
+    r.Set(a)
+  } else {
+    r.Set(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) Abs(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigNew(p).Abs(bigValueOf(a))
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) Neg(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigNew(p).Neg(bigValueOf(a))
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) Add(a, b ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigNew(p).Add(bigValueOf(a), bigValueOf(b))
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) Sub(a, b ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigNew(p).Sub(bigValueOf(a), bigValueOf(b))
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) Mul(a, b ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigNew(p).Mul(bigValueOf(a), bigValueOf(b))
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) Div(a, b ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigNew(p).Quo(bigValueOf(a), bigValueOf(b))
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) LogAdd(a, b ConstScalar, t Scalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    x := bigValueOf(a)
+    y := bigValueOf(b)
+    if x.Cmp(y) < 0 {
+      x, y = y, x
+    }
+    if y.IsInf() && y.Sign() < 0 || x.IsInf() && x.Sign() > 0 {
+      return x
+    }
+    // log(exp(x) + exp(y)) = x + log1p(exp(y - x))
+    w := p + bigGuardBits
+    r := bigExp(w, bigNew(w).Sub(y, x))
+    r  = bigLog1p(w, r)
+    return r.Add(r, x)
+  })
+}
+
+func (c *BigReal) LogSub(a, b ConstScalar, t Scalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    x := bigValueOf(a)
+    y := bigValueOf(b)
+    if y.IsInf() && y.Sign() < 0 {
+      return x
+    }
+    // log(exp(x) - exp(y)) = x + log1p(-exp(y - x))
+    w := p + bigGuardBits
+    r := bigExp(w, bigNew(w).Sub(y, x))
+    r  = bigLog1p(w, r.Neg(r))
+    return r.Add(r, x)
+  })
+}
+
+func (c *BigReal) Log1pExp(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    w := p + bigGuardBits
+    return bigLog1p(p, bigExp(w, bigValueOf(a)))
+  })
+}
+
+func (c *BigReal) Sigmoid(a ConstScalar, t Scalar) Scalar {
+  return c.Logistic(a)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) Pow(a, k ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigPow(p, bigValueOf(a), bigValueOf(k))
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) Sqrt(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    x := bigValueOf(a)
+    if x.Sign() < 0 {
+      panic(bigRealNaN{})
+    }
+    return bigNew(p).Sqrt(x)
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) Sin(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    r, _ := bigSinCos(p, bigValueOf(a))
+    return r
+  })
+}
+
+func (c *BigReal) Sinh(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigSinh(p, bigValueOf(a))
+  })
+}
+
+func (c *BigReal) Cos(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    _, r := bigSinCos(p, bigValueOf(a))
+    return r
+  })
+}
+
+func (c *BigReal) Cosh(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigCosh(p, bigValueOf(a))
+  })
+}
+
+func (c *BigReal) Tan(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    w := p + bigGuardBits
+    s, r := bigSinCos(w, bigValueOf(a))
+    return r.Quo(s, r)
+  })
+}
+
+func (c *BigReal) Tanh(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigTanh(p, bigValueOf(a))
+  })
+}
+
+func (c *BigReal) Exp(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigExp(p, bigValueOf(a))
+  })
+}
+
+func (c *BigReal) Log(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigLog(p, bigValueOf(a))
+  })
+}
+
+func (c *BigReal) Log1p(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigLog1p(p, bigValueOf(a))
+  })
+}
+
+func (c *BigReal) Logistic(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    w := p + bigGuardBits
+    r := bigExp(w, bigNew(w).Neg(bigValueOf(a)))
+    r.Add(r, bigInt(w, 1))
+    return r.Quo(bigInt(w, 1), r)
+  })
+}
+
+func (c *BigReal) Erf(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigErf(p, bigValueOf(a))
+  })
+}
+
+func (c *BigReal) Erfc(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigErfc(p, bigValueOf(a))
+  })
+}
+
+func (c *BigReal) LogErfc(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    w := p + bigGuardBits
+    return bigLog(p, bigErfc(w, bigValueOf(a)))
+  })
+}
+
+func (c *BigReal) Gamma(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigGamma(p, bigValueOf(a))
+  })
+}
+
+// Logarithm of the gamma function. The result is NaN if the gamma function
+// is negative.
+func (c *BigReal) Lgamma(a ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    r, s := bigLgamma(p, bigValueOf(a))
+    if s < 0 {
+      panic(bigRealNaN{})
+    }
+    return r
+  })
+}
+
+func (c *BigReal) Mlgamma(a ConstScalar, k int) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    w := p + bigGuardBits
+    x := bigValueOf(a)
+    r := bigLog(w, bigPi(w))
+    r.Mul(r, bigInt(w, int64(k*(k-1))))
+    r.SetMantExp(r, -2)
+    for i := 1; i <= k; i++ {
+      // gamma(x + (1-i)/2)
+      t := bigInt(w, int64(1-i))
+      t.SetMantExp(t, -1)
+      t.Add(t, x)
+      g, s := bigLgamma(w, t)
+      if s < 0 {
+        panic(bigRealNaN{})
+      }
+      r.Add(r, g)
+    }
+    return r
+  })
+}
+
+func (c *BigReal) GammaP(a float64, x ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigGammaP(p, a, bigValueOf(x))
+  })
+}
+
+func (c *BigReal) BesselI(v float64, x ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    return bigBesselI(p, v, bigValueOf(x))
+  })
+}
+
+func (c *BigReal) LogBesselI(v float64, x ConstScalar) Scalar {
+  return c.eval(func(p uint) *big.Float {
+    w := p + bigGuardBits
+    return bigLog(p, bigBesselI(w, v, bigValueOf(x)))
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *BigReal) SmoothMax(x ConstVector, alpha ConstReal, t [2]Scalar) Scalar {
+  r   .Reset()
+  t[1].Reset()
+  for i := 0; i < x.Dim(); i++ {
+    t[0].Mul(alpha, x.ConstAt(i))
+    t[0].Exp(t[0])
+    t[1].Add(t[1], t[0])
+    t[0].Mul(t[0], x.ConstAt(i))
+    r .Add(r , t[0])
+  }
+  r.Div(r, t[1])
+  return r
+}
+
+func (r *BigReal) LogSmoothMax(x ConstVector, alpha ConstReal, t [3]Scalar) Scalar {
+  r   .Reset()
+  t[2].SetValue(math.Inf(-1))
+  for i := 0; i < x.Dim(); i++ {
+    t[0].Mul(x.ConstAt(i), alpha)
+    t[2].LogAdd(t[2], t[0], t[1])
+    t[1].Log(x.ConstAt(i))
+    t[0].Add(t[0], t[1])
+    r.LogAdd(r, t[0], t[1])
+  }
+  r.Sub(r, t[2])
+  r.Exp(r)
+  return r
+}
+
+func (r *BigReal) Vmean(a ConstVector) Scalar {
+  r.Reset()
+  for i := 0; i < a.Dim(); i++ {
+    r.Add(r, a.ConstAt(i))
+  }
+  return r.Div(r, ConstReal(float64(a.Dim())))
+}
+
+func (r *BigReal) VdotV(a, b ConstVector) Scalar {
+  if a.Dim() != b.Dim() {
+    panic("vector dimensions do not match")
+  }
+  r.Reset()
+  t := NewBigRealWithPrecision(0.0, r.GetPrecision())
+  for i := 0; i < a.Dim(); i++ {
+    t.Mul(a.ConstAt(i), b.ConstAt(i))
+    r.Add(r, t)
+  }
+  return r
+}
+
+func (r *BigReal) Vnorm(a ConstVector) Scalar {
+  r.Reset()
+  t := NewBigRealWithPrecision(0.0, r.GetPrecision())
+  for it := a.ConstIterator(); it.Ok(); it.Next() {
+    t.Mul(it.GetConst(), it.GetConst())
+    r.Add(r, t)
+  }
+  r.Sqrt(r)
+  return r
+}
+
+func (r *BigReal) Mtrace(a ConstMatrix) Scalar {
+  n, m := a.Dims()
+  if n != m {
+    panic("not a square matrix")
+  }
+  if n == 0 {
+    return nil
+  }
+  r.Reset()
+  for i := 0; i < n; i++ {
+    r.Add(r, a.ConstAt(i,i))
+  }
+  return r
+}
+
+// Frobenius norm.
+func (r *BigReal) Mnorm(a ConstMatrix) Scalar {
+  n, m := a.Dims()
+  if n == 0 || m == 0 {
+    return nil
+  }
+  t := NewBigRealWithPrecision(0.0, r.GetPrecision())
+  v := a.AsConstVector()
+  r.Mul(v.ConstAt(0), v.ConstAt(0))
+  for i := 1; i < v.Dim(); i++ {
+    t.Mul(v.ConstAt(i), v.ConstAt(i))
+    r.Add(r, t)
+  }
+  return r
+}
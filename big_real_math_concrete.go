@@ -0,0 +1,141 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BigReal) EQUALS(b *BigReal, epsilon float64) bool {
+  return a.Equals(b, epsilon)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BigReal) GREATER(b *BigReal) bool {
+  return a.Greater(b)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BigReal) SMALLER(b *BigReal) bool {
+  return a.Smaller(b)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *BigReal) SIGN() int {
+  return a.Sign()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *BigReal) MIN(a, b *BigReal) Scalar {
+  return r.Min(a, b)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *BigReal) MAX(a, b *BigReal) Scalar {
+  return r.Max(a, b)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) ABS(a *BigReal) Scalar {
+  return c.Abs(a)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) NEG(a *BigReal) *BigReal {
+  c.Neg(a)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) ADD(a, b *BigReal) *BigReal {
+  c.Add(a, b)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) SUB(a, b *BigReal) *BigReal {
+  c.Sub(a, b)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) MUL(a, b *BigReal) *BigReal {
+  c.Mul(a, b)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) DIV(a, b *BigReal) *BigReal {
+  c.Div(a, b)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) LOGADD(a, b, t *BigReal) *BigReal {
+  c.LogAdd(a, b, t)
+  return c
+}
+
+func (c *BigReal) LOGSUB(a, b, t *BigReal) *BigReal {
+  c.LogSub(a, b, t)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) POW(a, k *BigReal) *BigReal {
+  c.Pow(a, k)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) SQRT(a *BigReal) *BigReal {
+  c.Sqrt(a)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *BigReal) EXP(a *BigReal) *BigReal {
+  c.Exp(a)
+  return c
+}
+
+func (c *BigReal) LOG(a *BigReal) *BigReal {
+  c.Log(a)
+  return c
+}
+
+func (c *BigReal) LOG1P(a *BigReal) *BigReal {
+  c.Log1p(a)
+  return c
+}
@@ -0,0 +1,636 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+import "math"
+import "math/big"
+import "sync"
+
+/* arbitrary precision functions
+ * -------------------------------------------------------------------------- */
+
+// All functions in this file take the precision p of the result as first
+// argument. Intermediate results are computed with additional guard bits.
+// Arguments are never modified. Invalid operations panic with bigRealNaN,
+// which is recovered by BigReal methods.
+
+const bigGuardBits = 64
+
+type bigRealNaN struct{}
+
+func bigNew(p uint) *big.Float {
+  return new(big.Float).SetPrec(p)
+}
+
+func bigInt(p uint, v int64) *big.Float {
+  return new(big.Float).SetPrec(p).SetInt64(v)
+}
+
+// Returns true if t is negligible compared to r at precision p.
+func bigNegligible(t, r *big.Float, p uint) bool {
+  if t.Sign() == 0 {
+    return true
+  }
+  if r.Sign() == 0 {
+    return false
+  }
+  return t.MantExp(nil) < r.MantExp(nil) - int(p) - 1
+}
+
+// Compare the absolute values of x and y.
+func bigCmpAbs(x, y *big.Float) int {
+  return new(big.Float).Abs(x).Cmp(new(big.Float).Abs(y))
+}
+
+// Returns the exponent of x if it is positive and zero otherwise.
+func bigExponent(x *big.Float) uint {
+  if e := x.MantExp(nil); e > 0 {
+    return uint(e)
+  }
+  return 0
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Series of the inverse tangent (hyperbolic = false) or the inverse
+// hyperbolic tangent (hyperbolic = true) for |z| <= 1/2.
+func bigAtanSeries(p uint, z *big.Float, hyperbolic bool) *big.Float {
+  r  := bigNew(p).Set(z)
+  t  := bigNew(p).Set(z)
+  s  := bigNew(p)
+  z2 := bigNew(p).Mul(z, z)
+  if !hyperbolic {
+    z2.Neg(z2)
+  }
+  for k := int64(1); ; k++ {
+    t.Mul(t, z2)
+    s.Quo(t, bigInt(p, 2*k+1))
+    r.Add(r, s)
+    if bigNegligible(s, r, p) {
+      break
+    }
+  }
+  return r
+}
+
+func bigLn2(p uint) *big.Float {
+  w := p + bigGuardBits
+  // log(2) = 2 atanh(1/3)
+  r := bigAtanSeries(w, bigNew(w).Quo(bigInt(w, 1), bigInt(w, 3)), true)
+  return bigNew(p).SetMantExp(r, 1)
+}
+
+func bigPi(p uint) *big.Float {
+  w := p + bigGuardBits
+  // pi = 16 atan(1/5) - 4 atan(1/239)
+  r1 := bigAtanSeries(w, bigNew(w).Quo(bigInt(w, 1), bigInt(w,   5)), false)
+  r2 := bigAtanSeries(w, bigNew(w).Quo(bigInt(w, 1), bigInt(w, 239)), false)
+  r1.SetMantExp(r1, 2)
+  r1.Sub(r1, r2)
+  return bigNew(p).SetMantExp(r1, 2)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func bigExp(p uint, x *big.Float) *big.Float {
+  switch {
+  case x.IsInf() && x.Sign() > 0:
+    return bigNew(p).SetInf(false)
+  case x.IsInf():
+    return bigNew(p)
+  case x.Sign() == 0:
+    return bigInt(p, 1)
+  }
+  // the exponent of big.Float is limited to 32 bits
+  if v, _ := x.Float64(); math.Abs(v) > 1.4e9 {
+    if v > 0.0 {
+      return bigNew(p).SetInf(false)
+    } else {
+      return bigNew(p)
+    }
+  }
+  w := p + bigGuardBits
+  // x = k log(2) + r with |r| <= log(2)/2
+  l := bigLn2(w + 32)
+  k, _ := bigNew(w).Quo(x, l).Float64()
+  k = math.Floor(k + 0.5)
+  r := bigNew(w + 32).Mul(l, bigNew(64).SetFloat64(k))
+  r.Sub(x, r)
+  // r = r / 2^s
+  s := 16
+  r.SetMantExp(r, -s)
+  // Taylor series
+  t := bigInt(w, 1)
+  y := bigInt(w, 1)
+  for n := int64(1); ; n++ {
+    t.Mul(t, r)
+    t.Quo(t, bigInt(w, n))
+    y.Add(y, t)
+    if bigNegligible(t, y, w) {
+      break
+    }
+  }
+  for i := 0; i < s; i++ {
+    y.Mul(y, y)
+  }
+  return bigNew(p).SetMantExp(y, int(k))
+}
+
+func bigLog(p uint, x *big.Float) *big.Float {
+  switch {
+  case x.Sign() < 0:
+    panic(bigRealNaN{})
+  case x.Sign() == 0:
+    return bigNew(p).SetInf(true)
+  case x.IsInf():
+    return bigNew(p).SetInf(false)
+  }
+  w := p + bigGuardBits
+  // x = m 2^e with 1/sqrt(2) <= m < sqrt(2)
+  m := bigNew(w)
+  e := x.MantExp(m)
+  if m.Cmp(big.NewFloat(math.Sqrt2/2.0)) < 0 {
+    m.SetMantExp(m, 1)
+    e--
+  }
+  // log(m) = 2 atanh((m-1)/(m+1))
+  z := bigNew(w).Sub(m, bigInt(w, 1))
+  z.Quo(z, bigNew(w).Add(m, bigInt(w, 1)))
+  r := bigAtanSeries(w, z, true)
+  r.SetMantExp(r, 1)
+  r.Add(r, bigNew(w).Mul(bigLn2(w + 32), bigInt(w, int64(e))))
+  return bigNew(p).Set(r)
+}
+
+func bigLog1p(p uint, x *big.Float) *big.Float {
+  w := p + bigGuardBits
+  switch c := x.Cmp(bigInt(w, -1)); {
+  case c < 0:
+    panic(bigRealNaN{})
+  case c == 0:
+    return bigNew(p).SetInf(true)
+  }
+  if x.IsInf() {
+    return bigNew(p).SetInf(false)
+  }
+  if x.Cmp(big.NewFloat(-0.5)) > 0 && x.Cmp(big.NewFloat(0.5)) < 0 {
+    // log(1+x) = 2 atanh(x/(2+x))
+    z := bigNew(w).Quo(x, bigNew(w).Add(x, bigInt(w, 2)))
+    r := bigAtanSeries(w, z, true)
+    return bigNew(p).SetMantExp(r, 1)
+  }
+  return bigLog(p, bigNew(w).Add(x, bigInt(w, 1)))
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Returns sin(x) and cos(x).
+func bigSinCos(p uint, x *big.Float) (*big.Float, *big.Float) {
+  if x.IsInf() {
+    panic(bigRealNaN{})
+  }
+  w := p + bigGuardBits + bigExponent(x)
+  // x = k pi/2 + r with |r| <= pi/4
+  h := bigPi(w)
+  h.SetMantExp(h, -1)
+  q := bigNew(w).Quo(x, h)
+  if q.Sign() >= 0 {
+    q.Add(q, big.NewFloat(0.5))
+  } else {
+    q.Sub(q, big.NewFloat(0.5))
+  }
+  k, _ := q.Int(nil)
+  r := bigNew(w).Mul(bigNew(w).SetInt(k), h)
+  r.Sub(x, r)
+  // Taylor series
+  r2 := bigNew(w).Mul(r, r)
+  r2.Neg(r2)
+  s  := bigNew(w).Set(r)
+  c  := bigInt(w, 1)
+  ts := bigNew(w).Set(r)
+  tc := bigInt(w, 1)
+  for n := int64(1); ; n++ {
+    ts.Mul(ts, r2)
+    ts.Quo(ts, bigInt(w, (2*n)*(2*n+1)))
+    tc.Mul(tc, r2)
+    tc.Quo(tc, bigInt(w, (2*n-1)*(2*n)))
+    s.Add(s, ts)
+    c.Add(c, tc)
+    if bigNegligible(ts, s, w) && bigNegligible(tc, c, w) {
+      break
+    }
+  }
+  switch new(big.Int).Mod(k, big.NewInt(4)).Int64() {
+  case 1:
+    s, c = c, s.Neg(s)
+  case 2:
+    s, c = s.Neg(s), c.Neg(c)
+  case 3:
+    s, c = c.Neg(c), s
+  }
+  return bigNew(p).Set(s), bigNew(p).Set(c)
+}
+
+func bigSinh(p uint, x *big.Float) *big.Float {
+  w := p + bigGuardBits
+  if x.IsInf() {
+    return bigNew(p).Set(x)
+  }
+  if bigCmpAbs(x, bigInt(w, 1)) < 0 {
+    // Taylor series
+    x2 := bigNew(w).Mul(x, x)
+    t  := bigNew(w).Set(x)
+    r  := bigNew(w).Set(x)
+    for n := int64(1); ; n++ {
+      t.Mul(t, x2)
+      t.Quo(t, bigInt(w, (2*n)*(2*n+1)))
+      r.Add(r, t)
+      if bigNegligible(t, r, w) {
+        break
+      }
+    }
+    return bigNew(p).Set(r)
+  }
+  r := bigExp(w, x)
+  r.Sub(r, bigExp(w, bigNew(w).Neg(x)))
+  return bigNew(p).SetMantExp(r, -1)
+}
+
+func bigCosh(p uint, x *big.Float) *big.Float {
+  w := p + bigGuardBits
+  if x.IsInf() {
+    return bigNew(p).SetInf(false)
+  }
+  r := bigExp(w, x)
+  r.Add(r, bigExp(w, bigNew(w).Neg(x)))
+  return bigNew(p).SetMantExp(r, -1)
+}
+
+func bigTanh(p uint, x *big.Float) *big.Float {
+  w := p + bigGuardBits
+  if x.IsInf() {
+    return bigInt(p, int64(x.Sign()))
+  }
+  if bigCmpAbs(x, bigInt(w, 1)) < 0 {
+    return bigNew(p).Quo(bigSinh(w, x), bigCosh(w, x))
+  }
+  // tanh(|x|) = 1 - 2/(exp(2|x|) + 1)
+  t := bigNew(w).Abs(x)
+  t.SetMantExp(t, 1)
+  t = bigExp(w, t)
+  t.Add(t, bigInt(w, 1))
+  t.Quo(bigInt(w, 2), t)
+  t.Sub(bigInt(w, 1), t)
+  if x.Sign() < 0 {
+    t.Neg(t)
+  }
+  return bigNew(p).Set(t)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute x^n by repeated squaring.
+func bigPowInt(p uint, x *big.Float, n int64) *big.Float {
+  w := p + bigGuardBits
+  r := bigInt(w, 1)
+  y := bigNew(w).Set(x)
+  m := n
+  if m < 0 {
+    m = -m
+  }
+  for ; m > 0; m >>= 1 {
+    if m & 1 == 1 {
+      r.Mul(r, y)
+    }
+    if m > 1 {
+      y.Mul(y, y)
+    }
+  }
+  if n < 0 {
+    r.Quo(bigInt(w, 1), r)
+  }
+  return bigNew(p).Set(r)
+}
+
+func bigPow(p uint, x, y *big.Float) *big.Float {
+  if y.IsInt() && bigCmpAbs(y, bigInt(64, 1 << 31)) < 0 {
+    n, _ := y.Int64()
+    return bigPowInt(p, x, n)
+  }
+  switch {
+  case x.Sign() < 0:
+    panic(bigRealNaN{})
+  case x.Sign() == 0 && y.Sign() > 0:
+    return bigNew(p)
+  case x.Sign() == 0:
+    return bigNew(p).SetInf(false)
+  }
+  w := p + bigGuardBits + bigExponent(y)
+  r := bigLog(w, x)
+  r.Mul(r, y)
+  return bigExp(p, r)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Error function computed from the series
+//   erf(x) = 2/sqrt(pi) exp(-x^2) sum_n 2^n x^(2n+1) / (1 3 5 ... (2n+1)),
+// which has only terms of equal sign.
+func bigErfSeries(p uint, x *big.Float) *big.Float {
+  w  := p + bigGuardBits
+  x2 := bigNew(w).Mul(x, x)
+  a  := bigNew(w).SetMantExp(x2, 1)
+  t  := bigNew(w).Set(x)
+  r  := bigNew(w).Set(x)
+  for n := int64(1); ; n++ {
+    t.Mul(t, a)
+    t.Quo(t, bigInt(w, 2*n+1))
+    r.Add(r, t)
+    if bigNegligible(t, r, w) && a.Cmp(bigInt(w, n)) < 0 {
+      break
+    }
+  }
+  r.Mul(r, bigExp(w, bigNew(w).Neg(x2)))
+  r.Mul(r, bigInt(w, 2))
+  r.Quo(r, bigNew(w).Sqrt(bigPi(w)))
+  return bigNew(p).Set(r)
+}
+
+// Asymptotic series of the complementary error function for large x > 0
+//   erfc(x) = exp(-x^2)/(x sqrt(pi)) sum_n (-1)^n (2n-1)!! / (2x^2)^n
+func bigErfcAsymptotic(p uint, x *big.Float) *big.Float {
+  w  := p + bigGuardBits
+  x2 := bigNew(w).Mul(x, x)
+  a  := bigNew(w).SetMantExp(x2, 1)
+  t  := bigInt(w, 1)
+  r  := bigInt(w, 1)
+  for n := int64(1); ; n++ {
+    t.Mul(t, bigInt(w, -(2*n-1)))
+    t.Quo(t, a)
+    r.Add(r, t)
+    if bigNegligible(t, r, w) || x2.Cmp(bigInt(w, n)) < 0 {
+      break
+    }
+  }
+  r.Mul(r, bigExp(w, bigNew(w).Neg(x2)))
+  r.Quo(r, x)
+  r.Quo(r, bigNew(w).Sqrt(bigPi(w)))
+  return bigNew(p).Set(r)
+}
+
+// Returns true if the asymptotic series of erfc is accurate at
+// precision p.
+func bigErfcUseAsymptotic(p uint, x *big.Float) bool {
+  v, _ := x.Float64()
+  return v*v > float64(p + bigGuardBits + 8)*math.Ln2
+}
+
+func bigErf(p uint, x *big.Float) *big.Float {
+  if x.IsInf() {
+    return bigInt(p, int64(x.Sign()))
+  }
+  if bigErfcUseAsymptotic(p, x) {
+    w := p + bigGuardBits
+    r := bigErfcAsymptotic(w, bigNew(w).Abs(x))
+    r.Sub(bigInt(w, 1), r)
+    if x.Sign() < 0 {
+      r.Neg(r)
+    }
+    return bigNew(p).Set(r)
+  }
+  return bigErfSeries(p, x)
+}
+
+func bigErfc(p uint, x *big.Float) *big.Float {
+  switch {
+  case x.IsInf() && x.Sign() > 0:
+    return bigNew(p)
+  case x.IsInf():
+    return bigInt(p, 2)
+  case x.Sign() <= 0:
+    r := bigErf(p + bigGuardBits, x)
+    return bigNew(p).Sub(bigInt(p, 1), r)
+  case bigErfcUseAsymptotic(p, x):
+    return bigErfcAsymptotic(p, x)
+  }
+  // compensate for cancellation in 1 - erf(x)
+  v, _ := x.Float64()
+  w := p + bigGuardBits + uint(v*v/math.Ln2)
+  r := bigErfSeries(w, x)
+  return bigNew(p).Sub(bigInt(w, 1), r)
+}
+
+/* -------------------------------------------------------------------------- */
+
+var bigBernoulli struct {
+  sync.Once
+  b []*big.Rat
+}
+
+// Returns the Bernoulli numbers B_0, ..., B_80 computed with the
+// Akiyama-Tanigawa algorithm.
+func bigBernoulliNumbers() []*big.Rat {
+  bigBernoulli.Do(func() {
+    n := 80
+    a := make([]*big.Rat, n+1)
+    b := make([]*big.Rat, n+1)
+    for m := 0; m <= n; m++ {
+      a[m] = big.NewRat(1, int64(m+1))
+      for j := m; j >= 1; j-- {
+        a[j-1].Sub(a[j-1], a[j])
+        a[j-1].Mul(a[j-1], big.NewRat(int64(j), 1))
+      }
+      b[m] = new(big.Rat).Set(a[0])
+    }
+    bigBernoulli.b = b
+  })
+  return bigBernoulli.b
+}
+
+// Logarithm of the gamma function for x > 0 computed with Stirling's
+// series after shifting the argument.
+func bigLgammaPositive(p uint, x *big.Float) *big.Float {
+  if x.IsInf() {
+    return bigNew(p).SetInf(false)
+  }
+  w := p + bigGuardBits
+  b := bigBernoulliNumbers()
+  // the argument must be large enough for the series to converge
+  // with at most 40 terms
+  zmin := math.Max(0.12*float64(w), math.Exp2(float64(w+180)/79.0))
+  // q = x (x+1) ... (x+N-1) and z = x + N
+  q := bigInt(w, 1)
+  z := bigNew(w).Set(x)
+  for v, _ := z.Float64(); v < zmin; v += 1.0 {
+    q.Mul(q, z)
+    z.Add(z, bigInt(w, 1))
+  }
+  // (z - 1/2) log(z) - z + log(2 pi)/2
+  r := bigNew(w).Sub(z, big.NewFloat(0.5))
+  r.Mul(r, bigLog(w, z))
+  r.Sub(r, z)
+  t := bigPi(w)
+  t.SetMantExp(t, 1)
+  t = bigLog(w, t)
+  t.SetMantExp(t, -1)
+  r.Add(r, t)
+  // sum_k B_2k / (2k (2k-1) z^(2k-1))
+  zk := bigNew(w).Set(z)
+  z2 := bigNew(w).Mul(z, z)
+  for k := 1; 2*k < len(b); k++ {
+    t.SetRat(b[2*k])
+    t.Quo(t, bigInt(w, int64(2*k*(2*k-1))))
+    t.Quo(t, zk)
+    r.Add(r, t)
+    if bigNegligible(t, r, w) {
+      break
+    }
+    zk.Mul(zk, z2)
+  }
+  r.Sub(r, bigLog(w, q))
+  return bigNew(p).Set(r)
+}
+
+// Returns the logarithm of the absolute value of the gamma function and
+// its sign.
+func bigLgamma(p uint, x *big.Float) (*big.Float, int) {
+  switch {
+  case x.Sign() > 0:
+    return bigLgammaPositive(p, x), 1
+  case x.IsInf():
+    panic(bigRealNaN{})
+  case x.IsInt():
+    return bigNew(p).SetInf(false), 1
+  }
+  w := p + bigGuardBits + bigExponent(x)
+  // reflection formula
+  //   log |gamma(x)| = log(pi) - log |sin(pi x)| - log gamma(1-x)
+  t := bigPi(w)
+  s, _ := bigSinCos(w, bigNew(w).Mul(t, x))
+  r := bigLog(w, t)
+  r.Sub(r, bigLog(w, bigNew(w).Abs(s)))
+  r.Sub(r, bigLgammaPositive(w, bigNew(w).Sub(bigInt(w, 1), x)))
+  return bigNew(p).Set(r), s.Sign()
+}
+
+func bigGamma(p uint, x *big.Float) *big.Float {
+  switch {
+  case x.Sign() == 0:
+    return bigNew(p).SetInf(false)
+  case x.Sign() < 0 && x.IsInt():
+    panic(bigRealNaN{})
+  }
+  w := p + bigGuardBits
+  r, s := bigLgamma(w, x)
+  r = bigExp(p, r)
+  if s < 0 {
+    r.Neg(r)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Regularized lower incomplete gamma function computed from the series
+//   P(a, x) = x^a exp(-x) / gamma(a+1) sum_n x^n / ((a+1) ... (a+n))
+func bigGammaP(p uint, a float64, x *big.Float) *big.Float {
+  switch {
+  case !(a > 0.0) || x.Sign() < 0:
+    panic(bigRealNaN{})
+  case x.Sign() == 0:
+    return bigNew(p)
+  case x.IsInf():
+    return bigInt(p, 1)
+  }
+  w := p + bigGuardBits + bigExponent(x)
+  b := bigNew(w).SetFloat64(a)
+  // prefactor
+  f := bigLog(w, x)
+  f.Mul(f, b)
+  f.Sub(f, x)
+  f.Sub(f, bigLgammaPositive(w, bigNew(w).Add(b, bigInt(w, 1))))
+  f = bigExp(w, f)
+  // series
+  t := bigInt(w, 1)
+  r := bigInt(w, 1)
+  for n := int64(1); ; n++ {
+    c := bigNew(w).Add(b, bigInt(w, n))
+    t.Mul(t, x)
+    t.Quo(t, c)
+    r.Add(r, t)
+    if bigNegligible(t, r, w) && c.Cmp(bigNew(w).SetMantExp(x, 1)) > 0 {
+      break
+    }
+  }
+  return bigNew(p).Mul(r, f)
+}
+
+// Modified Bessel function of the first kind computed from the series
+//   I_v(x) = sum_k (x/2)^(2k+v) / (k! gamma(k+v+1))
+func bigBesselI(p uint, v float64, x *big.Float) *big.Float {
+  if v < 0.0 && v == math.Floor(v) {
+    // I_{-n} = I_n
+    v = -v
+  }
+  if x.IsInf() {
+    panic(bigRealNaN{})
+  }
+  w := p + bigGuardBits + bigExponent(x)
+  h := bigNew(w).Abs(x)
+  h.SetMantExp(h, -1)
+  if x.Sign() < 0 && v != math.Floor(v) {
+    panic(bigRealNaN{})
+  }
+  if x.Sign() == 0 {
+    switch {
+    case v == 0.0:
+      return bigInt(p, 1)
+    case v  > 0.0:
+      return bigNew(p)
+    default:
+      return bigNew(p).SetInf(false)
+    }
+  }
+  b := bigNew(w).SetFloat64(v)
+  // t = (x/2)^v / gamma(v+1)
+  g, s := bigLgamma(w, bigNew(w).Add(b, bigInt(w, 1)))
+  t := bigLog(w, h)
+  t.Mul(t, b)
+  t.Sub(t, g)
+  t = bigExp(w, t)
+  if s < 0 {
+    t.Neg(t)
+  }
+  r  := bigNew(w).Set(t)
+  h2 := bigNew(w).Mul(h, h)
+  for k := int64(1); ; k++ {
+    c := bigNew(w).Add(b, bigInt(w, k))
+    c.Mul(c, bigInt(w, k))
+    t.Mul(t, h2)
+    t.Quo(t, c)
+    r.Add(r, t)
+    if bigNegligible(t, r, w) && c.Cmp(bigNew(w).SetMantExp(h2, 1)) > 0 {
+      break
+    }
+  }
+  if x.Sign() < 0 && math.Mod(v, 2.0) == 1.0 {
+    r.Neg(r)
+  }
+  return bigNew(p).Set(r)
+}
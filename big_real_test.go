@@ -0,0 +1,119 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "math"
+import "math/big"
+import "testing"
+
+import "github.com/pbenner/autodiff/special"
+
+/* -------------------------------------------------------------------------- */
+
+func TestBigReal1(t *testing.T) {
+
+  // reference values with 60 digits
+  check := func(r Scalar, s string) {
+    v, _, err := big.ParseFloat(s, 10, 256, big.ToNearestEven)
+    if err != nil {
+      panic(err)
+    }
+    d := new(big.Float).Sub(r.(*BigReal).GetBigValue(), v)
+    d.Quo(d, v)
+    if d.Abs(d).Cmp(big.NewFloat(1e-58)) > 0 {
+      t.Errorf("test failed for `%s'", s)
+    }
+  }
+  r := NullBigReal()
+  check(r.Exp(ConstReal(1.0)),
+    "2.71828182845904523536028747135266249775724709369995957496697")
+  check(r.Log(ConstReal(2.0)),
+    "0.693147180559945309417232121458176568075500134360255254120680")
+  check(r.Gamma(ConstReal(0.5)),
+    "1.77245385090551602729816748334114518279754945612238712821381")
+  check(r.Erf(ConstReal(1.0)),
+    "0.842700792949714869341220635082609259296066997966302908459938")
+  check(r.Sin(ConstReal(3.0)),
+    "0.141120008059867222100744802808110279846933264252265584151882")
+  check(r.Sqrt(ConstReal(2.0)),
+    "1.41421356237309504880168872420969807856967187537694807317668")
+}
+
+func TestBigReal2(t *testing.T) {
+
+  f := []func(Scalar, float64) (Scalar, float64){
+    func(r Scalar, x float64) (Scalar, float64) { return r.Exp    (ConstReal(x)), math.Exp(x) },
+    func(r Scalar, x float64) (Scalar, float64) { return r.Log1p  (ConstReal(x)), math.Log1p(x) },
+    func(r Scalar, x float64) (Scalar, float64) { return r.Cos    (ConstReal(x)), math.Cos(x) },
+    func(r Scalar, x float64) (Scalar, float64) { return r.Tanh   (ConstReal(x)), math.Tanh(x) },
+    func(r Scalar, x float64) (Scalar, float64) { return r.Sinh   (ConstReal(x)), math.Sinh(x) },
+    func(r Scalar, x float64) (Scalar, float64) { return r.Erfc   (ConstReal(x)), math.Erfc(x) },
+    func(r Scalar, x float64) (Scalar, float64) { v, _ := math.Lgamma(x); return r.Lgamma(ConstReal(x)), v },
+    func(r Scalar, x float64) (Scalar, float64) { return r.Gamma  (ConstReal(x)), math.Gamma(x) },
+    func(r Scalar, x float64) (Scalar, float64) { return r.GammaP (2.5, ConstReal(x)), special.GammaP(2.5, x) },
+    func(r Scalar, x float64) (Scalar, float64) { return r.BesselI(1.5, ConstReal(x)), special.BesselI(1.5, x) },
+    func(r Scalar, x float64) (Scalar, float64) { return r.Pow    (ConstReal(x), ConstReal(2.3)), math.Pow(x, 2.3) },
+  }
+  for i, fi := range f {
+    for _, x := range []float64{0.1, 0.7, 3.2, 7.5} {
+      r, v := fi(NullBigReal(), x)
+      if math.Abs(r.GetValue() - v) > 1e-12*math.Abs(v) {
+        t.Errorf("test %d failed for x = %f", i, x)
+      }
+    }
+  }
+}
+
+func TestBigReal3(t *testing.T) {
+
+  r := NewBigRealWithPrecision(0.0, 512)
+
+  if r.Log(ConstReal(-1.0)); !r.IsNaN() || !math.IsNaN(r.GetValue()) {
+    t.Error("test failed")
+  }
+  if r.Add(r, ConstReal(1.0)); !r.IsNaN() {
+    t.Error("test failed")
+  }
+  if r.SetValue(1.0); r.IsNaN() || r.GetPrecision() != 512 {
+    t.Error("test failed")
+  }
+  // exp(-1000) is not representable as float64
+  r.Exp(ConstReal(-1000.0))
+  r.Log(r)
+  if r.GetValue() != -1000.0 {
+    t.Error("test failed")
+  }
+}
+
+func TestBigReal4(t *testing.T) {
+
+  a := NewMatrix(BigRealType, 2, 2, []float64{1, 2, 3, 4})
+  x := NewVector(BigRealType, []float64{1, 2})
+  r := NullVector(BigRealType, 2)
+
+  if _, ok := a.(*DenseBigRealMatrix); !ok {
+    t.Error("test failed")
+  }
+  r.MdotV(a, x)
+
+  if r.ValueAt(0) != 5.0 || r.ValueAt(1) != 11.0 {
+    t.Error("test failed")
+  }
+}
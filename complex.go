@@ -0,0 +1,399 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "encoding/json"
+import "reflect"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Scalars with complex values implement this interface. The methods of
+// ConstScalar give access to the real part only.
+type ConstComplexScalar interface {
+  ConstScalar
+  GetComplexValue     ()         complex128
+  GetComplexDerivative(int)      complex128
+  GetComplexHessian   (int, int) complex128
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Complex is a scalar with a complex value. First and second derivatives
+// with respect to real variables are stored as complex numbers. Complex
+// implements the Scalar interface, where GetValue, GetDerivative and
+// GetHessian return real parts and comparisons are based on the real
+// part. Arguments of other scalar types are treated as real numbers.
+type Complex struct {
+  Value            complex128
+  Order            int
+  Derivative     []complex128
+  Hessian      [][]complex128
+  N                int
+}
+
+/* register scalar type
+ * -------------------------------------------------------------------------- */
+
+var ComplexType ScalarType = NewComplex(0.0).Type()
+
+func init() {
+  f := func(value float64) Scalar { return NewComplex(value) }
+  RegisterScalar(ComplexType, f)
+}
+
+/* constructors
+ * -------------------------------------------------------------------------- */
+
+// Create a new complex scalar with real value v.
+func NewComplex(v float64) *Complex {
+  return &Complex{Value: complex(v, 0.0)}
+}
+
+func NewComplex128(v complex128) *Complex {
+  return &Complex{Value: v}
+}
+
+func NullComplex() *Complex {
+  return &Complex{}
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *Complex) Clone() *Complex {
+  r := NullComplex()
+  r.SET(a)
+  return r
+}
+
+func (a *Complex) CloneScalar() Scalar {
+  return a.Clone()
+}
+
+func (a *Complex) Type() ScalarType {
+  return reflect.TypeOf(a)
+}
+
+func (a *Complex) ConvertType(t ScalarType) Scalar {
+  switch t {
+  case ComplexType:
+    return a
+  case RealType:
+    return NewReal(a.GetValue())
+  case BareRealType:
+    return NewBareReal(a.GetValue())
+  case ReverseRealType:
+    return NewReverseReal(a.GetValue())
+  case SparseRealType:
+    return NewSparseReal(a.GetValue())
+  case DualRealType:
+    return NewDualReal(a.GetValue())
+  case TaylorRealType:
+    return NewTaylorReal(a.GetValue())
+  case BareReal32Type:
+    return NewBareReal32(a.GetValue())
+  case IntervalType:
+    return NewInterval(a.GetValue())
+  case BigRealType:
+    return NewBigReal(a.GetValue())
+  case BatchRealType:
+    return NewBatchReal(a.GetValue())
+  default:
+    panic(fmt.Sprintf("cannot convert `Complex' to type `%v'", t))
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Allocate memory for derivatives of n variables.
+func (a *Complex) Alloc(n, order int) {
+  if a.N != n || a.Order != order {
+    a.N     = n
+    a.Order = order
+    if a.Order >= 1 {
+      a.Derivative = make([]complex128, n)
+      if a.Order >= 2 {
+        a.Hessian = make([][]complex128, n)
+        for i := 0; i < n; i++ {
+          a.Hessian[i] = make([]complex128, n)
+        }
+      } else {
+        a.Hessian = nil
+      }
+    } else {
+      a.Derivative = nil
+      a.Hessian    = nil
+    }
+  }
+}
+
+func (c *Complex) AllocForOne(a ConstScalar) {
+  c.Alloc(a.GetN(), a.GetOrder())
+}
+
+func (c *Complex) AllocForTwo(a, b ConstScalar) {
+  c.Alloc(iMax(a.GetN(), b.GetN()), iMax(a.GetOrder(), b.GetOrder()))
+}
+
+/* read access
+ * -------------------------------------------------------------------------- */
+
+func (a *Complex) GetOrder() int {
+  return a.Order
+}
+
+// Returns the real part of the value.
+func (a *Complex) GetValue() float64 {
+  return real(a.Value)
+}
+
+func (a *Complex) GetLogValue() float64 {
+  return math.Log(real(a.Value))
+}
+
+// Returns the real part of the derivative of the ith variable.
+func (a *Complex) GetDerivative(i int) float64 {
+  return real(a.GetComplexDerivative(i))
+}
+
+func (a *Complex) GetHessian(i, j int) float64 {
+  return real(a.GetComplexHessian(i, j))
+}
+
+func (a *Complex) GetN() int {
+  return a.N
+}
+
+func (a *Complex) GetComplexValue() complex128 {
+  return a.Value
+}
+
+func (a *Complex) GetComplexDerivative(i int) complex128 {
+  if a.Order >= 1 {
+    return a.Derivative[i]
+  } else {
+    return 0.0
+  }
+}
+
+func (a *Complex) GetComplexHessian(i, j int) complex128 {
+  if a.Order >= 2 {
+    return a.Hessian[i][j]
+  } else {
+    return 0.0
+  }
+}
+
+// Returns the imaginary part of the value.
+func (a *Complex) GetImag() float64 {
+  return imag(a.Value)
+}
+
+/* write access
+ * -------------------------------------------------------------------------- */
+
+func (a *Complex) Reset() {
+  a.Value = 0.0
+  a.ResetDerivatives()
+}
+
+func (a *Complex) ResetDerivatives() {
+  if a.Order >= 1 {
+    for i := 0; i < a.N; i++ {
+      a.Derivative[i] = 0.0
+    }
+    if a.Order >= 2 {
+      for i := 0; i < a.N; i++ {
+        for j := 0; j < a.N; j++ {
+          a.Hessian[i][j] = 0.0
+        }
+      }
+    }
+  }
+}
+
+// Set the state to b. This includes the value and all derivatives. The
+// imaginary part is zero if b is not a complex scalar.
+func (a *Complex) Set(b ConstScalar) {
+  if r, ok := b.(*Complex); ok {
+    a.SET(r)
+    return
+  }
+  a.Alloc(b.GetN(), b.GetOrder())
+  if a.Order >= 1 {
+    for i := 0; i < a.N; i++ {
+      a.Derivative[i] = complexDerivativeOf(b, i)
+    }
+    if a.Order >= 2 {
+      for i := 0; i < a.N; i++ {
+        for j := 0; j < a.N; j++ {
+          a.Hessian[i][j] = complexHessianOf(b, i, j)
+        }
+      }
+    }
+  }
+  a.Value = complexValueOf(b)
+}
+
+func (a *Complex) SET(b *Complex) {
+  if a == b {
+    return
+  }
+  a.Alloc(b.N, b.Order)
+  if a.Order >= 1 {
+    copy(a.Derivative, b.Derivative)
+    if a.Order >= 2 {
+      for i := 0; i < a.N; i++ {
+        copy(a.Hessian[i], b.Hessian[i])
+      }
+    }
+  }
+  a.Value = b.Value
+}
+
+// Set the value to the real number v. All derivatives are reset to zero.
+func (a *Complex) SetValue(v float64) {
+  a.SetComplexValue(complex(v, 0.0))
+}
+
+func (a *Complex) setValue(v float64) {
+  a.Value = complex(v, 0.0)
+}
+
+// Set the value to v. All derivatives are reset to zero.
+func (a *Complex) SetComplexValue(v complex128) {
+  a.Value = v
+  a.ResetDerivatives()
+}
+
+func (a *Complex) SetDerivative(i int, v float64) {
+  a.Derivative[i] = complex(v, 0.0)
+}
+
+func (a *Complex) SetHessian(i, j int, v float64) {
+  a.Hessian[i][j] = complex(v, 0.0)
+}
+
+func (a *Complex) SetComplexDerivative(i int, v complex128) {
+  a.Derivative[i] = v
+}
+
+func (a *Complex) SetComplexHessian(i, j int, v complex128) {
+  a.Hessian[i][j] = v
+}
+
+// Allocate memory for n variables and set the derivative of the ith
+// variable to 1. Variables are always real.
+func (a *Complex) SetVariable(i, n, order int) error {
+  if order > 2 {
+    return fmt.Errorf("order `%d' not supported by this type", order)
+  }
+  a.Alloc(n, order)
+  a.ResetDerivatives()
+  if order > 0 {
+    a.Derivative[i] = 1
+  }
+  return nil
+}
+
+/* type conversion
+ * -------------------------------------------------------------------------- */
+
+func (a *Complex) String() string {
+  return fmt.Sprintf("%e", a.Value)
+}
+
+/* json
+ * -------------------------------------------------------------------------- */
+
+// Complex numbers are stored as pairs of real and imaginary parts.
+type complexJson [2]float64
+
+func newComplexJson(v complex128) complexJson {
+  return complexJson{real(v), imag(v)}
+}
+
+func (v complexJson) value() complex128 {
+  return complex(v[0], v[1])
+}
+
+func (obj *Complex) MarshalJSON() ([]byte, error) {
+  if obj.Order > 0 && obj.N > 0 {
+    r := struct{Value complexJson; Derivative []complexJson; Hessian [][]complexJson}{}
+    r.Value      = newComplexJson(obj.Value)
+    r.Derivative = make([]complexJson, obj.N)
+    for i := 0; i < obj.N; i++ {
+      r.Derivative[i] = newComplexJson(obj.Derivative[i])
+    }
+    if obj.Order > 1 {
+      r.Hessian = make([][]complexJson, obj.N)
+      for i := 0; i < obj.N; i++ {
+        r.Hessian[i] = make([]complexJson, obj.N)
+        for j := 0; j < obj.N; j++ {
+          r.Hessian[i][j] = newComplexJson(obj.Hessian[i][j])
+        }
+      }
+    }
+    return json.Marshal(r)
+  } else {
+    return json.Marshal(newComplexJson(obj.Value))
+  }
+}
+
+func (obj *Complex) UnmarshalJSON(data []byte) error {
+  r := struct{Value complexJson; Derivative []complexJson; Hessian [][]complexJson}{}
+  if err := json.Unmarshal(data, &r); err == nil {
+    n := len(r.Derivative)
+    if len(r.Hessian) != 0 {
+      if len(r.Hessian) != n {
+        return fmt.Errorf("invalid json scalar representation")
+      }
+      obj.Alloc(n, 2)
+    } else {
+      obj.Alloc(n, 1)
+    }
+    for i := 0; i < n; i++ {
+      obj.Derivative[i] = r.Derivative[i].value()
+    }
+    for i := 0; i < len(r.Hessian); i++ {
+      if len(r.Hessian[i]) != n {
+        return fmt.Errorf("invalid json scalar representation")
+      }
+      for j := 0; j < n; j++ {
+        obj.Hessian[i][j] = r.Hessian[i][j].value()
+      }
+    }
+    obj.Value = r.Value.value()
+    return nil
+  } else {
+    v := complexJson{}
+    if err := json.Unmarshal(data, &v); err != nil {
+      // accept real numbers
+      x := 0.0
+      if err := json.Unmarshal(data, &x); err != nil {
+        return err
+      }
+      v[0] = x
+    }
+    obj.Alloc(0, 0)
+    obj.Value = v.value()
+    return nil
+  }
+}
@@ -0,0 +1,162 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+// Scalars that do not implement ConstComplexScalar have real values and
+// derivatives.
+func complexValueOf(a ConstScalar) complex128 {
+  if r, ok := a.(ConstComplexScalar); ok {
+    return r.GetComplexValue()
+  }
+  return complex(a.GetValue(), 0.0)
+}
+
+func complexDerivativeOf(a ConstScalar, i int) complex128 {
+  if i >= a.GetN() {
+    return 0.0
+  }
+  if r, ok := a.(ConstComplexScalar); ok {
+    return r.GetComplexDerivative(i)
+  }
+  return complex(a.GetDerivative(i), 0.0)
+}
+
+func complexHessianOf(a ConstScalar, i, j int) complex128 {
+  if i >= a.GetN() || j >= a.GetN() {
+    return 0.0
+  }
+  if r, ok := a.(ConstComplexScalar); ok {
+    return r.GetComplexHessian(i, j)
+  }
+  return complex(a.GetHessian(i, j), 0.0)
+}
+
+/* derivatives of monadic functions
+ * -------------------------------------------------------------------------- */
+
+// Compute derivatives of c = f(a) for a holomorphic function f, where
+// - v0 = f(a)
+// - v1 = d/dz f(z) | z=a
+// - v2 = d^2/dz^2 f(z) | z=a
+func (c *Complex) monadic(a ConstScalar, v0, v1, v2 complex128) *Complex {
+  c.AllocForOne(a)
+  if c.Order >= 1 {
+    if c.Order >= 2 {
+      // compute hessian
+      for i := 0; i < c.N; i++ {
+        for j := i; j < c.N; j++ {
+          c.Hessian[i][j] =
+            complexDerivativeOf(a, i)*complexDerivativeOf(a, j)*v2 +
+            complexHessianOf(a, i, j)*v1
+          c.Hessian[j][i] = c.Hessian[i][j]
+        }
+      }
+    }
+    // compute first derivatives
+    for i := 0; i < c.N; i++ {
+      c.Derivative[i] = complexDerivativeOf(a, i)*v1
+    }
+  }
+  // compute new value
+  c.Value = v0
+  return c
+}
+
+func (c *Complex) monadicLazy(a ConstScalar, v0 complex128, f1, f2 func() complex128) *Complex {
+  v1, v2 := complex128(0.0), complex128(0.0)
+  if a.GetOrder() >= 1 {
+    v1 = f1()
+    if a.GetOrder() >= 2 {
+      v2 = f2()
+    }
+  }
+  return c.monadic(a, v0, v1, v2)
+}
+
+// Compute c = f(a) for an R-linear function f, which is applied to the
+// value and all derivatives. This allows to compute derivatives of
+// functions that are not holomorphic, such as the complex conjugate.
+func (c *Complex) linear(a ConstScalar, f func(complex128) complex128) *Complex {
+  c.AllocForOne(a)
+  if c.Order >= 1 {
+    if c.Order >= 2 {
+      for i := 0; i < c.N; i++ {
+        for j := 0; j < c.N; j++ {
+          c.Hessian[i][j] = f(complexHessianOf(a, i, j))
+        }
+      }
+    }
+    for i := 0; i < c.N; i++ {
+      c.Derivative[i] = f(complexDerivativeOf(a, i))
+    }
+  }
+  c.Value = f(complexValueOf(a))
+  return c
+}
+
+/* derivatives of dyadic functions
+ * -------------------------------------------------------------------------- */
+
+func (c *Complex) dyadic(a, b ConstScalar, v0, v10, v01, v11, v20, v02 complex128) *Complex {
+  c.AllocForTwo(a, b)
+  if c.Order >= 1 {
+    if c.Order >= 2 {
+      // compute hessian
+      for i := 0; i < c.N; i++ {
+        for j := i; j < c.N; j++ {
+          dai := complexDerivativeOf(a, i)
+          daj := complexDerivativeOf(a, j)
+          dbi := complexDerivativeOf(b, i)
+          dbj := complexDerivativeOf(b, j)
+          c.Hessian[i][j] =
+            complexHessianOf(a, i, j)*v10 +
+            complexHessianOf(b, i, j)*v01 +
+            dai*daj*v20 +
+            dbi*dbj*v02 +
+            dai*dbj*v11 +
+            dbi*daj*v11
+          c.Hessian[j][i] = c.Hessian[i][j]
+        }
+      }
+    }
+    // compute first derivatives
+    for i := 0; i < c.N; i++ {
+      c.Derivative[i] = complexDerivativeOf(a, i)*v10 + complexDerivativeOf(b, i)*v01
+    }
+  }
+  // compute new value
+  c.Value = v0
+  return c
+}
+
+func (c *Complex) dyadicLazy(a, b ConstScalar, v0 complex128, f1 func() (complex128, complex128), f2 func() (complex128, complex128, complex128)) *Complex {
+  v10, v01, v11, v20, v02 := complex128(0.0), complex128(0.0), complex128(0.0), complex128(0.0), complex128(0.0)
+  order := iMax(a.GetOrder(), b.GetOrder())
+  if order >= 1 {
+    v10, v01 = f1()
+    if order >= 2 {
+      v11, v20, v02 = f2()
+    }
+  }
+  return c.dyadic(a, b, v0, v10, v01, v11, v20, v02)
+}
@@ -0,0 +1,541 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "math"
+import "math/cmplx"
+
+/* -------------------------------------------------------------------------- */
+
+func (a *Complex) Equals(b ConstScalar, epsilon float64) bool {
+  return cmplx.Abs(a.GetComplexValue() - complexValueOf(b)) < epsilon
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compare real parts.
+func (a *Complex) Greater(b ConstScalar) bool {
+  return a.GetValue() > b.GetValue()
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compare real parts.
+func (a *Complex) Smaller(b ConstScalar) bool {
+  return a.GetValue() < b.GetValue()
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Sign of the real part.
+func (a *Complex) Sign() int {
+  if a.GetValue() < 0.0 {
+    return -1
+  }
+  if a.GetValue() > 0.0 {
+    return  1
+  }
+  return 0
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *Complex) Min(a, b ConstScalar) Scalar {
+  if a.GetValue() < b.GetValue() {
+    r.Set(a)
+  } else {
+    r.Set(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *Complex) Max(a, b ConstScalar) Scalar {
+  if a.GetValue() > b.GetValue() {
+    r.Set(a)
+  } else {
+    r.Set(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the modulus |a|. The result has a zero imaginary part.
+func (c *Complex) Abs(a ConstScalar) Scalar {
+  if complexValueOf(a) == 0.0 {
+    c.Reset()
+    return c
+  }
+  // |a| = exp(Re(log a))
+  c.Log(a)
+  c.RealPart(c)
+  c.Exp(c)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the argument (phase) of a. The result has a zero imaginary part.
+func (c *Complex) Arg(a ConstScalar) Scalar {
+  // arg(a) = Im(log a)
+  c.Log(a)
+  c.ImagPart(c)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the complex conjugate of a.
+func (c *Complex) Conj(a ConstScalar) Scalar {
+  return c.linear(a, cmplx.Conj)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Set c to the real part of a.
+func (c *Complex) RealPart(a ConstScalar) Scalar {
+  return c.linear(a, func(z complex128) complex128 { return complex(real(z), 0.0) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Set c to the imaginary part of a.
+func (c *Complex) ImagPart(a ConstScalar) Scalar {
+  return c.linear(a, func(z complex128) complex128 { return complex(imag(z), 0.0) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) Neg(a ConstScalar) Scalar {
+  x := complexValueOf(a)
+  return c.monadic(a, -x, -1, 0)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) Add(a, b ConstScalar) Scalar {
+  x := complexValueOf(a)
+  y := complexValueOf(b)
+  return c.dyadic(a, b, x+y, 1, 1, 0, 0, 0)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) Sub(a, b ConstScalar) Scalar {
+  x := complexValueOf(a)
+  y := complexValueOf(b)
+  return c.dyadic(a, b, x-y, 1, -1, 0, 0, 0)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) Mul(a, b ConstScalar) Scalar {
+  x := complexValueOf(a)
+  y := complexValueOf(b)
+  return c.dyadic(a, b, x*y, y, x, 1, 0, 0)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) Div(a, b ConstScalar) Scalar {
+  x := complexValueOf(a)
+  y := complexValueOf(b)
+  return c.dyadic(a, b, x/y, 1/y, -x/(y*y), -1/(y*y), 0, 2*x/(y*y*y))
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) LogAdd(a, b ConstScalar, t Scalar) Scalar {
+  if a.Greater(b) {
+    // swap
+    a, b = b, a
+  }
+  if math.IsInf(a.GetValue(), 0) {
+    // cases:
+    //  i) a = -Inf and b >= a    => c = b
+    // ii) a =  Inf and b  = Inf  => c = Inf
+    c.Set(b)
+    return c
+  }
+  t.Sub(a, b)
+  t.Exp(t)
+  t.Log1p(t)
+  c.Add(t, b)
+  return c
+}
+
+func (c *Complex) LogSub(a, b ConstScalar, t Scalar) Scalar {
+  if math.IsInf(b.GetValue(), -1) {
+    c.Set(a)
+    return c
+  }
+  //   log(exp(a) - exp(b))
+  // = log(1 - exp(b-a)) + a
+  t.Sub(b, a)
+  t.Exp(t)
+  t.Neg(t)
+  t.Log1p(t)
+  c.Add(t, a)
+  return c
+}
+
+func (c *Complex) Log1pExp(a ConstScalar) Scalar {
+  v := a.GetValue()
+  if v <= -37.0 {
+    c.Exp(a)
+  } else
+  if v <=  18.0 {
+    c.Exp(a)
+    c.Log1p(c)
+  } else
+  if v <=  33.3 {
+    t := NullComplex()
+    t.Neg(a)
+    t.Exp(t)
+    c.Add(a, t)
+  } else {
+    c.Set(a)
+  }
+  return c
+}
+
+func (c *Complex) Sigmoid(a ConstScalar, t Scalar) Scalar {
+  if a.GetValue() >= 0 {
+    c.Neg(a)
+    c.Exp(c)
+    c.Add(c, ConstReal(1.0))
+    c.Div(ConstReal(1.0), c)
+  } else {
+    t.Exp(a)
+    c.Set(t)
+    t.Add(t, ConstReal(1.0))
+    c.Div(c, t)
+  }
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the principal value of a^k.
+func (c *Complex) Pow(a, k ConstScalar) Scalar {
+  x := complexValueOf(a)
+  y := complexValueOf(k)
+  v0 := cmplx.Pow(x, y)
+  if k.GetOrder() >= 1 {
+    f1 := func() (complex128, complex128) {
+      f10 := cmplx.Pow(x, y-1)*y
+      f01 := cmplx.Pow(x, y-0)*cmplx.Log(x)
+      return f10, f01
+    }
+    f2 := func() (complex128, complex128, complex128) {
+      f11 := cmplx.Pow(x, y-1)*(1 + y*cmplx.Log(x))
+      f20 := cmplx.Pow(x, y-2)*(y - 1)*y
+      f02 := cmplx.Pow(x, y-0)*cmplx.Log(x)*cmplx.Log(x)
+      return f11, f20, f02
+    }
+    return c.dyadicLazy(a, k, v0, f1, f2)
+  } else {
+    f1 := func() complex128 {
+      return cmplx.Pow(x, y-1)*y
+    }
+    f2 := func() complex128 {
+      return cmplx.Pow(x, y-2)*(y - 1)*y
+    }
+    return c.monadicLazy(a, v0, f1, f2)
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the principal square root of a.
+func (c *Complex) Sqrt(a ConstScalar) Scalar {
+  x := complexValueOf(a)
+  v0 := cmplx.Sqrt(x)
+  f1 := func() complex128 { return  1/(2*v0) }
+  f2 := func() complex128 { return -1/(4*x*v0) }
+  return c.monadicLazy(a, v0, f1, f2)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) Sin(a ConstScalar) Scalar {
+  x := complexValueOf(a)
+  v0 := cmplx.Sin(x)
+  f1 := func() complex128 { return  cmplx.Cos(x) }
+  f2 := func() complex128 { return -v0 }
+  return c.monadicLazy(a, v0, f1, f2)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) Sinh(a ConstScalar) Scalar {
+  x := complexValueOf(a)
+  v0 := cmplx.Sinh(x)
+  f1 := func() complex128 { return cmplx.Cosh(x) }
+  f2 := func() complex128 { return v0 }
+  return c.monadicLazy(a, v0, f1, f2)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) Cos(a ConstScalar) Scalar {
+  x := complexValueOf(a)
+  v0 := cmplx.Cos(x)
+  f1 := func() complex128 { return -cmplx.Sin(x) }
+  f2 := func() complex128 { return -v0 }
+  return c.monadicLazy(a, v0, f1, f2)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) Cosh(a ConstScalar) Scalar {
+  x := complexValueOf(a)
+  v0 := cmplx.Cosh(x)
+  f1 := func() complex128 { return cmplx.Sinh(x) }
+  f2 := func() complex128 { return v0 }
+  return c.monadicLazy(a, v0, f1, f2)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) Tan(a ConstScalar) Scalar {
+  x := complexValueOf(a)
+  v0 := cmplx.Tan(x)
+  f1 := func() complex128 { return 1 + v0*v0 }
+  f2 := func() complex128 { return 2*v0*(1 + v0*v0) }
+  return c.monadicLazy(a, v0, f1, f2)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) Tanh(a ConstScalar) Scalar {
+  x := complexValueOf(a)
+  v0 := cmplx.Tanh(x)
+  f1 := func() complex128 { return 1 - v0*v0 }
+  f2 := func() complex128 { return -2*v0*(1 - v0*v0) }
+  return c.monadicLazy(a, v0, f1, f2)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) Exp(a ConstScalar) Scalar {
+  x := complexValueOf(a)
+  v0 := cmplx.Exp(x)
+  f1 := func() complex128 { return v0 }
+  f2 := func() complex128 { return v0 }
+  return c.monadicLazy(a, v0, f1, f2)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the principal value of the logarithm of a.
+func (c *Complex) Log(a ConstScalar) Scalar {
+  x := complexValueOf(a)
+  v0 := cmplx.Log(x)
+  f1 := func() complex128 { return  1/x }
+  f2 := func() complex128 { return -1/(x*x) }
+  return c.monadicLazy(a, v0, f1, f2)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) Log1p(a ConstScalar) Scalar {
+  x := complexValueOf(a)
+  v0 := complex128(0.0)
+  if imag(x) == 0.0 && real(x) >= -1.0 {
+    v0 = complex(math.Log1p(real(x)), 0.0)
+  } else {
+    v0 = cmplx.Log(1+x)
+  }
+  f1 := func() complex128 { return  1/ (1+x) }
+  f2 := func() complex128 { return -1/((1+x)*(1+x)) }
+  return c.monadicLazy(a, v0, f1, f2)
+}
+
+func (c *Complex) Logistic(a ConstScalar) Scalar {
+  c.Neg(a)
+  c.Exp(c)
+  c.Add(ConstReal(1.0), c)
+  c.Div(ConstReal(1.0), c)
+  return c
+}
+
+/* special functions
+ * -------------------------------------------------------------------------- */
+
+// Evaluate a special function that is implemented only for real arguments.
+// The function f evaluates the special function on a Real variable x, which
+// gives the first and second derivatives that are required for computing
+// the derivatives of c. The result is NaN if a has a non-zero imaginary
+// part.
+func (c *Complex) realFunction(a ConstScalar, f func(r, x *Real)) *Complex {
+  z := complexValueOf(a)
+  if imag(z) != 0.0 {
+    return c.monadic(a, cmplx.NaN(), cmplx.NaN(), cmplx.NaN())
+  }
+  x := NewReal(real(z))
+  x.SetVariable(0, 1, 2)
+  r := NullReal()
+  f(r, x)
+  v0 := complex(r.GetValue(), 0.0)
+  v1 := complex(r.GetDerivative(0), 0.0)
+  v2 := complex(r.GetHessian(0, 0), 0.0)
+  return c.monadic(a, v0, v1, v2)
+}
+
+func (c *Complex) Erf(a ConstScalar) Scalar {
+  return c.realFunction(a, func(r, x *Real) { r.Erf(x) })
+}
+
+func (c *Complex) Erfc(a ConstScalar) Scalar {
+  return c.realFunction(a, func(r, x *Real) {
+    r.Erf(x)
+    r.Sub(ConstReal(1.0), r)
+  })
+}
+
+func (c *Complex) LogErfc(a ConstScalar) Scalar {
+  return c.realFunction(a, func(r, x *Real) { r.LogErfc(x) })
+}
+
+func (c *Complex) Gamma(a ConstScalar) Scalar {
+  return c.realFunction(a, func(r, x *Real) { r.Gamma(x) })
+}
+
+func (c *Complex) Lgamma(a ConstScalar) Scalar {
+  return c.realFunction(a, func(r, x *Real) { r.Lgamma(x) })
+}
+
+func (c *Complex) Mlgamma(a ConstScalar, k int) Scalar {
+  return c.realFunction(a, func(r, x *Real) { r.Mlgamma(x, k) })
+}
+
+func (c *Complex) GammaP(a float64, b ConstScalar) Scalar {
+  return c.realFunction(b, func(r, x *Real) { r.GammaP(a, x) })
+}
+
+func (c *Complex) BesselI(v float64, b ConstScalar) Scalar {
+  return c.realFunction(b, func(r, x *Real) { r.BesselI(v, x) })
+}
+
+func (c *Complex) LogBesselI(v float64, b ConstScalar) Scalar {
+  return c.realFunction(b, func(r, x *Real) { r.LogBesselI(v, x) })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *Complex) SmoothMax(x ConstVector, alpha ConstReal, t [2]Scalar) Scalar {
+  r   .Reset()
+  t[1].Reset()
+  for i := 0; i < x.Dim(); i++ {
+    t[0].Mul(alpha, x.ConstAt(i))
+    t[0].Exp(t[0])
+    t[1].Add(t[1], t[0])
+    t[0].Mul(t[0], x.ConstAt(i))
+    r .Add(r , t[0])
+  }
+  r.Div(r, t[1])
+  return r
+}
+
+func (r *Complex) LogSmoothMax(x ConstVector, alpha ConstReal, t [3]Scalar) Scalar {
+  r   .Reset()
+  t[2].SetValue(math.Inf(-1))
+  for i := 0; i < x.Dim(); i++ {
+    t[0].Mul(x.ConstAt(i), alpha)
+    t[2].LogAdd(t[2], t[0], t[1])
+    t[1].Log(x.ConstAt(i))
+    t[0].Add(t[0], t[1])
+    r.LogAdd(r, t[0], t[1])
+  }
+  r.Sub(r, t[2])
+  r.Exp(r)
+  return r
+}
+
+func (r *Complex) Vmean(a ConstVector) Scalar {
+  r.Reset()
+  for i := 0; i < a.Dim(); i++ {
+    r.Add(r, a.ConstAt(i))
+  }
+  return r.Div(r, ConstReal(float64(a.Dim())))
+}
+
+// Compute the bilinear product sum_i a_i b_i, i.e. elements of a are not
+// conjugated.
+func (r *Complex) VdotV(a, b ConstVector) Scalar {
+  if a.Dim() != b.Dim() {
+    panic("vector dimensions do not match")
+  }
+  r.Reset()
+  t := NullComplex()
+  for i := 0; i < a.Dim(); i++ {
+    t.Mul(a.ConstAt(i), b.ConstAt(i))
+    r.Add(r, t)
+  }
+  return r
+}
+
+// Compute the Euclidean norm sqrt(sum_i a_i conj(a_i)).
+func (r *Complex) Vnorm(a ConstVector) Scalar {
+  r.Reset()
+  t := NullComplex()
+  for it := a.ConstIterator(); it.Ok(); it.Next() {
+    t.Conj(it.GetConst())
+    t.Mul(t, it.GetConst())
+    r.Add(r, t)
+  }
+  r.Sqrt(r)
+  return r
+}
+
+func (r *Complex) Mtrace(a ConstMatrix) Scalar {
+  n, m := a.Dims()
+  if n != m {
+    panic("not a square matrix")
+  }
+  if n == 0 {
+    return nil
+  }
+  r.Reset()
+  for i := 0; i < n; i++ {
+    r.Add(r, a.ConstAt(i,i))
+  }
+  return r
+}
+
+// Frobenius norm.
+func (r *Complex) Mnorm(a ConstMatrix) Scalar {
+  n, m := a.Dims()
+  if n == 0 || m == 0 {
+    return nil
+  }
+  t := NullComplex()
+  v := a.AsConstVector()
+  r.Reset()
+  for i := 0; i < v.Dim(); i++ {
+    t.Conj(v.ConstAt(i))
+    t.Mul(t, v.ConstAt(i))
+    r.Add(r, t)
+  }
+  return r
+}
@@ -0,0 +1,185 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "math"
+import "math/cmplx"
+
+/* -------------------------------------------------------------------------- */
+
+func (a *Complex) EQUALS(b *Complex, epsilon float64) bool {
+  return cmplx.Abs(a.Value - b.Value) < epsilon
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *Complex) GREATER(b *Complex) bool {
+  return a.GetValue() > b.GetValue()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *Complex) SMALLER(b *Complex) bool {
+  return a.GetValue() < b.GetValue()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *Complex) SIGN() int {
+  if a.GetValue() < 0.0 {
+    return -1
+  }
+  if a.GetValue() > 0.0 {
+    return  1
+  }
+  return 0
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *Complex) MIN(a, b *Complex) Scalar {
+  if a.GetValue() < b.GetValue() {
+    r.SET(a)
+  } else {
+    r.SET(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *Complex) MAX(a, b *Complex) Scalar {
+  if a.GetValue() > b.GetValue() {
+    r.SET(a)
+  } else {
+    r.SET(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) ABS(a *Complex) Scalar {
+  return c.Abs(a)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) NEG(a *Complex) *Complex {
+  x := a.Value
+  return c.monadic(a, -x, -1, 0)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) ADD(a, b *Complex) *Complex {
+  x := a.Value
+  y := b.Value
+  return c.dyadic(a, b, x+y, 1, 1, 0, 0, 0)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) SUB(a, b *Complex) *Complex {
+  x := a.Value
+  y := b.Value
+  return c.dyadic(a, b, x-y, 1, -1, 0, 0, 0)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) MUL(a, b *Complex) *Complex {
+  x := a.Value
+  y := b.Value
+  return c.dyadic(a, b, x*y, y, x, 1, 0, 0)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) DIV(a, b *Complex) *Complex {
+  x := a.Value
+  y := b.Value
+  return c.dyadic(a, b, x/y, 1/y, -x/(y*y), -1/(y*y), 0, 2*x/(y*y*y))
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) LOGADD(a, b, t *Complex) *Complex {
+  if a.GREATER(b) {
+    // swap
+    a, b = b, a
+  }
+  if math.IsInf(a.GetValue(), 0) {
+    // cases:
+    //  i) a = -Inf and b >= a    => c = b
+    // ii) a =  Inf and b  = Inf  => c = Inf
+    c.SET(b)
+    return c
+  }
+  t.SUB(a, b)
+  t.EXP(t)
+  t.LOG1P(t)
+  c.ADD(t, b)
+  return c
+}
+
+func (c *Complex) LOGSUB(a, b, t *Complex) *Complex {
+  if math.IsInf(b.GetValue(), -1) {
+    c.SET(a)
+    return c
+  }
+  t.SUB(b, a)
+  t.EXP(t)
+  t.NEG(t)
+  t.LOG1P(t)
+  c.ADD(t, a)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) POW(a, k *Complex) *Complex {
+  c.Pow(a, k)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) SQRT(a *Complex) *Complex {
+  c.Sqrt(a)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Complex) EXP(a *Complex) *Complex {
+  c.Exp(a)
+  return c
+}
+
+func (c *Complex) LOG(a *Complex) *Complex {
+  c.Log(a)
+  return c
+}
+
+func (c *Complex) LOG1P(a *Complex) *Complex {
+  c.Log1p(a)
+  return c
+}
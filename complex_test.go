@@ -0,0 +1,159 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "encoding/json"
+import "math"
+import "math/cmplx"
+import "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestComplex1(t *testing.T) {
+
+  // f(x, y) = g(x + iy) for holomorphic and non-holomorphic g
+  f := []func(r *Complex, z ConstScalar) *Complex{
+    func(r *Complex, z ConstScalar) *Complex { r.Exp (z); return r },
+    func(r *Complex, z ConstScalar) *Complex { r.Log (z); return r },
+    func(r *Complex, z ConstScalar) *Complex { r.Sqrt(z); return r },
+    func(r *Complex, z ConstScalar) *Complex { r.Sin (z); return r },
+    func(r *Complex, z ConstScalar) *Complex { r.Tanh(z); return r },
+    func(r *Complex, z ConstScalar) *Complex { r.Abs (z); return r },
+    func(r *Complex, z ConstScalar) *Complex { r.Arg (z); return r },
+    func(r *Complex, z ConstScalar) *Complex { r.Conj(z); return r },
+    func(r *Complex, z ConstScalar) *Complex { r.Pow (z, z); return r },
+    func(r *Complex, z ConstScalar) *Complex {
+      t := NullComplex()
+      t.Conj(z)
+      r.Mul(z, t)
+      r.Div(r, z)
+      return r
+    },
+  }
+  eval := func(i int, x, y float64, order int) *Complex {
+    a := NewReal(x)
+    b := NewReal(y)
+    Variables(order, a, b)
+    z := NullComplex()
+    s := NullComplex()
+    // z = a + ib
+    s.Mul(b, NewComplex128(1i))
+    z.Add(a, s)
+    return f[i](NullComplex(), z)
+  }
+  h := 1e-6
+  for i, _ := range f {
+    r := eval(i, 0.7, -1.3, 2)
+    // compare first derivatives with finite differences of values
+    // and second derivatives with finite differences of first
+    // derivatives
+    for k := 0; k < 2; k++ {
+      x1, y1 := 0.7, -1.3
+      x2, y2 := 0.7, -1.3
+      if k == 0 {
+        x1 += h; x2 -= h
+      } else {
+        y1 += h; y2 -= h
+      }
+      r1 := eval(i, x1, y1, 1)
+      r2 := eval(i, x2, y2, 1)
+      d  := (r1.GetComplexValue() - r2.GetComplexValue())/complex(2*h, 0)
+      if cmplx.Abs(d - r.GetComplexDerivative(k)) > 1e-6 {
+        t.Errorf("test %d failed", i)
+      }
+      for l := 0; l < 2; l++ {
+        d := (r1.GetComplexDerivative(l) - r2.GetComplexDerivative(l))/complex(2*h, 0)
+        if cmplx.Abs(d - r.GetComplexHessian(k, l)) > 1e-6 {
+          t.Errorf("test %d failed", i)
+        }
+      }
+    }
+  }
+}
+
+func TestComplex2(t *testing.T) {
+
+  f := []func(r Scalar, x ConstScalar) Scalar{
+    func(r Scalar, x ConstScalar) Scalar { return r.Erf(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.LogErfc(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Lgamma(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.GammaP(2.5, x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.BesselI(1.5, x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Log1pExp(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Cosh(x) },
+  }
+  for i, _ := range f {
+    x1 := NewReal   (1.7)
+    x2 := NewComplex(1.7)
+    Variables(2, x1)
+    Variables(2, x2)
+    r1 := f[i](NullReal   (), x1)
+    r2 := f[i](NullComplex(), x2).(*Complex)
+    if math.Abs(r1.GetValue() - r2.GetValue()) > 1e-10 || r2.GetImag() != 0.0 {
+      t.Errorf("test %d failed", i)
+    }
+    if math.Abs(r1.GetDerivative(0) - r2.GetDerivative(0)) > 1e-10 {
+      t.Errorf("test %d failed", i)
+    }
+    if math.Abs(r1.GetHessian(0, 0) - r2.GetHessian(0, 0)) > 1e-10 {
+      t.Errorf("test %d failed", i)
+    }
+  }
+  // special functions are not defined for complex arguments
+  if r := NullComplex(); !cmplx.IsNaN(r.Lgamma(NewComplex128(1+1i)).(*Complex).GetComplexValue()) {
+    t.Error("test failed")
+  }
+}
+
+func TestComplex3(t *testing.T) {
+
+  v := NullDenseComplexVector(2)
+  v.AT(0).SetComplexValue(3+4i)
+  v.AT(1).SetComplexValue(1i)
+  v.Variables(2)
+
+  r := NullComplex()
+  r.Vnorm(v)
+
+  if math.Abs(r.GetValue() - math.Sqrt(26.0)) > 1e-12 || r.GetImag() != 0.0 {
+    t.Error("test failed")
+  }
+  // d/dv_0 sqrt(v_0 conj(v_0) + v_1 conj(v_1))
+  if cmplx.Abs(r.GetComplexDerivative(0) - complex(3.0/math.Sqrt(26.0), 0.0)) > 1e-12 {
+    t.Error("test failed")
+  }
+  w := NullDenseComplexVector(2)
+  w.VaddV(v, v)
+  if w.AT(1).GetComplexValue() != 2i {
+    t.Error("test failed")
+  }
+  // json
+  if b, err := json.Marshal(w.AT(0)); err != nil {
+    t.Error(err)
+  } else {
+    z := NullComplex()
+    if err := json.Unmarshal(b, z); err != nil {
+      t.Error(err)
+    }
+    if z.GetComplexValue() != 6+8i || z.GetComplexDerivative(0) != 2 || z.GetComplexHessian(0, 1) != 0 {
+      t.Error("test failed")
+    }
+  }
+}
@@ -0,0 +1,316 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "encoding/json"
+import "reflect"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// DualReal is a scalar that carries, in addition to its value and the
+// gradient, the directional derivative of both along a fixed direction v.
+// The direction is set by assigning tangents to the variables. For a
+// function f, the tangent of the result is then the directional derivative
+// of f along v and the derivative of the tangent is the Hessian-vector
+// product H·v, which is computed without allocating the Hessian. Second
+// derivatives are not available through GetHessian.
+type DualReal struct {
+  Value               float64
+  // directional derivative of the value
+  Tangent             float64
+  Order               int
+  Derivative        []float64
+  // directional derivative of the gradient
+  TangentDerivative []float64
+  N                   int
+}
+
+/* register scalar type
+ * -------------------------------------------------------------------------- */
+
+var DualRealType ScalarType = NewDualReal(0.0).Type()
+
+func init() {
+  f := func(value float64) Scalar { return NewDualReal(value) }
+  RegisterScalar(DualRealType, f)
+}
+
+/* constructors
+ * -------------------------------------------------------------------------- */
+
+func NewDualReal(v float64) *DualReal {
+  return &DualReal{Value: v}
+}
+
+func NullDualReal() *DualReal {
+  return &DualReal{}
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *DualReal) Clone() *DualReal {
+  r := NewDualReal(0.0)
+  r.SET(a)
+  return r
+}
+
+func (a *DualReal) CloneScalar() Scalar {
+  return a.Clone()
+}
+
+func (a *DualReal) Type() ScalarType {
+  return reflect.TypeOf(a)
+}
+
+func (a *DualReal) ConvertType(t ScalarType) Scalar {
+  switch t {
+  case DualRealType:
+    return a
+  case RealType:
+    return NewReal(a.GetValue())
+  case BareRealType:
+    return NewBareReal(a.GetValue())
+  case ReverseRealType:
+    return NewReverseReal(a.GetValue())
+  case SparseRealType:
+    return NewSparseReal(a.GetValue())
+  case TaylorRealType:
+    return NewTaylorReal(a.GetValue())
+  case ComplexType:
+    return NewComplex(a.GetValue())
+  case BareReal32Type:
+    return NewBareReal32(a.GetValue())
+  case IntervalType:
+    return NewInterval(a.GetValue())
+  case BigRealType:
+    return NewBigReal(a.GetValue())
+  case BatchRealType:
+    return NewBatchReal(a.GetValue())
+  default:
+    panic(fmt.Sprintf("cannot convert `DualReal' to type `%v'", t))
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Allocate memory for derivatives of n variables. Only first order
+// derivatives are stored.
+func (a *DualReal) Alloc(n, order int) {
+  if order > 1 {
+    order = 1
+  }
+  if a.N != n || a.Order != order {
+    a.N     = n
+    a.Order = order
+    if a.Order >= 1 {
+      a.Derivative        = make([]float64, n)
+      a.TangentDerivative = make([]float64, n)
+    } else {
+      a.Derivative        = nil
+      a.TangentDerivative = nil
+    }
+  }
+}
+
+func (c *DualReal) AllocForOne(a ConstScalar) {
+  c.Alloc(a.GetN(), a.GetOrder())
+}
+
+func (c *DualReal) AllocForTwo(a, b ConstScalar) {
+  c.Alloc(iMax(a.GetN(), b.GetN()), iMax(a.GetOrder(), b.GetOrder()))
+}
+
+/* read access
+ * -------------------------------------------------------------------------- */
+
+func (a *DualReal) GetOrder() int {
+  return a.Order
+}
+
+func (a *DualReal) GetValue() float64 {
+  return a.Value
+}
+
+func (a *DualReal) GetLogValue() float64 {
+  return math.Log(a.Value)
+}
+
+// Returns the derivative of the ith variable.
+func (a *DualReal) GetDerivative(i int) float64 {
+  if a.Order >= 1 {
+    return a.Derivative[i]
+  } else {
+    return 0.0
+  }
+}
+
+func (a *DualReal) GetHessian(i, j int) float64 {
+  return 0.0
+}
+
+// Number of variables for which derivates are stored.
+func (a *DualReal) GetN() int {
+  return a.N
+}
+
+// Returns the directional derivative of the value.
+func (a *DualReal) GetTangent() float64 {
+  return a.Tangent
+}
+
+// Returns the directional derivative of the derivative of the ith
+// variable, i.e. the ith element of the Hessian-vector product.
+func (a *DualReal) GetTangentDerivative(i int) float64 {
+  if a.Order >= 1 {
+    return a.TangentDerivative[i]
+  } else {
+    return 0.0
+  }
+}
+
+/* write access
+ * -------------------------------------------------------------------------- */
+
+func (a *DualReal) Reset() {
+  a.Value = 0.0
+  a.ResetDerivatives()
+}
+
+// Reset all derivatives including the tangent to zero.
+func (a *DualReal) ResetDerivatives() {
+  a.Tangent = 0.0
+  if a.Order >= 1 {
+    for i := 0; i < a.N; i++ {
+      a.Derivative       [i] = 0.0
+      a.TangentDerivative[i] = 0.0
+    }
+  }
+}
+
+// Set the state to b. Tangents are only copied if b is also
+// a DualReal.
+func (a *DualReal) Set(b ConstScalar) {
+  if r, ok := b.(*DualReal); ok {
+    a.SET(r)
+    return
+  }
+  a.Value   = b.GetValue()
+  a.Tangent = 0.0
+  a.Alloc(b.GetN(), b.GetOrder())
+  if a.Order >= 1 {
+    for i := 0; i < b.GetN(); i++ {
+      a.Derivative       [i] = b.GetDerivative(i)
+      a.TangentDerivative[i] = 0.0
+    }
+  }
+}
+
+func (a *DualReal) SET(b *DualReal) {
+  a.Value   = b.Value
+  a.Tangent = b.Tangent
+  a.Alloc(b.N, b.Order)
+  if a.Order >= 1 {
+    copy(a.Derivative,        b.Derivative)
+    copy(a.TangentDerivative, b.TangentDerivative)
+  }
+}
+
+// Set the value of the variable. All derivatives are reset to zero.
+func (a *DualReal) SetValue(v float64) {
+  a.Value = v
+  a.ResetDerivatives()
+}
+
+func (a *DualReal) setValue(v float64) {
+  a.Value = v
+}
+
+// Set the derivative of the ith variable to v.
+func (a *DualReal) SetDerivative(i int, v float64) {
+  a.Derivative[i] = v
+}
+
+func (a *DualReal) SetHessian(i, j int, v float64) {
+}
+
+// Set the directional derivative of the value to v.
+func (a *DualReal) SetTangent(v float64) {
+  a.Tangent = v
+}
+
+// Allocate memory for n variables and set the derivative of the ith
+// variable to 1. The tangent is not modified.
+func (a *DualReal) SetVariable(i, n, order int) error {
+  if order > 1 {
+    return fmt.Errorf("order `%d' not supported by this type", order)
+  }
+  a.Alloc(n, order)
+  if order > 0 {
+    for j := 0; j < n; j++ {
+      a.Derivative       [j] = 0.0
+      a.TangentDerivative[j] = 0.0
+    }
+    a.Derivative[i] = 1
+  }
+  return nil
+}
+
+/* type conversion
+ * -------------------------------------------------------------------------- */
+
+func (a *DualReal) String() string {
+  return fmt.Sprintf("%e", a.GetValue())
+}
+
+/* json
+ * -------------------------------------------------------------------------- */
+
+func (obj *DualReal) MarshalJSON() ([]byte, error) {
+  if obj.Tangent != 0.0 || obj.Order > 0 {
+    r := struct{Value float64; Tangent float64; Derivative []float64; TangentDerivative []float64}{
+      obj.Value, obj.Tangent, obj.Derivative, obj.TangentDerivative}
+    return json.Marshal(r)
+  } else {
+    return json.Marshal(obj.Value)
+  }
+}
+
+func (obj *DualReal) UnmarshalJSON(data []byte) error {
+  r := struct{Value float64; Tangent float64; Derivative []float64; TangentDerivative []float64}{}
+  if err := json.Unmarshal(data, &r); err == nil {
+    if len(r.Derivative) != len(r.TangentDerivative) {
+      return fmt.Errorf("invalid json scalar representation")
+    }
+    obj.Value   = r.Value
+    obj.Tangent = r.Tangent
+    if len(r.Derivative) != 0 {
+      obj.Alloc(len(r.Derivative), 1)
+      obj.Derivative        = r.Derivative
+      obj.TangentDerivative = r.TangentDerivative
+    } else {
+      obj.Alloc(0, 0)
+    }
+    return nil
+  } else {
+    obj.Reset()
+    return json.Unmarshal(data, &obj.Value)
+  }
+}
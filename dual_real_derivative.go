@@ -0,0 +1,111 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+// Scalars other than DualReal have zero tangents.
+func dualTangentOf(a ConstScalar) float64 {
+  if r, ok := a.(*DualReal); ok {
+    return r.Tangent
+  }
+  return 0.0
+}
+
+func dualTangentDerivativeOf(a ConstScalar, i int) float64 {
+  if r, ok := a.(*DualReal); ok && r.Order >= 1 {
+    return r.TangentDerivative[i]
+  }
+  return 0.0
+}
+
+/* derivatives of monadic functions
+ * -------------------------------------------------------------------------- */
+
+// Compute the gradient and tangents of c = f(a), where
+// - v0 = f(a)
+// - v1 = d/dx f(x) | x=a
+// - v2 = d^2/dx^2 f(x) | x=a
+func (c *DualReal) monadic(a ConstScalar, v0, v1, v2 float64) *DualReal {
+  c.AllocForOne(a)
+  t := dualTangentOf(a)
+  if c.Order >= 1 {
+    for i := 0; i < c.N; i++ {
+      d := a.GetDerivative(i)
+      c.TangentDerivative[i] = dualTangentDerivativeOf(a, i)*v1 + d*t*v2
+      c.Derivative       [i] = d*v1
+    }
+  }
+  c.Tangent = t*v1
+  // compute new value
+  c.setValue(v0)
+  return c
+}
+
+func (c *DualReal) monadicLazy(a ConstScalar, v0 float64, f1, f2 func() float64) *DualReal {
+  v1, v2 := 0.0, 0.0
+  t := dualTangentOf(a)
+  if a.GetOrder() >= 1 || t != 0.0 {
+    v1 = f1()
+    if a.GetOrder() >= 1 && t != 0.0 {
+      v2 = f2()
+    }
+  }
+  return c.monadic(a, v0, v1, v2)
+}
+
+/* derivatives of dyadic functions
+ * -------------------------------------------------------------------------- */
+
+func (c *DualReal) dyadic(a, b ConstScalar, v0, v10, v01, v11, v20, v02 float64) *DualReal {
+  c.AllocForTwo(a, b)
+  s := dualTangentOf(a)
+  t := dualTangentOf(b)
+  if c.Order >= 1 {
+    for i := 0; i < c.N; i++ {
+      da := a.GetDerivative(i)
+      db := b.GetDerivative(i)
+      c.TangentDerivative[i] =
+        dualTangentDerivativeOf(a, i)*v10 +
+        dualTangentDerivativeOf(b, i)*v01 +
+        da*(s*v20 + t*v11) +
+        db*(s*v11 + t*v02)
+      c.Derivative[i] = da*v10 + db*v01
+    }
+  }
+  c.Tangent = s*v10 + t*v01
+  // compute new value
+  c.setValue(v0)
+  return c
+}
+
+func (c *DualReal) dyadicLazy(a, b ConstScalar, v0 float64, f1 func() (float64, float64), f2 func() (float64, float64, float64)) *DualReal {
+  v10, v01, v11, v20, v02 := 0.0, 0.0, 0.0, 0.0, 0.0
+  order   := iMax(a.GetOrder(), b.GetOrder())
+  tangent := dualTangentOf(a) != 0.0 || dualTangentOf(b) != 0.0
+  if order >= 1 || tangent {
+    v10, v01 = f1()
+    if order >= 1 && tangent {
+      v11, v20, v02 = f2()
+    }
+  }
+  return c.dyadic(a, b, v0, v10, v01, v11, v20, v02)
+}
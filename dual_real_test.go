@@ -0,0 +1,119 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "math"
+import "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestDualReal1(t *testing.T) {
+
+  f := func(x ConstVector) ConstScalar {
+    t := NullScalar(x.ElementType())
+    r := NullScalar(x.ElementType())
+    for i := 0; i < x.Dim(); i++ {
+      t.Sin(x.ConstAt(i))
+      t.Mul(t, x.ConstAt((i+1) % x.Dim()))
+      t.Exp(t)
+      r.Add(r, t)
+      t.Lgamma(x.ConstAt(i))
+      r.Sub(t, r)
+      t.Log1pExp(x.ConstAt(i))
+      r.Mul(r, t)
+    }
+    return r.Div(r, x.ConstAt(0))
+  }
+  x := NewVector(RealType,     []float64{1.1, 2.3, 0.7, 4.1, 3.3})
+  v := NewVector(BareRealType, []float64{0.3,-1.2, 2.0, 0.5, 1.0})
+  // compute the full Hessian
+  H := NullMatrix(RealType, x.Dim(), x.Dim())
+  H.Hessian(f, x)
+
+  r1 := NullVector(RealType, x.Dim())
+  r1.MdotV(H, v)
+  r2 := HessianVectorProduct(f, x, v)
+
+  if r1.Dim() != r2.Dim() {
+    t.Error("test failed")
+  } else {
+    for i := 0; i < r1.Dim(); i++ {
+      if math.Abs(r1.ValueAt(i) - r2.ValueAt(i)) > 1e-10 {
+        t.Error("test failed")
+      }
+    }
+  }
+}
+
+func TestDualReal2(t *testing.T) {
+
+  a := NewDualReal(2.0)
+  b := NewDualReal(3.0)
+
+  Variables(1, a, b)
+  // direction
+  a.SetTangent(1.0)
+  b.SetTangent(2.0)
+
+  // c = a^2 b
+  c := NullDualReal()
+  c.Mul(a, a)
+  c.Mul(c, b)
+
+  if c.GetValue() != 12.0 {
+    t.Error("test failed")
+  }
+  if c.GetDerivative(0) != 12.0 || c.GetDerivative(1) != 4.0 {
+    t.Error("test failed")
+  }
+  // directional derivative: 12*1 + 4*2
+  if c.GetTangent() != 20.0 {
+    t.Error("test failed")
+  }
+  // H = [2b 2a; 2a 0] = [6 4; 4 0]
+  if c.GetTangentDerivative(0) != 14.0 || c.GetTangentDerivative(1) != 4.0 {
+    t.Error("test failed")
+  }
+}
+
+func TestDualReal3(t *testing.T) {
+
+  x := NewDualReal(1.0)
+
+  if err := Variables(2, x); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestDualReal4(t *testing.T) {
+  // f does not return a DualReal
+  f := func(x ConstVector) ConstScalar {
+    return NewReal(x.ValueAt(0))
+  }
+  x := NewVector(RealType,     []float64{1.0, 2.0})
+  v := NewVector(BareRealType, []float64{1.0, 1.0})
+
+  defer func() {
+    if recover() == nil {
+      t.Error("test failed")
+    }
+  }()
+  HessianVectorProduct(f, x, v)
+}
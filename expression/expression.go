@@ -0,0 +1,245 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package expression
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+type operator int
+
+const (
+  opVariable operator = iota
+  opConstant
+  opNeg
+  opAbs
+  opAdd
+  opSub
+  opMul
+  opDiv
+  opPow
+  opMin
+  opMax
+  opLogAdd
+  opLogSub
+  opLog1pExp
+  opSigmoid
+  opSqrt
+  opSin
+  opSinh
+  opCos
+  opCosh
+  opTan
+  opTanh
+  opExp
+  opLog
+  opLog1p
+  opLogistic
+  opErf
+  opErfc
+  opLogErfc
+  opGamma
+  opLgamma
+  opMlgamma
+  opGammaP
+  opBesselI
+)
+
+var operatorNames = [...]string{
+  opNeg     : "neg",
+  opAbs     : "abs",
+  opAdd     : "+",
+  opSub     : "-",
+  opMul     : "*",
+  opDiv     : "/",
+  opPow     : "pow",
+  opMin     : "min",
+  opMax     : "max",
+  opLogAdd  : "logAdd",
+  opLogSub  : "logSub",
+  opLog1pExp: "log1pExp",
+  opSigmoid : "sigmoid",
+  opSqrt    : "sqrt",
+  opSin     : "sin",
+  opSinh    : "sinh",
+  opCos     : "cos",
+  opCosh    : "cosh",
+  opTan     : "tan",
+  opTanh    : "tanh",
+  opExp     : "exp",
+  opLog     : "log",
+  opLog1p   : "log1p",
+  opLogistic: "logistic",
+  opErf     : "erf",
+  opErfc    : "erfc",
+  opLogErfc : "logErfc",
+  opGamma   : "gamma",
+  opLgamma  : "lgamma",
+  opMlgamma : "mlgamma",
+  opGammaP  : "gammaP",
+  opBesselI : "besselI" }
+
+/* -------------------------------------------------------------------------- */
+
+// Expr is a node of an expression graph. Nodes are immutable and unique
+// within their graph, i.e. constructing the same expression twice returns
+// the same node. Common subexpressions are therefore shared and evaluated
+// only once.
+type Expr struct {
+  graph *Graph
+  id    int
+  op    operator
+  args  [2]*Expr
+  // value of constants or real parameter of the operator
+  c     float64
+  // index of variables or integer parameter of the operator
+  k     int
+}
+
+type exprKey struct {
+  op operator
+  a  int
+  b  int
+  c  uint64
+  k  int
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Graph stores all nodes of an expression graph. Variable i of the
+// graph refers to the i-th element of the vector at which expressions are
+// evaluated.
+type Graph struct {
+  nodes []*Expr
+  index map[exprKey]*Expr
+  n     int
+}
+
+func NewGraph() *Graph {
+  return &Graph{index: make(map[exprKey]*Expr)}
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Returns the i-th variable of the graph.
+func (g *Graph) Variable(i int) *Expr {
+  if i < 0 {
+    panic("invalid variable index")
+  }
+  if i >= g.n {
+    g.n = i+1
+  }
+  return g.node(opVariable, nil, nil, 0.0, i)
+}
+
+// Returns the first n variables of the graph.
+func (g *Graph) Variables(n int) []*Expr {
+  r := make([]*Expr, n)
+  for i := 0; i < n; i++ {
+    r[i] = g.Variable(i)
+  }
+  return r
+}
+
+func (g *Graph) Constant(v float64) *Expr {
+  return g.node(opConstant, nil, nil, v, 0)
+}
+
+// Number of variables referenced by the graph.
+func (g *Graph) NVariables() int {
+  return g.n
+}
+
+// Number of unique nodes in the graph.
+func (g *Graph) Size() int {
+  return len(g.nodes)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (g *Graph) node(op operator, a, b *Expr, c float64, k int) *Expr {
+  key := exprKey{op: op, a: -1, b: -1, c: math.Float64bits(c), k: k}
+  if a != nil {
+    if a.graph != g {
+      panic("expressions belong to different graphs")
+    }
+    key.a = a.id
+  }
+  if b != nil {
+    if b.graph != g {
+      panic("expressions belong to different graphs")
+    }
+    key.b = b.id
+  }
+  if r, ok := g.index[key]; ok {
+    return r
+  }
+  r := &Expr{graph: g, id: len(g.nodes), op: op, args: [2]*Expr{a, b}, c: c, k: k}
+  g.nodes = append(g.nodes, r)
+  g.index[key] = r
+  return r
+}
+
+func (g *Graph) unary(op operator, a *Expr) *Expr {
+  return g.node(op, a, nil, 0.0, 0)
+}
+
+func (g *Graph) binary(op operator, a, b *Expr) *Expr {
+  return g.node(op, a, b, 0.0, 0)
+}
+
+// Arguments of commutative operations are sorted so that a+b and b+a
+// are identified.
+func (g *Graph) commutative(op operator, a, b *Expr) *Expr {
+  if a.id > b.id {
+    a, b = b, a
+  }
+  return g.node(op, a, b, 0.0, 0)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *Expr) Graph() *Graph {
+  return a.graph
+}
+
+// Returns true if a is a variable or a constant.
+func (a *Expr) IsLeaf() bool {
+  return a.op == opVariable || a.op == opConstant
+}
+
+func (a *Expr) String() string {
+  switch a.op {
+  case opVariable:
+    return fmt.Sprintf("x%d", a.k)
+  case opConstant:
+    return fmt.Sprintf("%v", a.c)
+  case opAdd, opSub, opMul, opDiv:
+    return fmt.Sprintf("(%v %s %v)", a.args[0], operatorNames[a.op], a.args[1])
+  case opPow, opMin, opMax, opLogAdd, opLogSub:
+    return fmt.Sprintf("%s(%v, %v)", operatorNames[a.op], a.args[0], a.args[1])
+  case opMlgamma:
+    return fmt.Sprintf("%s(%v, %d)", operatorNames[a.op], a.args[0], a.k)
+  case opGammaP, opBesselI:
+    return fmt.Sprintf("%s(%v, %v)", operatorNames[a.op], a.c, a.args[0])
+  default:
+    return fmt.Sprintf("%s(%v)", operatorNames[a.op], a.args[0])
+  }
+}
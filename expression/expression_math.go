@@ -0,0 +1,154 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package expression
+
+/* -------------------------------------------------------------------------- */
+
+func (a *Expr) Neg() *Expr {
+  return a.graph.unary(opNeg, a)
+}
+
+func (a *Expr) Abs() *Expr {
+  return a.graph.unary(opAbs, a)
+}
+
+func (a *Expr) Add(b *Expr) *Expr {
+  return a.graph.commutative(opAdd, a, b)
+}
+
+func (a *Expr) Sub(b *Expr) *Expr {
+  return a.graph.binary(opSub, a, b)
+}
+
+func (a *Expr) Mul(b *Expr) *Expr {
+  return a.graph.commutative(opMul, a, b)
+}
+
+func (a *Expr) Div(b *Expr) *Expr {
+  return a.graph.binary(opDiv, a, b)
+}
+
+func (a *Expr) Pow(b *Expr) *Expr {
+  return a.graph.binary(opPow, a, b)
+}
+
+func (a *Expr) Min(b *Expr) *Expr {
+  return a.graph.commutative(opMin, a, b)
+}
+
+func (a *Expr) Max(b *Expr) *Expr {
+  return a.graph.commutative(opMax, a, b)
+}
+
+// log(exp(a) + exp(b))
+func (a *Expr) LogAdd(b *Expr) *Expr {
+  return a.graph.commutative(opLogAdd, a, b)
+}
+
+// log(exp(a) - exp(b))
+func (a *Expr) LogSub(b *Expr) *Expr {
+  return a.graph.binary(opLogSub, a, b)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func Log1pExp(a *Expr) *Expr {
+  return a.graph.unary(opLog1pExp, a)
+}
+
+func Sigmoid(a *Expr) *Expr {
+  return a.graph.unary(opSigmoid, a)
+}
+
+func Sqrt(a *Expr) *Expr {
+  return a.graph.unary(opSqrt, a)
+}
+
+func Sin(a *Expr) *Expr {
+  return a.graph.unary(opSin, a)
+}
+
+func Sinh(a *Expr) *Expr {
+  return a.graph.unary(opSinh, a)
+}
+
+func Cos(a *Expr) *Expr {
+  return a.graph.unary(opCos, a)
+}
+
+func Cosh(a *Expr) *Expr {
+  return a.graph.unary(opCosh, a)
+}
+
+func Tan(a *Expr) *Expr {
+  return a.graph.unary(opTan, a)
+}
+
+func Tanh(a *Expr) *Expr {
+  return a.graph.unary(opTanh, a)
+}
+
+func Exp(a *Expr) *Expr {
+  return a.graph.unary(opExp, a)
+}
+
+func Log(a *Expr) *Expr {
+  return a.graph.unary(opLog, a)
+}
+
+func Log1p(a *Expr) *Expr {
+  return a.graph.unary(opLog1p, a)
+}
+
+func Logistic(a *Expr) *Expr {
+  return a.graph.unary(opLogistic, a)
+}
+
+func Erf(a *Expr) *Expr {
+  return a.graph.unary(opErf, a)
+}
+
+func Erfc(a *Expr) *Expr {
+  return a.graph.unary(opErfc, a)
+}
+
+func LogErfc(a *Expr) *Expr {
+  return a.graph.unary(opLogErfc, a)
+}
+
+func Gamma(a *Expr) *Expr {
+  return a.graph.unary(opGamma, a)
+}
+
+func Lgamma(a *Expr) *Expr {
+  return a.graph.unary(opLgamma, a)
+}
+
+// multivariate log gamma
+func Mlgamma(a *Expr, k int) *Expr {
+  return a.graph.node(opMlgamma, a, nil, 0.0, k)
+}
+
+// regularized lower incomplete gamma
+func GammaP(a float64, x *Expr) *Expr {
+  return x.graph.node(opGammaP, x, nil, a, 0)
+}
+
+// modified bessel function of the first kind
+func BesselI(v float64, x *Expr) *Expr {
+  return x.graph.node(opBesselI, x, nil, v, 0)
+}
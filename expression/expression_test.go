@@ -0,0 +1,110 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package expression
+
+/* -------------------------------------------------------------------------- */
+
+import "math"
+import "testing"
+
+import . "github.com/pbenner/autodiff"
+
+/* -------------------------------------------------------------------------- */
+
+func TestExpression1(t *testing.T) {
+  g := NewGraph()
+  x := g.Variable(0)
+  y := g.Variable(1)
+  z := g.Variable(2)
+
+  f1 := x.Mul(y).Add(Exp(z))
+  f2 := Exp(z).Add(y.Mul(x))
+
+  if f1 != f2 {
+    t.Error("test failed")
+  }
+  if g.Size() != 6 {
+    t.Error("test failed")
+  }
+  if x.Sub(y) == y.Sub(x) {
+    t.Error("test failed")
+  }
+  if g.Constant(2.0) != g.Constant(2.0) {
+    t.Error("test failed")
+  }
+  if f1.String() != "((x0 * x1) + exp(x2))" {
+    t.Error("test failed")
+  }
+}
+
+func TestExpression2(t *testing.T) {
+  g := NewGraph()
+  x := g.Variables(2)
+  // f(x) = exp(x0*x1) + log(exp(x0*x1) + 2)
+  s := Exp(x[0].Mul(x[1]))
+  f := s.Add(Log(s.Add(g.Constant(2.0))))
+
+  v := NewDenseRealVector([]float64{0.5, 1.5})
+  v.Variables(1)
+
+  r, err := f.Eval(RealType, v)
+  if err != nil {
+    t.Error(err)
+    return
+  }
+  e  := math.Exp(0.5*1.5)
+  d0 := e*1.5 + e*1.5/(e + 2.0)
+  d1 := e*0.5 + e*0.5/(e + 2.0)
+  if math.Abs(r.GetValue() - (e + math.Log(e + 2.0))) > 1e-12 {
+    t.Error("test failed")
+  }
+  if math.Abs(r.GetDerivative(0) - d0) > 1e-12 {
+    t.Error("test failed")
+  }
+  if math.Abs(r.GetDerivative(1) - d1) > 1e-12 {
+    t.Error("test failed")
+  }
+  // exp(x0*x1) is evaluated only once
+  if p := g.Compile(RealType, f); p.Size() != 8 {
+    t.Error("test failed")
+  }
+}
+
+func TestExpression3(t *testing.T) {
+  g := NewGraph()
+  x := g.Variable(0)
+  y := g.Variable(1)
+  f := Sqrt(x.Mul(x).Add(y.Mul(y)))
+  h := x.LogAdd(y)
+
+  for _, st := range []ScalarType{BareRealType, BareReal32Type, BigRealType, IntervalType} {
+    r, err := g.Eval(st, NewDenseBareRealVector([]float64{3.0, 4.0}), f, h)
+    if err != nil {
+      t.Error(err)
+      return
+    }
+    if r[0].Type() != st || math.Abs(r[0].GetValue() - 5.0) > 1e-6 {
+      t.Error("test failed")
+    }
+    if math.Abs(r[1].GetValue() - (4.0 + math.Log1p(math.Exp(-1.0)))) > 1e-6 {
+      t.Error("test failed")
+    }
+  }
+  if _, err := f.Eval(RealType, NewDenseRealVector([]float64{1.0})); err == nil {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,210 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package expression
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+
+import . "github.com/pbenner/autodiff"
+
+/* -------------------------------------------------------------------------- */
+
+// Program evaluates a set of expressions with scalars of a fixed type.
+// Every node that is required by at least one expression is assigned a
+// single scalar, which is reused across evaluations.
+type Program struct {
+  nodes  []*Expr
+  values []Scalar
+  roots  []int
+  result []Scalar
+  // position of graph nodes within the program
+  index  []int
+  // number of variables required for evaluation
+  n      int
+  t      Scalar
+}
+
+// Compile expressions e for evaluation with scalars of type t.
+func (g *Graph) Compile(t ScalarType, e ...*Expr) *Program {
+  // mark all nodes reachable from the given expressions
+  reachable := make([]bool, len(g.nodes))
+  stack     := []*Expr{}
+  for _, r := range e {
+    if r.graph != g {
+      panic("expressions belong to different graphs")
+    }
+    stack = append(stack, r)
+  }
+  for len(stack) > 0 {
+    r := stack[len(stack)-1]
+    stack = stack[0:len(stack)-1]
+    if reachable[r.id] {
+      continue
+    }
+    reachable[r.id] = true
+    for _, a := range r.args {
+      if a != nil {
+        stack = append(stack, a)
+      }
+    }
+  }
+  p := Program{}
+  p.t = NullScalar(t)
+  // arguments are always created before a node, hence the order of
+  // ids is a topological order
+  p.index = make([]int, len(g.nodes))
+  for i, r := range g.nodes {
+    if !reachable[i] {
+      continue
+    }
+    if r.op == opVariable && r.k >= p.n {
+      p.n = r.k+1
+    }
+    p.index[i] = len(p.nodes)
+    p.nodes  = append(p.nodes,  r)
+    p.values = append(p.values, NullScalar(t))
+  }
+  p.roots  = make([]int,    len(e))
+  p.result = make([]Scalar, len(e))
+  for i, r := range e {
+    p.roots [i] = p.index[r.id]
+    p.result[i] = p.values[p.index[r.id]]
+  }
+  return &p
+}
+
+// Evaluate expressions e at x with scalars of type t. Common subexpressions
+// are evaluated only once.
+func (g *Graph) Eval(t ScalarType, x ConstVector, e ...*Expr) ([]Scalar, error) {
+  return g.Compile(t, e...).Eval(x)
+}
+
+// Evaluate the expression at x with scalars of type t.
+func (a *Expr) Eval(t ScalarType, x ConstVector) (Scalar, error) {
+  if r, err := a.graph.Eval(t, x, a); err != nil {
+    return nil, err
+  } else {
+    return r[0], nil
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Number of nodes that are evaluated by the program.
+func (p *Program) Size() int {
+  return len(p.nodes)
+}
+
+// Evaluate all expressions at x, where variable i takes the value x_i.
+// Derivatives of x are propagated to the results. The returned scalars
+// are owned by the program and overwritten by the next evaluation.
+func (p *Program) Eval(x ConstVector) ([]Scalar, error) {
+  if x.Dim() < p.n {
+    return nil, fmt.Errorf("expression requires %d variables, but vector has dimension %d", p.n, x.Dim())
+  }
+  for i, r := range p.nodes {
+    p.evalNode(r, p.values[i], x)
+  }
+  return p.result, nil
+}
+
+func (p *Program) value(e *Expr) ConstScalar {
+  return p.values[p.index[e.id]]
+}
+
+func (p *Program) evalNode(e *Expr, r Scalar, x ConstVector) {
+  var a, b ConstScalar
+  if e.args[0] != nil {
+    a = p.value(e.args[0])
+  }
+  if e.args[1] != nil {
+    b = p.value(e.args[1])
+  }
+  switch e.op {
+  case opVariable:
+    r.Set(x.ConstAt(e.k))
+  case opConstant:
+    r.SetValue(e.c)
+  case opNeg:
+    r.Neg(a)
+  case opAbs:
+    r.Abs(a)
+  case opAdd:
+    r.Add(a, b)
+  case opSub:
+    r.Sub(a, b)
+  case opMul:
+    r.Mul(a, b)
+  case opDiv:
+    r.Div(a, b)
+  case opPow:
+    r.Pow(a, b)
+  case opMin:
+    r.Min(a, b)
+  case opMax:
+    r.Max(a, b)
+  case opLogAdd:
+    r.LogAdd(a, b, p.t)
+  case opLogSub:
+    r.LogSub(a, b, p.t)
+  case opLog1pExp:
+    r.Log1pExp(a)
+  case opSigmoid:
+    r.Sigmoid(a, p.t)
+  case opSqrt:
+    r.Sqrt(a)
+  case opSin:
+    r.Sin(a)
+  case opSinh:
+    r.Sinh(a)
+  case opCos:
+    r.Cos(a)
+  case opCosh:
+    r.Cosh(a)
+  case opTan:
+    r.Tan(a)
+  case opTanh:
+    r.Tanh(a)
+  case opExp:
+    r.Exp(a)
+  case opLog:
+    r.Log(a)
+  case opLog1p:
+    r.Log1p(a)
+  case opLogistic:
+    r.Logistic(a)
+  case opErf:
+    r.Erf(a)
+  case opErfc:
+    r.Erfc(a)
+  case opLogErfc:
+    r.LogErfc(a)
+  case opGamma:
+    r.Gamma(a)
+  case opLgamma:
+    r.Lgamma(a)
+  case opMlgamma:
+    r.Mlgamma(a, e.k)
+  case opGammaP:
+    r.GammaP(e.c, a)
+  case opBesselI:
+    r.BesselI(e.c, a)
+  default:
+    panic("internal error")
+  }
+}
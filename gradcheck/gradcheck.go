@@ -0,0 +1,326 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gradcheck
+
+/* -------------------------------------------------------------------------- */
+
+import   "bytes"
+import   "fmt"
+import   "math"
+
+import . "github.com/pbenner/autodiff"
+
+/* -------------------------------------------------------------------------- */
+
+type objective func(Vector) (Scalar, error)
+
+// Step size of finite differences. The step for coordinate i is
+// Value*max(1, |x_i|).
+type Step struct {
+  Value float64
+}
+
+// Use complex-step differentiation instead of central differences. The
+// objective function is evaluated with Complex scalars, hence it must be
+// analytic and must not depend on the real part of its arguments alone
+// (e.g. through comparisons or Abs).
+type ComplexStep struct {
+  Value bool
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Errors are measured as |analytic - numeric|/max(1, |numeric|), i.e.
+// absolute errors for small and relative errors for large derivatives.
+func derivativeError(analytic, numeric float64) float64 {
+  if math.IsNaN(analytic) && math.IsNaN(numeric) {
+    return 0.0
+  }
+  if analytic == numeric {
+    return 0.0
+  }
+  return math.Abs(analytic - numeric)/math.Max(1.0, math.Abs(numeric))
+}
+
+/* -------------------------------------------------------------------------- */
+
+type GradientReport struct {
+  Analytic []float64
+  Numeric  []float64
+  Error    []float64
+}
+
+func newGradientReport(n int) GradientReport {
+  r := GradientReport{}
+  r.Analytic = make([]float64, n)
+  r.Numeric  = make([]float64, n)
+  r.Error    = make([]float64, n)
+  return r
+}
+
+// Returns the coordinate with the largest error and the error itself.
+// NaN errors are considered larger than any other error.
+func (r GradientReport) MaxError() (int, float64) {
+  k := -1
+  e := 0.0
+  for i, v := range r.Error {
+    if math.IsNaN(v) {
+      return i, v
+    }
+    if k == -1 || v > e {
+      k, e = i, v
+    }
+  }
+  return k, e
+}
+
+func (r GradientReport) String() string {
+  var buffer bytes.Buffer
+  fmt.Fprintf(&buffer, "%5s %15s %15s %15s\n", "i", "analytic", "numeric", "error")
+  for i := 0; i < len(r.Error); i++ {
+    fmt.Fprintf(&buffer, "%5d %15e %15e %15e\n", i, r.Analytic[i], r.Numeric[i], r.Error[i])
+  }
+  return buffer.String()
+}
+
+/* -------------------------------------------------------------------------- */
+
+type HessianReport struct {
+  Analytic [][]float64
+  Numeric  [][]float64
+  Error    [][]float64
+}
+
+func newHessianReport(n int) HessianReport {
+  r := HessianReport{}
+  r.Analytic = make([][]float64, n)
+  r.Numeric  = make([][]float64, n)
+  r.Error    = make([][]float64, n)
+  for i := 0; i < n; i++ {
+    r.Analytic[i] = make([]float64, n)
+    r.Numeric [i] = make([]float64, n)
+    r.Error   [i] = make([]float64, n)
+  }
+  return r
+}
+
+// Returns the entry with the largest error and the error itself. NaN
+// errors are considered larger than any other error.
+func (r HessianReport) MaxError() (int, int, float64) {
+  k, l := -1, -1
+  e := 0.0
+  for i := 0; i < len(r.Error); i++ {
+    for j, v := range r.Error[i] {
+      if math.IsNaN(v) {
+        return i, j, v
+      }
+      if k == -1 || v > e {
+        k, l, e = i, j, v
+      }
+    }
+  }
+  return k, l, e
+}
+
+func (r HessianReport) String() string {
+  var buffer bytes.Buffer
+  fmt.Fprintf(&buffer, "%5s %5s %15s %15s %15s\n", "i", "j", "analytic", "numeric", "error")
+  for i := 0; i < len(r.Error); i++ {
+    for j := 0; j < len(r.Error[i]); j++ {
+      fmt.Fprintf(&buffer, "%5d %5d %15e %15e %15e\n", i, j, r.Analytic[i][j], r.Numeric[i][j], r.Error[i][j])
+    }
+  }
+  return buffer.String()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func evalReal(f objective, x ConstVector, order int) (Scalar, error) {
+  y := NullVector(RealType, x.Dim())
+  y.Set(x)
+  if order > 0 {
+    if err := y.Variables(order); err != nil {
+      return nil, err
+    }
+  }
+  return f(y)
+}
+
+// Evaluate f with Complex scalars, where coordinate i is shifted by i*h.
+func evalComplex(f objective, x ConstVector, i int, h float64, order int) (Scalar, error) {
+  y := NullVector(ComplexType, x.Dim())
+  y.Set(x)
+  y.At(i).(*Complex).SetComplexValue(complex(x.ConstAt(i).GetValue(), h))
+  if order > 0 {
+    if err := y.Variables(order); err != nil {
+      return nil, err
+    }
+  }
+  return f(y)
+}
+
+// Evaluate f with Real scalars, where coordinate i is shifted by h.
+func evalShifted(f objective, x ConstVector, i int, h float64, order int) (Scalar, error) {
+  y := NullVector(RealType, x.Dim())
+  y.Set(x)
+  y.At(i).SetValue(x.ConstAt(i).GetValue() + h)
+  if order > 0 {
+    if err := y.Variables(order); err != nil {
+      return nil, err
+    }
+  }
+  return f(y)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Compute the numeric derivative of the k-th derivative of f with respect
+// to coordinate i, where k = -1 refers to the value of f. The value of f
+// is differentiated with order = 0 and the gradient with order = 1.
+func numericDerivative(f objective, x ConstVector, i, k, order int, h float64, complexStep bool) (float64, error) {
+  get := func(y Scalar) float64 {
+    if k < 0 {
+      return y.GetValue()
+    } else {
+      return y.GetDerivative(k)
+    }
+  }
+  h *= math.Max(1.0, math.Abs(x.ConstAt(i).GetValue()))
+  if complexStep {
+    y, err := evalComplex(f, x, i, h, order)
+    if err != nil {
+      return math.NaN(), err
+    }
+    c, ok := y.(*Complex)
+    if !ok {
+      return math.NaN(), fmt.Errorf("objective function did not return a complex scalar")
+    }
+    if k < 0 {
+      return imag(c.GetComplexValue())/h, nil
+    } else {
+      return imag(c.GetComplexDerivative(k))/h, nil
+    }
+  } else {
+    y1, err := evalShifted(f, x, i,  h, order)
+    if err != nil {
+      return math.NaN(), err
+    }
+    v1 := get(y1)
+    y2, err := evalShifted(f, x, i, -h, order)
+    if err != nil {
+      return math.NaN(), err
+    }
+    v2 := get(y2)
+    return (v1 - v2)/(2.0*h), nil
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+func checkGradient(f objective, x ConstVector, tol, h float64, complexStep bool) (GradientReport, error) {
+  n := x.Dim()
+  r := newGradientReport(n)
+  y, err := evalReal(f, x, 1)
+  if err != nil {
+    return r, err
+  }
+  for i := 0; i < n; i++ {
+    r.Analytic[i] = y.GetDerivative(i)
+  }
+  for i := 0; i < n; i++ {
+    if v, err := numericDerivative(f, x, i, -1, 0, h, complexStep); err != nil {
+      return r, err
+    } else {
+      r.Numeric[i] = v
+    }
+    r.Error[i] = derivativeError(r.Analytic[i], r.Numeric[i])
+  }
+  if i, e := r.MaxError(); !(e <= tol) {
+    return r, fmt.Errorf("gradient check failed for coordinate %d: analytic derivative is %e but numeric derivative is %e", i, r.Analytic[i], r.Numeric[i])
+  }
+  return r, nil
+}
+
+func checkHessian(f objective, x ConstVector, tol, h float64, complexStep bool) (HessianReport, error) {
+  n := x.Dim()
+  r := newHessianReport(n)
+  y, err := evalReal(f, x, 2)
+  if err != nil {
+    return r, err
+  }
+  for i := 0; i < n; i++ {
+    for j := 0; j < n; j++ {
+      r.Analytic[i][j] = y.GetHessian(i, j)
+    }
+  }
+  // differentiate the analytic gradient numerically
+  for i := 0; i < n; i++ {
+    for j := 0; j < n; j++ {
+      if v, err := numericDerivative(f, x, i, j, 1, h, complexStep); err != nil {
+        return r, err
+      } else {
+        r.Numeric[i][j] = v
+      }
+      r.Error[i][j] = derivativeError(r.Analytic[i][j], r.Numeric[i][j])
+    }
+  }
+  if i, j, e := r.MaxError(); !(e <= tol) {
+    return r, fmt.Errorf("Hessian check failed for entry (%d,%d): analytic derivative is %e but numeric derivative is %e", i, j, r.Analytic[i][j], r.Numeric[i][j])
+  }
+  return r, nil
+}
+
+/* -------------------------------------------------------------------------- */
+
+func getOptions(args ...interface{}) (float64, bool) {
+  h           := math.NaN()
+  complexStep := false
+  for _, arg := range args {
+    switch a := arg.(type) {
+    case Step:
+      h = a.Value
+    case ComplexStep:
+      complexStep = a.Value
+    default:
+      panic("invalid optional argument")
+    }
+  }
+  if math.IsNaN(h) {
+    if complexStep {
+      h = 1e-20
+    } else {
+      h = math.Cbrt(2.220446e-16)
+    }
+  }
+  return h, complexStep
+}
+
+// Compare the gradient of f at x computed with Real scalars to finite
+// differences. An error is returned if the error of any coordinate
+// exceeds tol. The report contains the errors of all coordinates.
+func CheckGradient(f func(Vector) (Scalar, error), x ConstVector, tol float64, args ...interface{}) (GradientReport, error) {
+  h, complexStep := getOptions(args...)
+  return checkGradient(f, x, tol, h, complexStep)
+}
+
+// Compare the Hessian of f at x computed with Real scalars to finite
+// differences of the gradient. An error is returned if the error of any
+// entry exceeds tol. The report contains the errors of all entries.
+func CheckHessian(f func(Vector) (Scalar, error), x ConstVector, tol float64, args ...interface{}) (HessianReport, error) {
+  h, complexStep := getOptions(args...)
+  return checkHessian(f, x, tol, h, complexStep)
+}
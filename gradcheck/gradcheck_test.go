@@ -0,0 +1,211 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gradcheck
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "reflect"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+
+/* -------------------------------------------------------------------------- */
+
+func checkScalarFunction(t *testing.T, name string, f func(Vector) (Scalar, error), x []float64) {
+  if _, err := CheckGradient(f, NewDenseBareRealVector(x), 1e-6); err != nil {
+    t.Errorf("%s at %v: %v", name, x, err)
+  }
+  if _, err := CheckHessian(f, NewDenseBareRealVector(x), 1e-6); err != nil {
+    t.Errorf("%s at %v: %v", name, x, err)
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+func TestGradCheck1(t *testing.T) {
+  // f(x) = x0^2 sin(x1) + exp(x0 x1)
+  f := func(x Vector) (Scalar, error) {
+    r := NullScalar(x.ElementType())
+    s := NullScalar(x.ElementType())
+    r.Mul(x.At(0), x.At(0))
+    s.Sin(x.At(1))
+    r.Mul(r, s)
+    s.Mul(x.At(0), x.At(1))
+    s.Exp(s)
+    r.Add(r, s)
+    return r, nil
+  }
+  x := NewDenseBareRealVector([]float64{0.7, 1.3})
+
+  if r, err := CheckGradient(f, x, 1e-8); err != nil {
+    t.Error(err)
+  } else {
+    if math.Abs(r.Analytic[0] - (2.0*0.7*math.Sin(1.3) + 1.3*math.Exp(0.7*1.3))) > 1e-12 {
+      t.Error("test failed")
+    }
+  }
+  if _, err := CheckGradient(f, x, 1e-12, ComplexStep{true}); err != nil {
+    t.Error(err)
+  }
+  if _, err := CheckHessian(f, x, 1e-8); err != nil {
+    t.Error(err)
+  }
+  if _, err := CheckHessian(f, x, 1e-12, ComplexStep{true}); err != nil {
+    t.Error(err)
+  }
+}
+
+func TestGradCheck2(t *testing.T) {
+  // f(x) = x0 x1 with a wrong derivative for x1
+  f := func(x Vector) (Scalar, error) {
+    r := NullScalar(x.ElementType())
+    r.Mul(x.At(0), x.At(1))
+    if r.GetOrder() >= 1 {
+      r.SetDerivative(1, 2.0*r.GetDerivative(1))
+    }
+    return r, nil
+  }
+  x := NewDenseBareRealVector([]float64{2.0, 3.0})
+
+  if r, err := CheckGradient(f, x, 1e-6); err == nil {
+    t.Error("test failed")
+  } else {
+    if i, e := r.MaxError(); i != 1 || math.Abs(e - 1.0) > 1e-6 {
+      t.Error("test failed")
+    }
+    if r.Error[0] > 1e-6 {
+      t.Error("test failed")
+    }
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+func TestScalarMethods(t *testing.T) {
+  unary := []struct{
+    name string
+    f    func(r Scalar, a ConstScalar)
+    x    []float64
+  }{
+    {"Abs",      func(r Scalar, a ConstScalar) { r.Abs(a) },          []float64{-0.7, 1.3}},
+    {"Neg",      func(r Scalar, a ConstScalar) { r.Neg(a) },          []float64{-0.7, 1.3}},
+    {"Sqrt",     func(r Scalar, a ConstScalar) { r.Sqrt(a) },         []float64{0.3, 1.7}},
+    {"Sin",      func(r Scalar, a ConstScalar) { r.Sin(a) },          []float64{-0.7, 1.3}},
+    {"Sinh",     func(r Scalar, a ConstScalar) { r.Sinh(a) },         []float64{-0.7, 1.3}},
+    {"Cos",      func(r Scalar, a ConstScalar) { r.Cos(a) },          []float64{-0.7, 1.3}},
+    {"Cosh",     func(r Scalar, a ConstScalar) { r.Cosh(a) },         []float64{-0.7, 1.3}},
+    {"Tan",      func(r Scalar, a ConstScalar) { r.Tan(a) },          []float64{-0.7, 1.3}},
+    {"Tanh",     func(r Scalar, a ConstScalar) { r.Tanh(a) },         []float64{-0.7, 1.3}},
+    {"Exp",      func(r Scalar, a ConstScalar) { r.Exp(a) },          []float64{-0.7, 1.3}},
+    {"Log",      func(r Scalar, a ConstScalar) { r.Log(a) },          []float64{0.3, 1.7}},
+    {"Log1p",    func(r Scalar, a ConstScalar) { r.Log1p(a) },        []float64{-0.3, 1.7}},
+    {"Log1pExp", func(r Scalar, a ConstScalar) { r.Log1pExp(a) },     []float64{-40.0, -10.0, 0.5, 10.0, 25.0, 40.0}},
+    {"Logistic", func(r Scalar, a ConstScalar) { r.Logistic(a) },     []float64{-0.7, 1.3}},
+    {"Sigmoid",  func(r Scalar, a ConstScalar) { r.Sigmoid(a, NullScalar(r.Type())) }, []float64{-0.7, 1.3}},
+    {"Erf",      func(r Scalar, a ConstScalar) { r.Erf(a) },          []float64{-0.7, 1.3}},
+    {"Erfc",     func(r Scalar, a ConstScalar) { r.Erfc(a) },         []float64{-0.7, 1.3}},
+    {"LogErfc",  func(r Scalar, a ConstScalar) { r.LogErfc(a) },      []float64{-0.7, 1.3, 5.0}},
+    {"Gamma",    func(r Scalar, a ConstScalar) { r.Gamma(a) },        []float64{0.7, 2.3}},
+    {"Lgamma",   func(r Scalar, a ConstScalar) { r.Lgamma(a) },       []float64{0.7, 2.3}},
+    {"Mlgamma",  func(r Scalar, a ConstScalar) { r.Mlgamma(a, 2) },   []float64{1.7, 3.3}},
+    {"GammaP",   func(r Scalar, a ConstScalar) { r.GammaP(2.5, a) },  []float64{0.7, 2.3}},
+    {"BesselI",  func(r Scalar, a ConstScalar) { r.BesselI(1.5, a) }, []float64{0.7, 2.3}},
+  }
+  binary := []struct{
+    name string
+    f    func(r Scalar, a, b ConstScalar)
+    x    [][]float64
+  }{
+    {"Add",    func(r Scalar, a, b ConstScalar) { r.Add(a, b) },    [][]float64{{-0.7, 1.3}}},
+    {"Sub",    func(r Scalar, a, b ConstScalar) { r.Sub(a, b) },    [][]float64{{-0.7, 1.3}}},
+    {"Mul",    func(r Scalar, a, b ConstScalar) { r.Mul(a, b) },    [][]float64{{-0.7, 1.3}}},
+    {"Div",    func(r Scalar, a, b ConstScalar) { r.Div(a, b) },    [][]float64{{-0.7, 1.3}}},
+    {"Pow",    func(r Scalar, a, b ConstScalar) { r.Pow(a, b) },    [][]float64{{0.7, 1.3}, {1.7, -2.3}}},
+    {"Min",    func(r Scalar, a, b ConstScalar) { r.Min(a, b) },    [][]float64{{-0.7, 1.3}, {1.7, -2.3}}},
+    {"Max",    func(r Scalar, a, b ConstScalar) { r.Max(a, b) },    [][]float64{{-0.7, 1.3}, {1.7, -2.3}}},
+    {"LogAdd", func(r Scalar, a, b ConstScalar) { r.LogAdd(a, b, NullScalar(r.Type())) }, [][]float64{{-0.7, 1.3}, {1.7, -2.3}}},
+    {"LogSub", func(r Scalar, a, b ConstScalar) { r.LogSub(a, b, NullScalar(r.Type())) }, [][]float64{{1.3, -0.7}, {1.7, -2.3}}},
+  }
+  vector := []struct{
+    name string
+    f    func(r Scalar, a Vector)
+  }{
+    {"Vmean",        func(r Scalar, a Vector) { r.Vmean(a) }},
+    {"VdotV",        func(r Scalar, a Vector) { r.VdotV(a.Slice(0, 2), a.Slice(2, 4)) }},
+    {"Vnorm",        func(r Scalar, a Vector) { r.Vnorm(a) }},
+    {"Mnorm",        func(r Scalar, a Vector) { r.Mnorm(a.AsMatrix(2, 2)) }},
+    {"Mtrace",       func(r Scalar, a Vector) { r.Mtrace(a.AsMatrix(2, 2)) }},
+    {"SmoothMax",    func(r Scalar, a Vector) {
+      r.SmoothMax(a, ConstReal(2.0), [2]Scalar{NullScalar(r.Type()), NullScalar(r.Type())}) }},
+    {"LogSmoothMax", func(r Scalar, a Vector) {
+      r.LogSmoothMax(a, ConstReal(2.0), [3]Scalar{NullScalar(r.Type()), NullScalar(r.Type()), NullScalar(r.Type())}) }},
+  }
+  for _, test := range unary {
+    for _, x := range test.x {
+      f := func(x Vector) (Scalar, error) {
+        r := NullScalar(x.ElementType())
+        test.f(r, x.At(0))
+        return r, nil
+      }
+      checkScalarFunction(t, test.name, f, []float64{x})
+    }
+  }
+  for _, test := range binary {
+    for _, x := range test.x {
+      f := func(x Vector) (Scalar, error) {
+        r := NullScalar(x.ElementType())
+        test.f(r, x.At(0), x.At(1))
+        return r, nil
+      }
+      checkScalarFunction(t, test.name, f, x)
+    }
+  }
+  for _, test := range vector {
+    f := func(x Vector) (Scalar, error) {
+      r := NullScalar(x.ElementType())
+      test.f(r, x)
+      return r, nil
+    }
+    checkScalarFunction(t, test.name, f, []float64{-0.7, 1.3, 0.4, 2.1})
+  }
+  // check that all methods of the Scalar interface that compute a new
+  // value are covered by the tables above
+  names := make(map[string]bool)
+  for _, test := range unary {
+    names[test.name] = true
+  }
+  for _, test := range binary {
+    names[test.name] = true
+  }
+  for _, test := range vector {
+    names[test.name] = true
+  }
+  s := reflect.TypeOf((*Scalar)(nil)).Elem()
+  for i := 0; i < s.NumMethod(); i++ {
+    m := s.Method(i)
+    if m.Type.NumIn() == 0 || m.Type.NumOut() != 1 || m.Type.Out(0) != s {
+      continue
+    }
+    if m.Name == "ConvertType" {
+      continue
+    }
+    if !names[m.Name] {
+      t.Errorf("method %s is not checked", m.Name)
+    }
+  }
+}
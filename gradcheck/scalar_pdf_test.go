@@ -0,0 +1,128 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gradcheck
+
+/* -------------------------------------------------------------------------- */
+
+import   "math"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+import . "github.com/pbenner/autodiff/statistics"
+import   "github.com/pbenner/autodiff/statistics/scalarDistribution"
+
+/* -------------------------------------------------------------------------- */
+
+type scalarPdfTest struct {
+  // constructor of the distribution, NewScalarPdf is used if nil
+  pdf        func() ScalarPdf
+  parameters []float64
+  // parameters with respect to which LogPdf is differentiated
+  free       []int
+  x          []float64
+}
+
+func newTestNormal() ScalarPdf {
+  r, _ := scalarDistribution.NewNormalDistribution(NewReal(0.5), NewReal(1.5))
+  return r
+}
+
+var scalarPdfTests = map[string]scalarPdfTest{
+  "scalar:beta distribution"              : {nil, []float64{2.5, 1.5, 0.0}, []int{0, 1}, []float64{0.3, 0.8}},
+  "scalar:binomial distribution"          : {nil, []float64{math.Log(0.3), 10.0}, []int{0}, []float64{3.0, 7.0}},
+  "scalar:categorical distribution"       : {
+    func() ScalarPdf {
+      r, _ := scalarDistribution.NewCategoricalDistribution(NullVector(RealType, 3))
+      return r
+    }, []float64{math.Log(0.2), math.Log(0.3), math.Log(0.5)}, []int{0, 1, 2}, []float64{0.0, 2.0}},
+  "scalar:cauchy distribution"            : {nil, []float64{0.5, 1.5}, []int{0, 1}, []float64{-1.0, 2.0}},
+  "scalar:delta distribution"             : {nil, []float64{1.0}, []int{}, []float64{1.0}},
+  "scalar:exponential distribution"       : {nil, []float64{1.5}, []int{0}, []float64{0.5, 2.0}},
+  "scalar:gamma distribution"             : {nil, []float64{2.5, 1.5}, []int{0, 1}, []float64{0.5, 2.0}},
+  "scalar:generalized gamma distribution" : {nil, []float64{1.5, 2.5, 1.3}, []int{0, 1, 2}, []float64{0.5, 2.0}},
+  "scalar:geometric distribution"         : {nil, []float64{0.3}, []int{0}, []float64{0.0, 3.0}},
+  "scalar:gev distribution"               : {nil, []float64{0.5, 1.5, 0.2}, []int{0, 1, 2}, []float64{0.0, 2.0}},
+  "scalar:mixture distribution"           : {
+    func() ScalarPdf {
+      r, _ := scalarDistribution.NewMixture(NewVector(RealType, []float64{0.5, 0.5}), []ScalarPdf{newTestNormal(), newTestNormal()})
+      return r
+    }, []float64{math.Log(0.3), math.Log(0.7), -0.5, 1.5, 1.0, 0.5}, []int{0, 1, 2, 3, 4, 5}, []float64{-1.0, 2.0}},
+  "scalar:laplace distribution"           : {nil, []float64{0.5, 1.5}, []int{0, 1}, []float64{-1.0, 2.0}},
+  "scalar:negative binomial distribution" : {nil, []float64{3.5, 0.4}, []int{0, 1}, []float64{0.0, 5.0}},
+  "scalar:normal distribution"            : {nil, []float64{0.5, 1.5}, []int{0, 1}, []float64{-1.0, 2.0}},
+  "scalar:pareto distribution"            : {nil, []float64{1.5, 2.5}, []int{0, 1}, []float64{0.5, 2.0}},
+  "scalar:generalized pareto distribution": {nil, []float64{0.0, 1.5, 0.2}, []int{1, 2}, []float64{0.5, 2.0}},
+  "scalar:poisson distribution"           : {nil, []float64{2.5}, []int{0}, []float64{0.0, 3.0}},
+  "scalar:power law distribution"         : {nil, []float64{2.5, 1.0}, []int{0, 1}, []float64{2.0, 5.0}},
+  "scalar:pdf log transform"              : {
+    func() ScalarPdf {
+      r, _ := scalarDistribution.NewPdfLogTransform(newTestNormal(), 1.0)
+      return r
+    }, []float64{0.5, 1.5}, []int{0, 1}, []float64{0.5, 2.0}},
+  "scalar:pdf translation"                : {
+    func() ScalarPdf {
+      r, _ := scalarDistribution.NewPdfTranslation(newTestNormal(), 1.0)
+      return r
+    }, []float64{0.5, 1.5}, []int{0, 1}, []float64{-1.0, 2.0}},
+}
+
+/* -------------------------------------------------------------------------- */
+
+func TestScalarPdfs(t *testing.T) {
+  for name, _ := range ScalarPdfRegistry {
+    test, ok := scalarPdfTests[name]
+    if !ok {
+      t.Errorf("no gradient check for `%s'", name)
+      continue
+    }
+    // log-likelihood of test.x as a function of the free parameters
+    f := func(theta Vector) (Scalar, error) {
+      var pdf ScalarPdf
+      if test.pdf != nil {
+        pdf = test.pdf()
+      } else {
+        pdf = NewScalarPdf(name)
+      }
+      p := NewVector(RealType, test.parameters)
+      for k, i := range test.free {
+        p.At(i).Set(theta.At(k))
+      }
+      if err := pdf.SetParameters(p); err != nil {
+        return nil, err
+      }
+      r := NullReal()
+      s := NullReal()
+      for _, x := range test.x {
+        if err := pdf.LogPdf(s, ConstReal(x)); err != nil {
+          return nil, err
+        }
+        r.Add(r, s)
+      }
+      return r, nil
+    }
+    theta := NullVector(BareRealType, len(test.free))
+    for k, i := range test.free {
+      theta.At(k).SetValue(test.parameters[i])
+    }
+    if _, err := CheckGradient(f, theta, 1e-6); err != nil {
+      t.Errorf("%s: %v", name, err)
+    }
+    if _, err := CheckHessian(f, theta, 1e-6); err != nil {
+      t.Errorf("%s: %v", name, err)
+    }
+  }
+}
@@ -0,0 +1,267 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "encoding/json"
+import "reflect"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// Interval is a scalar that represents the closed interval [Lower, Upper]
+// of all values that a quantity might take. Operations return intervals
+// that are guaranteed to contain the result for every choice of arguments
+// from the argument intervals, where bounds are rounded outwards to
+// account for floating point errors. Intervals carry no derivatives.
+//
+// GetValue returns the midpoint of the interval. Comparisons are defined
+// as follows:
+//   a.Greater(b)       is true if every element of a is greater than every element of b
+//   a.Smaller(b)       is true if every element of a is smaller than every element of b
+//   a.Equals(b, eps)   is true if a and b contain elements with distance smaller than eps
+//   a.Sign()           is zero if a contains zero
+// Hence, overlapping intervals are neither greater nor smaller than each
+// other, but they are equal. Scalars of other types are treated as
+// intervals that contain a single point.
+type Interval struct {
+  Lower float64
+  Upper float64
+}
+
+/* register scalar type
+ * -------------------------------------------------------------------------- */
+
+var IntervalType ScalarType = NewInterval(0.0).Type()
+
+func init() {
+  f := func(value float64) Scalar { return NewInterval(value) }
+  RegisterScalar(IntervalType, f)
+}
+
+/* constructors
+ * -------------------------------------------------------------------------- */
+
+// Create a new interval that contains only v.
+func NewInterval(v float64) *Interval {
+  return &Interval{Lower: v, Upper: v}
+}
+
+// Create a new interval [lower, upper].
+func NewIntervalBounds(lower, upper float64) *Interval {
+  if lower > upper {
+    panic("lower bound is greater than upper bound")
+  }
+  return &Interval{Lower: lower, Upper: upper}
+}
+
+func NullInterval() *Interval {
+  return &Interval{}
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *Interval) Clone() *Interval {
+  r := NullInterval()
+  r.SET(a)
+  return r
+}
+
+func (a *Interval) CloneScalar() Scalar {
+  return a.Clone()
+}
+
+func (a *Interval) Type() ScalarType {
+  return reflect.TypeOf(a)
+}
+
+func (a *Interval) ConvertType(t ScalarType) Scalar {
+  switch t {
+  case IntervalType:
+    return a
+  case RealType:
+    return NewReal(a.GetValue())
+  case BareRealType:
+    return NewBareReal(a.GetValue())
+  case ReverseRealType:
+    return NewReverseReal(a.GetValue())
+  case SparseRealType:
+    return NewSparseReal(a.GetValue())
+  case DualRealType:
+    return NewDualReal(a.GetValue())
+  case TaylorRealType:
+    return NewTaylorReal(a.GetValue())
+  case ComplexType:
+    return NewComplex(a.GetValue())
+  case BareReal32Type:
+    return NewBareReal32(a.GetValue())
+  case BigRealType:
+    return NewBigReal(a.GetValue())
+  case BatchRealType:
+    return NewBatchReal(a.GetValue())
+  default:
+    panic(fmt.Sprintf("cannot convert `Interval' to type `%v'", t))
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *Interval) Alloc(n, order int) {
+}
+
+func (c *Interval) AllocForOne(a ConstScalar) {
+}
+
+func (c *Interval) AllocForTwo(a, b ConstScalar) {
+}
+
+/* read access
+ * -------------------------------------------------------------------------- */
+
+func (a *Interval) GetOrder() int {
+  return 0
+}
+
+// Returns the midpoint of the interval.
+func (a *Interval) GetValue() float64 {
+  if math.IsInf(a.Lower, -1) && math.IsInf(a.Upper, 1) {
+    return 0.0
+  }
+  return a.Lower/2.0 + a.Upper/2.0
+}
+
+func (a *Interval) GetLogValue() float64 {
+  return math.Log(a.GetValue())
+}
+
+func (a *Interval) GetDerivative(i int) float64 {
+  return 0.0
+}
+
+func (a *Interval) GetHessian(i, j int) float64 {
+  return 0.0
+}
+
+func (a *Interval) GetN() int {
+  return 0
+}
+
+func (a *Interval) GetLower() float64 {
+  return a.Lower
+}
+
+func (a *Interval) GetUpper() float64 {
+  return a.Upper
+}
+
+// Returns the width of the interval, rounded upwards.
+func (a *Interval) GetWidth() float64 {
+  return roundUp(a.Upper - a.Lower)
+}
+
+// Returns true if v is an element of the interval.
+func (a *Interval) Contains(v float64) bool {
+  return a.Lower <= v && v <= a.Upper
+}
+
+/* write access
+ * -------------------------------------------------------------------------- */
+
+func (a *Interval) Reset() {
+  a.Lower = 0.0
+  a.Upper = 0.0
+}
+
+func (a *Interval) ResetDerivatives() {
+}
+
+func (a *Interval) Set(b ConstScalar) {
+  a.Lower, a.Upper = intervalBounds(b)
+}
+
+func (a *Interval) SET(b *Interval) {
+  a.Lower = b.Lower
+  a.Upper = b.Upper
+}
+
+// Set the interval to the single point v.
+func (a *Interval) SetValue(v float64) {
+  a.Lower = v
+  a.Upper = v
+}
+
+func (a *Interval) setValue(v float64) {
+  a.SetValue(v)
+}
+
+// Set the interval to [lower, upper].
+func (a *Interval) SetBounds(lower, upper float64) {
+  if lower > upper {
+    panic("lower bound is greater than upper bound")
+  }
+  a.Lower = lower
+  a.Upper = upper
+}
+
+func (a *Interval) SetDerivative(i int, v float64) {
+}
+
+func (a *Interval) SetHessian(i, j int, v float64) {
+}
+
+func (a *Interval) SetVariable(i, n, order int) error {
+  return fmt.Errorf("Interval cannot be used as a variable")
+}
+
+/* type conversion
+ * -------------------------------------------------------------------------- */
+
+func (a *Interval) String() string {
+  return fmt.Sprintf("[%e, %e]", a.Lower, a.Upper)
+}
+
+/* json
+ * -------------------------------------------------------------------------- */
+
+func (obj *Interval) MarshalJSON() ([]byte, error) {
+  if obj.Lower == obj.Upper {
+    return json.Marshal(obj.Lower)
+  } else {
+    return json.Marshal([2]float64{obj.Lower, obj.Upper})
+  }
+}
+
+func (obj *Interval) UnmarshalJSON(data []byte) error {
+  r := [2]float64{}
+  if err := json.Unmarshal(data, &r); err == nil {
+    if r[0] > r[1] {
+      return fmt.Errorf("invalid json scalar representation")
+    }
+    obj.Lower = r[0]
+    obj.Upper = r[1]
+    return nil
+  } else {
+    v := 0.0
+    if err := json.Unmarshal(data, &v); err != nil {
+      return err
+    }
+    obj.SetValue(v)
+    return nil
+  }
+}
@@ -0,0 +1,776 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+import "math"
+
+import "github.com/pbenner/autodiff/special"
+
+/* -------------------------------------------------------------------------- */
+
+// Relative accuracy that is assumed for elementary functions (exp, log,
+// sin, ...) of the math package. Results are widened accordingly.
+const intervalEpsElementary = 1e-15
+
+// Relative accuracy that is assumed for special functions (lgamma, erf,
+// incomplete gamma, bessel functions, ...).
+const intervalEpsSpecial = 1e-12
+
+// Below this threshold error terms of floating point operations are not
+// reliable and results are rounded in both directions.
+const intervalTiny = 1e-290
+
+// Location and value of the minimum of the gamma function on (0, Inf).
+const intervalGammaArgMin = 1.4616321449683623
+const intervalGammaMin    = 0.8856031944108887
+
+/* rounding
+ * -------------------------------------------------------------------------- */
+
+func roundDown(x float64) float64 {
+  return math.Nextafter(x, math.Inf(-1))
+}
+
+func roundUp(x float64) float64 {
+  return math.Nextafter(x, math.Inf( 1))
+}
+
+// Round p outwards, where e is the error term of the floating point
+// operation that computed p, i.e. the exact result is p + e.
+func roundError(p, e float64) (float64, float64) {
+  switch {
+  case e > 0.0:
+    return p, roundUp(p)
+  case e < 0.0:
+    return roundDown(p), p
+  default:
+    return p, p
+  }
+}
+
+// Widen v, which was computed by a library function with relative
+// accuracy eps.
+func roundWiden(v, eps float64) (float64, float64) {
+  if math.IsInf(v, 0) || math.IsNaN(v) {
+    return v, v
+  }
+  d := eps*math.Abs(v)
+  return roundDown(v - d), roundUp(v + d)
+}
+
+// Bounds of x + y.
+func addBounds(x, y float64) (float64, float64) {
+  s := x + y
+  if math.IsInf(x, 0) || math.IsInf(y, 0) || math.IsNaN(s) {
+    return s, s
+  }
+  if math.IsInf(s, 0) {
+    // overflow
+    return roundDown(s), roundUp(s)
+  }
+  z := s - x
+  return roundError(s, (x - (s - z)) + (y - z))
+}
+
+// Bounds of x * y, where zero times infinity is zero.
+func mulBounds(x, y float64) (float64, float64) {
+  if x == 0.0 || y == 0.0 {
+    return 0.0, 0.0
+  }
+  p := x*y
+  if math.IsInf(x, 0) || math.IsInf(y, 0) || math.IsNaN(p) {
+    return p, p
+  }
+  if math.IsInf(p, 0) || math.Abs(p) < intervalTiny {
+    return roundDown(p), roundUp(p)
+  }
+  return roundError(p, math.FMA(x, y, -p))
+}
+
+// Bounds of x / y, where y must be nonzero.
+func divBounds(x, y float64) (float64, float64) {
+  q := x/y
+  if math.IsInf(x, 0) || math.IsInf(y, 0) || math.IsNaN(q) || q == 0.0 && x == 0.0 {
+    return q, q
+  }
+  if math.IsInf(q, 0) || math.Abs(q) < intervalTiny {
+    return roundDown(q), roundUp(q)
+  }
+  // exact remainder x - q*y
+  r := math.FMA(-q, y, x)
+  if y < 0.0 {
+    r = -r
+  }
+  return roundError(q, r)
+}
+
+// Bounds of the square root of x >= 0.
+func sqrtBounds(x float64) (float64, float64) {
+  s := math.Sqrt(x)
+  if x == 0.0 || math.IsInf(x, 0) || math.IsNaN(s) {
+    return s, s
+  }
+  if x < intervalTiny {
+    return roundDown(s), roundUp(s)
+  }
+  return roundError(s, math.FMA(-s, s, x))
+}
+
+// Bounds of x^n for x >= 0 computed by repeated squaring.
+func powBounds(x float64, n int) (float64, float64) {
+  rl, ru := 1.0, 1.0
+  pl, pu := x, x
+  for ; n > 0; n >>= 1 {
+    if n & 1 == 1 {
+      rl, _ = mulBounds(rl, pl)
+      _, ru = mulBounds(ru, pu)
+    }
+    if n > 1 {
+      pl, _ = mulBounds(pl, pl)
+      _, pu = mulBounds(pu, pu)
+    }
+  }
+  return rl, ru
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Returns the bounds of a, which are identical if a is not an interval.
+func intervalBounds(a ConstScalar) (float64, float64) {
+  if r, ok := a.(*Interval); ok {
+    return r.Lower, r.Upper
+  }
+  v := a.GetValue()
+  return v, v
+}
+
+// Returns true if [l, u] might contain a point c + k*p for some integer k.
+// The test is conservative, i.e. it may also return true if the interval
+// contains no such point.
+func intervalContainsPeriodic(l, u, c, p float64) bool {
+  if !(u - l < p && math.Abs(l) < 1e12 && math.Abs(u) < 1e12) {
+    return true
+  }
+  s := (l - c)/p
+  t := (u - c)/p
+  d := 1e-14*(1.0 + math.Abs(s) + math.Abs(t))
+  return math.Ceil(s - d) <= t + d
+}
+
+// Evaluate a monotonically increasing function f with relative accuracy eps.
+func (c *Interval) increasing(a ConstScalar, f func(float64) float64, eps float64) *Interval {
+  l, u := intervalBounds(a)
+  c.Lower, _ = roundWiden(f(l), eps)
+  _, c.Upper = roundWiden(f(u), eps)
+  return c
+}
+
+// Evaluate a monotonically decreasing function f with relative accuracy eps.
+func (c *Interval) decreasing(a ConstScalar, f func(float64) float64, eps float64) *Interval {
+  l, u := intervalBounds(a)
+  c.Lower, _ = roundWiden(f(u), eps)
+  _, c.Upper = roundWiden(f(l), eps)
+  return c
+}
+
+// Restrict bounds to the range [lower, upper] of a function.
+func (c *Interval) clip(lower, upper float64) *Interval {
+  c.Lower = math.Max(c.Lower, lower)
+  c.Upper = math.Min(c.Upper, upper)
+  return c
+}
+
+func (c *Interval) setEntire() *Interval {
+  c.Lower = math.Inf(-1)
+  c.Upper = math.Inf( 1)
+  return c
+}
+
+func (c *Interval) setNaN() *Interval {
+  c.Lower = math.NaN()
+  c.Upper = math.NaN()
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Returns true if a and b contain elements with distance smaller than
+// epsilon. For intervals that contain single points this is equivalent
+// to |a - b| < epsilon.
+func (a *Interval) Equals(b ConstScalar, epsilon float64) bool {
+  bl, bu := intervalBounds(b)
+  return math.Max(a.Lower - bu, bl - a.Upper) < epsilon
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Returns true if all elements of a are greater than all elements of b.
+func (a *Interval) Greater(b ConstScalar) bool {
+  _, bu := intervalBounds(b)
+  return a.Lower > bu
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Returns true if all elements of a are smaller than all elements of b.
+func (a *Interval) Smaller(b ConstScalar) bool {
+  bl, _ := intervalBounds(b)
+  return a.Upper < bl
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Returns the sign of all elements of a, or zero if a contains zero.
+func (a *Interval) Sign() int {
+  if a.Upper < 0.0 {
+    return -1
+  }
+  if a.Lower > 0.0 {
+    return  1
+  }
+  return 0
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *Interval) Min(a, b ConstScalar) Scalar {
+  al, au := intervalBounds(a)
+  bl, bu := intervalBounds(b)
+  r.Lower = math.Min(al, bl)
+  r.Upper = math.Min(au, bu)
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *Interval) Max(a, b ConstScalar) Scalar {
+  al, au := intervalBounds(a)
+  bl, bu := intervalBounds(b)
+  r.Lower = math.Max(al, bl)
+  r.Upper = math.Max(au, bu)
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Interval) Abs(a ConstScalar) Scalar {
+  l, u := intervalBounds(a)
+  switch {
+  case l >= 0.0:
+    c.Lower, c.Upper =  l,  u
+  case u <= 0.0:
+    c.Lower, c.Upper = -u, -l
+  default:
+    c.Lower, c.Upper = 0.0, math.Max(-l, u)
+  }
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Interval) Neg(a ConstScalar) Scalar {
+  l, u := intervalBounds(a)
+  c.Lower, c.Upper = -u, -l
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Interval) Add(a, b ConstScalar) Scalar {
+  al, au := intervalBounds(a)
+  bl, bu := intervalBounds(b)
+  c.Lower, _ = addBounds(al, bl)
+  _, c.Upper = addBounds(au, bu)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Interval) Sub(a, b ConstScalar) Scalar {
+  al, au := intervalBounds(a)
+  bl, bu := intervalBounds(b)
+  c.Lower, _ = addBounds(al, -bu)
+  _, c.Upper = addBounds(au, -bl)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Interval) Mul(a, b ConstScalar) Scalar {
+  al, au := intervalBounds(a)
+  bl, bu := intervalBounds(b)
+  l1, u1 := mulBounds(al, bl)
+  l2, u2 := mulBounds(al, bu)
+  l3, u3 := mulBounds(au, bl)
+  l4, u4 := mulBounds(au, bu)
+  c.Lower = math.Min(math.Min(l1, l2), math.Min(l3, l4))
+  c.Upper = math.Max(math.Max(u1, u2), math.Max(u3, u4))
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Division by an interval that contains zero results in the entire real
+// line.
+func (c *Interval) Div(a, b ConstScalar) Scalar {
+  al, au := intervalBounds(a)
+  bl, bu := intervalBounds(b)
+  if bl <= 0.0 && bu >= 0.0 {
+    return c.setEntire()
+  }
+  l1, u1 := divBounds(al, bl)
+  l2, u2 := divBounds(al, bu)
+  l3, u3 := divBounds(au, bl)
+  l4, u4 := divBounds(au, bu)
+  c.Lower = math.Min(math.Min(l1, l2), math.Min(l3, l4))
+  c.Upper = math.Max(math.Max(u1, u2), math.Max(u3, u4))
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func intervalLogAdd(x, y float64) *Interval {
+  if x > y {
+    x, y = y, x
+  }
+  r := NewInterval(y)
+  if math.IsInf(x, -1) || math.IsInf(y, 1) {
+    return r
+  }
+  t := NewInterval(x)
+  t.Sub(t, r)
+  t.Exp(t)
+  t.Log1p(t)
+  r.Add(r, t)
+  return r
+}
+
+func intervalLogSub(x, y float64) *Interval {
+  r := NewInterval(x)
+  switch {
+  case math.IsInf(y, -1):
+    return r
+  case x < y:
+    return r.setNaN()
+  case x == y:
+    r.SetValue(math.Inf(-1))
+    return r
+  }
+  t := NewInterval(y)
+  t.Sub(t, r)
+  t.Exp(t)
+  t.Neg(t)
+  t.Log1p(t)
+  r.Add(r, t)
+  return r
+}
+
+func (c *Interval) LogAdd(a, b ConstScalar, t Scalar) Scalar {
+  al, au := intervalBounds(a)
+  bl, bu := intervalBounds(b)
+  c.Lower = intervalLogAdd(al, bl).Lower
+  c.Upper = intervalLogAdd(au, bu).Upper
+  return c
+}
+
+// The result is restricted to elements of a and b for which the logarithm
+// is defined.
+func (c *Interval) LogSub(a, b ConstScalar, t Scalar) Scalar {
+  al, au := intervalBounds(a)
+  bl, bu := intervalBounds(b)
+  if au < bl {
+    return c.setNaN()
+  }
+  if al <= bu {
+    c.Lower = math.Inf(-1)
+  } else {
+    c.Lower = intervalLogSub(al, bu).Lower
+  }
+  c.Upper = intervalLogSub(au, bl).Upper
+  return c
+}
+
+func (c *Interval) Log1pExp(a ConstScalar) Scalar {
+  f := func(x float64) float64 {
+    if x <= 18.0 {
+      return math.Log1p(math.Exp(x))
+    } else
+    if x <= 33.3 {
+      return x + math.Exp(-x)
+    } else {
+      return x
+    }
+  }
+  return c.increasing(a, f, 4.0*intervalEpsElementary).clip(0.0, math.Inf(1))
+}
+
+func (c *Interval) Sigmoid(a ConstScalar, t Scalar) Scalar {
+  return c.Logistic(a)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// If the exponent k is not an integer, the result is restricted to
+// nonnegative elements of a.
+func (c *Interval) Pow(a, k ConstScalar) Scalar {
+  kl, ku := intervalBounds(k)
+  if kl == ku && kl == math.Floor(kl) && math.Abs(kl) < 1<<53 {
+    return c.powInt(a, int(math.Abs(kl)), kl < 0.0)
+  }
+  t := NullInterval()
+  t.Log(a)
+  t.Mul(t, k)
+  c.Exp(t)
+  return c
+}
+
+func (c *Interval) powInt(a ConstScalar, n int, inverse bool) *Interval {
+  l, u := intervalBounds(a)
+  switch {
+  case n == 0:
+    c.SetValue(1.0)
+  case l >= 0.0:
+    c.Lower, _ = powBounds( l, n)
+    _, c.Upper = powBounds( u, n)
+  case n % 2 == 1:
+    // odd power of an interval with negative elements
+    _, r1 := powBounds(-l, n)
+    r2, _ := powBounds(-u, n)
+    c.Lower = -r1
+    if u <= 0.0 {
+      c.Upper = -r2
+    } else {
+      _, c.Upper = powBounds(u, n)
+    }
+  case u <= 0.0:
+    c.Lower, _ = powBounds(-u, n)
+    _, c.Upper = powBounds(-l, n)
+  default:
+    c.Lower    = 0.0
+    _, c.Upper = powBounds(math.Max(-l, u), n)
+  }
+  if inverse {
+    c.Div(ConstReal(1.0), c)
+  }
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+// The result is restricted to nonnegative elements of a.
+func (c *Interval) Sqrt(a ConstScalar) Scalar {
+  l, u := intervalBounds(a)
+  if u < 0.0 {
+    return c.setNaN()
+  }
+  c.Lower, _ = sqrtBounds(math.Max(l, 0.0))
+  _, c.Upper = sqrtBounds(u)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Interval) Sin(a ConstScalar) Scalar {
+  l, u := intervalBounds(a)
+  l1, l2 := roundWiden(math.Sin(l), intervalEpsElementary)
+  u1, u2 := roundWiden(math.Sin(u), intervalEpsElementary)
+  c.Lower = math.Min(l1, u1)
+  c.Upper = math.Max(l2, u2)
+  if intervalContainsPeriodic(l, u,  math.Pi/2.0, 2.0*math.Pi) {
+    c.Upper =  1.0
+  }
+  if intervalContainsPeriodic(l, u, -math.Pi/2.0, 2.0*math.Pi) {
+    c.Lower = -1.0
+  }
+  return c.clip(-1.0, 1.0)
+}
+
+func (c *Interval) Sinh(a ConstScalar) Scalar {
+  return c.increasing(a, math.Sinh, intervalEpsElementary)
+}
+
+func (c *Interval) Cos(a ConstScalar) Scalar {
+  l, u := intervalBounds(a)
+  l1, l2 := roundWiden(math.Cos(l), intervalEpsElementary)
+  u1, u2 := roundWiden(math.Cos(u), intervalEpsElementary)
+  c.Lower = math.Min(l1, u1)
+  c.Upper = math.Max(l2, u2)
+  if intervalContainsPeriodic(l, u, 0.0, 2.0*math.Pi) {
+    c.Upper =  1.0
+  }
+  if intervalContainsPeriodic(l, u, math.Pi, 2.0*math.Pi) {
+    c.Lower = -1.0
+  }
+  return c.clip(-1.0, 1.0)
+}
+
+func (c *Interval) Cosh(a ConstScalar) Scalar {
+  l, u := intervalBounds(a)
+  switch {
+  case l >= 0.0:
+    c.increasing(a, math.Cosh, intervalEpsElementary)
+  case u <= 0.0:
+    c.decreasing(a, math.Cosh, intervalEpsElementary)
+  default:
+    c.Lower    = 1.0
+    _, c.Upper = roundWiden(math.Cosh(math.Max(-l, u)), intervalEpsElementary)
+  }
+  return c.clip(1.0, math.Inf(1))
+}
+
+// The result is the entire real line if a contains a pole.
+func (c *Interval) Tan(a ConstScalar) Scalar {
+  l, u := intervalBounds(a)
+  if intervalContainsPeriodic(l, u, math.Pi/2.0, math.Pi) {
+    return c.setEntire()
+  }
+  return c.increasing(a, math.Tan, intervalEpsElementary)
+}
+
+func (c *Interval) Tanh(a ConstScalar) Scalar {
+  return c.increasing(a, math.Tanh, intervalEpsElementary).clip(-1.0, 1.0)
+}
+
+func (c *Interval) Exp(a ConstScalar) Scalar {
+  return c.increasing(a, math.Exp, intervalEpsElementary).clip(0.0, math.Inf(1))
+}
+
+// The result is restricted to nonnegative elements of a.
+func (c *Interval) Log(a ConstScalar) Scalar {
+  l, u := intervalBounds(a)
+  if u < 0.0 {
+    return c.setNaN()
+  }
+  c.Lower, _ = roundWiden(math.Log(math.Max(l, 0.0)), intervalEpsElementary)
+  _, c.Upper = roundWiden(math.Log(u), intervalEpsElementary)
+  return c
+}
+
+// The result is restricted to elements of a greater or equal to -1.
+func (c *Interval) Log1p(a ConstScalar) Scalar {
+  l, u := intervalBounds(a)
+  if u < -1.0 {
+    return c.setNaN()
+  }
+  c.Lower, _ = roundWiden(math.Log1p(math.Max(l, -1.0)), intervalEpsElementary)
+  _, c.Upper = roundWiden(math.Log1p(u), intervalEpsElementary)
+  return c
+}
+
+func (c *Interval) Logistic(a ConstScalar) Scalar {
+  f := func(x float64) float64 {
+    return 1.0/(1.0 + math.Exp(-x))
+  }
+  return c.increasing(a, f, 4.0*intervalEpsElementary).clip(0.0, 1.0)
+}
+
+func (c *Interval) Erf(a ConstScalar) Scalar {
+  return c.increasing(a, math.Erf, intervalEpsSpecial).clip(-1.0, 1.0)
+}
+
+func (c *Interval) Erfc(a ConstScalar) Scalar {
+  return c.decreasing(a, math.Erfc, intervalEpsSpecial).clip(0.0, 2.0)
+}
+
+func (c *Interval) LogErfc(a ConstScalar) Scalar {
+  return c.decreasing(a, special.LogErfc, intervalEpsSpecial)
+}
+
+// The result is the entire real line if a contains nonpositive elements.
+func (c *Interval) Gamma(a ConstScalar) Scalar {
+  return c.gamma(a, math.Gamma, intervalGammaMin)
+}
+
+// The result is the entire real line if a contains nonpositive elements.
+func (c *Interval) Lgamma(a ConstScalar) Scalar {
+  f := func(x float64) float64 {
+    v, _ := math.Lgamma(x)
+    return v
+  }
+  return c.gamma(a, f, math.Log(intervalGammaMin))
+}
+
+// Evaluate a function f that is decreasing on (0, x_min] and increasing
+// on [x_min, Inf), where fmin = f(x_min) and x_min is the location of the
+// minimum of the gamma function.
+func (c *Interval) gamma(a ConstScalar, f func(float64) float64, fmin float64) *Interval {
+  l, u := intervalBounds(a)
+  switch {
+  case !(l > 0.0):
+    c.setEntire()
+  case u < roundDown(intervalGammaArgMin):
+    c.decreasing(a, f, intervalEpsSpecial)
+  case l > roundUp  (intervalGammaArgMin):
+    c.increasing(a, f, intervalEpsSpecial)
+  default:
+    _, r1 := roundWiden(f(l), intervalEpsSpecial)
+    _, r2 := roundWiden(f(u), intervalEpsSpecial)
+    c.Lower, _ = roundWiden(fmin, intervalEpsSpecial)
+    c.Upper    = math.Max(r1, r2)
+  }
+  return c
+}
+
+func (c *Interval) Mlgamma(a ConstScalar, k int) Scalar {
+  r := NullInterval()
+  t := NullInterval()
+  // log(pi)
+  r.SetBounds(roundDown(math.Pi), roundUp(math.Pi))
+  r.Log(r)
+  r.Mul(r, ConstReal(float64(k*(k-1))/4.0))
+  for i := 1; i <= k; i++ {
+    t.Add(a, ConstReal(float64(1-i)/2.0))
+    t.Lgamma(t)
+    r.Add(r, t)
+  }
+  c.SET(r)
+  return c
+}
+
+// Regularized lower incomplete gamma function. The result is restricted
+// to nonnegative elements of x.
+func (c *Interval) GammaP(a float64, x ConstScalar) Scalar {
+  l, u := intervalBounds(x)
+  if u < 0.0 {
+    return c.setNaN()
+  }
+  f := func(x float64) float64 {
+    return special.GammaP(a, x)
+  }
+  return c.increasing(NewIntervalBounds(math.Max(l, 0.0), u), f, intervalEpsSpecial).clip(0.0, 1.0)
+}
+
+// Modified Bessel function of the first kind. The result is the entire
+// real line unless v and all elements of x are nonnegative.
+func (c *Interval) BesselI(v float64, x ConstScalar) Scalar {
+  if l, _ := intervalBounds(x); v < 0.0 || l < 0.0 {
+    return c.setEntire()
+  }
+  f := func(x float64) float64 {
+    return special.BesselI(v, x)
+  }
+  return c.increasing(x, f, intervalEpsSpecial).clip(0.0, math.Inf(1))
+}
+
+// Logarithm of the modified Bessel function of the first kind. The result
+// is the entire real line unless v and all elements of x are nonnegative.
+func (c *Interval) LogBesselI(v float64, x ConstScalar) Scalar {
+  if l, _ := intervalBounds(x); v < 0.0 || l < 0.0 {
+    return c.setEntire()
+  }
+  f := func(x float64) float64 {
+    return special.LogBesselI(v, x)
+  }
+  return c.increasing(x, f, intervalEpsSpecial)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *Interval) SmoothMax(x ConstVector, alpha ConstReal, t [2]Scalar) Scalar {
+  r   .Reset()
+  t[1].Reset()
+  for i := 0; i < x.Dim(); i++ {
+    t[0].Mul(alpha, x.ConstAt(i))
+    t[0].Exp(t[0])
+    t[1].Add(t[1], t[0])
+    t[0].Mul(t[0], x.ConstAt(i))
+    r .Add(r , t[0])
+  }
+  r.Div(r, t[1])
+  return r
+}
+
+func (r *Interval) LogSmoothMax(x ConstVector, alpha ConstReal, t [3]Scalar) Scalar {
+  r   .Reset()
+  t[2].SetValue(math.Inf(-1))
+  for i := 0; i < x.Dim(); i++ {
+    t[0].Mul(x.ConstAt(i), alpha)
+    t[2].LogAdd(t[2], t[0], t[1])
+    t[1].Log(x.ConstAt(i))
+    t[0].Add(t[0], t[1])
+    r.LogAdd(r, t[0], t[1])
+  }
+  r.Sub(r, t[2])
+  r.Exp(r)
+  return r
+}
+
+func (r *Interval) Vmean(a ConstVector) Scalar {
+  r.Reset()
+  for i := 0; i < a.Dim(); i++ {
+    r.Add(r, a.ConstAt(i))
+  }
+  return r.Div(r, ConstReal(float64(a.Dim())))
+}
+
+func (r *Interval) VdotV(a, b ConstVector) Scalar {
+  if a.Dim() != b.Dim() {
+    panic("vector dimensions do not match")
+  }
+  r.Reset()
+  t := NullInterval()
+  for i := 0; i < a.Dim(); i++ {
+    t.Mul(a.ConstAt(i), b.ConstAt(i))
+    r.Add(r, t)
+  }
+  return r
+}
+
+func (r *Interval) Vnorm(a ConstVector) Scalar {
+  r.Reset()
+  t := NullInterval()
+  for it := a.ConstIterator(); it.Ok(); it.Next() {
+    t.Pow(it.GetConst(), ConstReal(2.0))
+    r.Add(r, t)
+  }
+  r.Sqrt(r)
+  return r
+}
+
+func (r *Interval) Mtrace(a ConstMatrix) Scalar {
+  n, m := a.Dims()
+  if n != m {
+    panic("not a square matrix")
+  }
+  if n == 0 {
+    return nil
+  }
+  r.Reset()
+  for i := 0; i < n; i++ {
+    r.Add(r, a.ConstAt(i,i))
+  }
+  return r
+}
+
+// Frobenius norm.
+func (r *Interval) Mnorm(a ConstMatrix) Scalar {
+  n, m := a.Dims()
+  if n == 0 || m == 0 {
+    return nil
+  }
+  c := ConstReal(2.0)
+  t := NullInterval()
+  v := a.AsConstVector()
+  r.Pow(v.ConstAt(0), c)
+  for i := 1; i < v.Dim(); i++ {
+    t.Pow(v.ConstAt(i), c)
+    r.Add(r, t)
+  }
+  return r
+}
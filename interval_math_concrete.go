@@ -0,0 +1,143 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+func (a *Interval) EQUALS(b *Interval, epsilon float64) bool {
+  return a.Equals(b, epsilon)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *Interval) GREATER(b *Interval) bool {
+  return a.Lower > b.Upper
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *Interval) SMALLER(b *Interval) bool {
+  return a.Upper < b.Lower
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *Interval) SIGN() int {
+  return a.Sign()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *Interval) MIN(a, b *Interval) Scalar {
+  return r.Min(a, b)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *Interval) MAX(a, b *Interval) Scalar {
+  return r.Max(a, b)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Interval) ABS(a *Interval) Scalar {
+  return c.Abs(a)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Interval) NEG(a *Interval) *Interval {
+  c.Lower, c.Upper = -a.Upper, -a.Lower
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Interval) ADD(a, b *Interval) *Interval {
+  c.Lower, _ = addBounds(a.Lower, b.Lower)
+  _, c.Upper = addBounds(a.Upper, b.Upper)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Interval) SUB(a, b *Interval) *Interval {
+  c.Lower, _ = addBounds(a.Lower, -b.Upper)
+  _, c.Upper = addBounds(a.Upper, -b.Lower)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Interval) MUL(a, b *Interval) *Interval {
+  c.Mul(a, b)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Interval) DIV(a, b *Interval) *Interval {
+  c.Div(a, b)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Interval) LOGADD(a, b, t *Interval) *Interval {
+  c.LogAdd(a, b, t)
+  return c
+}
+
+func (c *Interval) LOGSUB(a, b, t *Interval) *Interval {
+  c.LogSub(a, b, t)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Interval) POW(a, k *Interval) *Interval {
+  c.Pow(a, k)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Interval) SQRT(a *Interval) *Interval {
+  c.Sqrt(a)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *Interval) EXP(a *Interval) *Interval {
+  c.Exp(a)
+  return c
+}
+
+func (c *Interval) LOG(a *Interval) *Interval {
+  c.Log(a)
+  return c
+}
+
+func (c *Interval) LOG1P(a *Interval) *Interval {
+  c.Log1p(a)
+  return c
+}
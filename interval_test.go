@@ -0,0 +1,136 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "math"
+import "math/big"
+import "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestInterval1(t *testing.T) {
+
+  a := NewInterval(0.1)
+  b := NewInterval(0.2)
+  c := NullInterval()
+
+  // check that the exact sum of the two floating point numbers
+  // is contained in the result
+  c.Add(a, b)
+  if c.Lower >= c.Upper {
+    t.Error("test failed")
+  }
+  r := new(big.Float).SetPrec(200)
+  r.Add(big.NewFloat(0.1), big.NewFloat(0.2))
+  if r.Cmp(big.NewFloat(c.Lower)) < 0 || r.Cmp(big.NewFloat(c.Upper)) > 0 {
+    t.Error("test failed")
+  }
+  r.SetPrec(200).Quo(big.NewFloat(0.1), big.NewFloat(0.2))
+  c.Div(a, b)
+  if r.Cmp(big.NewFloat(c.Lower)) < 0 || r.Cmp(big.NewFloat(c.Upper)) > 0 {
+    t.Error("test failed")
+  }
+  // exact operations are not widened
+  c.Mul(NewInterval(3.0), NewInterval(0.5))
+  if c.Lower != 1.5 || c.Upper != 1.5 {
+    t.Error("test failed")
+  }
+  c.Div(a, NewIntervalBounds(-1, 1))
+  if !math.IsInf(c.Lower, -1) || !math.IsInf(c.Upper, 1) {
+    t.Error("test failed")
+  }
+}
+
+func TestInterval2(t *testing.T) {
+
+  a := NewIntervalBounds(1.0, 2.0)
+  b := NewIntervalBounds(1.5, 3.0)
+  c := NewIntervalBounds(2.5, 3.0)
+
+  // overlapping intervals
+  if a.Greater(b) || a.Smaller(b) || !a.Equals(b, 1e-12) {
+    t.Error("test failed")
+  }
+  if c.Greater(a) != true || a.Smaller(c) != true || a.Equals(c, 0.4) {
+    t.Error("test failed")
+  }
+  if !a.Equals(c, 0.6) {
+    t.Error("test failed")
+  }
+  if NewIntervalBounds(-1, 1).Sign() != 0 || a.Sign() != 1 {
+    t.Error("test failed")
+  }
+  if a.GetValue() != 1.5 {
+    t.Error("test failed")
+  }
+}
+
+func TestInterval3(t *testing.T) {
+
+  x := NewIntervalBounds(-0.5, 2.0)
+  r := NullInterval()
+
+  check := func(f func(x float64) float64, l, u float64) {
+    for i := 0; i <= 100; i++ {
+      v := f(x.Lower + float64(i)/100.0*(x.Upper - x.Lower))
+      if !r.Contains(v) {
+        t.Errorf("test failed for value %v", v)
+      }
+    }
+    if math.Abs(r.Lower - l) > 1e-8 || math.Abs(r.Upper - u) > 1e-8 {
+      t.Error("test failed")
+    }
+  }
+  r.Exp(x)
+  check(math.Exp, math.Exp(-0.5), math.Exp(2.0))
+  r.Erf(x)
+  check(math.Erf, math.Erf(-0.5), math.Erf(2.0))
+  r.Pow(x, ConstReal(2.0))
+  check(func(x float64) float64 { return x*x }, 0.0, 4.0)
+  r.Cos(x)
+  check(math.Cos, math.Cos(2.0), 1.0)
+
+  y := NewIntervalBounds(0.5, 3.0)
+  x.SET(y)
+  r.Log(y)
+  check(math.Log, math.Log(0.5), math.Log(3.0))
+  r.Lgamma(y)
+  check(func(x float64) float64 { v, _ := math.Lgamma(x); return v }, math.Log(intervalGammaMin), math.Log(2.0))
+  r.Pow(y, ConstReal(0.5))
+  check(math.Sqrt, math.Sqrt(0.5), math.Sqrt(3.0))
+}
+
+func TestInterval4(t *testing.T) {
+
+  x := NewVector(IntervalType, []float64{1.0, 2.0, 3.0})
+
+  if _, ok := x.(DenseIntervalVector); !ok {
+    t.Error("test failed")
+  }
+  r := NullInterval()
+  r.VdotV(x, x)
+
+  if r.Lower != 14.0 || r.Upper != 14.0 {
+    t.Error("test failed")
+  }
+  if err := r.SetVariable(0, 1, 1); err == nil {
+    t.Error("test failed")
+  }
+}
@@ -0,0 +1,109 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+// A linear operator is a linear map that is only accessed through matrix
+// vector products, i.e. the matrix representation is not required to be
+// stored explicitly. Matrices are converted to linear operators with
+// AsLinearOperator.
+type LinearOperator interface {
+  Dims ()                       (int, int)
+  // compute the product with b and store the result in r
+  MdotV(r Vector, b ConstVector) Vector
+}
+
+/* -------------------------------------------------------------------------- */
+
+type linearOperatorFunc struct {
+  rows int
+  cols int
+  f    func(r Vector, b ConstVector)
+}
+
+// Create a linear operator from a function that computes the matrix vector
+// product of a rows x cols matrix with b and stores the result in r.
+func NewLinearOperator(rows, cols int, f func(r Vector, b ConstVector)) LinearOperator {
+  return linearOperatorFunc{rows: rows, cols: cols, f: f}
+}
+
+func (obj linearOperatorFunc) Dims() (int, int) {
+  return obj.rows, obj.cols
+}
+
+func (obj linearOperatorFunc) MdotV(r Vector, b ConstVector) Vector {
+  if r.Dim() != obj.rows || b.Dim() != obj.cols {
+    panic("matrix/vector dimensions do not match!")
+  }
+  obj.f(r, b)
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Convert a matrix into a linear operator. For sparse matrices only non-zero
+// elements are visited when computing matrix vector products. Low-rank
+// matrices are multiplied in factored form.
+func AsLinearOperator(a ConstMatrix) LinearOperator {
+  n, m := a.Dims()
+  switch a := a.(type) {
+  case CSRConstRealMatrix:
+    return NewLinearOperator(n, m, func(r Vector, b ConstVector) {
+      compressedGather(r, b, a.rowPtr, a.colIdx, a.values)
+    })
+  case CSCConstRealMatrix:
+    return NewLinearOperator(n, m, func(r Vector, b ConstVector) {
+      compressedScatter(r, b, a.colPtr, a.rowIdx, a.values)
+    })
+  case LowRankConstRealMatrix:
+    return NewLinearOperator(n, m, func(r Vector, b ConstVector) {
+      lowRankProduct(r, b, a.u, a.s, a.v, a.rows, a.cols, a.rank)
+    })
+  case *SparseRealMatrix, *SparseBareRealMatrix:
+    return NewLinearOperator(n, m, func(r Vector, b ConstVector) {
+      sparseMdotV(r, a, b)
+    })
+  default:
+    return NewLinearOperator(n, m, func(r Vector, b ConstVector) {
+      r.MdotV(a, b)
+    })
+  }
+}
+
+func sparseMdotV(r Vector, a ConstMatrix, b ConstVector) {
+  n, m := a.Dims()
+  if n == 0 || m == 0 {
+    return
+  }
+  if r.At(0) == b.ConstAt(0) {
+    panic("result and argument must be different vectors")
+  }
+  t := NullScalar(r.ElementType())
+  for i := 0; i < n; i++ {
+    r.At(i).Reset()
+  }
+  for it := a.ConstIterator(); it.Ok(); it.Next() {
+    i, j := it.Index()
+    // skip elements outside of slices
+    if i < 0 || i >= n || j < 0 || j >= m {
+      continue
+    }
+    t.Mul(it.GetConst(), b.ConstAt(j))
+    r.At(i).Add(r.At(i), t)
+  }
+}
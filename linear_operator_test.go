@@ -0,0 +1,75 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestLinearOperator1(t *testing.T) {
+  a := NewMatrix(RealType, 3, 2, []float64{
+    1, 2,
+    0, 3,
+    4, 0 })
+  s := NewSparseRealMatrix(3, 2, []int{0, 0, 1, 2}, []int{0, 1, 1, 0}, []float64{1, 2, 3, 4})
+  f := NewLinearOperator(3, 2, func(r Vector, b ConstVector) {
+    r.MdotV(a, b)
+  })
+  b := NewVector(RealType, []float64{1, -1})
+  r := NewVector(RealType, []float64{-1, -3, 4})
+
+  for _, op := range []LinearOperator{AsLinearOperator(a), AsLinearOperator(a.T().T()), AsLinearOperator(s), f} {
+    x := NullVector(RealType, 3)
+    if n, m := op.Dims(); n != 3 || m != 2 {
+      t.Error("test failed")
+    }
+    if !op.MdotV(x, b).Equals(r, 1e-12) {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestLinearOperator2(t *testing.T) {
+  s := NewSparseRealMatrix(3, 2, []int{0, 2}, []int{1, 0}, []float64{2, 4})
+  b := NewVector(RealType, []float64{1, -1})
+  r := NewVector(RealType, []float64{-2, 0, 4})
+  x := NewVector(RealType, []float64{7, 7, 7})
+
+  if !AsLinearOperator(s).MdotV(x, b).Equals(r, 1e-12) {
+    t.Error("test failed")
+  }
+  // transposed sparse matrix
+  r = NewVector(RealType, []float64{4, 0})
+  x = NullVector(RealType, 2)
+  if !AsLinearOperator(s.T()).MdotV(x, NewVector(RealType, []float64{0, -1, 1})).Equals(r, 1e-12) {
+    t.Error("test failed")
+  }
+}
+
+func TestLinearOperator3(t *testing.T) {
+  s := NewSparseRealMatrix(3, 3, []int{0, 1, 2, 2}, []int{0, 2, 0, 1}, []float64{1, 2, 3, 4})
+  b := NewVector(RealType, []float64{1, 2})
+  r := NewVector(RealType, []float64{0, 11})
+  x := NullVector(RealType, 2)
+  // slice of a sparse matrix
+  if !AsLinearOperator(s.Slice(1, 3, 0, 2)).MdotV(x, b).Equals(r, 1e-12) {
+    t.Error("test failed")
+  }
+}
@@ -154,6 +154,24 @@ func NewMatrix(t ScalarType, rows, cols int, values []float64) Matrix {
     return NewDenseRealMatrix(rows, cols, values)
   case BareRealType:
     return NewDenseBareRealMatrix(rows, cols, values)
+  case ReverseRealType:
+    return NewDenseReverseRealMatrix(rows, cols, values)
+  case SparseRealType:
+    return NewDenseSparseRealMatrix(rows, cols, values)
+  case DualRealType:
+    return NewDenseDualRealMatrix(rows, cols, values)
+  case TaylorRealType:
+    return NewDenseTaylorRealMatrix(rows, cols, values)
+  case ComplexType:
+    return NewDenseComplexMatrix(rows, cols, values)
+  case BareReal32Type:
+    return NewDenseBareReal32Matrix(rows, cols, values)
+  case IntervalType:
+    return NewDenseIntervalMatrix(rows, cols, values)
+  case BigRealType:
+    return NewDenseBigRealMatrix(rows, cols, values)
+  case BatchRealType:
+    return NewDenseBatchRealMatrix(rows, cols, values)
   default:
     panic("unknown type")
   }
@@ -165,6 +183,24 @@ func NullMatrix(t ScalarType, rows, cols int) Matrix {
     return NullDenseRealMatrix(rows, cols)
   case BareRealType:
     return NullDenseBareRealMatrix(rows, cols)
+  case ReverseRealType:
+    return NullDenseReverseRealMatrix(rows, cols)
+  case SparseRealType:
+    return NullDenseSparseRealMatrix(rows, cols)
+  case DualRealType:
+    return NullDenseDualRealMatrix(rows, cols)
+  case TaylorRealType:
+    return NullDenseTaylorRealMatrix(rows, cols)
+  case ComplexType:
+    return NullDenseComplexMatrix(rows, cols)
+  case BareReal32Type:
+    return NullDenseBareReal32Matrix(rows, cols)
+  case IntervalType:
+    return NullDenseIntervalMatrix(rows, cols)
+  case BigRealType:
+    return NullDenseBigRealMatrix(rows, cols)
+  case BatchRealType:
+    return NullDenseBatchRealMatrix(rows, cols)
   default:
     panic("unknown type")
   }
@@ -176,6 +212,24 @@ func AsMatrix(t ScalarType, m Matrix) Matrix {
     return AsDenseRealMatrix(m)
   case BareRealType:
     return AsDenseBareRealMatrix(m)
+  case ReverseRealType:
+    return AsDenseReverseRealMatrix(m)
+  case SparseRealType:
+    return AsDenseSparseRealMatrix(m)
+  case DualRealType:
+    return AsDenseDualRealMatrix(m)
+  case TaylorRealType:
+    return AsDenseTaylorRealMatrix(m)
+  case ComplexType:
+    return AsDenseComplexMatrix(m)
+  case BareReal32Type:
+    return AsDenseBareReal32Matrix(m)
+  case IntervalType:
+    return AsDenseIntervalMatrix(m)
+  case BigRealType:
+    return AsDenseBigRealMatrix(m)
+  case BatchRealType:
+    return AsDenseBatchRealMatrix(m)
   default:
     panic("unknown type")
   }
@@ -191,3 +245,24 @@ func IdentityMatrix(t ScalarType, dim int) Matrix {
   }
   return matrix
 }
+
+/* -------------------------------------------------------------------------- */
+
+// Check if a carries first order derivatives. Complex matrices and
+// matrices with second order derivatives are excluded.
+func HasFirstOrderDerivatives(a ConstMatrix) bool {
+  r := false
+  for it := a.ConstIterator(); it.Ok(); it.Next() {
+    s := it.GetConst()
+    if _, ok := s.(ConstComplexScalar); ok {
+      return false
+    }
+    if s.GetOrder() > 1 {
+      return false
+    }
+    if s.GetOrder() == 1 && s.GetN() > 0 {
+      r = true
+    }
+  }
+  return r
+}
@@ -0,0 +1,264 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "bytes"
+import "unsafe"
+
+/* -------------------------------------------------------------------------- */
+
+// Sparse matrix in compressed sparse column (CSC) format. The row indices
+// of non-zero elements in column j are stored in rowIdx[colPtr[j]:colPtr[j+1]]
+// in increasing order, the corresponding values in values[colPtr[j]:colPtr[j+1]].
+type CSCConstRealMatrix struct {
+  rows   int
+  cols   int
+  colPtr []int
+  rowIdx []int
+  values []float64
+}
+
+/* constructors
+ * -------------------------------------------------------------------------- */
+
+// Allocate a new matrix from a list of (row, column, value) triplets.
+// Values of duplicate entries are summed and zeros are dropped.
+func NewCSCConstRealMatrix(rows, cols int, rowIndices, colIndices []int, values []float64) CSCConstRealMatrix {
+  return NewCSRConstRealMatrix(cols, rows, colIndices, rowIndices, values).T()
+}
+
+// Allocate a new matrix from the given arrays without copying or checking
+// them.
+func UnsafeCSCConstRealMatrix(rows, cols int, colPtr, rowIdx []int, values []float64) CSCConstRealMatrix {
+  return CSCConstRealMatrix{rows: rows, cols: cols, colPtr: colPtr, rowIdx: rowIdx, values: values}
+}
+
+// Convert matrix type.
+func AsCSCConstRealMatrix(matrix ConstMatrix) CSCConstRealMatrix {
+  if a, ok := matrix.(CSCConstRealMatrix); ok {
+    return a
+  }
+  n, m := matrix.Dims()
+  rowIndices, colIndices, values := constMatrixTriplets(matrix)
+  return NewCSCConstRealMatrix(n, m, rowIndices, colIndices, values)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (matrix CSCConstRealMatrix) CloneConstMatrix() ConstMatrix {
+  return matrix.Clone()
+}
+
+func (matrix CSCConstRealMatrix) Clone() CSCConstRealMatrix {
+  return matrix.T().Clone().T()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (matrix CSCConstRealMatrix) storageLocation() uintptr {
+  if len(matrix.values) == 0 {
+    return 0
+  }
+  return uintptr(unsafe.Pointer(&matrix.values[0]))
+}
+
+func (matrix CSCConstRealMatrix) ElementType() ScalarType {
+  return BareRealType
+}
+
+func (matrix CSCConstRealMatrix) Dims() (int, int) {
+  return matrix.rows, matrix.cols
+}
+
+func (matrix CSCConstRealMatrix) ValueAt(i, j int) float64 {
+  if i < 0 || j < 0 || i >= matrix.rows || j >= matrix.cols {
+    panic(fmt.Errorf("index (%d,%d) out of bounds for matrix of dimension %dx%d", i, j, matrix.rows, matrix.cols))
+  }
+  if k, ok := compressedFind(matrix.colPtr, matrix.rowIdx, j, i); ok {
+    return matrix.values[k]
+  }
+  return 0.0
+}
+
+func (matrix CSCConstRealMatrix) ConstAt(i, j int) ConstScalar {
+  return ConstReal(matrix.ValueAt(i, j))
+}
+
+func (matrix CSCConstRealMatrix) ConstSlice(rfrom, rto, cfrom, cto int) ConstMatrix {
+  return matrix.T().ConstSlice(cfrom, cto, rfrom, rto).(CSRConstRealMatrix).T()
+}
+
+func (matrix CSCConstRealMatrix) ConstRow(i int) ConstVector {
+  return matrix.T().ConstCol(i)
+}
+
+func (matrix CSCConstRealMatrix) ConstCol(j int) ConstVector {
+  return matrix.T().ConstRow(j)
+}
+
+func (matrix CSCConstRealMatrix) ConstDiag() ConstVector {
+  return matrix.T().ConstDiag()
+}
+
+func (matrix CSCConstRealMatrix) GetValues() []float64 {
+  v := make([]float64, matrix.rows*matrix.cols)
+  for j := 0; j < matrix.cols; j++ {
+    for k := matrix.colPtr[j]; k < matrix.colPtr[j+1]; k++ {
+      v[matrix.rowIdx[k]*matrix.cols + j] = matrix.values[k]
+    }
+  }
+  return v
+}
+
+func (matrix CSCConstRealMatrix) AsConstVector() ConstVector {
+  return AsCSRConstRealMatrix(matrix).AsConstVector()
+}
+
+func (matrix CSCConstRealMatrix) IsSymmetric(epsilon float64) bool {
+  return matrix.T().IsSymmetric(epsilon)
+}
+
+// Transpose the matrix. The result shares its storage with the original
+// matrix.
+func (matrix CSCConstRealMatrix) T() CSRConstRealMatrix {
+  return UnsafeCSRConstRealMatrix(matrix.cols, matrix.rows, matrix.colPtr, matrix.rowIdx, matrix.values)
+}
+
+/* methods specific to this type
+ * -------------------------------------------------------------------------- */
+
+func (matrix CSCConstRealMatrix) GetSparseColumnPointers() []int {
+  return matrix.colPtr
+}
+
+func (matrix CSCConstRealMatrix) GetSparseRowIndices() []int {
+  return matrix.rowIdx
+}
+
+func (matrix CSCConstRealMatrix) GetSparseValues() []float64 {
+  return matrix.values
+}
+
+// Number of non-zero elements.
+func (matrix CSCConstRealMatrix) NNZ() int {
+  return len(matrix.values)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (m CSCConstRealMatrix) String() string {
+  var buffer bytes.Buffer
+  buffer.WriteString("[")
+  for i := 0; i < m.rows; i++ {
+    if i != 0 {
+      buffer.WriteString(",\n ")
+    }
+    buffer.WriteString("[")
+    for j := 0; j < m.cols; j++ {
+      if j != 0 {
+        buffer.WriteString(", ")
+      }
+      buffer.WriteString(m.ConstAt(i,j).String())
+    }
+    buffer.WriteString("]")
+  }
+  buffer.WriteString("]")
+  return buffer.String()
+}
+
+func (a CSCConstRealMatrix) Table() string {
+  var buffer bytes.Buffer
+  n, m := a.Dims()
+  for i := 0; i < n; i++ {
+    if i != 0 {
+      buffer.WriteString("\n")
+    }
+    for j := 0; j < m; j++ {
+      if j != 0 {
+        buffer.WriteString(" ")
+      }
+      buffer.WriteString(a.ConstAt(i,j).String())
+    }
+  }
+  return buffer.String()
+}
+
+/* implement ConstScalarContainer
+ * -------------------------------------------------------------------------- */
+
+func (matrix CSCConstRealMatrix) Reduce(f func(Scalar, ConstScalar) Scalar, r Scalar) Scalar {
+  for i := 0; i < matrix.rows; i++ {
+    for j := 0; j < matrix.cols; j++ {
+      r = f(r, matrix.ConstAt(i, j))
+    }
+  }
+  return r
+}
+
+/* math
+ * -------------------------------------------------------------------------- */
+
+func (a CSCConstRealMatrix) Equals(b ConstMatrix, epsilon float64) bool {
+  n1, m1 := a.Dims()
+  n2, m2 := b.Dims()
+  if n1 != n2 || m1 != m2 {
+    panic("MEqual(): matrix dimensions do not match!")
+  }
+  for i := 0; i < n1; i++ {
+    for j := 0; j < m1; j++ {
+      if !a.ConstAt(i, j).Equals(b.ConstAt(i, j), epsilon) {
+        return false
+      }
+    }
+  }
+  return true
+}
+
+/* iterator methods
+ * -------------------------------------------------------------------------- */
+
+func (m CSCConstRealMatrix) ConstIterator() MatrixConstIterator {
+  return m.ITERATOR()
+}
+
+func (m CSCConstRealMatrix) ITERATOR() *CSCConstRealMatrixIterator {
+  return &CSCConstRealMatrixIterator{*m.T().ITERATOR()}
+}
+
+/* const iterator
+ * -------------------------------------------------------------------------- */
+
+// Iterator over all non-zero elements of a CSC matrix.
+type CSCConstRealMatrixIterator struct {
+  CSRConstRealMatrixIterator
+}
+
+func (obj *CSCConstRealMatrixIterator) Index() (int, int) {
+  j, i := obj.CSRConstRealMatrixIterator.Index()
+  return i, j
+}
+
+func (obj *CSCConstRealMatrixIterator) Clone() *CSCConstRealMatrixIterator {
+  return &CSCConstRealMatrixIterator{*obj.CSRConstRealMatrixIterator.Clone()}
+}
+
+func (obj *CSCConstRealMatrixIterator) CloneConstIterator() MatrixConstIterator {
+  return obj.Clone()
+}
@@ -0,0 +1,513 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "bytes"
+import "sort"
+import "unsafe"
+
+/* -------------------------------------------------------------------------- */
+
+// Sparse matrix in compressed sparse row (CSR) format. The column indices
+// of non-zero elements in row i are stored in colIdx[rowPtr[i]:rowPtr[i+1]]
+// in increasing order, the corresponding values in values[rowPtr[i]:rowPtr[i+1]].
+// In contrast to SparseRealMatrix the sparsity pattern is fixed, which allows
+// fast matrix vector products.
+type CSRConstRealMatrix struct {
+  rows   int
+  cols   int
+  rowPtr []int
+  colIdx []int
+  values []float64
+}
+
+/* constructors
+ * -------------------------------------------------------------------------- */
+
+// Allocate a new matrix from a list of (row, column, value) triplets.
+// Values of duplicate entries are summed and zeros are dropped.
+func NewCSRConstRealMatrix(rows, cols int, rowIndices, colIndices []int, values []float64) CSRConstRealMatrix {
+  if len(rowIndices) != len(values) || len(colIndices) != len(values) {
+    panic("number of indices does not match number of values")
+  }
+  for k := 0; k < len(values); k++ {
+    if rowIndices[k] < 0 || rowIndices[k] >= rows || colIndices[k] < 0 || colIndices[k] >= cols {
+      panic(fmt.Errorf("index (%d,%d) out of bounds for matrix of dimension %dx%d", rowIndices[k], colIndices[k], rows, cols))
+    }
+  }
+  ptr, idx, val := compressTriplets(rows, rowIndices, colIndices, values)
+  return CSRConstRealMatrix{rows: rows, cols: cols, rowPtr: ptr, colIdx: idx, values: val}
+}
+
+// Allocate a new matrix from the given arrays without copying or checking
+// them.
+func UnsafeCSRConstRealMatrix(rows, cols int, rowPtr, colIdx []int, values []float64) CSRConstRealMatrix {
+  return CSRConstRealMatrix{rows: rows, cols: cols, rowPtr: rowPtr, colIdx: colIdx, values: values}
+}
+
+// Convert matrix type.
+func AsCSRConstRealMatrix(matrix ConstMatrix) CSRConstRealMatrix {
+  if a, ok := matrix.(CSRConstRealMatrix); ok {
+    return a
+  }
+  n, m := matrix.Dims()
+  rowIndices, colIndices, values := constMatrixTriplets(matrix)
+  return NewCSRConstRealMatrix(n, m, rowIndices, colIndices, values)
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Sort triplets by major index and then by minor index, sum duplicates and
+// drop zeros. Returns the compressed pointer, index and value arrays.
+func compressTriplets(n int, major, minor []int, values []float64) ([]int, []int, []float64) {
+  ptr := make([]int, n+1)
+  for _, i := range major {
+    ptr[i+1]++
+  }
+  for i := 0; i < n; i++ {
+    ptr[i+1] += ptr[i]
+  }
+  // counting sort by major index
+  pos := make([]int, n)
+  copy(pos, ptr[0:n])
+  idx := make([]int, len(values))
+  val := make([]float64, len(values))
+  for k, i := range major {
+    idx[pos[i]] = minor[k]
+    val[pos[i]] = values[k]
+    pos[i]++
+  }
+  // sort by minor index, sum duplicates and drop zeros
+  nnz := 0
+  for i := 0; i < n; i++ {
+    from, to := ptr[i], ptr[i+1]
+    sort.Sort(sortIntFloat{idx[from:to], val[from:to]})
+    ptr[i] = nnz
+    for k := from; k < to; k++ {
+      if nnz > ptr[i] && idx[nnz-1] == idx[k] {
+        val[nnz-1] += val[k]
+      } else {
+        idx[nnz] = idx[k]
+        val[nnz] = val[k]
+        nnz++
+      }
+    }
+    // drop zeros
+    j := ptr[i]
+    for k := ptr[i]; k < nnz; k++ {
+      if val[k] != 0.0 {
+        idx[j] = idx[k]
+        val[j] = val[k]
+        j++
+      }
+    }
+    nnz = j
+  }
+  ptr[n] = nnz
+  return ptr, idx[0:nnz], val[0:nnz]
+}
+
+// Extract all non-zero elements of a matrix as (row, column, value)
+// triplets.
+func constMatrixTriplets(matrix ConstMatrix) ([]int, []int, []float64) {
+  n, m := matrix.Dims()
+  rowIndices := []int{}
+  colIndices := []int{}
+  values     := []float64{}
+  switch matrix.(type) {
+  case *SparseRealMatrix, *SparseBareRealMatrix, CSRConstRealMatrix, CSCConstRealMatrix:
+    // visit only non-zero elements
+    for it := matrix.ConstIterator(); it.Ok(); it.Next() {
+      i, j := it.Index()
+      // skip elements outside of slices
+      if i < 0 || i >= n || j < 0 || j >= m {
+        continue
+      }
+      if v := it.GetValue(); v != 0.0 {
+        rowIndices = append(rowIndices, i)
+        colIndices = append(colIndices, j)
+        values     = append(values, v)
+      }
+    }
+  default:
+    for i := 0; i < n; i++ {
+      for j := 0; j < m; j++ {
+        if v := matrix.ValueAt(i, j); v != 0.0 {
+          rowIndices = append(rowIndices, i)
+          colIndices = append(colIndices, j)
+          values     = append(values, v)
+        }
+      }
+    }
+  }
+  return rowIndices, colIndices, values
+}
+
+// Compute r_i = sum_k values[k] b[idx[k]] for k in ptr[i]:ptr[i+1].
+func compressedGather(r Vector, b ConstVector, ptr, idx []int, values []float64) {
+  if r.Dim() > 0 && b.Dim() > 0 && r.At(0) == b.ConstAt(0) {
+    panic("result and argument must be different vectors")
+  }
+  if r_, ok := r.(DenseBareRealVector); ok {
+    for i := 0; i < r_.Dim(); i++ {
+      s := 0.0
+      for k := ptr[i]; k < ptr[i+1]; k++ {
+        s += values[k]*b.ValueAt(idx[k])
+      }
+      r_[i] = BareReal(s)
+    }
+    return
+  }
+  s := NullScalar(r.ElementType())
+  t := NullScalar(r.ElementType())
+  for i := 0; i < r.Dim(); i++ {
+    s.Reset()
+    for k := ptr[i]; k < ptr[i+1]; k++ {
+      t.Mul(ConstReal(values[k]), b.ConstAt(idx[k]))
+      s.Add(s, t)
+    }
+    r.At(i).Set(s)
+  }
+}
+
+// Compute r_{idx[k]} = sum_i values[k] b_i for k in ptr[i]:ptr[i+1].
+func compressedScatter(r Vector, b ConstVector, ptr, idx []int, values []float64) {
+  if r.Dim() > 0 && b.Dim() > 0 && r.At(0) == b.ConstAt(0) {
+    panic("result and argument must be different vectors")
+  }
+  if r_, ok := r.(DenseBareRealVector); ok {
+    for j := 0; j < r_.Dim(); j++ {
+      r_[j] = 0.0
+    }
+    for i := 0; i < b.Dim(); i++ {
+      bi := b.ValueAt(i)
+      for k := ptr[i]; k < ptr[i+1]; k++ {
+        r_[idx[k]] += BareReal(values[k]*bi)
+      }
+    }
+    return
+  }
+  t := NullScalar(r.ElementType())
+  for j := 0; j < r.Dim(); j++ {
+    r.At(j).Reset()
+  }
+  for i := 0; i < b.Dim(); i++ {
+    bi := b.ConstAt(i)
+    for k := ptr[i]; k < ptr[i+1]; k++ {
+      t.Mul(ConstReal(values[k]), bi)
+      r.At(idx[k]).Add(r.At(idx[k]), t)
+    }
+  }
+}
+
+// Find the position of minor index j in idx[ptr[i]:ptr[i+1]].
+func compressedFind(ptr, idx []int, i, j int) (int, bool) {
+  from, to := ptr[i], ptr[i+1]
+  k := from + sort.SearchInts(idx[from:to], j)
+  if k < to && idx[k] == j {
+    return k, true
+  }
+  return k, false
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (matrix CSRConstRealMatrix) CloneConstMatrix() ConstMatrix {
+  return matrix.Clone()
+}
+
+func (matrix CSRConstRealMatrix) Clone() CSRConstRealMatrix {
+  r := matrix
+  r.rowPtr = make([]int, len(matrix.rowPtr))
+  r.colIdx = make([]int, len(matrix.colIdx))
+  r.values = make([]float64, len(matrix.values))
+  copy(r.rowPtr, matrix.rowPtr)
+  copy(r.colIdx, matrix.colIdx)
+  copy(r.values, matrix.values)
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (matrix CSRConstRealMatrix) storageLocation() uintptr {
+  if len(matrix.values) == 0 {
+    return 0
+  }
+  return uintptr(unsafe.Pointer(&matrix.values[0]))
+}
+
+func (matrix CSRConstRealMatrix) ElementType() ScalarType {
+  return BareRealType
+}
+
+func (matrix CSRConstRealMatrix) Dims() (int, int) {
+  return matrix.rows, matrix.cols
+}
+
+func (matrix CSRConstRealMatrix) ValueAt(i, j int) float64 {
+  if i < 0 || j < 0 || i >= matrix.rows || j >= matrix.cols {
+    panic(fmt.Errorf("index (%d,%d) out of bounds for matrix of dimension %dx%d", i, j, matrix.rows, matrix.cols))
+  }
+  if k, ok := compressedFind(matrix.rowPtr, matrix.colIdx, i, j); ok {
+    return matrix.values[k]
+  }
+  return 0.0
+}
+
+func (matrix CSRConstRealMatrix) ConstAt(i, j int) ConstScalar {
+  return ConstReal(matrix.ValueAt(i, j))
+}
+
+func (matrix CSRConstRealMatrix) ConstSlice(rfrom, rto, cfrom, cto int) ConstMatrix {
+  rowIndices := []int{}
+  colIndices := []int{}
+  values     := []float64{}
+  for i := rfrom; i < rto; i++ {
+    k, _ := compressedFind(matrix.rowPtr, matrix.colIdx, i, cfrom)
+    for ; k < matrix.rowPtr[i+1] && matrix.colIdx[k] < cto; k++ {
+      rowIndices = append(rowIndices, i-rfrom)
+      colIndices = append(colIndices, matrix.colIdx[k]-cfrom)
+      values     = append(values, matrix.values[k])
+    }
+  }
+  return NewCSRConstRealMatrix(rto-rfrom, cto-cfrom, rowIndices, colIndices, values)
+}
+
+func (matrix CSRConstRealMatrix) ConstRow(i int) ConstVector {
+  from, to := matrix.rowPtr[i], matrix.rowPtr[i+1]
+  return UnsafeSparseConstRealVector(matrix.colIdx[from:to], matrix.values[from:to], matrix.cols)
+}
+
+func (matrix CSRConstRealMatrix) ConstCol(j int) ConstVector {
+  indices := []int{}
+  values  := []float64{}
+  for i := 0; i < matrix.rows; i++ {
+    if k, ok := compressedFind(matrix.rowPtr, matrix.colIdx, i, j); ok {
+      indices = append(indices, i)
+      values  = append(values, matrix.values[k])
+    }
+  }
+  return UnsafeSparseConstRealVector(indices, values, matrix.rows)
+}
+
+func (matrix CSRConstRealMatrix) ConstDiag() ConstVector {
+  n := matrix.rows
+  if matrix.cols < n {
+    n = matrix.cols
+  }
+  v := make([]float64, n)
+  for i := 0; i < n; i++ {
+    v[i] = matrix.ValueAt(i, i)
+  }
+  return DenseConstRealVector(v)
+}
+
+func (matrix CSRConstRealMatrix) GetValues() []float64 {
+  v := make([]float64, matrix.rows*matrix.cols)
+  for i := 0; i < matrix.rows; i++ {
+    for k := matrix.rowPtr[i]; k < matrix.rowPtr[i+1]; k++ {
+      v[i*matrix.cols + matrix.colIdx[k]] = matrix.values[k]
+    }
+  }
+  return v
+}
+
+func (matrix CSRConstRealMatrix) AsConstVector() ConstVector {
+  indices := make([]int, len(matrix.values))
+  for i := 0; i < matrix.rows; i++ {
+    for k := matrix.rowPtr[i]; k < matrix.rowPtr[i+1]; k++ {
+      indices[k] = i*matrix.cols + matrix.colIdx[k]
+    }
+  }
+  return UnsafeSparseConstRealVector(indices, matrix.values, matrix.rows*matrix.cols)
+}
+
+func (matrix CSRConstRealMatrix) IsSymmetric(epsilon float64) bool {
+  if matrix.rows != matrix.cols {
+    return false
+  }
+  for i := 0; i < matrix.rows; i++ {
+    for k := matrix.rowPtr[i]; k < matrix.rowPtr[i+1]; k++ {
+      if !ConstReal(matrix.values[k]).Equals(ConstReal(matrix.ValueAt(matrix.colIdx[k], i)), epsilon) {
+        return false
+      }
+    }
+  }
+  return true
+}
+
+// Transpose the matrix. The result shares its storage with the original
+// matrix.
+func (matrix CSRConstRealMatrix) T() CSCConstRealMatrix {
+  return UnsafeCSCConstRealMatrix(matrix.cols, matrix.rows, matrix.rowPtr, matrix.colIdx, matrix.values)
+}
+
+/* methods specific to this type
+ * -------------------------------------------------------------------------- */
+
+func (matrix CSRConstRealMatrix) GetSparseRowPointers() []int {
+  return matrix.rowPtr
+}
+
+func (matrix CSRConstRealMatrix) GetSparseColumnIndices() []int {
+  return matrix.colIdx
+}
+
+func (matrix CSRConstRealMatrix) GetSparseValues() []float64 {
+  return matrix.values
+}
+
+// Number of non-zero elements.
+func (matrix CSRConstRealMatrix) NNZ() int {
+  return len(matrix.values)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (m CSRConstRealMatrix) String() string {
+  var buffer bytes.Buffer
+  buffer.WriteString("[")
+  for i := 0; i < m.rows; i++ {
+    if i != 0 {
+      buffer.WriteString(",\n ")
+    }
+    buffer.WriteString("[")
+    for j := 0; j < m.cols; j++ {
+      if j != 0 {
+        buffer.WriteString(", ")
+      }
+      buffer.WriteString(m.ConstAt(i,j).String())
+    }
+    buffer.WriteString("]")
+  }
+  buffer.WriteString("]")
+  return buffer.String()
+}
+
+func (a CSRConstRealMatrix) Table() string {
+  var buffer bytes.Buffer
+  n, m := a.Dims()
+  for i := 0; i < n; i++ {
+    if i != 0 {
+      buffer.WriteString("\n")
+    }
+    for j := 0; j < m; j++ {
+      if j != 0 {
+        buffer.WriteString(" ")
+      }
+      buffer.WriteString(a.ConstAt(i,j).String())
+    }
+  }
+  return buffer.String()
+}
+
+/* implement ConstScalarContainer
+ * -------------------------------------------------------------------------- */
+
+func (matrix CSRConstRealMatrix) Reduce(f func(Scalar, ConstScalar) Scalar, r Scalar) Scalar {
+  for i := 0; i < matrix.rows; i++ {
+    k := matrix.rowPtr[i]
+    for j := 0; j < matrix.cols; j++ {
+      if k < matrix.rowPtr[i+1] && matrix.colIdx[k] == j {
+        r = f(r, ConstReal(matrix.values[k])); k++
+      } else {
+        r = f(r, ConstReal(0.0))
+      }
+    }
+  }
+  return r
+}
+
+/* math
+ * -------------------------------------------------------------------------- */
+
+func (a CSRConstRealMatrix) Equals(b ConstMatrix, epsilon float64) bool {
+  n1, m1 := a.Dims()
+  n2, m2 := b.Dims()
+  if n1 != n2 || m1 != m2 {
+    panic("MEqual(): matrix dimensions do not match!")
+  }
+  for i := 0; i < n1; i++ {
+    for j := 0; j < m1; j++ {
+      if !a.ConstAt(i, j).Equals(b.ConstAt(i, j), epsilon) {
+        return false
+      }
+    }
+  }
+  return true
+}
+
+/* iterator methods
+ * -------------------------------------------------------------------------- */
+
+func (m CSRConstRealMatrix) ConstIterator() MatrixConstIterator {
+  return m.ITERATOR()
+}
+
+func (m CSRConstRealMatrix) ITERATOR() *CSRConstRealMatrixIterator {
+  r := CSRConstRealMatrixIterator{m, 0, -1}
+  r.Next()
+  return &r
+}
+
+/* const iterator
+ * -------------------------------------------------------------------------- */
+
+// Iterator over all non-zero elements of a CSR matrix.
+type CSRConstRealMatrixIterator struct {
+  m CSRConstRealMatrix
+  i int
+  k int
+}
+
+func (obj *CSRConstRealMatrixIterator) GetConst() ConstScalar {
+  return ConstReal(obj.m.values[obj.k])
+}
+
+func (obj *CSRConstRealMatrixIterator) GetValue() float64 {
+  return obj.m.values[obj.k]
+}
+
+func (obj *CSRConstRealMatrixIterator) GET() ConstReal {
+  return ConstReal(obj.m.values[obj.k])
+}
+
+func (obj *CSRConstRealMatrixIterator) Ok() bool {
+  return obj.k < len(obj.m.values)
+}
+
+func (obj *CSRConstRealMatrixIterator) Next() {
+  obj.k++
+  for obj.i < obj.m.rows && obj.k >= obj.m.rowPtr[obj.i+1] {
+    obj.i++
+  }
+}
+
+func (obj *CSRConstRealMatrixIterator) Index() (int, int) {
+  return obj.i, obj.m.colIdx[obj.k]
+}
+
+func (obj *CSRConstRealMatrixIterator) Clone() *CSRConstRealMatrixIterator {
+  return &CSRConstRealMatrixIterator{obj.m, obj.i, obj.k}
+}
+
+func (obj *CSRConstRealMatrixIterator) CloneConstIterator() MatrixConstIterator {
+  return &CSRConstRealMatrixIterator{obj.m, obj.i, obj.k}
+}
@@ -0,0 +1,139 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestCSRMatrix1(t *testing.T) {
+  a := NewMatrix(RealType, 3, 4, []float64{
+    1, 0, 2, 0,
+    0, 0, 0, 0,
+    3, 4, 0, 5 })
+  // duplicate entries are summed and zeros are dropped
+  r := NewCSRConstRealMatrix(3, 4,
+    []int{2, 0, 2, 0, 2, 1, 2},
+    []int{3, 2, 0, 0, 1, 1, 3},
+    []float64{2, 2, 3, 1, 4, 0, 3})
+  c := NewCSCConstRealMatrix(3, 4,
+    []int{2, 0, 2, 0, 2, 1},
+    []int{3, 2, 0, 0, 1, 1},
+    []float64{5, 2, 3, 1, 4, 0})
+
+  for _, m := range []ConstMatrix{r, c, AsCSRConstRealMatrix(a), AsCSCConstRealMatrix(a), AsCSRConstRealMatrix(AsSparseRealMatrix(a)), AsCSRConstRealMatrix(c), AsCSCConstRealMatrix(r)} {
+    if !m.Equals(a, 1e-12) {
+      t.Error("test failed")
+    }
+    if !a.Equals(m, 1e-12) {
+      t.Error("test failed")
+    }
+    // iterator visits all non-zero elements
+    n := 0
+    for it := m.ConstIterator(); it.Ok(); it.Next() {
+      i, j := it.Index()
+      if it.GetValue() != a.ValueAt(i, j) {
+        t.Error("test failed")
+      }
+      n++
+    }
+    if n != 5 {
+      t.Error("test failed")
+    }
+    if !NewDenseConstRealMatrix(3, 4, m.GetValues()).Equals(a, 1e-12) {
+      t.Error("test failed")
+    }
+    if !m.ConstSlice(1, 3, 1, 4).Equals(a.ConstSlice(1, 3, 1, 4), 1e-12) {
+      t.Error("test failed")
+    }
+    if !m.ConstRow(2).Equals(a.ConstRow(2), 1e-12) || !m.ConstCol(0).Equals(a.ConstCol(0), 1e-12) {
+      t.Error("test failed")
+    }
+  }
+  if r.NNZ() != 5 || c.NNZ() != 5 {
+    t.Error("test failed")
+  }
+  if !r.T().Equals(a.T(), 1e-12) || !c.T().Equals(a.T(), 1e-12) {
+    t.Error("test failed")
+  }
+}
+
+func TestCSRMatrix2(t *testing.T) {
+  a := NewMatrix(RealType, 3, 4, []float64{
+    1, 0, 2, 0,
+    0, 0, 0, 0,
+    3, 4, 0, 5 })
+  r := AsCSRConstRealMatrix(a)
+  c := AsCSCConstRealMatrix(a)
+  x := NewVector(RealType, []float64{1, 2, 3, 4})
+  y := NewVector(RealType, []float64{1, 2, 3})
+  x.Variables(1)
+
+  z1 := NullVector(RealType, 3)
+  z1.MdotV(a, x)
+  z2 := NullVector(RealType, 4)
+  z2.VdotM(y, a)
+
+  for _, op := range []LinearOperator{
+    AsLinearOperator(r),
+    AsLinearOperator(c),
+    AsLinearOperator(c.T().T()),
+    AsLinearOperator(r.T().T()) } {
+    z := NullVector(RealType, 3)
+    op.MdotV(z, x)
+    if !z.Equals(z1, 1e-12) {
+      t.Error("test failed")
+    }
+    // derivatives are propagated
+    for i := 0; i < 3; i++ {
+      for j := 0; j < 4; j++ {
+        if z.At(i).GetDerivative(j) != a.ValueAt(i, j) {
+          t.Error("test failed")
+        }
+      }
+    }
+    // BareReal result
+    if !op.MdotV(NullVector(BareRealType, 3), x).Equals(z1, 1e-12) {
+      t.Error("test failed")
+    }
+  }
+  // matrix vector products visit only non-zero elements
+  for _, z := range []Vector{
+    NullVector(RealType, 3).MdotV(r, x),
+    NullVector(RealType, 3).MdotV(c, x),
+    NullDenseBareRealVector(3).MdotV(r, x),
+    NullSparseRealVector(3).MdotV(c, x) } {
+    if !z.Equals(z1, 1e-12) {
+      t.Error("test failed")
+    }
+  }
+  // transpose products
+  for _, z := range []Vector{
+    NullVector(RealType, 4).VdotM(y, r),
+    NullVector(RealType, 4).VdotM(y, c),
+    NullSparseRealVector(4).VdotM(y, r),
+    NullDenseBareRealVector(4).VdotM(y, c),
+    AsLinearOperator(r.T()).MdotV(NullVector(RealType, 4), y),
+    AsLinearOperator(c.T()).MdotV(NullVector(BareRealType, 4), y) } {
+    if !z.Equals(z2, 1e-12) {
+      t.Error("test failed")
+    }
+  }
+}
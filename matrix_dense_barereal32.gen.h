@@ -0,0 +1,10 @@
+
+//#define STORE_PTR 1
+
+#define SCALAR_NAME BareReal32
+#define MATRIX_NAME DenseBareReal32Matrix
+#define VECTOR_NAME DenseBareReal32Vector
+
+#define SCALAR_TYPE *SCALAR_NAME
+#define MATRIX_TYPE *MATRIX_NAME
+#define VECTOR_TYPE  VECTOR_NAME
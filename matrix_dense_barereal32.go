@@ -0,0 +1,624 @@
+//#define STORE_PTR 1
+/* -*- mode: go; -*-
+ *
+ * Copyright (C) 2015-2017 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+/* -------------------------------------------------------------------------- */
+package autodiff
+/* -------------------------------------------------------------------------- */
+import "bytes"
+import "bufio"
+import "compress/gzip"
+import "encoding/json"
+import "fmt"
+import "strconv"
+import "strings"
+import "os"
+import "unsafe"
+/* -------------------------------------------------------------------------- */
+/* matrix type declaration
+ * -------------------------------------------------------------------------- */
+type DenseBareReal32Matrix struct {
+  values DenseBareReal32Vector
+  rows int
+  cols int
+  rowOffset int
+  rowMax int
+  colOffset int
+  colMax int
+  transposed bool
+  tmp1 DenseBareReal32Vector
+  tmp2 DenseBareReal32Vector
+}
+/* constructors
+ * -------------------------------------------------------------------------- */
+func NewDenseBareReal32Matrix(rows, cols int, values []float64) *DenseBareReal32Matrix {
+  m := nilDenseBareReal32Matrix(rows, cols)
+  v := m.values
+  if len(values) == 1 {
+    for i := 0; i < rows*cols; i++ {
+      v[i] = *NewBareReal32(values[0])
+    }
+  } else if len(values) == rows*cols {
+    for i := 0; i < rows*cols; i++ {
+      v[i] = *NewBareReal32(values[i])
+    }
+  } else {
+    panic("NewMatrix(): Matrix dimension does not fit input values!")
+  }
+  m.initTmp()
+  return m
+}
+func NullDenseBareReal32Matrix(rows, cols int) *DenseBareReal32Matrix {
+  m := DenseBareReal32Matrix{}
+  m.values = NullDenseBareReal32Vector(rows*cols)
+  m.rows = rows
+  m.cols = cols
+  m.rowOffset = 0
+  m.rowMax = rows
+  m.colOffset = 0
+  m.colMax = cols
+  m.initTmp()
+  return &m
+}
+func nilDenseBareReal32Matrix(rows, cols int) *DenseBareReal32Matrix {
+  m := DenseBareReal32Matrix{}
+  m.values = nilDenseBareReal32Vector(rows*cols)
+  m.rows = rows
+  m.cols = cols
+  m.rowOffset = 0
+  m.rowMax = rows
+  m.colOffset = 0
+  m.colMax = cols
+  return &m
+}
+func AsDenseBareReal32Matrix(matrix ConstMatrix) *DenseBareReal32Matrix {
+  switch matrix_ := matrix.(type) {
+  case *DenseBareReal32Matrix:
+    return matrix_.Clone()
+  }
+  n, m := matrix.Dims()
+  r := NullDenseBareReal32Matrix(n, m)
+  for i := 0; i < n; i++ {
+    for j := 0; j < m; j++ {
+      r.AT(i,j).Set(matrix.ConstAt(i,j))
+    }
+  }
+  return r
+}
+func (matrix *DenseBareReal32Matrix) initTmp() {
+  if len(matrix.tmp1) < matrix.rows {
+    matrix.tmp1 = NullDenseBareReal32Vector(matrix.rows)
+  } else {
+    matrix.tmp1 = matrix.tmp1[0:matrix.rows]
+  }
+  if len(matrix.tmp2) < matrix.cols {
+    matrix.tmp2 = NullDenseBareReal32Vector(matrix.cols)
+  } else {
+    matrix.tmp2 = matrix.tmp2[0:matrix.cols]
+  }
+}
+/* cloning
+ * -------------------------------------------------------------------------- */
+// Clone matrix including data.
+func (matrix *DenseBareReal32Matrix) Clone() *DenseBareReal32Matrix {
+  return &DenseBareReal32Matrix{
+    values : matrix.values.Clone(),
+    rows : matrix.rows,
+    cols : matrix.cols,
+    transposed: matrix.transposed,
+    rowOffset : matrix.rowOffset,
+    rowMax : matrix.rowMax,
+    colOffset : matrix.colOffset,
+    colMax : matrix.colMax,
+    tmp1 : matrix.tmp1.Clone(),
+    tmp2 : matrix.tmp2.Clone() }
+}
+func (matrix *DenseBareReal32Matrix) CloneMatrix() Matrix {
+  return matrix.Clone()
+}
+func (matrix *DenseBareReal32Matrix) CloneConstMatrix() ConstMatrix {
+  return matrix.Clone()
+}
+/* field access
+ * -------------------------------------------------------------------------- */
+func (matrix *DenseBareReal32Matrix) index(i, j int) int {
+  if i < 0 || j < 0 || i >= matrix.rows || j >= matrix.cols {
+    panic(fmt.Errorf("index (%d,%d) out of bounds for matrix of dimension %dx%d", i, j, matrix.rows, matrix.cols))
+  }
+  if matrix.transposed {
+    return (matrix.colOffset + j)*matrix.rowMax + (matrix.rowOffset + i)
+  } else {
+    return (matrix.rowOffset + i)*matrix.colMax + (matrix.colOffset + j)
+  }
+}
+func (matrix *DenseBareReal32Matrix) ij(k int) (int, int) {
+  if matrix.transposed {
+    i := (k%matrix.rowMax) - matrix.rowOffset
+    j := (k/matrix.rowMax) - matrix.colOffset
+    return i, j
+  } else {
+    i := (k/matrix.colMax) - matrix.rowOffset
+    j := (k%matrix.colMax) - matrix.colOffset
+    return i, j
+  }
+}
+func (matrix *DenseBareReal32Matrix) Dims() (int, int) {
+  if matrix == nil {
+    return 0, 0
+  } else {
+    return matrix.rows, matrix.cols
+  }
+}
+func (matrix *DenseBareReal32Matrix) Row(i int) Vector {
+  return matrix.ROW(i)
+}
+func (matrix *DenseBareReal32Matrix) ROW(i int) DenseBareReal32Vector {
+  var v DenseBareReal32Vector
+  if matrix.transposed {
+    v = nilDenseBareReal32Vector(matrix.cols)
+    for j := 0; j < matrix.cols; j++ {
+      v[j] = matrix.values[matrix.index(i, j)]
+    }
+  } else {
+    i = matrix.index(i, 0)
+    v = matrix.values[i:i + matrix.cols]
+  }
+  return v
+}
+func (matrix *DenseBareReal32Matrix) Col(j int) Vector {
+  return matrix.COL(j)
+}
+func (matrix *DenseBareReal32Matrix) COL(j int) DenseBareReal32Vector {
+  var v DenseBareReal32Vector
+  if matrix.transposed {
+    j = matrix.index(0, j)
+    v = matrix.values[j:j + matrix.rows]
+  } else {
+    v = nilDenseBareReal32Vector(matrix.rows)
+    for i := 0; i < matrix.rows; i++ {
+      v[i] = matrix.values[matrix.index(i, j)]
+    }
+  }
+  return v
+}
+func (matrix *DenseBareReal32Matrix) Diag() Vector {
+  return matrix.DIAG()
+}
+func (matrix *DenseBareReal32Matrix) DIAG() DenseBareReal32Vector {
+  n, m := matrix.Dims()
+  if n != m {
+    panic("Diag(): not a square matrix!")
+  }
+  v := nilDenseBareReal32Vector(n)
+  for i := 0; i < n; i++ {
+    v[i] = matrix.values[matrix.index(i, i)]
+  }
+  return v
+}
+func (matrix *DenseBareReal32Matrix) Slice(rfrom, rto, cfrom, cto int) Matrix {
+  m := *matrix
+  m.rowOffset += rfrom
+  m.rows = rto - rfrom
+  m.colOffset += cfrom
+  m.cols = cto - cfrom
+  // crop tmp vectors
+  m.initTmp()
+  return &m
+}
+func (matrix *DenseBareReal32Matrix) Swap(i1, j1, i2, j2 int) {
+  k1 := matrix.index(i1, j1)
+  k2 := matrix.index(i2, j2)
+  matrix.values[k1], matrix.values[k2] = matrix.values[k2], matrix.values[k1]
+}
+func (matrix *DenseBareReal32Matrix) AsVector() Vector {
+  return matrix.AsDenseBareReal32Vector()
+}
+func (matrix *DenseBareReal32Matrix) AsConstVector() ConstVector {
+  return matrix.AsVector()
+}
+func (matrix *DenseBareReal32Matrix) AsDenseBareReal32Vector() DenseBareReal32Vector {
+  if matrix.cols < matrix.colMax - matrix.colOffset ||
+    (matrix.rows < matrix.rowMax - matrix.rowOffset) {
+    n, m := matrix.Dims()
+    v := nilDenseBareReal32Vector(n*m)
+    for i := 0; i < n; i++ {
+      for j := 0; j < m; j++ {
+        v[i*matrix.cols + j] = *matrix.AT(i, j)
+      }
+    }
+    return v
+  } else {
+    return DenseBareReal32Vector(matrix.values)
+  }
+}
+/* -------------------------------------------------------------------------- */
+func (matrix *DenseBareReal32Matrix) T() Matrix {
+  return &DenseBareReal32Matrix{
+    values : matrix.values,
+    rows : matrix.cols,
+    cols : matrix.rows,
+    transposed: !matrix.transposed,
+    rowOffset : matrix.colOffset,
+    rowMax : matrix.colMax,
+    colOffset : matrix.rowOffset,
+    colMax : matrix.rowMax,
+    tmp1 : matrix.tmp2,
+    tmp2 : matrix.tmp1 }
+}
+func (matrix *DenseBareReal32Matrix) Tip() {
+  mn := len(matrix.values)
+  visited := make([]bool, mn)
+  k := 0
+  for cycle := 1; cycle < mn; cycle++ {
+    if visited[cycle] {
+      continue
+    }
+    k = cycle
+    for {
+      if k != mn-1 {
+        k = matrix.rows*k % (mn-1)
+      }
+      visited[k] = true
+      // swap
+      matrix.values[k], matrix.values[cycle] = matrix.values[cycle], matrix.values[k]
+      if k == cycle {
+        break
+      }
+    }
+  }
+  matrix.rows, matrix.cols = matrix.cols, matrix.rows
+  matrix.rowOffset, matrix.colOffset = matrix.colOffset, matrix.rowOffset
+  matrix.rowMax, matrix.colMax = matrix.colMax, matrix.rowMax
+  matrix.tmp1, matrix.tmp2 = matrix.tmp2, matrix.tmp1
+}
+/* -------------------------------------------------------------------------- */
+func (matrix *DenseBareReal32Matrix) ValueAt(i, j int) float64 {
+  return matrix.values[matrix.index(i, j)].GetValue()
+}
+func (matrix *DenseBareReal32Matrix) ConstAt(i, j int) ConstScalar {
+  return matrix.values[matrix.index(i, j)]
+}
+func (matrix *DenseBareReal32Matrix) ConstSlice(rfrom, rto, cfrom, cto int) ConstMatrix {
+  return matrix.Slice(rfrom, rto, cfrom, cto)
+}
+func (matrix *DenseBareReal32Matrix) ConstRow(i int) ConstVector {
+  return matrix.ROW(i)
+}
+func (matrix *DenseBareReal32Matrix) ConstCol(i int) ConstVector {
+  return matrix.COL(i)
+}
+func (matrix *DenseBareReal32Matrix) ConstDiag() ConstVector {
+  return matrix.DIAG()
+}
+func (matrix *DenseBareReal32Matrix) GetValues() []float64 {
+  n, m := matrix.Dims()
+  s := make([]float64, n*m)
+  for i := 0; i < n; i++ {
+    for j := 0; j < m; j++ {
+      s[i*m+j] = matrix.ConstAt(i,j).GetValue()
+    }
+  }
+  return s
+}
+/* -------------------------------------------------------------------------- */
+func (matrix *DenseBareReal32Matrix) At(i, j int) Scalar {
+  return matrix.AT(i, j)
+}
+func (matrix *DenseBareReal32Matrix) AT(i, j int) *BareReal32 {
+  return &matrix.values[matrix.index(i, j)]
+}
+func (matrix *DenseBareReal32Matrix) Reset() {
+  for i := 0; i < len(matrix.values); i++ {
+    matrix.values[i].Reset()
+  }
+}
+func (matrix *DenseBareReal32Matrix) ResetDerivatives() {
+  for i := 0; i < len(matrix.values); i++ {
+    matrix.values[i].ResetDerivatives()
+  }
+}
+func (a *DenseBareReal32Matrix) Set(b ConstMatrix) {
+  n1, m1 := a.Dims()
+  n2, m2 := b.Dims()
+  if n1 != n2 || m1 != m2 {
+    panic("Copy(): Matrix dimension does not match!")
+  }
+  for i := 0; i < n1; i++ {
+    for j := 0; j < m1; j++ {
+      a.At(i, j).Set(b.ConstAt(i, j))
+    }
+  }
+}
+func (matrix *DenseBareReal32Matrix) SetIdentity() {
+  n, m := matrix.Dims()
+  c := NewScalar(matrix.ElementType(), 1.0)
+  for i := 0; i < n; i++ {
+    for j := 0; j < m; j++ {
+      if i == j {
+        matrix.At(i, j).Set(c)
+      } else {
+        matrix.At(i, j).Reset()
+      }
+    }
+  }
+}
+func (matrix *DenseBareReal32Matrix) IsSymmetric(epsilon float64) bool {
+  n, m := matrix.Dims()
+  if n != m {
+    return false
+  }
+  for i := 0; i < n; i++ {
+    for j := i+1; j < m; j++ {
+      if !matrix.At(i,j).Equals(matrix.At(j,i), 1e-12) {
+        return false
+      }
+    }
+  }
+  return true
+}
+func (matrix *DenseBareReal32Matrix) storageLocation() uintptr {
+  return uintptr(unsafe.Pointer(&matrix.values[0]))
+}
+/* implement ScalarContainer
+ * -------------------------------------------------------------------------- */
+func (matrix *DenseBareReal32Matrix) Map(f func(Scalar)) {
+  n, m := matrix.Dims()
+  for i := 0; i < n; i++ {
+    for j := 0; j < m; j++ {
+      f(matrix.At(i, j))
+    }
+  }
+}
+func (matrix *DenseBareReal32Matrix) MapSet(f func(ConstScalar) Scalar) {
+  n, m := matrix.Dims()
+  for i := 0; i < n; i++ {
+    for j := 0; j < m; j++ {
+      matrix.At(i,j).Set(f(matrix.ConstAt(i, j)))
+    }
+  }
+}
+func (matrix *DenseBareReal32Matrix) Reduce(f func(Scalar, ConstScalar) Scalar, r Scalar) Scalar {
+  n, m := matrix.Dims()
+  for i := 0; i < n; i++ {
+    for j := 0; j < m; j++ {
+      r = f(r, matrix.ConstAt(i, j))
+    }
+  }
+  return r
+}
+func (matrix *DenseBareReal32Matrix) ElementType() ScalarType {
+  return BareReal32Type
+}
+func (matrix *DenseBareReal32Matrix) Variables(order int) error {
+  for i, _ := range matrix.values {
+    if err := matrix.values[i].SetVariable(i, len(matrix.values), order); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+/* permutations
+ * -------------------------------------------------------------------------- */
+func (matrix *DenseBareReal32Matrix) SwapRows(i, j int) error {
+  _, m := matrix.Dims()
+  for k := 0; k < m; k++ {
+    matrix.Swap(i, k, j, k)
+  }
+  return nil
+}
+func (matrix *DenseBareReal32Matrix) SwapColumns(i, j int) error {
+  n, _ := matrix.Dims()
+  for k := 0; k < n; k++ {
+    matrix.Swap(k, i, k, j)
+  }
+  return nil
+}
+func (matrix *DenseBareReal32Matrix) PermuteRows(pi []int) error {
+  n, _ := matrix.Dims()
+  // permute matrix
+  if len(pi) != n || !applyPermutation(pi, func(i, j int) { matrix.SwapRows(i, j) }) {
+    return fmt.Errorf("PermuteRows(): invalid permutation")
+  }
+  return nil
+}
+func (matrix *DenseBareReal32Matrix) PermuteColumns(pi []int) error {
+  _, m := matrix.Dims()
+  // permute matrix
+  if len(pi) != m || !applyPermutation(pi, func(i, j int) { matrix.SwapColumns(i, j) }) {
+    return fmt.Errorf("PermuteColumns(): invalid permutation")
+  }
+  return nil
+}
+func (matrix *DenseBareReal32Matrix) SymmetricPermutation(pi []int) error {
+  n, m := matrix.Dims()
+  if n != m {
+    return fmt.Errorf("SymmetricPermutation(): matrix is not a square matrix")
+  }
+  swap := func(i, j int) {
+    // permute rows
+    matrix.SwapRows(i, j)
+    // permute colums
+    matrix.SwapColumns(i, j)
+  }
+  if len(pi) != n || !applyPermutation(pi, swap) {
+    return fmt.Errorf("SymmetricPermutation(): invalid permutation")
+  }
+  return nil
+}
+/* type conversion
+ * -------------------------------------------------------------------------- */
+func (m *DenseBareReal32Matrix) String() string {
+  var buffer bytes.Buffer
+  buffer.WriteString("[")
+  for i := 0; i < m.rows; i++ {
+    if i != 0 {
+      buffer.WriteString(",\n ")
+    }
+    buffer.WriteString("[")
+    for j := 0; j < m.cols; j++ {
+      if j != 0 {
+        buffer.WriteString(", ")
+      }
+      buffer.WriteString(m.ConstAt(i,j).String())
+    }
+    buffer.WriteString("]")
+  }
+  buffer.WriteString("]")
+  return buffer.String()
+}
+func (a *DenseBareReal32Matrix) Table() string {
+  var buffer bytes.Buffer
+  n, m := a.Dims()
+  for i := 0; i < n; i++ {
+    if i != 0 {
+      buffer.WriteString("\n")
+    }
+    for j := 0; j < m; j++ {
+      if j != 0 {
+        buffer.WriteString(" ")
+      }
+      buffer.WriteString(a.ConstAt(i,j).String())
+    }
+  }
+  return buffer.String()
+}
+func (m *DenseBareReal32Matrix) Export(filename string) error {
+  f, err := os.Create(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  w := bufio.NewWriter(f)
+  defer w.Flush()
+  if _, err := fmt.Fprintf(w, "%s\n", m.Table()); err != nil {
+    return err
+  }
+  return nil
+}
+func (m *DenseBareReal32Matrix) Import(filename string) error {
+  values := []float64{}
+  rows := 0
+  cols := 0
+  var scanner *bufio.Scanner
+  // open file
+  f, err := os.Open(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  isgzip, err := isGzip(filename)
+  if err != nil {
+    return err
+  }
+  // check if file is gzipped
+  if isgzip {
+    g, err := gzip.NewReader(f)
+    if err != nil {
+      return err
+    }
+    defer g.Close()
+    scanner = bufio.NewScanner(g)
+  } else {
+    scanner = bufio.NewScanner(f)
+  }
+  for scanner.Scan() {
+    fields := strings.Fields(scanner.Text())
+    if len(fields) == 0 {
+      continue
+    }
+    if cols == 0 {
+      cols = len(fields)
+    }
+    if cols != len(fields) {
+      return fmt.Errorf("invalid table")
+    }
+    for i := 0; i < len(fields); i++ {
+      value, err := strconv.ParseFloat(fields[i], 64)
+      if err != nil {
+        return fmt.Errorf("invalid table")
+      }
+      values = append(values, value)
+    }
+    rows++
+  }
+  *m = *NewDenseBareReal32Matrix(rows, cols, values)
+  return nil
+}
+/* json
+ * -------------------------------------------------------------------------- */
+func (obj *DenseBareReal32Matrix) MarshalJSON() ([]byte, error) {
+  if obj.transposed || obj.rowMax > obj.rows || obj.colMax > obj.cols {
+    n, m := obj.Dims()
+    tmp := NullDenseBareReal32Matrix(n, m)
+    tmp.Set(obj)
+    obj = tmp
+  }
+  r := struct{Values []BareReal32; Rows int; Cols int}{}
+  r.Values = obj.values
+  r.Rows = obj.rows
+  r.Cols = obj.cols
+  return json.MarshalIndent(r, "", "  ")
+}
+func (obj *DenseBareReal32Matrix) UnmarshalJSON(data []byte) error {
+  r := struct{Values []BareReal32; Rows int; Cols int}{}
+  if err := json.Unmarshal(data, &r); err != nil {
+    return err
+  }
+  obj.values = nilDenseBareReal32Vector(len(r.Values))
+  for i := 0; i < len(r.Values); i++ {
+    obj.values[i] = r.Values[i]
+  }
+  obj.rows = r.Rows
+  obj.rowMax = r.Rows
+  obj.rowOffset = 0
+  obj.cols = r.Cols
+  obj.colMax = r.Cols
+  obj.colOffset = 0
+  obj.transposed = false
+  obj.initTmp()
+  return nil
+}
+/* iterator methods
+ * -------------------------------------------------------------------------- */
+func (obj *DenseBareReal32Matrix) ConstIterator() MatrixConstIterator {
+  return obj.ITERATOR()
+}
+func (obj *DenseBareReal32Matrix) Iterator() MatrixIterator {
+  return obj.ITERATOR()
+}
+func (obj *DenseBareReal32Matrix) ITERATOR() *DenseBareReal32MatrixIterator {
+  r := DenseBareReal32MatrixIterator{*obj.values.ITERATOR(), obj}
+  return &r
+}
+/* iterator
+ * -------------------------------------------------------------------------- */
+type DenseBareReal32MatrixIterator struct {
+  DenseBareReal32VectorIterator
+  m *DenseBareReal32Matrix
+}
+func (obj *DenseBareReal32MatrixIterator) Index() (int, int) {
+  return obj.m.ij(obj.DenseBareReal32VectorIterator.Index())
+}
+func (obj *DenseBareReal32MatrixIterator) Clone() *DenseBareReal32MatrixIterator {
+  return &DenseBareReal32MatrixIterator{*obj.DenseBareReal32VectorIterator.Clone(), obj.m}
+}
+func (obj *DenseBareReal32MatrixIterator) CloneConstIterator() MatrixConstIterator {
+  return &DenseBareReal32MatrixIterator{*obj.DenseBareReal32VectorIterator.Clone(), obj.m}
+}
+func (obj *DenseBareReal32MatrixIterator) CloneIterator() MatrixIterator {
+  return &DenseBareReal32MatrixIterator{*obj.DenseBareReal32VectorIterator.Clone(), obj.m}
+}
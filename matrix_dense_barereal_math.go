@@ -241,6 +241,31 @@ func (r *DenseBareRealMatrix) Jacobian(f func(ConstVector) ConstVector, x_ Vecto
   }
   return r
 }
+// Compute the Jacobian of f at x_ column by column, where the jth column
+// is given by the Jacobian-vector product with the jth unit vector (see
+// JVP). Compared to Jacobian, f is evaluated once per input, but scalars
+// never store derivatives with respect to all inputs, which is favorable
+// for small input dimensions. The result is stored in r.
+func (r *DenseBareRealMatrix) JacobianJVP(f func(ConstVector) ConstVector, x_ ConstVector) Matrix {
+  n, m := r.Dims()
+  v := NullDenseBareRealVector(x_.Dim())
+  for j := 0; j < x_.Dim(); j++ {
+    v.AT(j).SetValue(1.0)
+    _, u := JVP(f, x_, v)
+    v.AT(j).SetValue(0.0)
+    // reallocate matrix if dimensions do not match
+    if j == 0 && (r == nil || x_.Dim() != m || u.Dim() != n) {
+       n = u.Dim()
+       m = x_.Dim()
+      *r = *NullDenseBareRealMatrix(n, m)
+    }
+    // copy derivatives
+    for i := 0; i < n; i++ {
+      r.At(i, j).SetValue(u.ValueAt(i))
+    }
+  }
+  return r
+}
 // Compute the Hessian of f at x_. The result is stored in r.
 func (r *DenseBareRealMatrix) Hessian(f func(ConstVector) ConstScalar, x_ Vector) Matrix {
   n, m := r.Dims()
@@ -0,0 +1,10 @@
+
+#define STORE_PTR 1
+
+#define SCALAR_NAME BatchReal
+#define MATRIX_NAME DenseBatchRealMatrix
+#define VECTOR_NAME DenseBatchRealVector
+
+#define SCALAR_TYPE *SCALAR_NAME
+#define MATRIX_TYPE *MATRIX_NAME
+#define VECTOR_TYPE  VECTOR_NAME
@@ -0,0 +1,10 @@
+
+#define STORE_PTR 1
+
+#define SCALAR_NAME BigReal
+#define MATRIX_NAME DenseBigRealMatrix
+#define VECTOR_NAME DenseBigRealVector
+
+#define SCALAR_TYPE *SCALAR_NAME
+#define MATRIX_TYPE *MATRIX_NAME
+#define VECTOR_TYPE  VECTOR_NAME
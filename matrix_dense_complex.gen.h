@@ -0,0 +1,10 @@
+
+#define STORE_PTR 1
+
+#define SCALAR_NAME Complex
+#define MATRIX_NAME DenseComplexMatrix
+#define VECTOR_NAME DenseComplexVector
+
+#define SCALAR_TYPE *SCALAR_NAME
+#define MATRIX_TYPE *MATRIX_NAME
+#define VECTOR_TYPE  VECTOR_NAME
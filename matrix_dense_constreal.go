@@ -90,13 +90,13 @@ func (matrix DenseConstRealMatrix) index(i, j int) int {
 
 func (matrix DenseConstRealMatrix) ij(k int) (int, int) {
   if matrix.transposed {
-    i := (k%matrix.colMax) - matrix.colOffset
-    j := (k/matrix.colMax) - matrix.rowOffset
-    return i, j
-  } else {
     i := (k%matrix.rowMax) - matrix.rowOffset
     j := (k/matrix.rowMax) - matrix.colOffset
     return i, j
+  } else {
+    i := (k/matrix.colMax) - matrix.rowOffset
+    j := (k%matrix.colMax) - matrix.colOffset
+    return i, j
   }
 }
 
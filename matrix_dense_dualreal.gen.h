@@ -0,0 +1,10 @@
+
+#define STORE_PTR 1
+
+#define SCALAR_NAME DualReal
+#define MATRIX_NAME DenseDualRealMatrix
+#define VECTOR_NAME DenseDualRealVector
+
+#define SCALAR_TYPE *SCALAR_NAME
+#define MATRIX_TYPE *MATRIX_NAME
+#define VECTOR_TYPE  VECTOR_NAME
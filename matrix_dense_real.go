@@ -146,13 +146,13 @@ func (matrix *DenseRealMatrix) index(i, j int) int {
 }
 func (matrix *DenseRealMatrix) ij(k int) (int, int) {
   if matrix.transposed {
-    i := (k%matrix.colMax) - matrix.colOffset
-    j := (k/matrix.colMax) - matrix.rowOffset
-    return i, j
-  } else {
     i := (k%matrix.rowMax) - matrix.rowOffset
     j := (k/matrix.rowMax) - matrix.colOffset
     return i, j
+  } else {
+    i := (k/matrix.colMax) - matrix.rowOffset
+    j := (k%matrix.colMax) - matrix.colOffset
+    return i, j
   }
 }
 func (matrix *DenseRealMatrix) Dims() (int, int) {
@@ -413,54 +413,32 @@ func (matrix *DenseRealMatrix) Variables(order int) error {
 /* permutations
  * -------------------------------------------------------------------------- */
 func (matrix *DenseRealMatrix) SwapRows(i, j int) error {
-  n, m := matrix.Dims()
-  if n != m {
-    return fmt.Errorf("SymmetricPermutation(): matrix is not a square matrix")
-  }
+  _, m := matrix.Dims()
   for k := 0; k < m; k++ {
     matrix.Swap(i, k, j, k)
   }
   return nil
 }
 func (matrix *DenseRealMatrix) SwapColumns(i, j int) error {
-  n, m := matrix.Dims()
-  if n != m {
-    return fmt.Errorf("SymmetricPermutation(): matrix is not a square matrix")
-  }
+  n, _ := matrix.Dims()
   for k := 0; k < n; k++ {
     matrix.Swap(k, i, k, j)
   }
   return nil
 }
 func (matrix *DenseRealMatrix) PermuteRows(pi []int) error {
-  n, m := matrix.Dims()
-  if n != m {
-    return fmt.Errorf("SymmetricPermutation(): matrix is not a square matrix")
-  }
+  n, _ := matrix.Dims()
   // permute matrix
-  for i := 0; i < n; i++ {
-    if pi[i] < 0 || pi[i] > n {
-      return fmt.Errorf("SymmetricPermutation(): invalid permutation")
-    }
-    if i != pi[i] && pi[i] > i {
-      matrix.SwapRows(i, pi[i])
-    }
+  if len(pi) != n || !applyPermutation(pi, func(i, j int) { matrix.SwapRows(i, j) }) {
+    return fmt.Errorf("PermuteRows(): invalid permutation")
   }
   return nil
 }
 func (matrix *DenseRealMatrix) PermuteColumns(pi []int) error {
-  n, m := matrix.Dims()
-  if n != m {
-    return fmt.Errorf("SymmetricPermutation(): matrix is not a square matrix")
-  }
+  _, m := matrix.Dims()
   // permute matrix
-  for i := 0; i < m; i++ {
-    if pi[i] < 0 || pi[i] > n {
-      return fmt.Errorf("SymmetricPermutation(): invalid permutation")
-    }
-    if i != pi[i] && pi[i] > i {
-      matrix.SwapColumns(i, pi[i])
-    }
+  if len(pi) != m || !applyPermutation(pi, func(i, j int) { matrix.SwapColumns(i, j) }) {
+    return fmt.Errorf("PermuteColumns(): invalid permutation")
   }
   return nil
 }
@@ -469,16 +447,14 @@ func (matrix *DenseRealMatrix) SymmetricPermutation(pi []int) error {
   if n != m {
     return fmt.Errorf("SymmetricPermutation(): matrix is not a square matrix")
   }
-  for i := 0; i < n; i++ {
-    if pi[i] < 0 || pi[i] > n {
-      return fmt.Errorf("SymmetricPermutation(): invalid permutation")
-    }
-    if pi[i] > i {
-      // permute rows
-      matrix.SwapRows(i, pi[i])
-      // permute colums
-      matrix.SwapColumns(i, pi[i])
-    }
+  swap := func(i, j int) {
+    // permute rows
+    matrix.SwapRows(i, j)
+    // permute colums
+    matrix.SwapColumns(i, j)
+  }
+  if len(pi) != n || !applyPermutation(pi, swap) {
+    return fmt.Errorf("SymmetricPermutation(): invalid permutation")
   }
   return nil
 }
@@ -250,6 +250,44 @@ func TestMatrixJacobian(t *testing.T) {
   }
 }
 
+func TestMatrixJacobian2(t *testing.T) {
+
+  f := func(x ConstVector) ConstVector {
+    y := NullVector(x.ElementType(), 2)
+    // sum_i x_i^2
+    for i := 0; i < x.Dim(); i++ {
+      y.At(1).Mul(x.ConstAt(i), x.ConstAt(i))
+      y.At(0).Add(y.At(0), y.At(1))
+    }
+    // x_0 x_1
+    y.At(1).Mul(x.ConstAt(0), x.ConstAt(1))
+    return y
+  }
+  s := NullReal()
+  for _, n := range []int{3, 16} {
+    x := NullVector(RealType, n)
+    J := NullMatrix(RealType, 2, n)
+    for i := 0; i < n; i++ {
+      x.At(i).SetValue(float64(i+1))
+      J.At(0, i).SetValue(2.0*float64(i+1))
+    }
+    J.At(1, 0).SetValue(2.0)
+    J.At(1, 1).SetValue(1.0)
+
+    r1 := NullDenseRealMatrix(2, n)
+    r1.Jacobian(f, x)
+    r2 := NullDenseRealMatrix(2, n)
+    r2.JacobianJVP(f, x)
+
+    if s.Mnorm(r1.MsubM(r1, J)).GetValue() > 1e-8 {
+      t.Error("Jacobian test failed!")
+    }
+    if s.Mnorm(r2.MsubM(r2, J)).GetValue() > 1e-8 {
+      t.Error("Jacobian test failed!")
+    }
+  }
+}
+
 func TestMatrixHessian(t *testing.T) {
   x := NewVector(RealType, []float64{1.5, 2.5})
   k := NewReal(3.0)
@@ -319,6 +357,69 @@ func TestSymmetricPermutation(t *testing.T) {
   }
 }
 
+func TestPermuteRows(t *testing.T) {
+  m1 := NewMatrix(RealType, 3, 3, []float64{
+    1, 2, 3,
+    4, 5, 6,
+    7, 8, 9 })
+  m2 := NewMatrix(RealType, 3, 3, []float64{
+    7, 8, 9,
+    1, 2, 3,
+    4, 5, 6 })
+  m3 := NewMatrix(RealType, 3, 3, []float64{
+    9, 7, 8,
+    3, 1, 2,
+    6, 4, 5 })
+  v1 := NewVector(RealType, []float64{1, 2, 3})
+  v2 := NewVector(RealType, []float64{3, 1, 2})
+  // cyclic permutation
+  pi := []int{2, 0, 1}
+
+  if err := m1.PermuteRows(pi); err != nil || !m1.Equals(m2, 1e-12) {
+    t.Error("test failed")
+  }
+  if err := m1.PermuteColumns(pi); err != nil || !m1.Equals(m3, 1e-12) {
+    t.Error("test failed")
+  }
+  if err := v1.Permute(pi); err != nil || !v1.Equals(v2, 1e-12) {
+    t.Error("test failed")
+  }
+  if err := v1.Permute([]int{0, 0, 1}); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestPermuteRowsNonSquare(t *testing.T) {
+  m1 := NewMatrix(RealType, 2, 3, []float64{
+    1, 2, 3,
+    4, 5, 6 })
+  m2 := NewMatrix(RealType, 2, 3, []float64{
+    6, 4, 5,
+    3, 1, 2 })
+  s1 := NewSparseRealMatrix(2, 3, []int{0, 1}, []int{2, 0}, []float64{1, 2})
+  // cyclic permutation of columns
+  pi := []int{2, 0, 1}
+
+  if err := m1.PermuteColumns(pi); err != nil || m1.At(0, 0).GetValue() != 3.0 {
+    t.Error("test failed")
+  }
+  if err := m1.PermuteRows([]int{1, 0}); err != nil || !m1.Equals(m2, 1e-12) {
+    t.Error("test failed")
+  }
+  if err := m1.PermuteRows(pi); err == nil {
+    t.Error("test failed")
+  }
+  if err := m1.SymmetricPermutation([]int{1, 0}); err == nil {
+    t.Error("test failed")
+  }
+  if err := s1.PermuteColumns(pi); err != nil || s1.At(0, 0).GetValue() != 1.0 || s1.At(1, 1).GetValue() != 2.0 {
+    t.Error("test failed")
+  }
+  if err := s1.SwapRows(0, 1); err != nil || s1.At(1, 0).GetValue() != 1.0 || s1.At(0, 1).GetValue() != 2.0 {
+    t.Error("test failed")
+  }
+}
+
 func TestMdotM(t *testing.T) {
   r1 := NewMatrix(RealType, 3, 3, []float64{
     1, 2, 3,
@@ -436,3 +537,23 @@ func TestMatrixTransposeInPlace(t *testing.T) {
     t.Error("test failed")
   }
 }
+
+func TestMatrixIterator(t *testing.T) {
+
+  m := NewMatrix(RealType, 2, 3, []float64{11,12,13,21,22,23})
+  c := NewDenseConstRealMatrix(2, 3, []float64{11,12,13,21,22,23})
+
+  for _, a := range []ConstMatrix{m, m.T(), c} {
+    n := 0
+    for it := a.ConstIterator(); it.Ok(); it.Next() {
+      i, j := it.Index()
+      if it.GetValue() != a.ConstAt(i, j).GetValue() {
+        t.Error("test failed")
+      }
+      n++
+    }
+    if n != 6 {
+      t.Error("test failed")
+    }
+  }
+}
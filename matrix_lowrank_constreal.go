@@ -0,0 +1,350 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "bytes"
+import "unsafe"
+
+/* -------------------------------------------------------------------------- */
+
+// Low-rank matrix U diag(s) V^T, where U and V have rank columns. Only the
+// factors are stored, which allows matrix vector products in O((rows+cols)
+// rank) operations. U and V are stored in row-major order.
+type LowRankConstRealMatrix struct {
+  rows int
+  cols int
+  rank int
+  u    []float64
+  s    []float64
+  v    []float64
+}
+
+/* constructors
+ * -------------------------------------------------------------------------- */
+
+// Allocate a new matrix U diag(s) V^T. The factors are copied.
+func NewLowRankConstRealMatrix(u ConstMatrix, s ConstVector, v ConstMatrix) LowRankConstRealMatrix {
+  rows, k1 := u.Dims()
+  cols, k2 := v.Dims()
+  if k1 != s.Dim() || k2 != s.Dim() {
+    panic("matrix/vector dimensions do not match!")
+  }
+  r := LowRankConstRealMatrix{rows: rows, cols: cols, rank: s.Dim()}
+  r.u = make([]float64, rows*r.rank)
+  r.s = make([]float64, r.rank)
+  r.v = make([]float64, cols*r.rank)
+  for k := 0; k < r.rank; k++ {
+    r.s[k] = s.ValueAt(k)
+    for i := 0; i < rows; i++ {
+      r.u[i*r.rank+k] = u.ValueAt(i, k)
+    }
+    for j := 0; j < cols; j++ {
+      r.v[j*r.rank+k] = v.ValueAt(j, k)
+    }
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (matrix LowRankConstRealMatrix) CloneConstMatrix() ConstMatrix {
+  return matrix.Clone()
+}
+
+func (matrix LowRankConstRealMatrix) Clone() LowRankConstRealMatrix {
+  r := matrix
+  r.u = make([]float64, len(matrix.u))
+  r.s = make([]float64, len(matrix.s))
+  r.v = make([]float64, len(matrix.v))
+  copy(r.u, matrix.u)
+  copy(r.s, matrix.s)
+  copy(r.v, matrix.v)
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (matrix LowRankConstRealMatrix) storageLocation() uintptr {
+  if len(matrix.u) == 0 {
+    return 0
+  }
+  return uintptr(unsafe.Pointer(&matrix.u[0]))
+}
+
+func (matrix LowRankConstRealMatrix) ElementType() ScalarType {
+  return BareRealType
+}
+
+func (matrix LowRankConstRealMatrix) Dims() (int, int) {
+  return matrix.rows, matrix.cols
+}
+
+// Number of stored factors.
+func (matrix LowRankConstRealMatrix) Rank() int {
+  return matrix.rank
+}
+
+func (matrix LowRankConstRealMatrix) ValueAt(i, j int) float64 {
+  if i < 0 || j < 0 || i >= matrix.rows || j >= matrix.cols {
+    panic(fmt.Errorf("index (%d,%d) out of bounds for matrix of dimension %dx%d", i, j, matrix.rows, matrix.cols))
+  }
+  u := matrix.u[i*matrix.rank:(i+1)*matrix.rank]
+  v := matrix.v[j*matrix.rank:(j+1)*matrix.rank]
+  r := 0.0
+  for k := 0; k < matrix.rank; k++ {
+    r += u[k]*matrix.s[k]*v[k]
+  }
+  return r
+}
+
+func (matrix LowRankConstRealMatrix) ConstAt(i, j int) ConstScalar {
+  return ConstReal(matrix.ValueAt(i, j))
+}
+
+// The result shares its storage with the original matrix.
+func (matrix LowRankConstRealMatrix) ConstSlice(rfrom, rto, cfrom, cto int) ConstMatrix {
+  r := matrix
+  r.rows = rto - rfrom
+  r.cols = cto - cfrom
+  r.u    = matrix.u[rfrom*matrix.rank:rto*matrix.rank]
+  r.v    = matrix.v[cfrom*matrix.rank:cto*matrix.rank]
+  return r
+}
+
+func (matrix LowRankConstRealMatrix) ConstRow(i int) ConstVector {
+  v := make([]float64, matrix.cols)
+  for j := 0; j < matrix.cols; j++ {
+    v[j] = matrix.ValueAt(i, j)
+  }
+  return DenseConstRealVector(v)
+}
+
+func (matrix LowRankConstRealMatrix) ConstCol(j int) ConstVector {
+  v := make([]float64, matrix.rows)
+  for i := 0; i < matrix.rows; i++ {
+    v[i] = matrix.ValueAt(i, j)
+  }
+  return DenseConstRealVector(v)
+}
+
+func (matrix LowRankConstRealMatrix) ConstDiag() ConstVector {
+  n := matrix.rows
+  if matrix.cols < n {
+    n = matrix.cols
+  }
+  v := make([]float64, n)
+  for i := 0; i < n; i++ {
+    v[i] = matrix.ValueAt(i, i)
+  }
+  return DenseConstRealVector(v)
+}
+
+func (matrix LowRankConstRealMatrix) GetValues() []float64 {
+  v := make([]float64, matrix.rows*matrix.cols)
+  for i := 0; i < matrix.rows; i++ {
+    for j := 0; j < matrix.cols; j++ {
+      v[i*matrix.cols + j] = matrix.ValueAt(i, j)
+    }
+  }
+  return v
+}
+
+func (matrix LowRankConstRealMatrix) AsConstVector() ConstVector {
+  return DenseConstRealVector(matrix.GetValues())
+}
+
+func (matrix LowRankConstRealMatrix) IsSymmetric(epsilon float64) bool {
+  if matrix.rows != matrix.cols {
+    return false
+  }
+  for i := 0; i < matrix.rows; i++ {
+    for j := i+1; j < matrix.cols; j++ {
+      if !ConstReal(matrix.ValueAt(i, j)).Equals(ConstReal(matrix.ValueAt(j, i)), epsilon) {
+        return false
+      }
+    }
+  }
+  return true
+}
+
+// Transpose the matrix. The result shares its storage with the original
+// matrix.
+func (matrix LowRankConstRealMatrix) T() LowRankConstRealMatrix {
+  r := matrix
+  r.rows, r.cols = matrix.cols, matrix.rows
+  r.u   , r.v    = matrix.v   , matrix.u
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (m LowRankConstRealMatrix) String() string {
+  var buffer bytes.Buffer
+  buffer.WriteString("[")
+  for i := 0; i < m.rows; i++ {
+    if i != 0 {
+      buffer.WriteString(",\n ")
+    }
+    buffer.WriteString("[")
+    for j := 0; j < m.cols; j++ {
+      if j != 0 {
+        buffer.WriteString(", ")
+      }
+      buffer.WriteString(m.ConstAt(i,j).String())
+    }
+    buffer.WriteString("]")
+  }
+  buffer.WriteString("]")
+  return buffer.String()
+}
+
+func (a LowRankConstRealMatrix) Table() string {
+  var buffer bytes.Buffer
+  n, m := a.Dims()
+  for i := 0; i < n; i++ {
+    if i != 0 {
+      buffer.WriteString("\n")
+    }
+    for j := 0; j < m; j++ {
+      if j != 0 {
+        buffer.WriteString(" ")
+      }
+      buffer.WriteString(a.ConstAt(i,j).String())
+    }
+  }
+  return buffer.String()
+}
+
+/* implement ConstScalarContainer
+ * -------------------------------------------------------------------------- */
+
+func (matrix LowRankConstRealMatrix) Reduce(f func(Scalar, ConstScalar) Scalar, r Scalar) Scalar {
+  for i := 0; i < matrix.rows; i++ {
+    for j := 0; j < matrix.cols; j++ {
+      r = f(r, ConstReal(matrix.ValueAt(i, j)))
+    }
+  }
+  return r
+}
+
+/* math
+ * -------------------------------------------------------------------------- */
+
+func (a LowRankConstRealMatrix) Equals(b ConstMatrix, epsilon float64) bool {
+  n1, m1 := a.Dims()
+  n2, m2 := b.Dims()
+  if n1 != n2 || m1 != m2 {
+    panic("MEqual(): matrix dimensions do not match!")
+  }
+  for i := 0; i < n1; i++ {
+    for j := 0; j < m1; j++ {
+      if !a.ConstAt(i, j).Equals(b.ConstAt(i, j), epsilon) {
+        return false
+      }
+    }
+  }
+  return true
+}
+
+// Compute r = x diag(s) y^T b, where x has n rows and y has m rows.
+func lowRankProduct(r Vector, b ConstVector, x, s, y []float64, n, m, rank int) {
+  t := make([]float64, rank)
+  for j := 0; j < m; j++ {
+    bj := b.ValueAt(j)
+    for k := 0; k < rank; k++ {
+      t[k] += y[j*rank+k]*bj
+    }
+  }
+  for k := 0; k < rank; k++ {
+    t[k] *= s[k]
+  }
+  if r_, ok := r.(DenseBareRealVector); ok {
+    for i := 0; i < n; i++ {
+      v := 0.0
+      for k := 0; k < rank; k++ {
+        v += x[i*rank+k]*t[k]
+      }
+      r_[i] = BareReal(v)
+    }
+    return
+  }
+  for i := 0; i < n; i++ {
+    v := 0.0
+    for k := 0; k < rank; k++ {
+      v += x[i*rank+k]*t[k]
+    }
+    r.At(i).SetValue(v)
+  }
+}
+
+/* iterator methods
+ * -------------------------------------------------------------------------- */
+
+func (m LowRankConstRealMatrix) ConstIterator() MatrixConstIterator {
+  return m.ITERATOR()
+}
+
+func (m LowRankConstRealMatrix) ITERATOR() *LowRankConstRealMatrixIterator {
+  r := LowRankConstRealMatrixIterator{m, -1}
+  r.Next()
+  return &r
+}
+
+/* const iterator
+ * -------------------------------------------------------------------------- */
+
+// Iterator over all elements of a low-rank matrix in row-major order.
+type LowRankConstRealMatrixIterator struct {
+  m LowRankConstRealMatrix
+  k int
+}
+
+func (obj *LowRankConstRealMatrixIterator) GetConst() ConstScalar {
+  return ConstReal(obj.GetValue())
+}
+
+func (obj *LowRankConstRealMatrixIterator) GetValue() float64 {
+  return obj.m.ValueAt(obj.Index())
+}
+
+func (obj *LowRankConstRealMatrixIterator) GET() ConstReal {
+  return ConstReal(obj.GetValue())
+}
+
+func (obj *LowRankConstRealMatrixIterator) Ok() bool {
+  return obj.k < obj.m.rows*obj.m.cols
+}
+
+func (obj *LowRankConstRealMatrixIterator) Next() {
+  obj.k++
+}
+
+func (obj *LowRankConstRealMatrixIterator) Index() (int, int) {
+  return obj.k / obj.m.cols, obj.k % obj.m.cols
+}
+
+func (obj *LowRankConstRealMatrixIterator) Clone() *LowRankConstRealMatrixIterator {
+  return &LowRankConstRealMatrixIterator{obj.m, obj.k}
+}
+
+func (obj *LowRankConstRealMatrixIterator) CloneConstIterator() MatrixConstIterator {
+  return &LowRankConstRealMatrixIterator{obj.m, obj.k}
+}
@@ -0,0 +1,98 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestLowRankMatrix1(t *testing.T) {
+  u := NewMatrix(BareRealType, 4, 2, []float64{
+    1, 0,
+    2, 1,
+    0, 3,
+    1, 1 })
+  v := NewMatrix(BareRealType, 3, 2, []float64{
+    1, 2,
+    0, 1,
+    3, 0 })
+  s := NewVector(BareRealType, []float64{2, -1})
+  // a = u diag(s) v^T
+  a := NewMatrix(BareRealType, 4, 3, []float64{
+     2, 0, 6,
+     2,-1,12,
+    -6,-3, 0,
+     0,-1, 6 })
+
+  r := NewLowRankConstRealMatrix(u, s, v)
+
+  if !r.Equals(a, 1e-12) || !a.Equals(r, 1e-12) {
+    t.Error("test failed")
+  }
+  if r.Rank() != 2 {
+    t.Error("test failed")
+  }
+  if !NewDenseConstRealMatrix(4, 3, r.GetValues()).Equals(a, 1e-12) {
+    t.Error("test failed")
+  }
+  if !r.ConstSlice(1, 3, 1, 3).Equals(a.ConstSlice(1, 3, 1, 3), 1e-12) {
+    t.Error("test failed")
+  }
+  if !r.ConstRow(1).Equals(a.ConstRow(1), 1e-12) || !r.ConstCol(2).Equals(a.ConstCol(2), 1e-12) {
+    t.Error("test failed")
+  }
+  if !r.T().Equals(a.T(), 1e-12) {
+    t.Error("test failed")
+  }
+  n := 0
+  for it := r.ConstIterator(); it.Ok(); it.Next() {
+    i, j := it.Index()
+    if it.GetValue() != a.ValueAt(i, j) {
+      t.Error("test failed")
+    }
+    n++
+  }
+  if n != 12 {
+    t.Error("test failed")
+  }
+}
+
+func TestLowRankMatrix2(t *testing.T) {
+  r := NewLowRankConstRealMatrix(
+    NewMatrix(BareRealType, 3, 1, []float64{1, 2, 3}),
+    NewVector(BareRealType, []float64{2}),
+    NewMatrix(BareRealType, 2, 1, []float64{1, -1}))
+  a := NewMatrix(RealType, 3, 2, []float64{
+    2, -2,
+    4, -4,
+    6, -6 })
+  x := NewVector(RealType, []float64{1, 2})
+  y := NewVector(RealType, []float64{1, 0, 1})
+
+  for _, z := range []Vector{NullVector(RealType, 3), NullDenseBareRealVector(3)} {
+    if !AsLinearOperator(r).MdotV(z, x).Equals(NullVector(RealType, 3).MdotV(a, x), 1e-12) {
+      t.Error("test failed")
+    }
+  }
+  if !AsLinearOperator(r.T()).MdotV(NullVector(RealType, 2), y).Equals(NewVector(RealType, []float64{8, -8}), 1e-12) {
+    t.Error("test failed")
+  }
+}
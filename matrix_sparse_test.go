@@ -0,0 +1,44 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestSparseMatrixIterator1(test *testing.T) {
+
+  m := NewSparseRealMatrix(2, 3, []int{0, 1, 1}, []int{2, 0, 2}, []float64{1, 2, 3})
+
+  for _, a := range []ConstMatrix{m, m.T()} {
+    n := 0
+    for it := a.ConstIterator(); it.Ok(); it.Next() {
+      i, j := it.Index()
+      if it.GetValue() != a.ConstAt(i, j).GetValue() {
+        test.Errorf("test failed")
+      }
+      n++
+    }
+    if n != 3 {
+      test.Errorf("test failed")
+    }
+  }
+}
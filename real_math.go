@@ -355,7 +355,7 @@ func (c *Real) Erf(a ConstScalar) Scalar {
 
 func (c *Real) Erfc(a ConstScalar) Scalar {
   x := a.GetValue()
-  v0 :=  math.Erf(x)
+  v0 :=  math.Erfc(x)
   f1 := func() float64 {
     return -2.0/(math.Exp(x*x)*special.M_SQRTPI)
   }
@@ -0,0 +1,228 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "encoding/json"
+import "reflect"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+// ReverseReal is a scalar that computes first derivatives in reverse mode.
+// Instead of carrying a dense gradient, every operation records its local
+// partial derivatives on a tape. The gradient of a value with respect to
+// all variables is computed by a single backward sweep the first time a
+// derivative is requested. Second derivatives are not supported.
+type ReverseReal struct {
+  Value float64
+  node  *reverseNode
+}
+
+/* register scalar type
+ * -------------------------------------------------------------------------- */
+
+var ReverseRealType ScalarType = NewReverseReal(0.0).Type()
+
+func init() {
+  f := func(value float64) Scalar { return NewReverseReal(value) }
+  RegisterScalar(ReverseRealType, f)
+}
+
+/* constructors
+ * -------------------------------------------------------------------------- */
+
+func NewReverseReal(v float64) *ReverseReal {
+  return &ReverseReal{Value: v}
+}
+
+func NullReverseReal() *ReverseReal {
+  return &ReverseReal{}
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *ReverseReal) Clone() *ReverseReal {
+  r := NewReverseReal(0.0)
+  r.SET(a)
+  return r
+}
+
+func (a *ReverseReal) CloneScalar() Scalar {
+  return a.Clone()
+}
+
+func (a *ReverseReal) Type() ScalarType {
+  return reflect.TypeOf(a)
+}
+
+func (a *ReverseReal) ConvertType(t ScalarType) Scalar {
+  switch t {
+  case ReverseRealType:
+    return a
+  case RealType:
+    return NewReal(a.GetValue())
+  case BareRealType:
+    return NewBareReal(a.GetValue())
+  case SparseRealType:
+    return NewSparseReal(a.GetValue())
+  case DualRealType:
+    return NewDualReal(a.GetValue())
+  case TaylorRealType:
+    return NewTaylorReal(a.GetValue())
+  case ComplexType:
+    return NewComplex(a.GetValue())
+  case BareReal32Type:
+    return NewBareReal32(a.GetValue())
+  case IntervalType:
+    return NewInterval(a.GetValue())
+  case BigRealType:
+    return NewBigReal(a.GetValue())
+  case BatchRealType:
+    return NewBatchReal(a.GetValue())
+  default:
+    panic(fmt.Sprintf("cannot convert `ReverseReal' to type `%v'", t))
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *ReverseReal) Alloc(n, order int) {
+}
+
+func (c *ReverseReal) AllocForOne(a ConstScalar) {
+}
+
+func (c *ReverseReal) AllocForTwo(a, b ConstScalar) {
+}
+
+/* read access
+ * -------------------------------------------------------------------------- */
+
+// Returns one if the value depends on at least one variable and zero
+// otherwise.
+func (a *ReverseReal) GetOrder() int {
+  if a.node == nil {
+    return 0
+  } else {
+    return 1
+  }
+}
+
+func (a *ReverseReal) GetValue() float64 {
+  return a.Value
+}
+
+func (a *ReverseReal) GetLogValue() float64 {
+  return math.Log(a.Value)
+}
+
+// Returns the derivative with respect to the ith variable. The
+// backward sweep is executed on the first call and its result is
+// cached for all subsequent calls.
+func (a *ReverseReal) GetDerivative(i int) float64 {
+  if a.node == nil {
+    return 0.0
+  }
+  return a.node.getGradient()[i]
+}
+
+func (a *ReverseReal) GetHessian(i, j int) float64 {
+  return 0.0
+}
+
+func (a *ReverseReal) GetN() int {
+  if a.node == nil {
+    return 0
+  } else {
+    return a.node.n
+  }
+}
+
+/* write access
+ * -------------------------------------------------------------------------- */
+
+func (a *ReverseReal) Reset() {
+  a.Value = 0.0
+  a.node  = nil
+}
+
+func (a *ReverseReal) ResetDerivatives() {
+  a.node = nil
+}
+
+// Set the state to b. Derivatives are only copied if b is also
+// a ReverseReal, all other types are treated as constants.
+func (a *ReverseReal) Set(b ConstScalar) {
+  a.Value = b.GetValue()
+  a.node  = reverseNodeOf(b)
+}
+
+func (a *ReverseReal) SET(b *ReverseReal) {
+  a.Value = b.Value
+  a.node  = b.node
+}
+
+func (a *ReverseReal) SetValue(v float64) {
+  a.Value = v
+  a.node  = nil
+}
+
+func (a *ReverseReal) setValue(v float64) {
+  a.Value = v
+}
+
+func (a *ReverseReal) SetDerivative(i int, v float64) {
+}
+
+func (a *ReverseReal) SetHessian(i, j int, v float64) {
+}
+
+// Mark the scalar as the ith out of n variables. Only first order
+// derivatives are supported.
+func (a *ReverseReal) SetVariable(i, n, order int) error {
+  if order > 1 {
+    return fmt.Errorf("order `%d' not supported by this type", order)
+  }
+  if order == 0 {
+    a.node = nil
+  } else {
+    a.node = newReverseVariable(i, n)
+  }
+  return nil
+}
+
+/* type conversion
+ * -------------------------------------------------------------------------- */
+
+func (a *ReverseReal) String() string {
+  return fmt.Sprintf("%e", a.GetValue())
+}
+
+/* json
+ * -------------------------------------------------------------------------- */
+
+func (obj *ReverseReal) MarshalJSON() ([]byte, error) {
+  return json.Marshal(obj.Value)
+}
+
+func (obj *ReverseReal) UnmarshalJSON(data []byte) error {
+  obj.node = nil
+  return json.Unmarshal(data, &obj.Value)
+}
@@ -0,0 +1,176 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+
+/* tape
+ * -------------------------------------------------------------------------- */
+
+// A node on the tape. Each node records up to two parents together with
+// the partial derivatives of the operation with respect to them. Variables
+// are leaves without parents and store the index of the variable. Values
+// that do not depend on any variable are not recorded at all.
+type reverseNode struct {
+  parents  [2]*reverseNode
+  weights  [2]float64
+  // index of the variable if this node is a leaf, -1 otherwise
+  index    int
+  // number of variables
+  n        int
+  // gradient computed by the backward sweep
+  gradient []float64
+}
+
+func newReverseVariable(i, n int) *reverseNode {
+  return &reverseNode{index: i, n: n}
+}
+
+func newReverseNode(p1, p2 *reverseNode, w1, w2 float64) *reverseNode {
+  if p1 == nil {
+    p1, p2 = p2, nil
+    w1, w2 = w2, 0.0
+  }
+  if p1 == nil {
+    return nil
+  }
+  r := reverseNode{index: -1, n: p1.n}
+  r.parents[0], r.weights[0] = p1, w1
+  if p2 != nil {
+    r.parents[1], r.weights[1] = p2, w2
+    if p2.n > r.n {
+      r.n = p2.n
+    }
+  }
+  return &r
+}
+
+func reverseNodeOf(a ConstScalar) *reverseNode {
+  if r, ok := a.(*ReverseReal); ok {
+    return r.node
+  }
+  return nil
+}
+
+/* backward sweep
+ * -------------------------------------------------------------------------- */
+
+// Sort all nodes that are reachable from this node topologically, so
+// that parents are always placed before their children.
+func (node *reverseNode) sort() ([]*reverseNode, map[*reverseNode]int) {
+  order := []*reverseNode{}
+  index := make(map[*reverseNode]int)
+  // use an explicit stack since tapes might be very long
+  type item struct {
+    node *reverseNode
+    next int
+  }
+  stack := []item{{node, 0}}
+  index[node] = -1
+  for len(stack) > 0 {
+    top := &stack[len(stack)-1]
+    if top.next < 2 {
+      p := top.node.parents[top.next]
+      top.next++
+      if p != nil {
+        if _, ok := index[p]; !ok {
+          index[p] = -1
+          stack    = append(stack, item{p, 0})
+        }
+      }
+    } else {
+      index[top.node] = len(order)
+      order = append(order, top.node)
+      stack = stack[0:len(stack)-1]
+    }
+  }
+  return order, index
+}
+
+func (node *reverseNode) backward() []float64 {
+  order, index := node.sort()
+  gradient := make([]float64, node.n)
+  adjoint  := make([]float64, len(order))
+  adjoint[len(order)-1] = 1.0
+  for k := len(order)-1; k >= 0; k-- {
+    a := adjoint[k]
+    if a == 0.0 {
+      continue
+    }
+    if r := order[k]; r.index >= 0 {
+      gradient[r.index] += a
+    } else {
+      for j := 0; j < 2 && r.parents[j] != nil; j++ {
+        adjoint[index[r.parents[j]]] += a*r.weights[j]
+      }
+    }
+  }
+  return gradient
+}
+
+func (node *reverseNode) getGradient() []float64 {
+  if node.gradient == nil {
+    node.gradient = node.backward()
+  }
+  return node.gradient
+}
+
+/* derivatives of monadic functions
+ * -------------------------------------------------------------------------- */
+
+// Record c = f(a) on the tape, where
+// - v0 = f(a)
+// - v1 = d/dx f(x) | x=a
+func (c *ReverseReal) monadic(a ConstScalar, v0, v1 float64) *ReverseReal {
+  c.node  = newReverseNode(reverseNodeOf(a), nil, v1, 0.0)
+  c.Value = v0
+  return c
+}
+
+func (c *ReverseReal) monadicLazy(a ConstScalar, v0 float64, f1 func() float64) *ReverseReal {
+  if p := reverseNodeOf(a); p != nil {
+    c.node = newReverseNode(p, nil, f1(), 0.0)
+  } else {
+    c.node = nil
+  }
+  c.Value = v0
+  return c
+}
+
+/* derivatives of dyadic functions
+ * -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) dyadic(a, b ConstScalar, v0, v10, v01 float64) *ReverseReal {
+  c.node  = newReverseNode(reverseNodeOf(a), reverseNodeOf(b), v10, v01)
+  c.Value = v0
+  return c
+}
+
+func (c *ReverseReal) dyadicLazy(a, b ConstScalar, v0 float64, f1 func() (float64, float64)) *ReverseReal {
+  p1 := reverseNodeOf(a)
+  p2 := reverseNodeOf(b)
+  if p1 != nil || p2 != nil {
+    v10, v01 := f1()
+    c.node = newReverseNode(p1, p2, v10, v01)
+  } else {
+    c.node = nil
+  }
+  c.Value = v0
+  return c
+}
@@ -0,0 +1,480 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "math"
+
+import "github.com/pbenner/autodiff/special"
+
+/* -------------------------------------------------------------------------- */
+
+func (a *ReverseReal) Equals(b ConstScalar, epsilon float64) bool {
+  return math.Abs(a.GetValue() - b.GetValue()) < epsilon
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *ReverseReal) Greater(b ConstScalar) bool {
+  return a.GetValue() > b.GetValue()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *ReverseReal) Smaller(b ConstScalar) bool {
+  return a.GetValue() < b.GetValue()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *ReverseReal) Sign() int {
+  if a.GetValue() < 0.0 {
+    return -1
+  }
+  if a.GetValue() > 0.0 {
+    return  1
+  }
+  return 0
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *ReverseReal) Min(a, b ConstScalar) Scalar {
+  if a.GetValue() < b.GetValue() {
+    r.Set(a)
+  } else {
+    r.Set(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *ReverseReal) Max(a, b ConstScalar) Scalar {
+  if a.GetValue() > b.GetValue() {
+    r.Set(a)
+  } else {
+    r.Set(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) Abs(a ConstScalar) Scalar {
+  switch a.Sign() {
+  case -1: c.Neg(a)
+  case  0: c.Reset()
+  case  1: c.Set(a)
+  }
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) Neg(a ConstScalar) Scalar {
+  x := a.GetValue()
+  return c.monadic(a, -x, -1)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) Add(a, b ConstScalar) Scalar {
+  x := a.GetValue()
+  y := b.GetValue()
+  return c.dyadic(a, b, x+y, 1, 1)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) Sub(a, b ConstScalar) Scalar {
+  x := a.GetValue()
+  y := b.GetValue()
+  return c.dyadic(a, b, x-y, 1, -1)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) Mul(a, b ConstScalar) Scalar {
+  x := a.GetValue()
+  y := b.GetValue()
+  return c.dyadic(a, b, x*y, y, x)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) Div(a, b ConstScalar) Scalar {
+  x := a.GetValue()
+  y := b.GetValue()
+  return c.dyadic(a, b, x/y, 1/y, -x/(y*y))
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) LogAdd(a, b ConstScalar, t Scalar) Scalar {
+  if a.Greater(b) {
+    // swap
+    a, b = b, a
+  }
+  if math.IsInf(a.GetValue(), 0) {
+    // cases:
+    //  i) a = -Inf and b >= a    => c = b
+    // ii) a =  Inf and b  = Inf  => c = Inf
+    c.Set(b)
+    return c
+  }
+  t.Sub(a, b)
+  t.Exp(t)
+  t.Log1p(t)
+  c.Add(t, b)
+  return c
+}
+
+func (c *ReverseReal) LogSub(a, b ConstScalar, t Scalar) Scalar {
+  if math.IsInf(b.GetValue(), -1) {
+    c.Set(a)
+    return c
+  }
+  //   log(exp(a) - exp(b))
+  // = log(1 - exp(b-a)) + a
+  t.Sub(b, a)
+  t.Exp(t)
+  t.Neg(t)
+  t.Log1p(t)
+  c.Add(t, a)
+  return c
+}
+
+func (c *ReverseReal) Log1pExp(a ConstScalar) Scalar {
+  v := a.GetValue()
+  if v <= -37.0 {
+    c.Exp(a)
+  } else
+  if v <=  18.0 {
+    c.Exp(a)
+    c.Log1p(c)
+  } else
+  if v <=  33.3 {
+    c.Neg(a)
+    c.Exp(a)
+    c.Add(c, a)
+  } else {
+    c.Set(a)
+  }
+  return c
+}
+
+func (c *ReverseReal) Sigmoid(a ConstScalar, t Scalar) Scalar {
+  if a.GetValue() >= 0 {
+    c.Neg(a)
+    c.Exp(c)
+    c.Add(c, ConstReal(1.0))
+    c.Div(ConstReal(1.0), c)
+  } else {
+    t.Exp(a)
+    c.Set(t)
+    t.Add(t, ConstReal(1.0))
+    c.Div(c, t)
+  }
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) Pow(a, k ConstScalar) Scalar {
+  x := a.GetValue()
+  y := k.GetValue()
+  v0 := math.Pow(x, y)
+  if reverseNodeOf(k) != nil {
+    f1 := func() (float64, float64) {
+      f10 := math.Pow(x, y-1)*y
+      f01 := math.Pow(x, y-0)*math.Log(x)
+      return f10, f01
+    }
+    return c.dyadicLazy(a, k, v0, f1)
+  } else {
+    f1 := func() (float64) {
+      return math.Pow(x, y-1)*y
+    }
+    return c.monadicLazy(a, v0, f1)
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) Sqrt(a ConstScalar) Scalar {
+  return c.Pow(a, ConstReal(1.0/2.0))
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) Sin(a ConstScalar) Scalar {
+  x := a.GetValue()
+  v0 := math.Sin(x)
+  f1 := func() float64 { return  math.Cos(x) }
+  return c.monadicLazy(a, v0, f1)
+}
+
+func (c *ReverseReal) Sinh(a ConstScalar) Scalar {
+  x := a.GetValue()
+  v0 := math.Sinh(x)
+  f1 := func() float64 { return  math.Cosh(x) }
+  return c.monadicLazy(a, v0, f1)
+}
+
+func (c *ReverseReal) Cos(a ConstScalar) Scalar {
+  x := a.GetValue()
+  v0 := math.Cos(x)
+  f1 := func() float64 { return -math.Sin(x) }
+  return c.monadicLazy(a, v0, f1)
+}
+
+func (c *ReverseReal) Cosh(a ConstScalar) Scalar {
+  x := a.GetValue()
+  v0 := math.Cosh(x)
+  f1 := func() float64 { return  math.Sinh(x) }
+  return c.monadicLazy(a, v0, f1)
+}
+
+func (c *ReverseReal) Tan(a ConstScalar) Scalar {
+  x := a.GetValue()
+  v0 := math.Tan(x)
+  f1 := func() float64 { return  1.0+math.Pow(math.Tan(x), 2) }
+  return c.monadicLazy(a, v0, f1)
+}
+
+func (c *ReverseReal) Tanh(a ConstScalar) Scalar {
+  x := a.GetValue()
+  v0 :=  math.Tanh(x)
+  f1 := func() float64 { return  1.0-math.Pow(math.Tanh(x), 2) }
+  return c.monadicLazy(a, v0, f1)
+}
+
+func (c *ReverseReal) Exp(a ConstScalar) Scalar {
+  x := a.GetValue()
+  v0 := math.Exp(x)
+  f1 := func() float64 { return v0 }
+  return c.monadicLazy(a, v0, f1)
+}
+
+func (c *ReverseReal) Log(a ConstScalar) Scalar {
+  x := a.GetValue()
+  v0 :=  math.Log(x)
+  f1 := func() float64 { return  1/x }
+  return c.monadicLazy(a, v0, f1)
+}
+
+func (c *ReverseReal) Log1p(a ConstScalar) Scalar {
+  x := a.GetValue()
+  v0 :=  math.Log1p(x)
+  f1 := func() float64 { return  1/ (1+x) }
+  return c.monadicLazy(a, v0, f1)
+}
+
+func (c *ReverseReal) Logistic(a ConstScalar) Scalar {
+  c.Neg(a)
+  c.Exp(c)
+  c.Add(ConstReal(1.0), c)
+  c.Div(ConstReal(1.0), c)
+  return c
+}
+
+func (c *ReverseReal) Erf(a ConstScalar) Scalar {
+  x := a.GetValue()
+  v0 :=  math.Erf(x)
+  f1 := func() float64 {
+    return  2.0/(math.Exp(x*x)*special.M_SQRTPI)
+  }
+  return c.monadicLazy(a, v0, f1)
+}
+
+func (c *ReverseReal) Erfc(a ConstScalar) Scalar {
+  x := a.GetValue()
+  v0 :=  math.Erfc(x)
+  f1 := func() float64 {
+    return -2.0/(math.Exp(x*x)*special.M_SQRTPI)
+  }
+  return c.monadicLazy(a, v0, f1)
+}
+
+func (c *ReverseReal) LogErfc(a ConstScalar) Scalar {
+  x := a.GetValue()
+  t := math.Erfc(x)
+  v0 :=  special.LogErfc(x)
+  f1 := func() float64 {
+    return -2.0/(math.Exp(x*x)*special.M_SQRTPI*t)
+  }
+  return c.monadicLazy(a, v0, f1)
+}
+
+func (c *ReverseReal) Gamma(a ConstScalar) Scalar {
+  x := a.GetValue()
+  v0 := math.Gamma(x)
+  f1 := func() float64 {
+    return v0*special.Digamma(x)
+  }
+  return c.monadicLazy(a, v0, f1)
+}
+
+func (c *ReverseReal) Lgamma(a ConstScalar) Scalar {
+  x := a.GetValue()
+  v0, s := math.Lgamma(a.GetValue())
+  if s == -1 {
+    v0 = math.NaN()
+  }
+  f1 := func() float64 { return special.Digamma(x) }
+  return c.monadicLazy(a, v0, f1)
+}
+
+func (c *ReverseReal) Mlgamma(a ConstScalar, k int) Scalar {
+  x := a.GetValue()
+  v0 := special.Mlgamma(x, k)
+  f1 := func() float64 {
+    s := 0.0
+    for j := 1; j <= k; j++ {
+      s += special.Digamma(x + float64(1-j)/2.0)
+    }
+    return s
+  }
+  return c.monadicLazy(a, v0, f1)
+}
+
+func (c *ReverseReal) GammaP(a float64, b ConstScalar) Scalar {
+  x  := b.GetValue()
+  v0 := special.GammaP(a, x)
+  f1 := func() float64 {
+    return special.GammaPfirstDerivative(a, x)
+  }
+  return c.monadicLazy(b, v0, f1)
+}
+
+func (c *ReverseReal) BesselI(v float64, b ConstScalar) Scalar {
+  x  := b.GetValue()
+  v0 := special.BesselI(v, x)
+  f1 := func() float64 {
+    v1 := special.BesselI(v-1.0, x)
+    return v1 - v/x*v0
+  }
+  return c.monadicLazy(b, v0, f1)
+}
+
+func (c *ReverseReal) LogBesselI(v float64, b ConstScalar) Scalar {
+  x  := b.GetValue()
+  v0 := special.LogBesselI(v, x)
+  f1 := func() float64 {
+    v1 := special.LogBesselI(v-1.0, x)
+    return math.Exp(v1-v0) - v/x
+  }
+  return c.monadicLazy(b, v0, f1)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *ReverseReal) SmoothMax(x ConstVector, alpha ConstReal, t [2]Scalar) Scalar {
+  r   .Reset()
+  t[1].Reset()
+  for i := 0; i < x.Dim(); i++ {
+    t[0].Mul(alpha, x.ConstAt(i))
+    t[0].Exp(t[0])
+    t[1].Add(t[1], t[0])
+    t[0].Mul(t[0], x.ConstAt(i))
+    r .Add(r , t[0])
+  }
+  r.Div(r, t[1])
+  return r
+}
+
+func (r *ReverseReal) LogSmoothMax(x ConstVector, alpha ConstReal, t [3]Scalar) Scalar {
+  r   .Reset()
+  t[2].SetValue(math.Inf(-1))
+  for i := 0; i < x.Dim(); i++ {
+    t[0].Mul(x.ConstAt(i), alpha)
+    t[2].LogAdd(t[2], t[0], t[1])
+    t[1].Log(x.ConstAt(i))
+    t[0].Add(t[0], t[1])
+    r.LogAdd(r, t[0], t[1])
+  }
+  r.Sub(r, t[2])
+  r.Exp(r)
+  return r
+}
+
+func (r *ReverseReal) Vmean(a ConstVector) Scalar {
+  r.Reset()
+  for i := 0; i < a.Dim(); i++ {
+    r.Add(r, a.ConstAt(i))
+  }
+  return r.Div(r, ConstReal(float64(a.Dim())))
+}
+
+func (r *ReverseReal) VdotV(a, b ConstVector) Scalar {
+  if a.Dim() != b.Dim() {
+    panic("vector dimensions do not match")
+  }
+  r.Reset()
+  t := NullReverseReal()
+  for i := 0; i < a.Dim(); i++ {
+    t.Mul(a.ConstAt(i), b.ConstAt(i))
+    r.Add(r, t)
+  }
+  return r
+}
+
+func (r *ReverseReal) Vnorm(a ConstVector) Scalar {
+  r.Reset()
+  t := NullReverseReal()
+  for it := a.ConstIterator(); it.Ok(); it.Next() {
+    t.Pow(it.GetConst(), ConstReal(2.0))
+    r.Add(r, t)
+  }
+  r.Sqrt(r)
+  return r
+}
+
+func (r *ReverseReal) Mtrace(a ConstMatrix) Scalar {
+  n, m := a.Dims()
+  if n != m {
+    panic("not a square matrix")
+  }
+  if n == 0 {
+    return nil
+  }
+  r.Reset()
+  for i := 0; i < n; i++ {
+    r.Add(r, a.ConstAt(i,i))
+  }
+  return r
+}
+
+// Frobenius norm.
+func (r *ReverseReal) Mnorm(a ConstMatrix) Scalar {
+  n, m := a.Dims()
+  if n == 0 || m == 0 {
+    return nil
+  }
+  c := ConstReal(2.0)
+  t := NullReverseReal()
+  v := a.AsConstVector()
+  r.Pow(v.ConstAt(0), c)
+  for i := 1; i < v.Dim(); i++ {
+    t.Pow(v.ConstAt(i), c)
+    r.Add(r, t)
+  }
+  return r
+}
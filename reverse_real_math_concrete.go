@@ -0,0 +1,200 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "math"
+
+/* -------------------------------------------------------------------------- */
+
+func (a *ReverseReal) EQUALS(b *ReverseReal, epsilon float64) bool {
+  return math.Abs(a.Value - b.Value) < epsilon
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *ReverseReal) GREATER(b *ReverseReal) bool {
+  return a.Value > b.Value
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *ReverseReal) SMALLER(b *ReverseReal) bool {
+  return a.Value < b.Value
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *ReverseReal) SIGN() int {
+  if a.Value < 0.0 {
+    return -1
+  }
+  if a.Value > 0.0 {
+    return  1
+  }
+  return 0
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *ReverseReal) MIN(a, b *ReverseReal) Scalar {
+  if a.Value < b.Value {
+    r.SET(a)
+  } else {
+    r.SET(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *ReverseReal) MAX(a, b *ReverseReal) Scalar {
+  if a.Value > b.Value {
+    r.SET(a)
+  } else {
+    r.SET(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) ABS(a *ReverseReal) Scalar {
+  if a.SIGN() == -1 {
+    c.NEG(a)
+  } else {
+    c.SET(a)
+  }
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) NEG(a *ReverseReal) *ReverseReal {
+  c.node  = newReverseNode(a.node, nil, -1, 0)
+  c.Value = -a.Value
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) ADD(a, b *ReverseReal) *ReverseReal {
+  c.node  = newReverseNode(a.node, b.node, 1, 1)
+  c.Value = a.Value + b.Value
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) SUB(a, b *ReverseReal) *ReverseReal {
+  c.node  = newReverseNode(a.node, b.node, 1, -1)
+  c.Value = a.Value - b.Value
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) MUL(a, b *ReverseReal) *ReverseReal {
+  x := a.Value
+  y := b.Value
+  c.node  = newReverseNode(a.node, b.node, y, x)
+  c.Value = x*y
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) DIV(a, b *ReverseReal) *ReverseReal {
+  x := a.Value
+  y := b.Value
+  c.node  = newReverseNode(a.node, b.node, 1/y, -x/(y*y))
+  c.Value = x/y
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) LOGADD(a, b, t *ReverseReal) *ReverseReal {
+  if a.GREATER(b) {
+    // swap
+    a, b = b, a
+  }
+  if math.IsInf(a.Value, 0) {
+    // cases:
+    //  i) a = -Inf and b >= a    => c = b
+    // ii) a =  Inf and b  = Inf  => c = Inf
+    c.SET(b)
+    return c
+  }
+  t.SUB(a, b)
+  t.EXP(t)
+  t.LOG1P(t)
+  c.ADD(t, b)
+  return c
+}
+
+func (c *ReverseReal) LOGSUB(a, b, t *ReverseReal) *ReverseReal {
+  if math.IsInf(b.Value, -1) {
+    c.SET(a)
+    return c
+  }
+  t.SUB(b, a)
+  t.EXP(t)
+  t.NEG(t)
+  t.LOG1P(t)
+  c.ADD(t, a)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) POW(a, k *ReverseReal) *ReverseReal {
+  c.Pow(a, k)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) SQRT(a *ReverseReal) *ReverseReal {
+  c.Pow(a, ConstReal(0.5))
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *ReverseReal) EXP(a *ReverseReal) *ReverseReal {
+  v0 := math.Exp(a.Value)
+  c.node  = newReverseNode(a.node, nil, v0, 0)
+  c.Value = v0
+  return c
+}
+
+func (c *ReverseReal) LOG(a *ReverseReal) *ReverseReal {
+  x := a.Value
+  c.node  = newReverseNode(a.node, nil, 1/x, 0)
+  c.Value = math.Log(x)
+  return c
+}
+
+func (c *ReverseReal) LOG1P(a *ReverseReal) *ReverseReal {
+  x := a.Value
+  c.node  = newReverseNode(a.node, nil, 1/(1+x), 0)
+  c.Value = math.Log1p(x)
+  return c
+}
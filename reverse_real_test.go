@@ -0,0 +1,153 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "math"
+import "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestReverseReal1(t *testing.T) {
+
+  t1 := NullReverseReal()
+
+  f := func(x Scalar) Scalar {
+    return t1.Add(t1.Mul(NewReverseReal(2), t1.Pow(x, NewBareReal(3))), NewReverseReal(4))
+  }
+  x := NewReverseReal(9)
+
+  Variables(1, x)
+
+  y := f(x)
+
+  if y.GetValue() != 1462 {
+    t.Error("test failed")
+  }
+  if y.GetDerivative(0) != 486 {
+    t.Error("test failed")
+  }
+}
+
+func TestReverseReal2(t *testing.T) {
+
+  a := NewReverseReal(13.123)
+  b := NewReverseReal( 4.321)
+
+  Variables(1, a, b)
+
+  a.Mul(a, a) // a^2
+  a.Mul(a, a) // a^4
+  a.Mul(a, b) // a^4 b
+
+  if math.Abs(a.GetValue() - 128149.4603376) > 1e-4 {
+    t.Error("test failed")
+  }
+  if math.Abs(a.GetDerivative(0) - 39061.025783) > 1e-4 {
+    t.Error("test failed")
+  }
+  if math.Abs(a.GetDerivative(1) - 29657.361800) > 1e-4 {
+    t.Error("test failed")
+  }
+}
+
+func TestReverseReal3(t *testing.T) {
+
+  f := func(x ConstVector, r Scalar) Scalar {
+    t := NullScalar(r.Type())
+    r.Reset()
+    for i := 0; i < x.Dim(); i++ {
+      t.Sin(x.ConstAt(i))
+      t.Mul(t, x.ConstAt((i+1) % x.Dim()))
+      t.Exp(t)
+      r.Add(r, t)
+      t.Lgamma(x.ConstAt(i))
+      r.Sub(r, t)
+      t.Log1pExp(x.ConstAt(i))
+      r.Add(r, t)
+    }
+    return r.Div(r, x.ConstAt(0))
+  }
+  x1 := NewVector(RealType,        []float64{1.1, 2.3, 0.7, 4.1, 3.3})
+  x2 := NewVector(ReverseRealType, []float64{1.1, 2.3, 0.7, 4.1, 3.3})
+  x1.Variables(1)
+  x2.Variables(1)
+
+  r1 := f(x1, NullReal())
+  r2 := f(x2, NullReverseReal())
+
+  if math.Abs(r1.GetValue() - r2.GetValue()) > 1e-12 {
+    t.Error("test failed")
+  }
+  if r1.GetN() != r2.GetN() {
+    t.Error("test failed")
+  }
+  for i := 0; i < x1.Dim(); i++ {
+    if math.Abs(r1.GetDerivative(i) - r2.GetDerivative(i)) > 1e-10 {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestReverseReal4(t *testing.T) {
+
+  // a long tape should not overflow the stack
+  x := NewReverseReal(1.0)
+  r := NewReverseReal(0.0)
+
+  Variables(1, x)
+
+  for i := 0; i < 1000000; i++ {
+    r.Add(r, x)
+  }
+  if r.GetDerivative(0) != 1000000 {
+    t.Error("test failed")
+  }
+}
+
+func TestReverseReal5(t *testing.T) {
+
+  x := NewReverseReal(1.0)
+
+  if err := Variables(2, x); err == nil {
+    t.Error("test failed")
+  }
+}
+
+func TestReverseRealMatrix(t *testing.T) {
+
+  a := NewMatrix(ReverseRealType, 2, 2, []float64{1, 2, 3, 4})
+  a.Variables(1)
+
+  // y = sum_ij (a a)_ij
+  b := NullMatrix(ReverseRealType, 2, 2)
+  b.MdotM(a, a)
+  y := NullReverseReal()
+  for it := b.ConstIterator(); it.Ok(); it.Next() {
+    y.Add(y, it.GetConst())
+  }
+  // dy/da_ij = sum_k a_jk + sum_k a_ki
+  r := []float64{7, 11, 9, 13}
+
+  for i := 0; i < 4; i++ {
+    if y.GetDerivative(i) != r[i] {
+      t.Error("test failed")
+    }
+  }
+}
@@ -180,6 +180,38 @@ func Variables(order int, reals ...Scalar) error {
   return nil
 }
 
+// Returns the highest order of derivatives, at most two, that scalars of
+// type t can carry. Zero is returned for types without derivatives, such as
+// BareReal, BareReal32, Interval and BigReal, and for BatchReal, which
+// carries a batch of derivatives instead of a single gradient.
+func DerivativeOrder(t ScalarType) int {
+  if t == BatchRealType {
+    return 0
+  }
+  s := NullScalar(t)
+  for order := 2; order > 0; order-- {
+    if err := s.SetVariable(0, 1, order); err == nil {
+      return order
+    }
+  }
+  return 0
+}
+
+// Returns true if derivatives of scalars of type t can be seeded with
+// SetDerivative. This excludes all types without derivatives and also
+// ReverseReal, which computes derivatives only by backpropagation.
+func hasForwardDerivatives(t ScalarType) bool {
+  if DerivativeOrder(t) == 0 {
+    return false
+  }
+  s := NullScalar(t)
+  if err := s.SetVariable(0, 1, 1); err != nil {
+    return false
+  }
+  s.SetDerivative(0, 2.0)
+  return s.GetDerivative(0) == 2.0
+}
+
 /* -------------------------------------------------------------------------- */
 
 func CopyGradient(g Vector, x ConstScalar) error {
@@ -225,3 +257,69 @@ func GetHessian(t ScalarType, x ConstScalar) Matrix {
   }
   return H
 }
+
+// Compute the product of the Hessian of f at x with the vector v. The
+// Hessian is not computed explicitly, instead f is evaluated once on
+// DualReal scalars, which requires O(N) memory per scalar. The function
+// f must not mix its argument with scalars of other types that carry
+// derivatives. The function panics if f does not return a DualReal.
+func HessianVectorProduct(f func(ConstVector) ConstScalar, x, v ConstVector) Vector {
+  n := x.Dim()
+  if v.Dim() != n {
+    panic("vector dimensions do not match")
+  }
+  X := NullDenseDualRealVector(n)
+  for i := 0; i < n; i++ {
+    X.AT(i).SetValue(x.ValueAt(i))
+  }
+  X.Variables(1)
+  // set direction
+  for i := 0; i < n; i++ {
+    X.AT(i).SetTangent(v.ValueAt(i))
+  }
+  y, ok := f(X).(*DualReal)
+  if !ok {
+    panic("HessianVectorProduct(): f must return a DualReal scalar!")
+  }
+  r := NullDenseBareRealVector(n)
+  for i := 0; i < n && i < y.GetN(); i++ {
+    r.AT(i).SetValue(y.GetTangentDerivative(i))
+  }
+  return r
+}
+
+// Compute f(x) and the product of the Jacobian of f at x with the vector v
+// (Jacobian-vector product). The function f is evaluated once on a copy of
+// x, where each scalar carries only a single derivative that is seeded from
+// v, i.e. the gradient with respect to all elements of x is never computed.
+// The element type of x is used for the evaluation. Vectors with element
+// types that cannot carry seeded derivatives, such as BareReal, Interval or
+// ReverseReal, are evaluated on Real scalars.
+func JVP(f func(ConstVector) ConstVector, x, v ConstVector) (Vector, Vector) {
+  n := x.Dim()
+  if v.Dim() != n {
+    panic("vector dimensions do not match")
+  }
+  t := x.ElementType()
+  if !hasForwardDerivatives(t) {
+    t = RealType
+  }
+  X := NullVector(t, n)
+  for i := 0; i < n; i++ {
+    X.At(i).SetValue(x.ValueAt(i))
+    if err := X.At(i).SetVariable(0, 1, 1); err != nil {
+      panic(err)
+    }
+    X.At(i).SetDerivative(0, v.ValueAt(i))
+  }
+  y := f(X)
+  r := NullDenseBareRealVector(y.Dim())
+  s := NullDenseBareRealVector(y.Dim())
+  for i := 0; i < y.Dim(); i++ {
+    r.AT(i).SetValue(y.ValueAt(i))
+    if y.ConstAt(i).GetN() > 0 {
+      s.AT(i).SetValue(y.ConstAt(i).GetDerivative(0))
+    }
+  }
+  return r, s
+}
@@ -18,6 +18,7 @@ package autodiff
 
 /* -------------------------------------------------------------------------- */
 
+import "math"
 import "testing"
 
 /* -------------------------------------------------------------------------- */
@@ -30,3 +31,79 @@ func TestScalar(t *testing.T) {
     t.Error("a.GetValue() should be 1.0")
   }
 }
+
+func TestJVP(t *testing.T) {
+
+  f := func(x ConstVector) ConstVector {
+    n := x.Dim()
+    r := NullVector(RealType, n+1)
+    s := NullReal()
+    for i := 0; i < n; i++ {
+      s.Mul(x.ConstAt(i), x.ConstAt((i+1) % n))
+      s.Sin(s)
+      r.At(i).Add(s, x.ConstAt(i))
+      r.At(n).Add(r.At(n), r.At(i))
+    }
+    return r
+  }
+  x := NewVector(RealType,     []float64{1.1, 2.3, 0.7, 4.1, 3.3, 0.2, 1.5, 2.1, 0.9, 1.3})
+  v := NewVector(BareRealType, []float64{0.3,-1.2, 2.0, 0.5, 1.0, 0.1,-0.4, 0.0, 1.7, 0.8})
+  // full Jacobian
+  J := NullMatrix(RealType, x.Dim()+1, x.Dim())
+  J.Jacobian(f, x)
+
+  r1 := NullVector(RealType, x.Dim()+1)
+  r1.MdotV(J, v)
+  y, r2 := JVP(f, x, v)
+
+  if y.Dim() != x.Dim()+1 || r2.Dim() != x.Dim()+1 {
+    t.Error("test failed")
+  } else {
+    z := f(x)
+    for i := 0; i < r1.Dim(); i++ {
+      if math.Abs(y.ValueAt(i) - z.ValueAt(i)) > 1e-10 {
+        t.Error("test failed")
+      }
+      if math.Abs(r1.ValueAt(i) - r2.ValueAt(i)) > 1e-10 {
+        t.Error("test failed")
+      }
+    }
+  }
+}
+
+func TestDerivativeOrder(t *testing.T) {
+  for _, test := range []struct{t ScalarType; order int}{
+    {BareRealType,    0},
+    {BareReal32Type,  0},
+    {IntervalType,    0},
+    {BigRealType,     0},
+    {BatchRealType,   0},
+    {ReverseRealType, 1},
+    {DualRealType,    1},
+    {RealType,        2} } {
+    if DerivativeOrder(test.t) != test.order {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestJVPTypes(t *testing.T) {
+
+  f := func(x ConstVector) ConstVector {
+    r := NullVector(x.ElementType(), 2)
+    r.At(0).Mul(x.ConstAt(0), x.ConstAt(1))
+    r.At(1).Sin(x.ConstAt(0))
+    return r
+  }
+  v := NewVector(BareRealType, []float64{0.3, -1.2})
+  for _, st := range []ScalarType{BareRealType, BareReal32Type, IntervalType, BigRealType, BatchRealType, ReverseRealType, DualRealType, RealType} {
+    x := NewVector(st, []float64{0.5, 2.0})
+    _, r := JVP(f, x, v)
+    if math.Abs(r.ValueAt(0) - (2.0*0.3 - 0.5*1.2)) > 1e-6 {
+      t.Error("test failed")
+    }
+    if math.Abs(r.ValueAt(1) - math.Cos(0.5)*0.3) > 1e-6 {
+      t.Error("test failed")
+    }
+  }
+}
@@ -0,0 +1,384 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "encoding/json"
+import "reflect"
+import "math"
+import "sort"
+
+/* -------------------------------------------------------------------------- */
+
+// SparseReal is a scalar that stores first and second derivatives only for
+// the variables on which its value actually depends. The indices of these
+// variables are kept sorted in Index, Derivative[p] is the derivative with
+// respect to variable Index[p] and Hessian[p][q] the second derivative with
+// respect to variables Index[p] and Index[q]. Operations propagate only the
+// entries of their arguments, so that values which depend on a few out of
+// many variables remain cheap.
+type SparseReal struct {
+  Value            float64
+  Order            int
+  Index          []int
+  Derivative     []float64
+  Hessian      [][]float64
+  N                int
+}
+
+/* register scalar type
+ * -------------------------------------------------------------------------- */
+
+var SparseRealType ScalarType = NewSparseReal(0.0).Type()
+
+func init() {
+  f := func(value float64) Scalar { return NewSparseReal(value) }
+  RegisterScalar(SparseRealType, f)
+}
+
+/* constructors
+ * -------------------------------------------------------------------------- */
+
+func NewSparseReal(v float64) *SparseReal {
+  return &SparseReal{Value: v}
+}
+
+func NullSparseReal() *SparseReal {
+  return &SparseReal{}
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *SparseReal) Clone() *SparseReal {
+  r := NewSparseReal(0.0)
+  r.SET(a)
+  return r
+}
+
+func (a *SparseReal) CloneScalar() Scalar {
+  return a.Clone()
+}
+
+func (a *SparseReal) Type() ScalarType {
+  return reflect.TypeOf(a)
+}
+
+func (a *SparseReal) ConvertType(t ScalarType) Scalar {
+  switch t {
+  case SparseRealType:
+    return a
+  case RealType:
+    return NewReal(a.GetValue())
+  case BareRealType:
+    return NewBareReal(a.GetValue())
+  case ReverseRealType:
+    return NewReverseReal(a.GetValue())
+  case DualRealType:
+    return NewDualReal(a.GetValue())
+  case TaylorRealType:
+    return NewTaylorReal(a.GetValue())
+  case ComplexType:
+    return NewComplex(a.GetValue())
+  case BareReal32Type:
+    return NewBareReal32(a.GetValue())
+  case IntervalType:
+    return NewInterval(a.GetValue())
+  case BigRealType:
+    return NewBigReal(a.GetValue())
+  case BatchRealType:
+    return NewBatchReal(a.GetValue())
+  default:
+    panic(fmt.Sprintf("cannot convert `SparseReal' to type `%v'", t))
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Set the number of variables and the order of derivatives. Memory is
+// allocated only for variables that are added to the index.
+func (a *SparseReal) Alloc(n, order int) {
+  if a.N != n || a.Order != order {
+    a.N     = n
+    a.Order = order
+    a.resize(0)
+  }
+}
+
+func (c *SparseReal) AllocForOne(a ConstScalar) {
+  c.Alloc(a.GetN(), a.GetOrder())
+}
+
+func (c *SparseReal) AllocForTwo(a, b ConstScalar) {
+  c.Alloc(iMax(a.GetN(), b.GetN()), iMax(a.GetOrder(), b.GetOrder()))
+}
+
+// Resize the index to k entries. Existing memory is reused if possible,
+// the content of the derivatives is undefined afterwards.
+func (a *SparseReal) resize(k int) {
+  if a.Order < 1 {
+    k = 0
+  }
+  if cap(a.Index) >= k {
+    a.Index = a.Index[0:k]
+  } else {
+    a.Index = make([]int, k)
+  }
+  if cap(a.Derivative) >= k {
+    a.Derivative = a.Derivative[0:k]
+  } else {
+    a.Derivative = make([]float64, k)
+  }
+  if a.Order >= 2 {
+    if cap(a.Hessian) >= k {
+      a.Hessian = a.Hessian[0:k]
+    } else {
+      a.Hessian = append(a.Hessian[0:cap(a.Hessian)], make([][]float64, k-cap(a.Hessian))...)
+    }
+    for i := 0; i < k; i++ {
+      if cap(a.Hessian[i]) >= k {
+        a.Hessian[i] = a.Hessian[i][0:k]
+      } else {
+        a.Hessian[i] = make([]float64, k)
+      }
+    }
+  } else {
+    a.Hessian = nil
+  }
+}
+
+// Returns the position of variable i in the index or -1 if the value
+// does not depend on i.
+func (a *SparseReal) position(i int) int {
+  p := sort.SearchInts(a.Index, i)
+  if p < len(a.Index) && a.Index[p] == i {
+    return p
+  }
+  return -1
+}
+
+// Add variable i to the index and return its position. Derivatives of
+// the new variable are initialized to zero.
+func (a *SparseReal) insert(i int) int {
+  if p := a.position(i); p != -1 {
+    return p
+  }
+  p := sort.SearchInts(a.Index, i)
+  k := len(a.Index)
+  a.Index      = append(a.Index,      0)
+  a.Derivative = append(a.Derivative, 0)
+  copy(a.Index     [p+1:], a.Index     [p:k])
+  copy(a.Derivative[p+1:], a.Derivative[p:k])
+  a.Index     [p] = i
+  a.Derivative[p] = 0.0
+  if a.Order >= 2 {
+    for j := 0; j < k; j++ {
+      a.Hessian[j] = append(a.Hessian[j], 0)
+      copy(a.Hessian[j][p+1:], a.Hessian[j][p:k])
+      a.Hessian[j][p] = 0.0
+    }
+    a.Hessian = append(a.Hessian, nil)
+    copy(a.Hessian[p+1:], a.Hessian[p:k])
+    a.Hessian[p] = make([]float64, k+1)
+  }
+  return p
+}
+
+/* read access
+ * -------------------------------------------------------------------------- */
+
+// Indicates the maximal order of derivatives that are computed for this
+// variable. `0' means no derivatives, `1' only the first derivative, and
+// `2' the first and second derivative.
+func (a *SparseReal) GetOrder() int {
+  return a.Order
+}
+
+func (a *SparseReal) GetValue() float64 {
+  return a.Value
+}
+
+func (a *SparseReal) GetLogValue() float64 {
+  return math.Log(a.Value)
+}
+
+// Returns the derivative of the ith variable.
+func (a *SparseReal) GetDerivative(i int) float64 {
+  if a.Order >= 1 {
+    if p := a.position(i); p != -1 {
+      return a.Derivative[p]
+    }
+  }
+  return 0.0
+}
+
+func (a *SparseReal) GetHessian(i, j int) float64 {
+  if a.Order >= 2 {
+    if p := a.position(i); p != -1 {
+      if q := a.position(j); q != -1 {
+        return a.Hessian[p][q]
+      }
+    }
+  }
+  return 0.0
+}
+
+// Number of variables for which derivates are stored.
+func (a *SparseReal) GetN() int {
+  return a.N
+}
+
+/* write access
+ * -------------------------------------------------------------------------- */
+
+func (a *SparseReal) Reset() {
+  a.Value = 0.0
+  a.ResetDerivatives()
+}
+
+// Reset all derivatives to zero. The index of variables is cleared.
+func (a *SparseReal) ResetDerivatives() {
+  a.resize(0)
+}
+
+// Set the state to b. This includes the value and all derivatives.
+func (a *SparseReal) Set(b ConstScalar) {
+  if r, ok := b.(*SparseReal); ok {
+    a.SET(r)
+    return
+  }
+  a.Value = b.GetValue()
+  a.Order = b.GetOrder()
+  a.N     = b.GetN()
+  a.resize(b.GetN())
+  if a.Order >= 1 {
+    for i := 0; i < b.GetN(); i++ {
+      a.Index     [i] = i
+      a.Derivative[i] = b.GetDerivative(i)
+    }
+    if a.Order >= 2 {
+      for i := 0; i < b.GetN(); i++ {
+        for j := 0; j < b.GetN(); j++ {
+          a.Hessian[i][j] = b.GetHessian(i, j)
+        }
+      }
+    }
+  }
+}
+
+func (a *SparseReal) SET(b *SparseReal) {
+  if a == b {
+    return
+  }
+  a.Value = b.Value
+  a.Order = b.Order
+  a.N     = b.N
+  a.resize(len(b.Index))
+  copy(a.Index,      b.Index)
+  copy(a.Derivative, b.Derivative)
+  if a.Order >= 2 {
+    for i := 0; i < len(b.Index); i++ {
+      copy(a.Hessian[i], b.Hessian[i])
+    }
+  }
+}
+
+// Set the value of the variable. All derivatives are reset to zero.
+func (a *SparseReal) SetValue(v float64) {
+  a.Value = v
+  a.ResetDerivatives()
+}
+
+func (a *SparseReal) setValue(v float64) {
+  a.Value = v
+}
+
+// Set the derivative of the ith variable to v. The variable is added
+// to the index if necessary.
+func (a *SparseReal) SetDerivative(i int, v float64) {
+  a.Derivative[a.insert(i)] = v
+}
+
+func (a *SparseReal) SetHessian(i, j int, v float64) {
+  a.insert(i)
+  a.insert(j)
+  a.Hessian[a.position(i)][a.position(j)] = v
+}
+
+// Set the number of variables to n and mark this scalar as the
+// ith variable.
+func (a *SparseReal) SetVariable(i, n, order int) error {
+  if order > 2 {
+    return fmt.Errorf("order `%d' not supported by this type", order)
+  }
+  a.Alloc(n, order)
+  if order > 0 {
+    a.resize(1)
+    a.Index     [0] = i
+    a.Derivative[0] = 1
+    if order > 1 {
+      a.Hessian[0][0] = 0
+    }
+  }
+  return nil
+}
+
+/* type conversion
+ * -------------------------------------------------------------------------- */
+
+func (a *SparseReal) String() string {
+  return fmt.Sprintf("%e", a.GetValue())
+}
+
+/* json
+ * -------------------------------------------------------------------------- */
+
+func (obj *SparseReal) MarshalJSON() ([]byte, error) {
+  if obj.Order > 0 && len(obj.Index) > 0 {
+    r := struct{Value float64; N int; Index []int; Derivative []float64; Hessian [][]float64}{
+      obj.Value, obj.N, obj.Index, obj.Derivative, obj.Hessian}
+    return json.Marshal(r)
+  } else {
+    return json.Marshal(obj.Value)
+  }
+}
+
+func (obj *SparseReal) UnmarshalJSON(data []byte) error {
+  r := struct{Value float64; N int; Index []int; Derivative []float64; Hessian [][]float64}{}
+  if err := json.Unmarshal(data, &r); err == nil {
+    if len(r.Index) != len(r.Derivative) {
+      return fmt.Errorf("invalid json scalar representation")
+    }
+    if len(r.Hessian) != 0 && len(r.Hessian) != len(r.Index) {
+      return fmt.Errorf("invalid json scalar representation")
+    }
+    obj.Value = r.Value
+    if len(r.Hessian) != 0 {
+      obj.Alloc(r.N, 2)
+    } else {
+      obj.Alloc(r.N, 1)
+    }
+    obj.Index      = r.Index
+    obj.Derivative = r.Derivative
+    obj.Hessian    = r.Hessian
+    return nil
+  } else {
+    obj.Reset()
+    return json.Unmarshal(data, &obj.Value)
+  }
+}
@@ -0,0 +1,295 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "sort"
+
+/* sparse view on the derivatives of an arbitrary scalar
+ * -------------------------------------------------------------------------- */
+
+type sparseOperand struct {
+  index        []int
+  derivative   []float64
+  hessian    [][]float64
+}
+
+// Scalars other than SparseReal are assumed to depend on all of
+// their variables.
+func sparseOperandOf(a ConstScalar) sparseOperand {
+  if r, ok := a.(*SparseReal); ok {
+    if r.Order >= 2 {
+      return sparseOperand{r.Index, r.Derivative, r.Hessian}
+    }
+    if r.Order >= 1 {
+      return sparseOperand{r.Index, r.Derivative, nil}
+    }
+    return sparseOperand{}
+  }
+  if a.GetOrder() < 1 {
+    return sparseOperand{}
+  }
+  n := a.GetN()
+  r := sparseOperand{}
+  r.index      = make([]int,     n)
+  r.derivative = make([]float64, n)
+  for i := 0; i < n; i++ {
+    r.index     [i] = i
+    r.derivative[i] = a.GetDerivative(i)
+  }
+  if a.GetOrder() >= 2 {
+    r.hessian = make([][]float64, n)
+    for i := 0; i < n; i++ {
+      r.hessian[i] = make([]float64, n)
+      for j := 0; j < n; j++ {
+        r.hessian[i][j] = a.GetHessian(i, j)
+      }
+    }
+  }
+  return r
+}
+
+func (a sparseOperand) getDerivative(p int) float64 {
+  if p < 0 {
+    return 0.0
+  }
+  return a.derivative[p]
+}
+
+func (a sparseOperand) getHessian(p, q int) float64 {
+  if p < 0 || q < 0 || a.hessian == nil {
+    return 0.0
+  }
+  return a.hessian[p][q]
+}
+
+// Returns the positions of the variables of b within the index of
+// a, or nil if b depends on variables that are not in the index of a.
+func (a sparseOperand) subset(b sparseOperand) []int {
+  r := make([]int, len(b.index))
+  for q, i := range b.index {
+    p := sort.SearchInts(a.index, i)
+    if p == len(a.index) || a.index[p] != i {
+      return nil
+    }
+    r[q] = p
+  }
+  return r
+}
+
+// Merge the indices of a and b. The positions of each variable within
+// a and b are returned as well (-1 if the variable is missing).
+func sparseMergeIndex(a, b sparseOperand, index, pa, pb []int) ([]int, []int, []int) {
+  index = index[0:0]
+  pa    = pa   [0:0]
+  pb    = pb   [0:0]
+  for i, j := 0, 0; i < len(a.index) || j < len(b.index); {
+    switch {
+    case j == len(b.index) || (i < len(a.index) && a.index[i] < b.index[j]):
+      index = append(index, a.index[i])
+      pa    = append(pa, i)
+      pb    = append(pb, -1)
+      i++
+    case i == len(a.index) || (j < len(b.index) && b.index[j] < a.index[i]):
+      index = append(index, b.index[j])
+      pa    = append(pa, -1)
+      pb    = append(pb, j)
+      j++
+    default:
+      index = append(index, a.index[i])
+      pa    = append(pa, i)
+      pb    = append(pb, j)
+      i++; j++
+    }
+  }
+  return index, pa, pb
+}
+
+/* derivatives of monadic functions
+ * -------------------------------------------------------------------------- */
+
+// Compute d/dx f(g(x)) and d^2/dx^2 f(g(x)) evaluated at x=x0, where
+// - a  = g(x0)
+// - v0 = f(a)
+// - v1 = d/dx f(x) | x=a
+// - v2 = d^2/dx^2 f(x) | x=a
+// Only derivatives of variables on which a depends are computed.
+func (c *SparseReal) monadic(a ConstScalar, v0, v1, v2 float64) *SparseReal {
+  x := sparseOperandOf(a)
+  k := len(x.index)
+  if r, ok := a.(*SparseReal); !ok || r != c {
+    c.N     = a.GetN()
+    c.Order = a.GetOrder()
+    c.resize(k)
+    copy(c.Index, x.index)
+  }
+  if c.Order >= 1 {
+    if c.Order >= 2 {
+      // compute hessian
+      for p := 0; p < k; p++ {
+        for q := p; q < k; q++ {
+          c.Hessian[p][q] = x.getDerivative(p)*x.getDerivative(q)*v2 + x.getHessian(p, q)*v1
+          c.Hessian[q][p] = c.Hessian[p][q]
+        }
+      }
+    }
+    // compute first derivatives
+    for p := 0; p < k; p++ {
+      c.Derivative[p] = x.getDerivative(p)*v1
+    }
+  }
+  // compute new value
+  c.setValue(v0)
+  return c
+}
+
+func (c *SparseReal) monadicLazy(a ConstScalar, v0 float64, f1, f2 func() float64) *SparseReal {
+  v1, v2 := 0.0, 0.0
+  if a.GetOrder() >= 1 {
+    v1 = f1()
+    if a.GetOrder() >= 2 {
+      v2 = f2()
+    }
+  }
+  return c.monadic(a, v0, v1, v2)
+}
+
+/* derivatives of dyadic functions
+ * -------------------------------------------------------------------------- */
+
+func (c *SparseReal) dyadic(a, b ConstScalar, v0, v10, v01, v11, v20, v02 float64) *SparseReal {
+  n     := iMax(a.GetN(), b.GetN())
+  order := iMax(a.GetOrder(), b.GetOrder())
+  x     := sparseOperandOf(a)
+  y     := sparseOperandOf(b)
+  // check if c is one of the arguments
+  ca := false
+  cb := false
+  if r, ok := a.(*SparseReal); ok && r == c {
+    ca = true
+  }
+  if r, ok := b.(*SparseReal); ok && r == c {
+    cb = true
+  }
+  if order >= 1 {
+    // accumulate in place if possible, i.e. c = a + v01*b where b depends
+    // only on variables already contained in the index of a
+    if ca && !cb && c.Order == order && v10 == 1 && v20 == 0 {
+      if py := x.subset(y); py != nil {
+        c.N = n
+        c.accumulate(x, y, py, v01, v11, v02)
+        c.setValue(v0)
+        return c
+      }
+    }
+    if cb && !ca && c.Order == order && v01 == 1 && v02 == 0 {
+      if px := y.subset(x); px != nil {
+        c.N = n
+        c.accumulate(y, x, px, v10, v11, v20)
+        c.setValue(v0)
+        return c
+      }
+    }
+  }
+  // compute the result in a new scalar if c is one of the arguments
+  r := c
+  if ca || cb {
+    r = NullSparseReal()
+  }
+  r.N     = n
+  r.Order = order
+  if r.Order >= 1 {
+    index, pa, pb := sparseMergeIndex(x, y, r.Index, nil, nil)
+    k := len(index)
+    r.Index = index
+    r.resize(k)
+    if r.Order >= 2 {
+      // compute hessian
+      for p := 0; p < k; p++ {
+        for q := p; q < k; q++ {
+          r.Hessian[p][q] =
+            x.getHessian(pa[p], pa[q])*v10 +
+            y.getHessian(pb[p], pb[q])*v01 +
+            x.getDerivative(pa[p])*x.getDerivative(pa[q])*v20 +
+            y.getDerivative(pb[p])*y.getDerivative(pb[q])*v02 +
+            x.getDerivative(pa[p])*y.getDerivative(pb[q])*v11 +
+            y.getDerivative(pb[p])*x.getDerivative(pa[q])*v11
+          r.Hessian[q][p] = r.Hessian[p][q]
+        }
+      }
+    }
+    // compute first derivatives
+    for p := 0; p < k; p++ {
+      r.Derivative[p] = x.getDerivative(pa[p])*v10 + y.getDerivative(pb[p])*v01
+    }
+  } else {
+    r.resize(0)
+  }
+  if r != c {
+    c.N          = r.N
+    c.Order      = r.Order
+    c.Index      = r.Index
+    c.Derivative = r.Derivative
+    c.Hessian    = r.Hessian
+  }
+  // compute new value
+  c.setValue(v0)
+  return c
+}
+
+// Compute c = a + f(b) in place, where c and a share the same memory
+// and the variables of b are a subset of the variables of a (py are the
+// positions of the variables of b within the index of a). Only entries
+// that depend on b are updated.
+func (c *SparseReal) accumulate(x, y sparseOperand, py []int, v01, v11, v02 float64) {
+  if c.Order >= 2 {
+    // compute hessian
+    if v11 != 0.0 {
+      for q := 0; q < len(py); q++ {
+        for p := 0; p < len(x.index); p++ {
+          t := x.derivative[p]*y.derivative[q]*v11
+          c.Hessian[py[q]][p] += t
+          c.Hessian[p][py[q]] += t
+        }
+      }
+    }
+    for q1 := 0; q1 < len(py); q1++ {
+      for q2 := 0; q2 < len(py); q2++ {
+        c.Hessian[py[q1]][py[q2]] +=
+          y.getDerivative(q1)*y.getDerivative(q2)*v02 +
+          y.getHessian(q1, q2)*v01
+      }
+    }
+  }
+  // compute first derivatives
+  for q := 0; q < len(py); q++ {
+    c.Derivative[py[q]] += y.derivative[q]*v01
+  }
+}
+
+func (c *SparseReal) dyadicLazy(a, b ConstScalar, v0 float64, f1 func() (float64, float64), f2 func() (float64, float64, float64)) *SparseReal {
+  v10, v01, v11, v20, v02 := 0.0, 0.0, 0.0, 0.0, 0.0
+  if order := iMax(a.GetOrder(), b.GetOrder()); order >= 1 {
+    v10, v01 = f1()
+    if order >= 2 {
+      v11, v20, v02 = f2()
+    }
+  }
+  return c.dyadic(a, b, v0, v10, v01, v11, v20, v02)
+}
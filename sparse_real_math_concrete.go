@@ -0,0 +1,222 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "math"
+
+//import "github.com/pbenner/autodiff/special"
+
+/* -------------------------------------------------------------------------- */
+
+func (a *SparseReal) EQUALS(b *SparseReal, epsilon float64) bool {
+  return math.Abs(a.GetValue() - b.GetValue()) < epsilon
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *SparseReal) GREATER(b *SparseReal) bool {
+  return a.GetValue() > b.GetValue()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *SparseReal) SMALLER(b *SparseReal) bool {
+  return a.GetValue() < b.GetValue()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *SparseReal) SIGN() int {
+  if a.GetValue() < 0.0 {
+    return -1
+  }
+  if a.GetValue() > 0.0 {
+    return  1
+  }
+  return 0
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *SparseReal) MIN(a, b *SparseReal) Scalar {
+  if a.GetValue() < b.GetValue() {
+    r.SET(a)
+  } else {
+    r.SET(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *SparseReal) MAX(a, b *SparseReal) Scalar {
+  if a.GetValue() > b.GetValue() {
+    r.SET(a)
+  } else {
+    r.SET(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *SparseReal) ABS(a *SparseReal) Scalar {
+  if a.SIGN() == -1 {
+    c.NEG(a)
+  } else {
+    c.SET(a)
+  }
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *SparseReal) NEG(a *SparseReal) *SparseReal {
+  x := a.GetValue()
+  return c.monadic(a, -x, -1, 0)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *SparseReal) ADD(a, b *SparseReal) *SparseReal {
+  x := a.GetValue()
+  y := b.GetValue()
+  return c.dyadic(a, b, x+y, 1, 1, 0, 0, 0)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *SparseReal) SUB(a, b *SparseReal) *SparseReal {
+  x := a.GetValue()
+  y := b.GetValue()
+  return c.dyadic(a, b, x-y, 1, -1, 0, 0, 0)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *SparseReal) MUL(a, b *SparseReal) *SparseReal {
+  x := a.GetValue()
+  y := b.GetValue()
+  return c.dyadic(a, b, x*y, y, x, 1, 0, 0)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *SparseReal) DIV(a, b *SparseReal) *SparseReal {
+  x := a.GetValue()
+  y := b.GetValue()
+  return c.dyadic(a, b, x/y, 1/y, -x/(y*y), -1/(y*y), 0, 2*x/(y*y*y))
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *SparseReal) LOGADD(a, b, t *SparseReal) *SparseReal {
+  if a.GREATER(b) {
+    // swap
+    a, b = b, a
+  }
+  if math.IsInf(a.GetValue(), 0) {
+    // cases:
+    //  i) a = -Inf and b >= a    => c = b
+    // ii) a =  Inf and b  = Inf  => c = Inf
+    c.SET(b)
+    return c
+  }
+  t.SUB(a, b)
+  t.EXP(t)
+  t.LOG1P(t)
+  c.ADD(t, b)
+  return c
+}
+
+func (c *SparseReal) LOGSUB(a, b, t *SparseReal) *SparseReal {
+  if math.IsInf(b.GetValue(), -1) {
+    c.SET(a)
+    return c
+  }
+  t.SUB(b, a)
+  t.EXP(t)
+  t.NEG(t)
+  t.LOG1P(t)
+  c.ADD(t, a)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *SparseReal) POW(a, k *SparseReal) *SparseReal {
+  x := a.GetValue()
+  y := k.GetValue()
+  v0 := math.Pow(x, y)
+  if k.GetOrder() >= 1 {
+    f1 := func() (float64, float64) {
+      f10 := math.Pow(x, y-1)*y
+      f01 := math.Pow(x, y-0)*math.Log(x)
+      return f10, f01
+    }
+    f2 := func() (float64, float64, float64) {
+      f11 := math.Pow(x, y-1)*(1 + y*math.Log(x))
+      f20 := math.Pow(x, y-2)*(y - 1)*y
+      f02 := math.Pow(x, y-0)*math.Log(x)*math.Log(x)
+      return f11, f20, f02
+    }
+    return c.dyadicLazy(a, k, v0, f1, f2)
+  } else {
+    f1 := func() (float64) {
+      return math.Pow(x, y-1)*y
+    }
+    f2 := func() (float64) {
+      return math.Pow(x, y-2)*(y - 1)*y
+    }
+    return c.monadicLazy(a, v0, f1, f2)
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *SparseReal) SQRT(a *SparseReal) *SparseReal {
+  return c.POW(a, NewSparseReal(0.5))
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *SparseReal) EXP(a *SparseReal) *SparseReal {
+  x := a.GetValue()
+  v0 := math.Exp(x)
+  f1 := func() float64 { return v0 }
+  f2 := func() float64 { return v0 }
+  return c.monadicLazy(a, v0, f1, f2)
+}
+
+func (c *SparseReal) LOG(a *SparseReal) *SparseReal {
+  x := a.GetValue()
+  v0 :=  math.Log(x)
+  f1 := func() float64 { return  1/x }
+  f2 := func() float64 { return -1/(x*x) }
+  return c.monadicLazy(a, v0, f1, f2)
+}
+
+func (c *SparseReal) LOG1P(a *SparseReal) *SparseReal {
+  x := a.GetValue()
+  v0 :=  math.Log1p(x)
+  f1 := func() float64 { return  1/ (1+x) }
+  f2 := func() float64 { return -1/((1+x)*(1+x)) }
+  return c.monadicLazy(a, v0, f1, f2)
+}
@@ -0,0 +1,180 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "encoding/json"
+import "math"
+import "testing"
+
+/* -------------------------------------------------------------------------- */
+
+func TestSparseReal1(t *testing.T) {
+
+  a := NewSparseReal(13.123)
+  b := NewSparseReal( 4.321)
+
+  Variables(2, a, b)
+
+  a.Mul(a, a) // a^2
+  a.Mul(a, a) // a^4
+  a.Mul(a, b) // a^4 b
+
+  if math.Abs(a.GetValue() - 128149.4603376) > 1e-4 {
+    t.Error("test failed")
+  }
+  if math.Abs(a.GetDerivative(0) - 39061.025783) > 1e-4 {
+    t.Error("test failed")
+  }
+  if math.Abs(a.GetDerivative(1) - 29657.361800) > 1e-4 {
+    t.Error("test failed")
+  }
+  if math.Abs(a.GetHessian(0, 0) - 8929.595165) > 1e-4 {
+    t.Error("test failed")
+  }
+  if math.Abs(a.GetHessian(0, 1) - 9039.811567) > 1e-4 {
+    t.Error("test failed")
+  }
+}
+
+func TestSparseReal2(t *testing.T) {
+
+  f := func(x ConstVector, r Scalar) Scalar {
+    t := NullScalar(r.Type())
+    r.Reset()
+    for i := 0; i < x.Dim(); i++ {
+      t.Sin(x.ConstAt(i))
+      t.Mul(t, x.ConstAt((i+1) % x.Dim()))
+      t.Exp(t)
+      r.Add(r, t)
+      t.Lgamma(x.ConstAt(i))
+      r.Sub(t, r)
+      t.Log1pExp(x.ConstAt(i))
+      r.Mul(r, t)
+    }
+    return r.Div(r, x.ConstAt(0))
+  }
+  x1 := NewVector(RealType,       []float64{1.1, 2.3, 0.7, 4.1, 3.3})
+  x2 := NewVector(SparseRealType, []float64{1.1, 2.3, 0.7, 4.1, 3.3})
+  x1.Variables(2)
+  x2.Variables(2)
+
+  r1 := f(x1, NullReal())
+  r2 := f(x2, NullSparseReal())
+
+  if math.Abs(r1.GetValue() - r2.GetValue()) > 1e-12 {
+    t.Error("test failed")
+  }
+  if r1.GetN() != r2.GetN() {
+    t.Error("test failed")
+  }
+  for i := 0; i < x1.Dim(); i++ {
+    if math.Abs(r1.GetDerivative(i) - r2.GetDerivative(i)) > 1e-10 {
+      t.Error("test failed")
+    }
+    for j := 0; j < x1.Dim(); j++ {
+      if math.Abs(r1.GetHessian(i, j) - r2.GetHessian(i, j)) > 1e-10 {
+        t.Error("test failed")
+      }
+    }
+  }
+}
+
+func TestSparseReal3(t *testing.T) {
+
+  n := 10000
+  x := NullVector(SparseRealType, n)
+  x.Variables(2)
+
+  // each term depends only on two variables
+  s := NullSparseReal()
+  r := NullSparseReal()
+  for i := 0; i < n; i += 2 {
+    s.Mul(x.At(i), x.At(i+1))
+    if len(s.Index) != 2 {
+      t.Error("test failed")
+    }
+    if i == 0 {
+      r.Set(s)
+    }
+  }
+  // sum over the first 100 variables
+  for i := 2; i < 100; i += 2 {
+    s.Mul(x.At(i), x.At(i+1))
+    r.Add(r, s)
+  }
+  if len(r.Index) != 100 {
+    t.Error("test failed")
+  }
+  if r.GetN() != n {
+    t.Error("test failed")
+  }
+  if r.GetHessian(4, 5) != 1.0 || r.GetHessian(4, 6) != 0.0 {
+    t.Error("test failed")
+  }
+}
+
+func TestSparseReal4(t *testing.T) {
+
+  // accumulate in place
+  x := NewVector(SparseRealType, []float64{1, 2, 3})
+  x.Variables(2)
+
+  r := NullSparseReal()
+  r.Add(x.At(0), x.At(1))
+  r.Add(r, x.At(2))
+  r.Mul(r, r)
+  r.Sub(r, x.At(1))
+
+  // r = (x0 + x1 + x2)^2 - x1
+  d := []float64{12, 11, 12}
+  for i := 0; i < 3; i++ {
+    if r.GetDerivative(i) != d[i] {
+      t.Error("test failed")
+    }
+    for j := 0; j < 3; j++ {
+      if r.GetHessian(i, j) != 2.0 {
+        t.Error("test failed")
+      }
+    }
+  }
+}
+
+func TestSparseReal5(t *testing.T) {
+
+  a := NewSparseReal(2.0)
+  a.SetVariable(0, 4, 1)
+  a.Exp(a)
+  a.SetDerivative(3, 2.0)
+
+  b := NullSparseReal()
+  if s, err := json.Marshal(a); err != nil {
+    t.Error(err)
+  } else {
+    if err := json.Unmarshal(s, b); err != nil {
+      t.Error(err)
+    }
+  }
+  if !b.Equals(a, 1e-12) {
+    t.Error("test failed")
+  }
+  if b.GetDerivative(0) != a.GetDerivative(0) || b.GetDerivative(3) != 2.0 {
+    t.Error("test failed")
+  }
+}
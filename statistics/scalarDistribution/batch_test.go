@@ -0,0 +1,67 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package scalarDistribution
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+
+/* -------------------------------------------------------------------------- */
+
+func TestBatchLogPdf(t *testing.T) {
+  x := []float64{-1.0, 0.3, 2.2, 4.0}
+
+  mu    := NewBatchReal(3.0)
+  sigma := NewBatchReal(math.Sqrt(2.0))
+  Variables(1, mu, sigma)
+
+  normal, _ := NewNormalDistribution(mu, sigma)
+
+  // evaluate all observations at once
+  r := NullBatchReal()
+  if err := normal.LogPdf(r, NewBatchRealFromSlice(x)); err != nil {
+    t.Error(err)
+    return
+  }
+  if r.BatchSize() != len(x) {
+    t.Error("test failed")
+    return
+  }
+  for i := 0; i < len(x); i++ {
+    mu    := NewReal(3.0)
+    sigma := NewReal(math.Sqrt(2.0))
+    Variables(1, mu, sigma)
+
+    normal, _ := NewNormalDistribution(mu, sigma)
+
+    s := NullReal()
+    normal.LogPdf(s, ConstReal(x[i]))
+
+    if math.Abs(r.GetBatchValue(i) - s.GetValue()) > 1e-12 {
+      t.Error("test failed")
+    }
+    for j := 0; j < 2; j++ {
+      if math.Abs(r.GetBatchDerivative(i, j) - s.GetDerivative(j)) > 1e-12 {
+        t.Error("test failed")
+      }
+    }
+  }
+}
@@ -18,7 +18,7 @@ package scalarEstimator
 
 /* -------------------------------------------------------------------------- */
 
-//import   "fmt"
+import   "fmt"
 import   "math"
 
 import . "github.com/pbenner/autodiff/statistics"
@@ -90,6 +90,15 @@ func (obj *NumericEstimator) Estimate(gamma ConstVector, p ThreadPool) error {
   x  := obj.x
   n  := obj.n
   m  := obj.x.Dim()
+  // use the scalar type of the density function for computing
+  // derivatives, unless it cannot carry derivatives
+  st := obj.ScalarPdf.ScalarType()
+  if DerivativeOrder(st) == 0 {
+    st = RealType
+  }
+  if obj.Method == "newton" && DerivativeOrder(st) < 2 {
+    return fmt.Errorf("Newton's method requires second order derivatives, which are not supported by scalar type `%v'", st)
+  }
   // create a copy of the density function
   f := make([]ScalarPdf, nt)
   for i := 0; i < len(f); i++ {
@@ -104,9 +113,9 @@ func (obj *NumericEstimator) Estimate(gamma ConstVector, p ThreadPool) error {
   // define the objective function
   objective_f := func(variables Vector) (Scalar, error) {
     // temporary variable
-    t := NullVector(RealType, nt)
-    s := NullVector(RealType, nt)
-    r := NullVector(RealType, nt)
+    t := NullVector(st, nt)
+    s := NullVector(st, nt)
+    r := NullVector(st, nt)
     for i := 0; i < len(f); i++ {
       if err := f[i].SetParameters(variables); err != nil {
         return nil, err
@@ -154,9 +163,9 @@ func (obj *NumericEstimator) Estimate(gamma ConstVector, p ThreadPool) error {
     return r.At(0), nil
   }
   // get parameters of the density function and convert
-  // the scalar type to real
+  // the scalar type
   theta_0 := obj.ScalarPdf.GetParameters()
-  theta_0  = AsVector(RealType, theta_0)
+  theta_0  = AsVector(st, theta_0)
 
   var theta_n ConstVector
   var err error
@@ -0,0 +1,63 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package scalarEstimator
+
+/* -------------------------------------------------------------------------- */
+
+//import   "fmt"
+import   "math"
+import   "testing"
+
+import . "github.com/pbenner/autodiff"
+import   "github.com/pbenner/autodiff/statistics/scalarDistribution"
+import   "github.com/pbenner/threadpool"
+
+/* -------------------------------------------------------------------------- */
+
+func TestNumericEstimator(t *testing.T) {
+  x := NewVector(RealType, []float64{
+    2.1, 0.4, 1.7, 3.2, 1.1, 2.6, 0.9, 1.5, 2.3, 1.8 })
+
+  newEstimator := func(mu, sigma Scalar, method string) *NumericEstimator {
+    d, err := scalarDistribution.NewNormalDistribution(mu, sigma)
+    if err != nil {
+      t.Fatal(err)
+    }
+    e, err := NewNumericEstimator(d)
+    if err != nil {
+      t.Fatal(err)
+    }
+    e.Method = method
+    return e
+  }
+  p := threadpool.New(1, 100)
+  // ReverseReal only supports first order derivatives
+  if err := newEstimator(NewReverseReal(1.0), NewReverseReal(2.0), "newton").EstimateOnData(x, nil, p); err == nil {
+    t.Error("test failed")
+  }
+  for _, e := range []*NumericEstimator{
+    newEstimator(NewReverseReal(1.0), NewReverseReal(2.0), "bfgs"),
+    newEstimator(NewBareReal32 (1.0), NewBareReal32 (2.0), "newton") } {
+    if err := e.EstimateOnData(x, nil, p); err != nil {
+      t.Error(err); continue
+    }
+    r := e.GetParameters()
+    if math.Abs(r.ValueAt(0) - 1.76) > 1e-3 {
+      t.Error("test failed")
+    }
+  }
+}
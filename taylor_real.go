@@ -0,0 +1,302 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+import "fmt"
+import "encoding/json"
+import "reflect"
+import "math"
+
+import "github.com/pbenner/autodiff/special"
+
+/* -------------------------------------------------------------------------- */
+
+// TaylorReal is a scalar that stores the truncated Taylor series
+//   f(x + t) = c_0 + c_1 t + c_2 t^2 + ... + c_K t^K
+// of a function f of a single variable x, where K is an arbitrary order.
+// The k-th derivative of f is given by k! c_k. Derivatives along a
+// direction v of several variables are obtained by setting the first
+// coefficient of each variable to the respective element of v.
+//
+// Scalars of other types are treated as constants, unless they depend
+// on a single variable, in which case their first and second derivatives
+// are used.
+type TaylorReal struct {
+  Order   int
+  // Taylor coefficients c_0, ..., c_K
+  Taylor []float64
+}
+
+/* register scalar type
+ * -------------------------------------------------------------------------- */
+
+var TaylorRealType ScalarType = NewTaylorReal(0.0).Type()
+
+func init() {
+  f := func(value float64) Scalar { return NewTaylorReal(value) }
+  RegisterScalar(TaylorRealType, f)
+}
+
+/* constructors
+ * -------------------------------------------------------------------------- */
+
+func NewTaylorReal(v float64) *TaylorReal {
+  return &TaylorReal{Order: 0, Taylor: []float64{v}}
+}
+
+func NullTaylorReal() *TaylorReal {
+  return NewTaylorReal(0.0)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *TaylorReal) Clone() *TaylorReal {
+  r := NewTaylorReal(0.0)
+  r.SET(a)
+  return r
+}
+
+func (a *TaylorReal) CloneScalar() Scalar {
+  return a.Clone()
+}
+
+func (a *TaylorReal) Type() ScalarType {
+  return reflect.TypeOf(a)
+}
+
+func (a *TaylorReal) ConvertType(t ScalarType) Scalar {
+  switch t {
+  case TaylorRealType:
+    return a
+  case RealType:
+    return NewReal(a.GetValue())
+  case BareRealType:
+    return NewBareReal(a.GetValue())
+  case ReverseRealType:
+    return NewReverseReal(a.GetValue())
+  case SparseRealType:
+    return NewSparseReal(a.GetValue())
+  case DualRealType:
+    return NewDualReal(a.GetValue())
+  case ComplexType:
+    return NewComplex(a.GetValue())
+  case BareReal32Type:
+    return NewBareReal32(a.GetValue())
+  case IntervalType:
+    return NewInterval(a.GetValue())
+  case BigRealType:
+    return NewBigReal(a.GetValue())
+  case BatchRealType:
+    return NewBatchReal(a.GetValue())
+  default:
+    panic(fmt.Sprintf("cannot convert `TaylorReal' to type `%v'", t))
+  }
+}
+
+/* -------------------------------------------------------------------------- */
+
+// Allocate memory for Taylor coefficients up to the given order. The
+// number of variables is ignored.
+func (a *TaylorReal) Alloc(n, order int) {
+  if a.Order != order {
+    t := make([]float64, order+1)
+    t[0] = a.Taylor[0]
+    a.Order  = order
+    a.Taylor = t
+  }
+}
+
+func (c *TaylorReal) AllocForOne(a ConstScalar) {
+  c.Alloc(1, taylorOrderOf(a))
+}
+
+func (c *TaylorReal) AllocForTwo(a, b ConstScalar) {
+  c.Alloc(1, iMax(taylorOrderOf(a), taylorOrderOf(b)))
+}
+
+/* read access
+ * -------------------------------------------------------------------------- */
+
+// Returns the order K of the Taylor series.
+func (a *TaylorReal) GetOrder() int {
+  return a.Order
+}
+
+func (a *TaylorReal) GetValue() float64 {
+  return a.Taylor[0]
+}
+
+func (a *TaylorReal) GetLogValue() float64 {
+  return math.Log(a.Taylor[0])
+}
+
+// Returns the first derivative if i is zero and zero otherwise.
+func (a *TaylorReal) GetDerivative(i int) float64 {
+  if i != 0 {
+    return 0.0
+  }
+  return a.GetTaylorCoefficient(1)
+}
+
+// Returns the second derivative if i and j are zero and zero otherwise.
+func (a *TaylorReal) GetHessian(i, j int) float64 {
+  if i != 0 || j != 0 {
+    return 0.0
+  }
+  return 2.0*a.GetTaylorCoefficient(2)
+}
+
+// Number of variables, which is one if derivatives are computed.
+func (a *TaylorReal) GetN() int {
+  if a.Order > 0 {
+    return 1
+  } else {
+    return 0
+  }
+}
+
+// Returns the k-th Taylor coefficient c_k.
+func (a *TaylorReal) GetTaylorCoefficient(k int) float64 {
+  if k <= a.Order {
+    return a.Taylor[k]
+  } else {
+    return 0.0
+  }
+}
+
+// Returns the k-th derivative k! c_k.
+func (a *TaylorReal) GetDerivativeOfOrder(k int) float64 {
+  return special.Factorial(k)*a.GetTaylorCoefficient(k)
+}
+
+/* write access
+ * -------------------------------------------------------------------------- */
+
+func (a *TaylorReal) Reset() {
+  for k := 0; k <= a.Order; k++ {
+    a.Taylor[k] = 0.0
+  }
+}
+
+func (a *TaylorReal) ResetDerivatives() {
+  for k := 1; k <= a.Order; k++ {
+    a.Taylor[k] = 0.0
+  }
+}
+
+// Set the state to b. This includes the value and all derivatives.
+func (a *TaylorReal) Set(b ConstScalar) {
+  if r, ok := b.(*TaylorReal); ok {
+    a.SET(r)
+  } else {
+    a.setTaylor(taylorOf(b, taylorOrderOf(b)))
+  }
+}
+
+func (a *TaylorReal) SET(b *TaylorReal) {
+  a.Alloc(1, b.Order)
+  copy(a.Taylor, b.Taylor)
+}
+
+// Set the value of the variable. All derivatives are reset to zero.
+func (a *TaylorReal) SetValue(v float64) {
+  a.Taylor[0] = v
+  a.ResetDerivatives()
+}
+
+func (a *TaylorReal) setValue(v float64) {
+  a.Taylor[0] = v
+}
+
+// Set the first derivative to v. The index i must be zero.
+func (a *TaylorReal) SetDerivative(i int, v float64) {
+  if i != 0 {
+    panic("index out of range")
+  }
+  a.SetTaylorCoefficient(1, v)
+}
+
+// Set the second derivative to v. Both indices must be zero.
+func (a *TaylorReal) SetHessian(i, j int, v float64) {
+  if i != 0 || j != 0 {
+    panic("index out of range")
+  }
+  a.SetTaylorCoefficient(2, v/2.0)
+}
+
+// Set the k-th Taylor coefficient c_k to v.
+func (a *TaylorReal) SetTaylorCoefficient(k int, v float64) {
+  a.Taylor[k] = v
+}
+
+// Mark this scalar as variable with Taylor coefficients up to the given
+// order. Only a single variable is supported.
+func (a *TaylorReal) SetVariable(i, n, order int) error {
+  if n != 1 {
+    return fmt.Errorf("type `TaylorReal' supports only a single variable")
+  }
+  a.Alloc(n, order)
+  a.ResetDerivatives()
+  if order > 0 {
+    a.Taylor[1] = 1.0
+  }
+  return nil
+}
+
+func (a *TaylorReal) setTaylor(t []float64) {
+  a.Order  = len(t)-1
+  a.Taylor = t
+}
+
+/* type conversion
+ * -------------------------------------------------------------------------- */
+
+func (a *TaylorReal) String() string {
+  return fmt.Sprintf("%e", a.GetValue())
+}
+
+/* json
+ * -------------------------------------------------------------------------- */
+
+func (obj *TaylorReal) MarshalJSON() ([]byte, error) {
+  if obj.Order > 0 {
+    r := struct{Taylor []float64}{obj.Taylor}
+    return json.Marshal(r)
+  } else {
+    return json.Marshal(obj.Taylor[0])
+  }
+}
+
+func (obj *TaylorReal) UnmarshalJSON(data []byte) error {
+  r := struct{Taylor []float64}{}
+  if err := json.Unmarshal(data, &r); err == nil {
+    if len(r.Taylor) == 0 {
+      return fmt.Errorf("invalid json scalar representation")
+    }
+    obj.setTaylor(r.Taylor)
+    return nil
+  } else {
+    v := 0.0
+    if err := json.Unmarshal(data, &v); err != nil {
+      return err
+    }
+    obj.setTaylor([]float64{v})
+    return nil
+  }
+}
@@ -0,0 +1,81 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+
+/* -------------------------------------------------------------------------- */
+
+// Order of the Taylor series of a. Scalars other than TaylorReal have a
+// series of order at most two if they depend on a single variable and are
+// constant otherwise.
+func taylorOrderOf(a ConstScalar) int {
+  if r, ok := a.(*TaylorReal); ok {
+    return r.Order
+  }
+  if a.GetN() == 1 {
+    return iMin(a.GetOrder(), 2)
+  }
+  return 0
+}
+
+// Returns a new slice with the Taylor coefficients of a up to the given
+// order.
+func taylorOf(a ConstScalar, order int) []float64 {
+  r := make([]float64, order+1)
+  if t, ok := a.(*TaylorReal); ok {
+    copy(r, t.Taylor)
+    return r
+  }
+  r[0] = a.GetValue()
+  if a.GetN() == 1 {
+    if order >= 1 && a.GetOrder() >= 1 {
+      r[1] = a.GetDerivative(0)
+    }
+    if order >= 2 && a.GetOrder() >= 2 {
+      r[2] = a.GetHessian(0, 0)/2.0
+    }
+  }
+  return r
+}
+
+/* series of monadic and dyadic functions
+ * -------------------------------------------------------------------------- */
+
+// Compute c = f(a), where f operates on Taylor series. The result slice
+// is allocated before f is called, so that c may be identical to a.
+func (c *TaylorReal) monadic(a ConstScalar, f func(r, a []float64)) *TaylorReal {
+  ta := taylorOf(a, taylorOrderOf(a))
+  r  := make([]float64, len(ta))
+  f(r, ta)
+  c.setTaylor(r)
+  return c
+}
+
+// Compute c = f(a, b), where f operates on Taylor series. Both series are
+// truncated at the larger order.
+func (c *TaylorReal) dyadic(a, b ConstScalar, f func(r, a, b []float64)) *TaylorReal {
+  n  := iMax(taylorOrderOf(a), taylorOrderOf(b))
+  ta := taylorOf(a, n)
+  tb := taylorOf(b, n)
+  r  := make([]float64, n+1)
+  f(r, ta, tb)
+  c.setTaylor(r)
+  return c
+}
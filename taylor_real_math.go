@@ -0,0 +1,539 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "math"
+
+import "github.com/pbenner/autodiff/special"
+
+/* -------------------------------------------------------------------------- */
+
+func (a *TaylorReal) Equals(b ConstScalar, epsilon float64) bool {
+  return math.Abs(a.GetValue() - b.GetValue()) < epsilon
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *TaylorReal) Greater(b ConstScalar) bool {
+  return a.GetValue() > b.GetValue()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *TaylorReal) Smaller(b ConstScalar) bool {
+  return a.GetValue() < b.GetValue()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *TaylorReal) Sign() int {
+  if a.GetValue() < 0.0 {
+    return -1
+  }
+  if a.GetValue() > 0.0 {
+    return  1
+  }
+  return 0
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *TaylorReal) Min(a, b ConstScalar) Scalar {
+  if a.GetValue() < b.GetValue() {
+    r.Set(a)
+  } else {
+    r.Set(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *TaylorReal) Max(a, b ConstScalar) Scalar {
+  if a.GetValue() > b.GetValue() {
+    r.Set(a)
+  } else {
+    r.Set(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Abs(a ConstScalar) Scalar {
+  switch a.Sign() {
+  case -1: c.Neg(a)
+  case  0: c.Reset()
+  case  1: c.Set(a)
+  }
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Neg(a ConstScalar) Scalar {
+  return c.monadic(a, func(r, a []float64) {
+    taylorScale(r, a, -1.0)
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Add(a, b ConstScalar) Scalar {
+  return c.dyadic(a, b, taylorAdd)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Sub(a, b ConstScalar) Scalar {
+  return c.dyadic(a, b, taylorSub)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Mul(a, b ConstScalar) Scalar {
+  return c.dyadic(a, b, taylorMul)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Div(a, b ConstScalar) Scalar {
+  return c.dyadic(a, b, taylorDiv)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) LogAdd(a, b ConstScalar, t Scalar) Scalar {
+  if a.Greater(b) {
+    // swap
+    a, b = b, a
+  }
+  if math.IsInf(a.GetValue(), 0) {
+    // cases:
+    //  i) a = -Inf and b >= a    => c = b
+    // ii) a =  Inf and b  = Inf  => c = Inf
+    c.Set(b)
+    return c
+  }
+  t.Sub(a, b)
+  t.Exp(t)
+  t.Log1p(t)
+  c.Add(t, b)
+  return c
+}
+
+func (c *TaylorReal) LogSub(a, b ConstScalar, t Scalar) Scalar {
+  if math.IsInf(b.GetValue(), -1) {
+    c.Set(a)
+    return c
+  }
+  //   log(exp(a) - exp(b))
+  // = log(1 - exp(b-a)) + a
+  t.Sub(b, a)
+  t.Exp(t)
+  t.Neg(t)
+  t.Log1p(t)
+  c.Add(t, a)
+  return c
+}
+
+func (c *TaylorReal) Log1pExp(a ConstScalar) Scalar {
+  v := a.GetValue()
+  if v <= -37.0 {
+    c.Exp(a)
+  } else
+  if v <=  18.0 {
+    c.Exp(a)
+    c.Log1p(c)
+  } else
+  if v <=  33.3 {
+    t := NullTaylorReal()
+    t.Neg(a)
+    t.Exp(t)
+    c.Add(a, t)
+  } else {
+    c.Set(a)
+  }
+  return c
+}
+
+func (c *TaylorReal) Sigmoid(a ConstScalar, t Scalar) Scalar {
+  if a.GetValue() >= 0 {
+    c.Neg(a)
+    c.Exp(c)
+    c.Add(c, ConstReal(1.0))
+    c.Div(ConstReal(1.0), c)
+  } else {
+    t.Exp(a)
+    c.Set(t)
+    t.Add(t, ConstReal(1.0))
+    c.Div(c, t)
+  }
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Pow(a, k ConstScalar) Scalar {
+  if taylorOrderOf(k) == 0 {
+    y := k.GetValue()
+    return c.monadic(a, func(r, a []float64) {
+      taylorPow(r, a, y)
+    })
+  }
+  // a^k = exp(k log a)
+  return c.dyadic(a, k, func(r, a, k []float64) {
+    t := make([]float64, len(r))
+    taylorLog(r, a)
+    taylorMul(t, r, k)
+    taylorExp(r, t)
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Sqrt(a ConstScalar) Scalar {
+  return c.Pow(a, ConstReal(1.0/2.0))
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Sin(a ConstScalar) Scalar {
+  return c.monadic(a, func(r, a []float64) {
+    taylorSinCos(r, make([]float64, len(r)), a)
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Sinh(a ConstScalar) Scalar {
+  return c.monadic(a, func(r, a []float64) {
+    taylorSinhCosh(r, make([]float64, len(r)), a)
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Cos(a ConstScalar) Scalar {
+  return c.monadic(a, func(r, a []float64) {
+    taylorSinCos(make([]float64, len(r)), r, a)
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Cosh(a ConstScalar) Scalar {
+  return c.monadic(a, func(r, a []float64) {
+    taylorSinhCosh(make([]float64, len(r)), r, a)
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Tan(a ConstScalar) Scalar {
+  return c.monadic(a, func(r, a []float64) {
+    s := make([]float64, len(r))
+    t := make([]float64, len(r))
+    taylorSinCos(s, t, a)
+    taylorDiv(r, s, t)
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Tanh(a ConstScalar) Scalar {
+  return c.monadic(a, func(r, a []float64) {
+    s := make([]float64, len(r))
+    t := make([]float64, len(r))
+    taylorSinhCosh(s, t, a)
+    taylorDiv(r, s, t)
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Exp(a ConstScalar) Scalar {
+  return c.monadic(a, taylorExp)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Log(a ConstScalar) Scalar {
+  return c.monadic(a, taylorLog)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) Log1p(a ConstScalar) Scalar {
+  return c.monadic(a, func(r, a []float64) {
+    x := a[0]
+    a[0] += 1.0
+    taylorLog(r, a)
+    r[0] = math.Log1p(x)
+  })
+}
+
+func (c *TaylorReal) Logistic(a ConstScalar) Scalar {
+  c.Neg(a)
+  c.Exp(c)
+  c.Add(ConstReal(1.0), c)
+  c.Div(ConstReal(1.0), c)
+  return c
+}
+
+// Series of the Gaussian exp(-a^2 + b), scaled by s.
+func taylorGaussian(r, a []float64, b, s float64) {
+  t := make([]float64, len(r))
+  taylorMul(t, a, a)
+  taylorScale(t, t, -1.0)
+  t[0] += b
+  taylorExp(r, t)
+  taylorScale(r, r, s)
+}
+
+func (c *TaylorReal) Erf(a ConstScalar) Scalar {
+  return c.monadic(a, func(r, a []float64) {
+    g := make([]float64, len(r))
+    taylorGaussian(g, a, 0.0, 2.0/special.M_SQRTPI)
+    taylorIntegrate(r, a, g, math.Erf(a[0]))
+  })
+}
+
+func (c *TaylorReal) Erfc(a ConstScalar) Scalar {
+  return c.monadic(a, func(r, a []float64) {
+    g := make([]float64, len(r))
+    taylorGaussian(g, a, 0.0, -2.0/special.M_SQRTPI)
+    taylorIntegrate(r, a, g, math.Erfc(a[0]))
+  })
+}
+
+func (c *TaylorReal) LogErfc(a ConstScalar) Scalar {
+  return c.monadic(a, func(r, a []float64) {
+    v0 := special.LogErfc(a[0])
+    // compute the series of q = erfc(a)/erfc(a_0), which is
+    // numerically stable for large a_0
+    g := make([]float64, len(r))
+    q := make([]float64, len(r))
+    taylorGaussian(g, a, -v0, -2.0/special.M_SQRTPI)
+    taylorIntegrate(q, a, g, 1.0)
+    taylorLog(r, q)
+    r[0] = v0
+  })
+}
+
+func (c *TaylorReal) Gamma(a ConstScalar) Scalar {
+  return c.monadic(a, func(r, a []float64) {
+    v0, s := math.Lgamma(a[0])
+    // Gamma(a) = sign * exp(lgamma(a))
+    t := make([]float64, len(r))
+    taylorCompose(t, a, taylorPolygammaSeries(a[0], len(r), v0))
+    taylorExp(r, t)
+    taylorScale(r, r, float64(s))
+  })
+}
+
+func (c *TaylorReal) Lgamma(a ConstScalar) Scalar {
+  return c.monadic(a, func(r, a []float64) {
+    v0, s := math.Lgamma(a[0])
+    if s == -1 {
+      v0 = math.NaN()
+    }
+    taylorCompose(r, a, taylorPolygammaSeries(a[0], len(r), v0))
+  })
+}
+
+func (c *TaylorReal) Mlgamma(a ConstScalar, k int) Scalar {
+  return c.monadic(a, func(r, a []float64) {
+    df := make([]float64, len(r))
+    for j := 1; j <= k; j++ {
+      t := taylorPolygammaSeries(a[0] + float64(1-j)/2.0, len(r), 0.0)
+      for m := 1; m < len(r); m++ {
+        df[m] += t[m]
+      }
+    }
+    df[0] = special.Mlgamma(a[0], k)
+    taylorCompose(r, a, df)
+  })
+}
+
+// Derivatives of lgamma at x up to order n-1, where v0 is used as value.
+func taylorPolygammaSeries(x float64, n int, v0 float64) []float64 {
+  df := make([]float64, n)
+  df[0] = v0
+  for m := 1; m < n; m++ {
+    switch m {
+    case 1:
+      df[m] = special.Digamma(x)
+    case 2:
+      df[m] = special.Trigamma(x)
+    default:
+      df[m] = special.Polygamma(m-1, x)
+    }
+  }
+  return df
+}
+
+func (c *TaylorReal) GammaP(a float64, b ConstScalar) Scalar {
+  return c.monadic(b, func(r, b []float64) {
+    // derivative of GammaP: b^(a-1) exp(-b) / Gamma(a)
+    g := make([]float64, len(r))
+    t := make([]float64, len(r))
+    l, _ := math.Lgamma(a)
+    taylorLog(t, b)
+    taylorScale(t, t, a-1.0)
+    taylorSub(g, t, b)
+    g[0] -= l
+    taylorExp(t, g)
+    taylorIntegrate(r, b, t, special.GammaP(a, b[0]))
+  })
+}
+
+func (c *TaylorReal) BesselI(v float64, b ConstScalar) Scalar {
+  return c.monadic(b, func(r, b []float64) {
+    x  := b[0]
+    df := make([]float64, len(r))
+    // d^m/dx^m I_v(x) = 2^-m sum_j binom(m, j) I_{v-m+2j}(x)
+    for m := 0; m < len(r); m++ {
+      z := 1.0
+      for j := 0; j <= m; j++ {
+        df[m] += z*special.BesselI(v-float64(m-2*j), x)
+        z *= float64(m-j)/float64(j+1)
+      }
+      df[m] /= math.Pow(2.0, float64(m))
+    }
+    taylorCompose(r, b, df)
+  })
+}
+
+func (c *TaylorReal) LogBesselI(v float64, b ConstScalar) Scalar {
+  return c.monadic(b, func(r, b []float64) {
+    x  := b[0]
+    v0 := special.LogBesselI(v, x)
+    df := make([]float64, len(r))
+    // compute the series of q = I_v(b)/I_v(b_0)
+    for m := 0; m < len(r); m++ {
+      z := 1.0
+      for j := 0; j <= m; j++ {
+        df[m] += z*math.Exp(special.LogBesselI(v-float64(m-2*j), x) - v0)
+        z *= float64(m-j)/float64(j+1)
+      }
+      df[m] /= math.Pow(2.0, float64(m))
+    }
+    q := make([]float64, len(r))
+    taylorCompose(q, b, df)
+    taylorLog(r, q)
+    r[0] = v0
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *TaylorReal) SmoothMax(x ConstVector, alpha ConstReal, t [2]Scalar) Scalar {
+  r   .Reset()
+  t[1].Reset()
+  for i := 0; i < x.Dim(); i++ {
+    t[0].Mul(alpha, x.ConstAt(i))
+    t[0].Exp(t[0])
+    t[1].Add(t[1], t[0])
+    t[0].Mul(t[0], x.ConstAt(i))
+    r .Add(r , t[0])
+  }
+  r.Div(r, t[1])
+  return r
+}
+
+func (r *TaylorReal) LogSmoothMax(x ConstVector, alpha ConstReal, t [3]Scalar) Scalar {
+  r   .Reset()
+  t[2].SetValue(math.Inf(-1))
+  for i := 0; i < x.Dim(); i++ {
+    t[0].Mul(x.ConstAt(i), alpha)
+    t[2].LogAdd(t[2], t[0], t[1])
+    t[1].Log(x.ConstAt(i))
+    t[0].Add(t[0], t[1])
+    r.LogAdd(r, t[0], t[1])
+  }
+  r.Sub(r, t[2])
+  r.Exp(r)
+  return r
+}
+
+func (r *TaylorReal) Vmean(a ConstVector) Scalar {
+  r.Reset()
+  for i := 0; i < a.Dim(); i++ {
+    r.Add(r, a.ConstAt(i))
+  }
+  return r.Div(r, ConstReal(float64(a.Dim())))
+}
+
+func (r *TaylorReal) VdotV(a, b ConstVector) Scalar {
+  if a.Dim() != b.Dim() {
+    panic("vector dimensions do not match")
+  }
+  r.Reset()
+  t := NullTaylorReal()
+  for i := 0; i < a.Dim(); i++ {
+    t.Mul(a.ConstAt(i), b.ConstAt(i))
+    r.Add(r, t)
+  }
+  return r
+}
+
+func (r *TaylorReal) Vnorm(a ConstVector) Scalar {
+  r.Reset()
+  t := NewTaylorReal(0.0)
+  for it := a.ConstIterator(); it.Ok(); it.Next() {
+    t.Pow(it.GetConst(), ConstReal(2.0))
+    r.Add(r, t)
+  }
+  r.Sqrt(r)
+  return r
+}
+
+func (r *TaylorReal) Mtrace(a ConstMatrix) Scalar {
+  n, m := a.Dims()
+  if n != m {
+    panic("not a square matrix")
+  }
+  if n == 0 {
+    return nil
+  }
+  r.Reset()
+  for i := 0; i < n; i++ {
+    r.Add(r, a.ConstAt(i,i))
+  }
+  return r
+}
+
+// Frobenius norm.
+func (r *TaylorReal) Mnorm(a ConstMatrix) Scalar {
+  n, m := a.Dims()
+  if n == 0 || m == 0 {
+    return nil
+  }
+  c := ConstReal(2.0)
+  t := NewScalar(r.Type(), 0.0)
+  v := a.AsConstVector()
+  r.Pow(v.ConstAt(0), ConstReal(2.0))
+  for i := 1; i < v.Dim(); i++ {
+    t.Pow(v.ConstAt(i), c)
+    r.Add(r, t)
+  }
+  return r
+}
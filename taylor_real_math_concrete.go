@@ -0,0 +1,181 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "math"
+
+//import "github.com/pbenner/autodiff/special"
+
+/* -------------------------------------------------------------------------- */
+
+func (a *TaylorReal) EQUALS(b *TaylorReal, epsilon float64) bool {
+  return math.Abs(a.GetValue() - b.GetValue()) < epsilon
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *TaylorReal) GREATER(b *TaylorReal) bool {
+  return a.GetValue() > b.GetValue()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *TaylorReal) SMALLER(b *TaylorReal) bool {
+  return a.GetValue() < b.GetValue()
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (a *TaylorReal) SIGN() int {
+  if a.GetValue() < 0.0 {
+    return -1
+  }
+  if a.GetValue() > 0.0 {
+    return  1
+  }
+  return 0
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *TaylorReal) MIN(a, b *TaylorReal) Scalar {
+  if a.GetValue() < b.GetValue() {
+    r.SET(a)
+  } else {
+    r.SET(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (r *TaylorReal) MAX(a, b *TaylorReal) Scalar {
+  if a.GetValue() > b.GetValue() {
+    r.SET(a)
+  } else {
+    r.SET(b)
+  }
+  return r
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) ABS(a *TaylorReal) Scalar {
+  if a.SIGN() == -1 {
+    c.NEG(a)
+  } else {
+    c.SET(a)
+  }
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) NEG(a *TaylorReal) *TaylorReal {
+  return c.monadic(a, func(r, a []float64) {
+    taylorScale(r, a, -1.0)
+  })
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) ADD(a, b *TaylorReal) *TaylorReal {
+  return c.dyadic(a, b, taylorAdd)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) SUB(a, b *TaylorReal) *TaylorReal {
+  return c.dyadic(a, b, taylorSub)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) MUL(a, b *TaylorReal) *TaylorReal {
+  return c.dyadic(a, b, taylorMul)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) DIV(a, b *TaylorReal) *TaylorReal {
+  return c.dyadic(a, b, taylorDiv)
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) LOGADD(a, b, t *TaylorReal) *TaylorReal {
+  if a.GREATER(b) {
+    // swap
+    a, b = b, a
+  }
+  if math.IsInf(a.GetValue(), 0) {
+    // cases:
+    //  i) a = -Inf and b >= a    => c = b
+    // ii) a =  Inf and b  = Inf  => c = Inf
+    c.SET(b)
+    return c
+  }
+  t.SUB(a, b)
+  t.EXP(t)
+  t.LOG1P(t)
+  c.ADD(t, b)
+  return c
+}
+
+func (c *TaylorReal) LOGSUB(a, b, t *TaylorReal) *TaylorReal {
+  if math.IsInf(b.GetValue(), -1) {
+    c.SET(a)
+    return c
+  }
+  t.SUB(b, a)
+  t.EXP(t)
+  t.NEG(t)
+  t.LOG1P(t)
+  c.ADD(t, a)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) POW(a, k *TaylorReal) *TaylorReal {
+  c.Pow(a, k)
+  return c
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) SQRT(a *TaylorReal) *TaylorReal {
+  return c.POW(a, NewTaylorReal(0.5))
+}
+
+/* -------------------------------------------------------------------------- */
+
+func (c *TaylorReal) EXP(a *TaylorReal) *TaylorReal {
+  return c.monadic(a, taylorExp)
+}
+
+func (c *TaylorReal) LOG(a *TaylorReal) *TaylorReal {
+  return c.monadic(a, taylorLog)
+}
+
+func (c *TaylorReal) LOG1P(a *TaylorReal) *TaylorReal {
+  c.Log1p(a)
+  return c
+}
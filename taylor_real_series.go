@@ -0,0 +1,195 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "math"
+
+/* arithmetic on truncated Taylor series
+ * -------------------------------------------------------------------------- */
+
+// All functions in this file operate on truncated Taylor series a_0 + a_1 t +
+// ... + a_K t^K, stored as slices of length K+1. Results are stored in the
+// first argument, which must not share memory with any of the other
+// arguments. All arguments must have the same length.
+
+func taylorAdd(r, a, b []float64) {
+  for k := 0; k < len(r); k++ {
+    r[k] = a[k] + b[k]
+  }
+}
+
+func taylorSub(r, a, b []float64) {
+  for k := 0; k < len(r); k++ {
+    r[k] = a[k] - b[k]
+  }
+}
+
+func taylorScale(r, a []float64, c float64) {
+  for k := 0; k < len(r); k++ {
+    r[k] = c*a[k]
+  }
+}
+
+func taylorMul(r, a, b []float64) {
+  for k := 0; k < len(r); k++ {
+    r[k] = 0.0
+    for j := 0; j <= k; j++ {
+      r[k] += a[j]*b[k-j]
+    }
+  }
+}
+
+func taylorDiv(r, a, b []float64) {
+  for k := 0; k < len(r); k++ {
+    r[k] = a[k]
+    for j := 1; j <= k; j++ {
+      r[k] -= b[j]*r[k-j]
+    }
+    r[k] /= b[0]
+  }
+}
+
+func taylorExp(r, a []float64) {
+  r[0] = math.Exp(a[0])
+  for k := 1; k < len(r); k++ {
+    r[k] = 0.0
+    for j := 1; j <= k; j++ {
+      r[k] += float64(j)*a[j]*r[k-j]
+    }
+    r[k] /= float64(k)
+  }
+}
+
+func taylorLog(r, a []float64) {
+  r[0] = math.Log(a[0])
+  for k := 1; k < len(r); k++ {
+    r[k] = 0.0
+    for j := 1; j < k; j++ {
+      r[k] += float64(j)*r[j]*a[k-j]
+    }
+    r[k] = (a[k] - r[k]/float64(k))/a[0]
+  }
+}
+
+// Compute r = a^y for a constant exponent y.
+func taylorPow(r, a []float64, y float64) {
+  if len(r) > 1 && y >= 0.0 && y == math.Floor(y) && y < 1024 {
+    // use multiplications for integer powers, which is also
+    // well defined if a_0 is zero
+    taylorPowInt(r, a, int(y))
+    return
+  }
+  r[0] = math.Pow(a[0], y)
+  for k := 1; k < len(r); k++ {
+    r[k] = 0.0
+    for j := 1; j <= k; j++ {
+      r[k] += (y*float64(j) - float64(k-j))*a[j]*r[k-j]
+    }
+    r[k] /= float64(k)*a[0]
+  }
+}
+
+func taylorPowInt(r, a []float64, n int) {
+  p := make([]float64, len(r))
+  t := make([]float64, len(r))
+  copy(p, a)
+  // r = 1
+  for k := 0; k < len(r); k++ {
+    r[k] = 0.0
+  }
+  r[0] = 1.0
+  for ; n > 0; n >>= 1 {
+    if n & 1 == 1 {
+      taylorMul(t, r, p)
+      copy(r, t)
+    }
+    if n > 1 {
+      taylorMul(t, p, p)
+      copy(p, t)
+    }
+  }
+}
+
+func taylorSinCos(s, c, a []float64) {
+  s[0] = math.Sin(a[0])
+  c[0] = math.Cos(a[0])
+  for k := 1; k < len(s); k++ {
+    s[k] = 0.0
+    c[k] = 0.0
+    for j := 1; j <= k; j++ {
+      s[k] += float64(j)*a[j]*c[k-j]
+      c[k] -= float64(j)*a[j]*s[k-j]
+    }
+    s[k] /= float64(k)
+    c[k] /= float64(k)
+  }
+}
+
+func taylorSinhCosh(s, c, a []float64) {
+  s[0] = math.Sinh(a[0])
+  c[0] = math.Cosh(a[0])
+  for k := 1; k < len(s); k++ {
+    s[k] = 0.0
+    c[k] = 0.0
+    for j := 1; j <= k; j++ {
+      s[k] += float64(j)*a[j]*c[k-j]
+      c[k] += float64(j)*a[j]*s[k-j]
+    }
+    s[k] /= float64(k)
+    c[k] /= float64(k)
+  }
+}
+
+// Compute r = f(a), where f is given by its value r0 = f(a_0) and the
+// Taylor series g = f'(a) of its derivative.
+func taylorIntegrate(r, a, g []float64, r0 float64) {
+  r[0] = r0
+  for k := 1; k < len(r); k++ {
+    r[k] = 0.0
+    for j := 1; j <= k; j++ {
+      r[k] += float64(j)*a[j]*g[k-j]
+    }
+    r[k] /= float64(k)
+  }
+}
+
+// Compute r = f(a), where df[m] is the m-th derivative of f at a_0.
+func taylorCompose(r, a, df []float64) {
+  // d = a - a_0
+  d := make([]float64, len(r))
+  copy(d, a)
+  d[0] = 0.0
+  // p = d^m / m!
+  p := make([]float64, len(r))
+  t := make([]float64, len(r))
+  p[0] = 1.0
+  for k := 0; k < len(r); k++ {
+    r[k] = 0.0
+  }
+  for m := 0; m < len(r); m++ {
+    if m > 0 {
+      taylorMul(t, p, d)
+      taylorScale(p, t, 1.0/float64(m))
+    }
+    for k := m; k < len(r); k++ {
+      r[k] += df[m]*p[k]
+    }
+  }
+}
@@ -0,0 +1,188 @@
+/* Copyright (C) 2019 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autodiff
+
+/* -------------------------------------------------------------------------- */
+
+//import "fmt"
+import "math"
+import "testing"
+
+import "github.com/pbenner/autodiff/special"
+
+/* -------------------------------------------------------------------------- */
+
+type logBesselI interface {
+  LogBesselI(v float64, b ConstScalar) Scalar
+}
+
+/* -------------------------------------------------------------------------- */
+
+func TestTaylorReal1(t *testing.T) {
+
+  f := []func(r Scalar, x ConstScalar) Scalar{
+    func(r Scalar, x ConstScalar) Scalar { return r.Sin(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Cos(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Tan(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Sinh(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Cosh(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Tanh(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Exp(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Log(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Log1p(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Log1pExp(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Logistic(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Sqrt(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Pow(x, ConstReal(2.7)) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Pow(x, x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Erf(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.LogErfc(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Gamma(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Lgamma(x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.Mlgamma(x, 3) },
+    func(r Scalar, x ConstScalar) Scalar { return r.GammaP(2.5, x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.BesselI(1.5, x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.(logBesselI).LogBesselI(1.5, x) },
+    func(r Scalar, x ConstScalar) Scalar {
+      r.Mul(x, x)
+      r.Sin(r)
+      return r.Div(r, x)
+    },
+  }
+  for i, _ := range f {
+    x1 := NewReal      (1.7)
+    x2 := NewTaylorReal(1.7)
+    Variables(2, x1)
+    Variables(2, x2)
+    r1 := f[i](NullReal      (), x1)
+    r2 := f[i](NullTaylorReal(), x2)
+    if math.Abs(r1.GetValue() - r2.GetValue()) > 1e-8 {
+      t.Errorf("test %d failed", i)
+    }
+    if math.Abs(r1.GetDerivative(0) - r2.GetDerivative(0)) > 1e-8 {
+      t.Errorf("test %d failed", i)
+    }
+    if math.Abs(r1.GetHessian(0, 0) - r2.GetHessian(0, 0)) > 1e-8 {
+      t.Errorf("test %d failed", i)
+    }
+  }
+}
+
+func TestTaylorReal2(t *testing.T) {
+
+  x := NewTaylorReal(0.5)
+  Variables(6, x)
+
+  r := NullTaylorReal()
+  r.Exp(x)
+
+  for k := 0; k <= 6; k++ {
+    if math.Abs(r.GetTaylorCoefficient(k) - math.Exp(0.5)/special.Factorial(k)) > 1e-12 {
+      t.Error("test failed")
+    }
+  }
+  // x^5
+  r.Pow(x, ConstReal(5.0))
+
+  if math.Abs(r.GetDerivativeOfOrder(4) - 120.0*0.5) > 1e-10 {
+    t.Error("test failed")
+  }
+  if math.Abs(r.GetDerivativeOfOrder(5) - 120.0) > 1e-10 {
+    t.Error("test failed")
+  }
+  if r.GetDerivativeOfOrder(6) != 0.0 {
+    t.Error("test failed")
+  }
+}
+
+func TestTaylorReal3(t *testing.T) {
+
+  x := NewTaylorReal(2.3)
+  Variables(4, x)
+
+  r := NullTaylorReal()
+  r.Lgamma(x)
+
+  for k := 3; k <= 4; k++ {
+    if math.Abs(r.GetDerivativeOfOrder(k) - special.Polygamma(k-1, 2.3)) > 1e-10 {
+      t.Error("test failed")
+    }
+  }
+  // tan(x) = x + x^3/3 + 2 x^5/15 + ...
+  y := NewTaylorReal(0.0)
+  Variables(5, y)
+
+  r.Tan(y)
+
+  c := []float64{0.0, 1.0, 0.0, 1.0/3.0, 0.0, 2.0/15.0}
+  for k := 0; k <= 5; k++ {
+    if math.Abs(r.GetTaylorCoefficient(k) - c[k]) > 1e-12 {
+      t.Error("test failed")
+    }
+  }
+  // erfc(x) = 1 - 2/sqrt(pi) (x - x^3/3 + x^5/10 - ...)
+  r.Erfc(y)
+
+  c = []float64{1.0, -1.0, 0.0, 1.0/3.0, 0.0, -1.0/10.0}
+  for k := 0; k <= 5; k++ {
+    v := c[k]
+    if k > 0 {
+      v *= 2.0/special.M_SQRTPI
+    }
+    if math.Abs(r.GetTaylorCoefficient(k) - v) > 1e-12 {
+      t.Error("test failed")
+    }
+  }
+}
+
+func TestTaylorReal4(t *testing.T) {
+
+  // third derivatives of special functions compared to finite
+  // differences of their second derivatives
+  f := []func(r Scalar, x ConstScalar) Scalar{
+    func(r Scalar, x ConstScalar) Scalar { return r.GammaP(2.5, x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.BesselI(1.5, x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.(logBesselI).LogBesselI(1.5, x) },
+    func(r Scalar, x ConstScalar) Scalar { return r.LogErfc(x) },
+  }
+  h := 1e-5
+  for i, _ := range f {
+    x := NewTaylorReal(1.7)
+    Variables(3, x)
+    r := f[i](NullTaylorReal(), x)
+
+    x1 := NewReal(1.7+h)
+    x2 := NewReal(1.7-h)
+    Variables(2, x1)
+    Variables(2, x2)
+    r1 := f[i](NullReal(), x1)
+    r2 := f[i](NullReal(), x2)
+
+    if math.Abs(r.(*TaylorReal).GetDerivativeOfOrder(3) - (r1.GetHessian(0, 0) - r2.GetHessian(0, 0))/(2.0*h)) > 1e-5 {
+      t.Errorf("test %d failed", i)
+    }
+  }
+}
+
+func TestTaylorReal5(t *testing.T) {
+
+  x := NewTaylorReal(1.0)
+
+  if err := Variables(1, x, NewTaylorReal(2.0)); err == nil {
+    t.Error("test failed")
+  }
+}
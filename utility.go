@@ -69,6 +69,36 @@ func isGzip(filename string) (bool, error) {
 
 /* -------------------------------------------------------------------------- */
 
+// Permute elements such that element i is replaced by element pi[i]. The
+// permutation is decomposed into cycles, which are applied as a sequence of
+// swaps. False is returned if pi is not a valid permutation, in which case
+// no swaps are performed.
+func applyPermutation(pi []int, swap func(i, j int)) bool {
+  visited := make([]bool, len(pi))
+  for _, k := range pi {
+    if k < 0 || k >= len(pi) || visited[k] {
+      return false
+    }
+    visited[k] = true
+  }
+  for i := range visited {
+    visited[i] = false
+  }
+  for i := range pi {
+    if visited[i] {
+      continue
+    }
+    visited[i] = true
+    for j := i; pi[j] != i; j = pi[j] {
+      swap(j, pi[j])
+      visited[pi[j]] = true
+    }
+  }
+  return true
+}
+
+/* -------------------------------------------------------------------------- */
+
 type sortIntFloat struct {
   a []int
   b []float64
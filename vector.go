@@ -132,6 +132,24 @@ func NewVector(t ScalarType, values []float64) Vector {
     return NewDenseRealVector(values)
   case BareRealType:
     return NewDenseBareRealVector(values)
+  case ReverseRealType:
+    return NewDenseReverseRealVector(values)
+  case SparseRealType:
+    return NewDenseSparseRealVector(values)
+  case DualRealType:
+    return NewDenseDualRealVector(values)
+  case TaylorRealType:
+    return NewDenseTaylorRealVector(values)
+  case ComplexType:
+    return NewDenseComplexVector(values)
+  case BareReal32Type:
+    return NewDenseBareReal32Vector(values)
+  case IntervalType:
+    return NewDenseIntervalVector(values)
+  case BigRealType:
+    return NewDenseBigRealVector(values)
+  case BatchRealType:
+    return NewDenseBatchRealVector(values)
   default:
     panic("unknown type")
   }
@@ -143,6 +161,24 @@ func NullVector(t ScalarType, length int) Vector {
     return NullDenseRealVector(length)
   case BareRealType:
     return NullDenseBareRealVector(length)
+  case ReverseRealType:
+    return NullDenseReverseRealVector(length)
+  case SparseRealType:
+    return NullDenseSparseRealVector(length)
+  case DualRealType:
+    return NullDenseDualRealVector(length)
+  case TaylorRealType:
+    return NullDenseTaylorRealVector(length)
+  case ComplexType:
+    return NullDenseComplexVector(length)
+  case BareReal32Type:
+    return NullDenseBareReal32Vector(length)
+  case IntervalType:
+    return NullDenseIntervalVector(length)
+  case BigRealType:
+    return NullDenseBigRealVector(length)
+  case BatchRealType:
+    return NullDenseBatchRealVector(length)
   default:
     panic("unknown type")
   }
@@ -154,6 +190,24 @@ func AsVector(t ScalarType, v Vector) Vector {
     return AsDenseRealVector(v)
   case BareRealType:
     return AsDenseBareRealVector(v)
+  case ReverseRealType:
+    return AsDenseReverseRealVector(v)
+  case SparseRealType:
+    return AsDenseSparseRealVector(v)
+  case DualRealType:
+    return AsDenseDualRealVector(v)
+  case TaylorRealType:
+    return AsDenseTaylorRealVector(v)
+  case ComplexType:
+    return AsDenseComplexVector(v)
+  case BareReal32Type:
+    return AsDenseBareReal32Vector(v)
+  case IntervalType:
+    return AsDenseIntervalVector(v)
+  case BigRealType:
+    return AsDenseBigRealVector(v)
+  case BatchRealType:
+    return AsDenseBatchRealVector(v)
   default:
     panic("unknown type")
   }
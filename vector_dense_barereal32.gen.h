@@ -0,0 +1,12 @@
+
+//#define STORE_PTR 1
+
+#define CONST_SCALAR_NAME ConstReal
+#define       SCALAR_NAME BareReal32
+#define       MATRIX_NAME DenseBareReal32Matrix
+#define       VECTOR_NAME DenseBareReal32Vector
+
+#define  CONST_SCALAR_TYPE CONST_SCALAR_NAME
+#define        SCALAR_TYPE      *SCALAR_NAME
+#define        MATRIX_TYPE      *MATRIX_NAME
+#define        VECTOR_TYPE       VECTOR_NAME
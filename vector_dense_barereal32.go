@@ -0,0 +1,543 @@
+//#define STORE_PTR 1
+/* -*- mode: go; -*-
+ *
+ * Copyright (C) 2015-2017 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+/* -------------------------------------------------------------------------- */
+package autodiff
+/* -------------------------------------------------------------------------- */
+import "fmt"
+import "bytes"
+import "bufio"
+import "encoding/json"
+import "errors"
+import "compress/gzip"
+import "sort"
+import "strconv"
+import "strings"
+import "os"
+/* -------------------------------------------------------------------------- */
+/* vector type declaration
+ * -------------------------------------------------------------------------- */
+type DenseBareReal32Vector []BareReal32
+/* constructors
+ * -------------------------------------------------------------------------- */
+// Allocate a new vector. Scalars are set to the given values.
+func NewDenseBareReal32Vector(values []float64) DenseBareReal32Vector {
+  v := nilDenseBareReal32Vector(len(values))
+  for i, _ := range values {
+    v[i] = *NewBareReal32(values[i])
+  }
+  return v
+}
+// Allocate a new vector. All scalars are set to zero.
+func NullDenseBareReal32Vector(length int) DenseBareReal32Vector {
+  v := nilDenseBareReal32Vector(length)
+  if length > 0 {
+    for i := 0; i < length; i++ {
+      v[i] = *NewBareReal32(0.0)
+    }
+  }
+  return v
+}
+// Create a empty vector without allocating memory for the scalar variables.
+func nilDenseBareReal32Vector(length int) DenseBareReal32Vector {
+  return make(DenseBareReal32Vector, length)
+}
+// Convert vector type.
+func AsDenseBareReal32Vector(v ConstVector) DenseBareReal32Vector {
+  switch v_ := v.(type) {
+  case DenseBareReal32Vector:
+    return v_.Clone()
+  }
+  r := NullDenseBareReal32Vector(v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    r.AT(i).Set(v.ConstAt(i))
+  }
+  return r
+}
+/* -------------------------------------------------------------------------- */
+// Create a deep copy of the vector.
+func (v DenseBareReal32Vector) Clone() DenseBareReal32Vector {
+  result := make(DenseBareReal32Vector, len(v))
+  for i, _ := range v {
+    result[i] = *v[i].Clone()
+  }
+  return result
+}
+func (v DenseBareReal32Vector) CloneVector() Vector {
+  return v.Clone()
+}
+// Copy scalars from w into this vector. The lengths of both vectors must
+// match.
+func (v DenseBareReal32Vector) Set(w ConstVector) {
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].Set(w.ConstAt(i))
+  }
+}
+func (v DenseBareReal32Vector) SET(w DenseBareReal32Vector) {
+  if v.IDEM(w) {
+    return
+  }
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].SET(w.AT(i))
+  }
+}
+func (v DenseBareReal32Vector) IDEM(w DenseBareReal32Vector) bool {
+  if len(v) != len(w) {
+    return false
+  }
+  if len(v) == 0 {
+    return false
+  }
+  return &v[0] == &w[0]
+}
+/* const vector methods
+ * -------------------------------------------------------------------------- */
+func (v DenseBareReal32Vector) ValueAt(i int) float64 {
+  return v[i].GetValue()
+}
+func (v DenseBareReal32Vector) ConstAt(i int) ConstScalar {
+  return v[i]
+}
+func (v DenseBareReal32Vector) ConstSlice(i, j int) ConstVector {
+  return v[i:j]
+}
+func (v DenseBareReal32Vector) GetValues() []float64 {
+  s := make([]float64, v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    s[i] = v.ConstAt(i).GetValue()
+  }
+  return s
+}
+/* iterator methods
+ * -------------------------------------------------------------------------- */
+func (obj DenseBareReal32Vector) ConstIterator() VectorConstIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseBareReal32Vector) Iterator() VectorIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseBareReal32Vector) JointIterator(b ConstVector) VectorJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseBareReal32Vector) ConstJointIterator(b ConstVector) VectorConstJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseBareReal32Vector) ITERATOR() *DenseBareReal32VectorIterator {
+  r := DenseBareReal32VectorIterator{obj, -1}
+  r.Next()
+  return &r
+}
+func (obj DenseBareReal32Vector) JOINT_ITERATOR(b ConstVector) *DenseBareReal32VectorJointIterator {
+  r := DenseBareReal32VectorJointIterator{obj.ITERATOR(), b.ConstIterator(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+func (obj DenseBareReal32Vector) JOINT_ITERATOR_(b DenseBareReal32Vector) *DenseBareReal32VectorJointIterator_ {
+  r := DenseBareReal32VectorJointIterator_{obj.ITERATOR(), b.ITERATOR(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+/* -------------------------------------------------------------------------- */
+func (v DenseBareReal32Vector) Dim() int {
+  return len(v)
+}
+func (v DenseBareReal32Vector) At(i int) Scalar {
+  return v.AT(i)
+}
+func (v DenseBareReal32Vector) AT(i int) *BareReal32 {
+  return &v[i]
+}
+func (v DenseBareReal32Vector) Reset() {
+  for i := 0; i < len(v); i++ {
+    v[i].Reset()
+  }
+}
+func (v DenseBareReal32Vector) ResetDerivatives() {
+  for i := 0; i < len(v); i++ {
+    v[i].ResetDerivatives()
+  }
+}
+func (v DenseBareReal32Vector) ReverseOrder() {
+  n := len(v)
+  for i := 0; i < n/2; i++ {
+    v[i], v[n-1-i] = v[n-1-i], v[i]
+  }
+}
+func (v DenseBareReal32Vector) Slice(i, j int) Vector {
+  return v[i:j]
+}
+func (v DenseBareReal32Vector) Append(w DenseBareReal32Vector) DenseBareReal32Vector {
+  return append(v, w...)
+}
+func (v DenseBareReal32Vector) AppendScalar(scalars ...Scalar) Vector {
+  for _, scalar := range scalars {
+    switch s := scalar.(type) {
+    case *BareReal32:
+      v = append(v, *s)
+    default:
+      v = append(v, *s.ConvertType(BareReal32Type).(*BareReal32))
+    }
+  }
+  return v
+}
+func (v DenseBareReal32Vector) AppendVector(w_ Vector) Vector {
+  switch w := w_.(type) {
+  case DenseBareReal32Vector:
+    return append(v, w...)
+  default:
+    for i := 0; i < w.Dim(); i++ {
+      v = append(v, *w.At(i).ConvertType(BareReal32Type).(*BareReal32))
+    }
+    return v
+  }
+}
+func (v DenseBareReal32Vector) Swap(i, j int) {
+  v[i], v[j] = v[j], v[i]
+}
+/* imlement ScalarContainer
+ * -------------------------------------------------------------------------- */
+func (v DenseBareReal32Vector) Map(f func(Scalar)) {
+  for i := 0; i < len(v); i++ {
+    f(&v[i])
+  }
+}
+func (v DenseBareReal32Vector) MapSet(f func(ConstScalar) Scalar) {
+  for i := 0; i < len(v); i++ {
+    v[i].Set(f(v.ConstAt(i)))
+  }
+}
+func (v DenseBareReal32Vector) Reduce(f func(Scalar, ConstScalar) Scalar, r Scalar) Scalar {
+  for i := 0; i < len(v); i++ {
+    r = f(r, v.ConstAt(i))
+  }
+  return r
+}
+func (v DenseBareReal32Vector) ElementType() ScalarType {
+  return BareReal32Type
+}
+func (v DenseBareReal32Vector) Variables(order int) error {
+  for i, _ := range v {
+    if err := v[i].SetVariable(i, len(v), order); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+/* permutations
+ * -------------------------------------------------------------------------- */
+func (v DenseBareReal32Vector) Permute(pi []int) error {
+  if len(pi) != len(v) {
+    return errors.New("Permute(): permutation vector has invalid length!")
+  }
+  // permute vector
+  if !applyPermutation(pi, func(i, j int) { v[i], v[j] = v[j], v[i] }) {
+    return errors.New("Permute(): invalid permutation")
+  }
+  return nil
+}
+/* sorting
+ * -------------------------------------------------------------------------- */
+type sortDenseBareReal32VectorByValue DenseBareReal32Vector
+func (v sortDenseBareReal32VectorByValue) Len() int { return len(v) }
+func (v sortDenseBareReal32VectorByValue) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+func (v sortDenseBareReal32VectorByValue) Less(i, j int) bool { return v[i].GetValue() < v[j].GetValue() }
+func (v DenseBareReal32Vector) Sort(reverse bool) {
+  if reverse {
+    sort.Sort(sort.Reverse(sortDenseBareReal32VectorByValue(v)))
+  } else {
+    sort.Sort(sortDenseBareReal32VectorByValue(v))
+  }
+}
+/* type conversion
+ * -------------------------------------------------------------------------- */
+func (v DenseBareReal32Vector) AsMatrix(n, m int) Matrix {
+  return v.ToDenseBareReal32Matrix(n, m)
+}
+func (v DenseBareReal32Vector) ToDenseBareReal32Matrix(n, m int) *DenseBareReal32Matrix {
+  if n*m != len(v) {
+    panic("Matrix dimension does not fit input vector!")
+  }
+  matrix := DenseBareReal32Matrix{}
+  matrix.values = v
+  matrix.rows = n
+  matrix.cols = m
+  matrix.rowOffset = 0
+  matrix.rowMax = n
+  matrix.colOffset = 0
+  matrix.colMax = m
+  matrix.initTmp()
+  return &matrix
+}
+func (v DenseBareReal32Vector) String() string {
+  var buffer bytes.Buffer
+  buffer.WriteString("[")
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(", ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  buffer.WriteString("]")
+  return buffer.String()
+}
+func (v DenseBareReal32Vector) Table() string {
+  var buffer bytes.Buffer
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(" ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  return buffer.String()
+}
+func (v DenseBareReal32Vector) Export(filename string) error {
+  f, err := os.Create(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  w := bufio.NewWriter(f)
+  defer w.Flush()
+  if _, err := fmt.Fprintf(w, "%s\n", v.Table()); err != nil {
+    return err
+  }
+  return nil
+}
+func (v *DenseBareReal32Vector) Import(filename string) error {
+  var scanner *bufio.Scanner
+  // open file
+  f, err := os.Open(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  isgzip, err := isGzip(filename)
+  if err != nil {
+    return err
+  }
+  // check if file is gzipped
+  if isgzip {
+    g, err := gzip.NewReader(f)
+    if err != nil {
+      return err
+    }
+    defer g.Close()
+    scanner = bufio.NewScanner(g)
+  } else {
+    scanner = bufio.NewScanner(f)
+  }
+  // reset vector
+  *v = DenseBareReal32Vector{}
+  for scanner.Scan() {
+    fields := strings.Fields(scanner.Text())
+    if len(fields) == 0 {
+      continue
+    }
+    if len(*v) != 0 {
+      return fmt.Errorf("invalid table")
+    }
+    for i := 0; i < len(fields); i++ {
+      value, err := strconv.ParseFloat(fields[i], 64)
+      if err != nil {
+        return fmt.Errorf("invalid table")
+      }
+      *v = append(*v, *NewBareReal32(value))
+    }
+  }
+  return nil
+}
+/* json
+ * -------------------------------------------------------------------------- */
+func (obj DenseBareReal32Vector) MarshalJSON() ([]byte, error) {
+  r := []BareReal32{}
+  r = obj
+  return json.MarshalIndent(r, "", "  ")
+}
+func (obj *DenseBareReal32Vector) UnmarshalJSON(data []byte) error {
+  r := []BareReal32{}
+  if err := json.Unmarshal(data, &r); err != nil {
+    return err
+  }
+  *obj = nilDenseBareReal32Vector(len(r))
+  for i := 0; i < len(r); i++ {
+    (*obj)[i] = r[i]
+  }
+  return nil
+}
+/* iterator
+ * -------------------------------------------------------------------------- */
+type DenseBareReal32VectorIterator struct {
+  v DenseBareReal32Vector
+  i int
+}
+func (obj *DenseBareReal32VectorIterator) Get() Scalar {
+  return obj.GET()
+}
+func (obj *DenseBareReal32VectorIterator) GetConst() ConstScalar {
+  return obj.GET()
+}
+func (obj *DenseBareReal32VectorIterator) GetValue() float64 {
+  return obj.GET().GetValue()
+}
+func (obj *DenseBareReal32VectorIterator) GET() *BareReal32 {
+  return &obj.v[obj.i]
+}
+func (obj *DenseBareReal32VectorIterator) Ok() bool {
+  return obj.i < len(obj.v)
+}
+func (obj *DenseBareReal32VectorIterator) Next() {
+  obj.i++
+}
+func (obj *DenseBareReal32VectorIterator) Index() int {
+  return obj.i
+}
+func (obj *DenseBareReal32VectorIterator) Clone() *DenseBareReal32VectorIterator {
+  return &DenseBareReal32VectorIterator{obj.v, obj.i}
+}
+func (obj *DenseBareReal32VectorIterator) CloneConstIterator() VectorConstIterator {
+  return &DenseBareReal32VectorIterator{obj.v, obj.i}
+}
+func (obj *DenseBareReal32VectorIterator) CloneIterator() VectorIterator {
+  return &DenseBareReal32VectorIterator{obj.v, obj.i}
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseBareReal32VectorJointIterator struct {
+  it1 *DenseBareReal32VectorIterator
+  it2 VectorConstIterator
+  idx int
+  s1 *BareReal32
+  s2 ConstScalar
+}
+func (obj *DenseBareReal32VectorJointIterator) Index() int {
+  return obj.idx
+}
+func (obj *DenseBareReal32VectorJointIterator) Ok() bool {
+  return !(obj.s1 == nil || obj.s1.GetValue() == 0.0) ||
+         !(obj.s2 == nil || obj.s2.GetValue() == 0.0)
+}
+func (obj *DenseBareReal32VectorJointIterator) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GetConst()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GetConst()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  } else {
+    obj.s2 = ConstReal(0.0)
+  }
+}
+func (obj *DenseBareReal32VectorJointIterator) Get() (Scalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseBareReal32VectorJointIterator) GetConst() (ConstScalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseBareReal32VectorJointIterator) GetValue() (float64, float64) {
+  a, b := obj.GET()
+  return a.GetValue(), b.GetValue()
+}
+func (obj *DenseBareReal32VectorJointIterator) GET() (*BareReal32, ConstScalar) {
+  if obj.s1 == nil {
+    return nil, obj.s2
+  } else {
+    return obj.s1, obj.s2
+  }
+}
+func (obj *DenseBareReal32VectorJointIterator) Clone() *DenseBareReal32VectorJointIterator {
+  r := DenseBareReal32VectorJointIterator{}
+  r.it1 = obj.it1.Clone()
+  r.it2 = obj.it2.CloneConstIterator()
+  r.idx = obj.idx
+  r.s1 = obj.s1
+  r.s2 = obj.s2
+  return &r
+}
+func (obj *DenseBareReal32VectorJointIterator) CloneConstJointIterator() VectorConstJointIterator {
+  return obj.Clone()
+}
+func (obj *DenseBareReal32VectorJointIterator) CloneJointIterator() VectorJointIterator {
+  return obj.Clone()
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseBareReal32VectorJointIterator_ struct {
+  it1 *DenseBareReal32VectorIterator
+  it2 *DenseBareReal32VectorIterator
+  idx int
+  s1 *BareReal32
+  s2 *BareReal32
+}
+func (obj *DenseBareReal32VectorJointIterator_) Index() int {
+  return obj.idx
+}
+func (obj *DenseBareReal32VectorJointIterator_) Ok() bool {
+  return obj.s1 != nil || obj.s2 != nil
+}
+func (obj *DenseBareReal32VectorJointIterator_) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GET()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GET()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  }
+}
+func (obj *DenseBareReal32VectorJointIterator_) GET() (*BareReal32, *BareReal32) {
+  return obj.s1, obj.s2
+}
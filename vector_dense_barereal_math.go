@@ -231,6 +231,15 @@ func (r DenseBareRealVector) MdotV(a ConstMatrix, b ConstVector) Vector {
   if r.AT(0) == b.ConstAt(0) {
     panic("result and argument must be different vectors")
   }
+  // visit only non-zero elements of compressed sparse matrices
+  switch a := a.(type) {
+  case CSRConstRealMatrix:
+    compressedGather (r, b, a.rowPtr, a.colIdx, a.values)
+    return r
+  case CSCConstRealMatrix:
+    compressedScatter(r, b, a.colPtr, a.rowIdx, a.values)
+    return r
+  }
   t := NullBareReal()
   for i := 0; i < n; i++ {
     r.AT(i).Reset()
@@ -254,6 +263,15 @@ func (r DenseBareRealVector) VdotM(a ConstVector, b ConstMatrix) Vector {
   if r.AT(0) == a.ConstAt(0) {
     panic("result and argument must be different vectors")
   }
+  // visit only non-zero elements of compressed sparse matrices
+  switch b := b.(type) {
+  case CSRConstRealMatrix:
+    compressedScatter(r, a, b.rowPtr, b.colIdx, b.values)
+    return r
+  case CSCConstRealMatrix:
+    compressedGather (r, a, b.colPtr, b.rowIdx, b.values)
+    return r
+  }
   t := NullBareReal()
   for i := 0; i < m; i++ {
     r.AT(i).Reset()
@@ -0,0 +1,542 @@
+/* -*- mode: go; -*-
+ *
+ * Copyright (C) 2015-2017 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+/* -------------------------------------------------------------------------- */
+package autodiff
+/* -------------------------------------------------------------------------- */
+import "fmt"
+import "bytes"
+import "bufio"
+import "encoding/json"
+import "errors"
+import "compress/gzip"
+import "sort"
+import "strconv"
+import "strings"
+import "os"
+/* -------------------------------------------------------------------------- */
+/* vector type declaration
+ * -------------------------------------------------------------------------- */
+type DenseBatchRealVector []*BatchReal
+/* constructors
+ * -------------------------------------------------------------------------- */
+// Allocate a new vector. Scalars are set to the given values.
+func NewDenseBatchRealVector(values []float64) DenseBatchRealVector {
+  v := nilDenseBatchRealVector(len(values))
+  for i, _ := range values {
+    v[i] = NewBatchReal(values[i])
+  }
+  return v
+}
+// Allocate a new vector. All scalars are set to zero.
+func NullDenseBatchRealVector(length int) DenseBatchRealVector {
+  v := nilDenseBatchRealVector(length)
+  if length > 0 {
+    for i := 0; i < length; i++ {
+      v[i] = NewBatchReal(0.0)
+    }
+  }
+  return v
+}
+// Create a empty vector without allocating memory for the scalar variables.
+func nilDenseBatchRealVector(length int) DenseBatchRealVector {
+  return make(DenseBatchRealVector, length)
+}
+// Convert vector type.
+func AsDenseBatchRealVector(v ConstVector) DenseBatchRealVector {
+  switch v_ := v.(type) {
+  case DenseBatchRealVector:
+    return v_.Clone()
+  }
+  r := NullDenseBatchRealVector(v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    r.AT(i).Set(v.ConstAt(i))
+  }
+  return r
+}
+/* -------------------------------------------------------------------------- */
+// Create a deep copy of the vector.
+func (v DenseBatchRealVector) Clone() DenseBatchRealVector {
+  result := make(DenseBatchRealVector, len(v))
+  for i, _ := range v {
+    result[i] = v[i].Clone()
+  }
+  return result
+}
+func (v DenseBatchRealVector) CloneVector() Vector {
+  return v.Clone()
+}
+// Copy scalars from w into this vector. The lengths of both vectors must
+// match.
+func (v DenseBatchRealVector) Set(w ConstVector) {
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].Set(w.ConstAt(i))
+  }
+}
+func (v DenseBatchRealVector) SET(w DenseBatchRealVector) {
+  if v.IDEM(w) {
+    return
+  }
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].SET(w.AT(i))
+  }
+}
+func (v DenseBatchRealVector) IDEM(w DenseBatchRealVector) bool {
+  if len(v) != len(w) {
+    return false
+  }
+  if len(v) == 0 {
+    return false
+  }
+  return &v[0] == &w[0]
+}
+/* const vector methods
+ * -------------------------------------------------------------------------- */
+func (v DenseBatchRealVector) ValueAt(i int) float64 {
+  return v[i].GetValue()
+}
+func (v DenseBatchRealVector) ConstAt(i int) ConstScalar {
+  return v[i]
+}
+func (v DenseBatchRealVector) ConstSlice(i, j int) ConstVector {
+  return v[i:j]
+}
+func (v DenseBatchRealVector) GetValues() []float64 {
+  s := make([]float64, v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    s[i] = v.ConstAt(i).GetValue()
+  }
+  return s
+}
+/* iterator methods
+ * -------------------------------------------------------------------------- */
+func (obj DenseBatchRealVector) ConstIterator() VectorConstIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseBatchRealVector) Iterator() VectorIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseBatchRealVector) JointIterator(b ConstVector) VectorJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseBatchRealVector) ConstJointIterator(b ConstVector) VectorConstJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseBatchRealVector) ITERATOR() *DenseBatchRealVectorIterator {
+  r := DenseBatchRealVectorIterator{obj, -1}
+  r.Next()
+  return &r
+}
+func (obj DenseBatchRealVector) JOINT_ITERATOR(b ConstVector) *DenseBatchRealVectorJointIterator {
+  r := DenseBatchRealVectorJointIterator{obj.ITERATOR(), b.ConstIterator(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+func (obj DenseBatchRealVector) JOINT_ITERATOR_(b DenseBatchRealVector) *DenseBatchRealVectorJointIterator_ {
+  r := DenseBatchRealVectorJointIterator_{obj.ITERATOR(), b.ITERATOR(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+/* -------------------------------------------------------------------------- */
+func (v DenseBatchRealVector) Dim() int {
+  return len(v)
+}
+func (v DenseBatchRealVector) At(i int) Scalar {
+  return v.AT(i)
+}
+func (v DenseBatchRealVector) AT(i int) *BatchReal {
+  return v[i]
+}
+func (v DenseBatchRealVector) Reset() {
+  for i := 0; i < len(v); i++ {
+    v[i].Reset()
+  }
+}
+func (v DenseBatchRealVector) ResetDerivatives() {
+  for i := 0; i < len(v); i++ {
+    v[i].ResetDerivatives()
+  }
+}
+func (v DenseBatchRealVector) ReverseOrder() {
+  n := len(v)
+  for i := 0; i < n/2; i++ {
+    v[i], v[n-1-i] = v[n-1-i], v[i]
+  }
+}
+func (v DenseBatchRealVector) Slice(i, j int) Vector {
+  return v[i:j]
+}
+func (v DenseBatchRealVector) Append(w DenseBatchRealVector) DenseBatchRealVector {
+  return append(v, w...)
+}
+func (v DenseBatchRealVector) AppendScalar(scalars ...Scalar) Vector {
+  for _, scalar := range scalars {
+    switch s := scalar.(type) {
+    case *BatchReal:
+      v = append(v, s)
+    default:
+      v = append(v, s.ConvertType(BatchRealType).(*BatchReal))
+    }
+  }
+  return v
+}
+func (v DenseBatchRealVector) AppendVector(w_ Vector) Vector {
+  switch w := w_.(type) {
+  case DenseBatchRealVector:
+    return append(v, w...)
+  default:
+    for i := 0; i < w.Dim(); i++ {
+      v = append(v, w.At(i).ConvertType(BatchRealType).(*BatchReal))
+    }
+    return v
+  }
+}
+func (v DenseBatchRealVector) Swap(i, j int) {
+  v[i], v[j] = v[j], v[i]
+}
+/* imlement ScalarContainer
+ * -------------------------------------------------------------------------- */
+func (v DenseBatchRealVector) Map(f func(Scalar)) {
+  for i := 0; i < len(v); i++ {
+    f( v[i])
+  }
+}
+func (v DenseBatchRealVector) MapSet(f func(ConstScalar) Scalar) {
+  for i := 0; i < len(v); i++ {
+    v[i].Set(f(v.ConstAt(i)))
+  }
+}
+func (v DenseBatchRealVector) Reduce(f func(Scalar, ConstScalar) Scalar, r Scalar) Scalar {
+  for i := 0; i < len(v); i++ {
+    r = f(r, v.ConstAt(i))
+  }
+  return r
+}
+func (v DenseBatchRealVector) ElementType() ScalarType {
+  return BatchRealType
+}
+func (v DenseBatchRealVector) Variables(order int) error {
+  for i, _ := range v {
+    if err := v[i].SetVariable(i, len(v), order); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+/* permutations
+ * -------------------------------------------------------------------------- */
+func (v DenseBatchRealVector) Permute(pi []int) error {
+  if len(pi) != len(v) {
+    return errors.New("Permute(): permutation vector has invalid length!")
+  }
+  // permute vector
+  if !applyPermutation(pi, func(i, j int) { v[i], v[j] = v[j], v[i] }) {
+    return errors.New("Permute(): invalid permutation")
+  }
+  return nil
+}
+/* sorting
+ * -------------------------------------------------------------------------- */
+type sortDenseBatchRealVectorByValue DenseBatchRealVector
+func (v sortDenseBatchRealVectorByValue) Len() int { return len(v) }
+func (v sortDenseBatchRealVectorByValue) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+func (v sortDenseBatchRealVectorByValue) Less(i, j int) bool { return v[i].GetValue() < v[j].GetValue() }
+func (v DenseBatchRealVector) Sort(reverse bool) {
+  if reverse {
+    sort.Sort(sort.Reverse(sortDenseBatchRealVectorByValue(v)))
+  } else {
+    sort.Sort(sortDenseBatchRealVectorByValue(v))
+  }
+}
+/* type conversion
+ * -------------------------------------------------------------------------- */
+func (v DenseBatchRealVector) AsMatrix(n, m int) Matrix {
+  return v.ToDenseBatchRealMatrix(n, m)
+}
+func (v DenseBatchRealVector) ToDenseBatchRealMatrix(n, m int) *DenseBatchRealMatrix {
+  if n*m != len(v) {
+    panic("Matrix dimension does not fit input vector!")
+  }
+  matrix := DenseBatchRealMatrix{}
+  matrix.values = v
+  matrix.rows = n
+  matrix.cols = m
+  matrix.rowOffset = 0
+  matrix.rowMax = n
+  matrix.colOffset = 0
+  matrix.colMax = m
+  matrix.initTmp()
+  return &matrix
+}
+func (v DenseBatchRealVector) String() string {
+  var buffer bytes.Buffer
+  buffer.WriteString("[")
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(", ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  buffer.WriteString("]")
+  return buffer.String()
+}
+func (v DenseBatchRealVector) Table() string {
+  var buffer bytes.Buffer
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(" ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  return buffer.String()
+}
+func (v DenseBatchRealVector) Export(filename string) error {
+  f, err := os.Create(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  w := bufio.NewWriter(f)
+  defer w.Flush()
+  if _, err := fmt.Fprintf(w, "%s\n", v.Table()); err != nil {
+    return err
+  }
+  return nil
+}
+func (v *DenseBatchRealVector) Import(filename string) error {
+  var scanner *bufio.Scanner
+  // open file
+  f, err := os.Open(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  isgzip, err := isGzip(filename)
+  if err != nil {
+    return err
+  }
+  // check if file is gzipped
+  if isgzip {
+    g, err := gzip.NewReader(f)
+    if err != nil {
+      return err
+    }
+    defer g.Close()
+    scanner = bufio.NewScanner(g)
+  } else {
+    scanner = bufio.NewScanner(f)
+  }
+  // reset vector
+  *v = DenseBatchRealVector{}
+  for scanner.Scan() {
+    fields := strings.Fields(scanner.Text())
+    if len(fields) == 0 {
+      continue
+    }
+    if len(*v) != 0 {
+      return fmt.Errorf("invalid table")
+    }
+    for i := 0; i < len(fields); i++ {
+      value, err := strconv.ParseFloat(fields[i], 64)
+      if err != nil {
+        return fmt.Errorf("invalid table")
+      }
+      *v = append(*v, NewBatchReal(value))
+    }
+  }
+  return nil
+}
+/* json
+ * -------------------------------------------------------------------------- */
+func (obj DenseBatchRealVector) MarshalJSON() ([]byte, error) {
+  r := []*BatchReal{}
+  r = obj
+  return json.MarshalIndent(r, "", "  ")
+}
+func (obj *DenseBatchRealVector) UnmarshalJSON(data []byte) error {
+  r := []*BatchReal{}
+  if err := json.Unmarshal(data, &r); err != nil {
+    return err
+  }
+  *obj = nilDenseBatchRealVector(len(r))
+  for i := 0; i < len(r); i++ {
+    (*obj)[i] = r[i]
+  }
+  return nil
+}
+/* iterator
+ * -------------------------------------------------------------------------- */
+type DenseBatchRealVectorIterator struct {
+  v DenseBatchRealVector
+  i int
+}
+func (obj *DenseBatchRealVectorIterator) Get() Scalar {
+  return obj.GET()
+}
+func (obj *DenseBatchRealVectorIterator) GetConst() ConstScalar {
+  return obj.GET()
+}
+func (obj *DenseBatchRealVectorIterator) GetValue() float64 {
+  return obj.GET().GetValue()
+}
+func (obj *DenseBatchRealVectorIterator) GET() *BatchReal {
+  return obj.v[obj.i]
+}
+func (obj *DenseBatchRealVectorIterator) Ok() bool {
+  return obj.i < len(obj.v)
+}
+func (obj *DenseBatchRealVectorIterator) Next() {
+  obj.i++
+}
+func (obj *DenseBatchRealVectorIterator) Index() int {
+  return obj.i
+}
+func (obj *DenseBatchRealVectorIterator) Clone() *DenseBatchRealVectorIterator {
+  return &DenseBatchRealVectorIterator{obj.v, obj.i}
+}
+func (obj *DenseBatchRealVectorIterator) CloneConstIterator() VectorConstIterator {
+  return &DenseBatchRealVectorIterator{obj.v, obj.i}
+}
+func (obj *DenseBatchRealVectorIterator) CloneIterator() VectorIterator {
+  return &DenseBatchRealVectorIterator{obj.v, obj.i}
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseBatchRealVectorJointIterator struct {
+  it1 *DenseBatchRealVectorIterator
+  it2 VectorConstIterator
+  idx int
+  s1 *BatchReal
+  s2 ConstScalar
+}
+func (obj *DenseBatchRealVectorJointIterator) Index() int {
+  return obj.idx
+}
+func (obj *DenseBatchRealVectorJointIterator) Ok() bool {
+  return !(obj.s1 == nil || obj.s1.GetValue() == 0.0) ||
+         !(obj.s2 == nil || obj.s2.GetValue() == 0.0)
+}
+func (obj *DenseBatchRealVectorJointIterator) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GetConst()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GetConst()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  } else {
+    obj.s2 = ConstReal(0.0)
+  }
+}
+func (obj *DenseBatchRealVectorJointIterator) Get() (Scalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseBatchRealVectorJointIterator) GetConst() (ConstScalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseBatchRealVectorJointIterator) GetValue() (float64, float64) {
+  a, b := obj.GET()
+  return a.GetValue(), b.GetValue()
+}
+func (obj *DenseBatchRealVectorJointIterator) GET() (*BatchReal, ConstScalar) {
+  if obj.s1 == nil {
+    return nil, obj.s2
+  } else {
+    return obj.s1, obj.s2
+  }
+}
+func (obj *DenseBatchRealVectorJointIterator) Clone() *DenseBatchRealVectorJointIterator {
+  r := DenseBatchRealVectorJointIterator{}
+  r.it1 = obj.it1.Clone()
+  r.it2 = obj.it2.CloneConstIterator()
+  r.idx = obj.idx
+  r.s1 = obj.s1
+  r.s2 = obj.s2
+  return &r
+}
+func (obj *DenseBatchRealVectorJointIterator) CloneConstJointIterator() VectorConstJointIterator {
+  return obj.Clone()
+}
+func (obj *DenseBatchRealVectorJointIterator) CloneJointIterator() VectorJointIterator {
+  return obj.Clone()
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseBatchRealVectorJointIterator_ struct {
+  it1 *DenseBatchRealVectorIterator
+  it2 *DenseBatchRealVectorIterator
+  idx int
+  s1 *BatchReal
+  s2 *BatchReal
+}
+func (obj *DenseBatchRealVectorJointIterator_) Index() int {
+  return obj.idx
+}
+func (obj *DenseBatchRealVectorJointIterator_) Ok() bool {
+  return obj.s1 != nil || obj.s2 != nil
+}
+func (obj *DenseBatchRealVectorJointIterator_) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GET()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GET()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  }
+}
+func (obj *DenseBatchRealVectorJointIterator_) GET() (*BatchReal, *BatchReal) {
+  return obj.s1, obj.s2
+}
@@ -0,0 +1,542 @@
+/* -*- mode: go; -*-
+ *
+ * Copyright (C) 2015-2017 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+/* -------------------------------------------------------------------------- */
+package autodiff
+/* -------------------------------------------------------------------------- */
+import "fmt"
+import "bytes"
+import "bufio"
+import "encoding/json"
+import "errors"
+import "compress/gzip"
+import "sort"
+import "strconv"
+import "strings"
+import "os"
+/* -------------------------------------------------------------------------- */
+/* vector type declaration
+ * -------------------------------------------------------------------------- */
+type DenseBigRealVector []*BigReal
+/* constructors
+ * -------------------------------------------------------------------------- */
+// Allocate a new vector. Scalars are set to the given values.
+func NewDenseBigRealVector(values []float64) DenseBigRealVector {
+  v := nilDenseBigRealVector(len(values))
+  for i, _ := range values {
+    v[i] = NewBigReal(values[i])
+  }
+  return v
+}
+// Allocate a new vector. All scalars are set to zero.
+func NullDenseBigRealVector(length int) DenseBigRealVector {
+  v := nilDenseBigRealVector(length)
+  if length > 0 {
+    for i := 0; i < length; i++ {
+      v[i] = NewBigReal(0.0)
+    }
+  }
+  return v
+}
+// Create a empty vector without allocating memory for the scalar variables.
+func nilDenseBigRealVector(length int) DenseBigRealVector {
+  return make(DenseBigRealVector, length)
+}
+// Convert vector type.
+func AsDenseBigRealVector(v ConstVector) DenseBigRealVector {
+  switch v_ := v.(type) {
+  case DenseBigRealVector:
+    return v_.Clone()
+  }
+  r := NullDenseBigRealVector(v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    r.AT(i).Set(v.ConstAt(i))
+  }
+  return r
+}
+/* -------------------------------------------------------------------------- */
+// Create a deep copy of the vector.
+func (v DenseBigRealVector) Clone() DenseBigRealVector {
+  result := make(DenseBigRealVector, len(v))
+  for i, _ := range v {
+    result[i] = v[i].Clone()
+  }
+  return result
+}
+func (v DenseBigRealVector) CloneVector() Vector {
+  return v.Clone()
+}
+// Copy scalars from w into this vector. The lengths of both vectors must
+// match.
+func (v DenseBigRealVector) Set(w ConstVector) {
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].Set(w.ConstAt(i))
+  }
+}
+func (v DenseBigRealVector) SET(w DenseBigRealVector) {
+  if v.IDEM(w) {
+    return
+  }
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].SET(w.AT(i))
+  }
+}
+func (v DenseBigRealVector) IDEM(w DenseBigRealVector) bool {
+  if len(v) != len(w) {
+    return false
+  }
+  if len(v) == 0 {
+    return false
+  }
+  return &v[0] == &w[0]
+}
+/* const vector methods
+ * -------------------------------------------------------------------------- */
+func (v DenseBigRealVector) ValueAt(i int) float64 {
+  return v[i].GetValue()
+}
+func (v DenseBigRealVector) ConstAt(i int) ConstScalar {
+  return v[i]
+}
+func (v DenseBigRealVector) ConstSlice(i, j int) ConstVector {
+  return v[i:j]
+}
+func (v DenseBigRealVector) GetValues() []float64 {
+  s := make([]float64, v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    s[i] = v.ConstAt(i).GetValue()
+  }
+  return s
+}
+/* iterator methods
+ * -------------------------------------------------------------------------- */
+func (obj DenseBigRealVector) ConstIterator() VectorConstIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseBigRealVector) Iterator() VectorIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseBigRealVector) JointIterator(b ConstVector) VectorJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseBigRealVector) ConstJointIterator(b ConstVector) VectorConstJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseBigRealVector) ITERATOR() *DenseBigRealVectorIterator {
+  r := DenseBigRealVectorIterator{obj, -1}
+  r.Next()
+  return &r
+}
+func (obj DenseBigRealVector) JOINT_ITERATOR(b ConstVector) *DenseBigRealVectorJointIterator {
+  r := DenseBigRealVectorJointIterator{obj.ITERATOR(), b.ConstIterator(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+func (obj DenseBigRealVector) JOINT_ITERATOR_(b DenseBigRealVector) *DenseBigRealVectorJointIterator_ {
+  r := DenseBigRealVectorJointIterator_{obj.ITERATOR(), b.ITERATOR(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+/* -------------------------------------------------------------------------- */
+func (v DenseBigRealVector) Dim() int {
+  return len(v)
+}
+func (v DenseBigRealVector) At(i int) Scalar {
+  return v.AT(i)
+}
+func (v DenseBigRealVector) AT(i int) *BigReal {
+  return v[i]
+}
+func (v DenseBigRealVector) Reset() {
+  for i := 0; i < len(v); i++ {
+    v[i].Reset()
+  }
+}
+func (v DenseBigRealVector) ResetDerivatives() {
+  for i := 0; i < len(v); i++ {
+    v[i].ResetDerivatives()
+  }
+}
+func (v DenseBigRealVector) ReverseOrder() {
+  n := len(v)
+  for i := 0; i < n/2; i++ {
+    v[i], v[n-1-i] = v[n-1-i], v[i]
+  }
+}
+func (v DenseBigRealVector) Slice(i, j int) Vector {
+  return v[i:j]
+}
+func (v DenseBigRealVector) Append(w DenseBigRealVector) DenseBigRealVector {
+  return append(v, w...)
+}
+func (v DenseBigRealVector) AppendScalar(scalars ...Scalar) Vector {
+  for _, scalar := range scalars {
+    switch s := scalar.(type) {
+    case *BigReal:
+      v = append(v, s)
+    default:
+      v = append(v, s.ConvertType(BigRealType).(*BigReal))
+    }
+  }
+  return v
+}
+func (v DenseBigRealVector) AppendVector(w_ Vector) Vector {
+  switch w := w_.(type) {
+  case DenseBigRealVector:
+    return append(v, w...)
+  default:
+    for i := 0; i < w.Dim(); i++ {
+      v = append(v, w.At(i).ConvertType(BigRealType).(*BigReal))
+    }
+    return v
+  }
+}
+func (v DenseBigRealVector) Swap(i, j int) {
+  v[i], v[j] = v[j], v[i]
+}
+/* imlement ScalarContainer
+ * -------------------------------------------------------------------------- */
+func (v DenseBigRealVector) Map(f func(Scalar)) {
+  for i := 0; i < len(v); i++ {
+    f( v[i])
+  }
+}
+func (v DenseBigRealVector) MapSet(f func(ConstScalar) Scalar) {
+  for i := 0; i < len(v); i++ {
+    v[i].Set(f(v.ConstAt(i)))
+  }
+}
+func (v DenseBigRealVector) Reduce(f func(Scalar, ConstScalar) Scalar, r Scalar) Scalar {
+  for i := 0; i < len(v); i++ {
+    r = f(r, v.ConstAt(i))
+  }
+  return r
+}
+func (v DenseBigRealVector) ElementType() ScalarType {
+  return BigRealType
+}
+func (v DenseBigRealVector) Variables(order int) error {
+  for i, _ := range v {
+    if err := v[i].SetVariable(i, len(v), order); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+/* permutations
+ * -------------------------------------------------------------------------- */
+func (v DenseBigRealVector) Permute(pi []int) error {
+  if len(pi) != len(v) {
+    return errors.New("Permute(): permutation vector has invalid length!")
+  }
+  // permute vector
+  if !applyPermutation(pi, func(i, j int) { v[i], v[j] = v[j], v[i] }) {
+    return errors.New("Permute(): invalid permutation")
+  }
+  return nil
+}
+/* sorting
+ * -------------------------------------------------------------------------- */
+type sortDenseBigRealVectorByValue DenseBigRealVector
+func (v sortDenseBigRealVectorByValue) Len() int { return len(v) }
+func (v sortDenseBigRealVectorByValue) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+func (v sortDenseBigRealVectorByValue) Less(i, j int) bool { return v[i].GetValue() < v[j].GetValue() }
+func (v DenseBigRealVector) Sort(reverse bool) {
+  if reverse {
+    sort.Sort(sort.Reverse(sortDenseBigRealVectorByValue(v)))
+  } else {
+    sort.Sort(sortDenseBigRealVectorByValue(v))
+  }
+}
+/* type conversion
+ * -------------------------------------------------------------------------- */
+func (v DenseBigRealVector) AsMatrix(n, m int) Matrix {
+  return v.ToDenseBigRealMatrix(n, m)
+}
+func (v DenseBigRealVector) ToDenseBigRealMatrix(n, m int) *DenseBigRealMatrix {
+  if n*m != len(v) {
+    panic("Matrix dimension does not fit input vector!")
+  }
+  matrix := DenseBigRealMatrix{}
+  matrix.values = v
+  matrix.rows = n
+  matrix.cols = m
+  matrix.rowOffset = 0
+  matrix.rowMax = n
+  matrix.colOffset = 0
+  matrix.colMax = m
+  matrix.initTmp()
+  return &matrix
+}
+func (v DenseBigRealVector) String() string {
+  var buffer bytes.Buffer
+  buffer.WriteString("[")
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(", ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  buffer.WriteString("]")
+  return buffer.String()
+}
+func (v DenseBigRealVector) Table() string {
+  var buffer bytes.Buffer
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(" ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  return buffer.String()
+}
+func (v DenseBigRealVector) Export(filename string) error {
+  f, err := os.Create(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  w := bufio.NewWriter(f)
+  defer w.Flush()
+  if _, err := fmt.Fprintf(w, "%s\n", v.Table()); err != nil {
+    return err
+  }
+  return nil
+}
+func (v *DenseBigRealVector) Import(filename string) error {
+  var scanner *bufio.Scanner
+  // open file
+  f, err := os.Open(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  isgzip, err := isGzip(filename)
+  if err != nil {
+    return err
+  }
+  // check if file is gzipped
+  if isgzip {
+    g, err := gzip.NewReader(f)
+    if err != nil {
+      return err
+    }
+    defer g.Close()
+    scanner = bufio.NewScanner(g)
+  } else {
+    scanner = bufio.NewScanner(f)
+  }
+  // reset vector
+  *v = DenseBigRealVector{}
+  for scanner.Scan() {
+    fields := strings.Fields(scanner.Text())
+    if len(fields) == 0 {
+      continue
+    }
+    if len(*v) != 0 {
+      return fmt.Errorf("invalid table")
+    }
+    for i := 0; i < len(fields); i++ {
+      value, err := strconv.ParseFloat(fields[i], 64)
+      if err != nil {
+        return fmt.Errorf("invalid table")
+      }
+      *v = append(*v, NewBigReal(value))
+    }
+  }
+  return nil
+}
+/* json
+ * -------------------------------------------------------------------------- */
+func (obj DenseBigRealVector) MarshalJSON() ([]byte, error) {
+  r := []*BigReal{}
+  r = obj
+  return json.MarshalIndent(r, "", "  ")
+}
+func (obj *DenseBigRealVector) UnmarshalJSON(data []byte) error {
+  r := []*BigReal{}
+  if err := json.Unmarshal(data, &r); err != nil {
+    return err
+  }
+  *obj = nilDenseBigRealVector(len(r))
+  for i := 0; i < len(r); i++ {
+    (*obj)[i] = r[i]
+  }
+  return nil
+}
+/* iterator
+ * -------------------------------------------------------------------------- */
+type DenseBigRealVectorIterator struct {
+  v DenseBigRealVector
+  i int
+}
+func (obj *DenseBigRealVectorIterator) Get() Scalar {
+  return obj.GET()
+}
+func (obj *DenseBigRealVectorIterator) GetConst() ConstScalar {
+  return obj.GET()
+}
+func (obj *DenseBigRealVectorIterator) GetValue() float64 {
+  return obj.GET().GetValue()
+}
+func (obj *DenseBigRealVectorIterator) GET() *BigReal {
+  return obj.v[obj.i]
+}
+func (obj *DenseBigRealVectorIterator) Ok() bool {
+  return obj.i < len(obj.v)
+}
+func (obj *DenseBigRealVectorIterator) Next() {
+  obj.i++
+}
+func (obj *DenseBigRealVectorIterator) Index() int {
+  return obj.i
+}
+func (obj *DenseBigRealVectorIterator) Clone() *DenseBigRealVectorIterator {
+  return &DenseBigRealVectorIterator{obj.v, obj.i}
+}
+func (obj *DenseBigRealVectorIterator) CloneConstIterator() VectorConstIterator {
+  return &DenseBigRealVectorIterator{obj.v, obj.i}
+}
+func (obj *DenseBigRealVectorIterator) CloneIterator() VectorIterator {
+  return &DenseBigRealVectorIterator{obj.v, obj.i}
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseBigRealVectorJointIterator struct {
+  it1 *DenseBigRealVectorIterator
+  it2 VectorConstIterator
+  idx int
+  s1 *BigReal
+  s2 ConstScalar
+}
+func (obj *DenseBigRealVectorJointIterator) Index() int {
+  return obj.idx
+}
+func (obj *DenseBigRealVectorJointIterator) Ok() bool {
+  return !(obj.s1 == nil || obj.s1.GetValue() == 0.0) ||
+         !(obj.s2 == nil || obj.s2.GetValue() == 0.0)
+}
+func (obj *DenseBigRealVectorJointIterator) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GetConst()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GetConst()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  } else {
+    obj.s2 = ConstReal(0.0)
+  }
+}
+func (obj *DenseBigRealVectorJointIterator) Get() (Scalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseBigRealVectorJointIterator) GetConst() (ConstScalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseBigRealVectorJointIterator) GetValue() (float64, float64) {
+  a, b := obj.GET()
+  return a.GetValue(), b.GetValue()
+}
+func (obj *DenseBigRealVectorJointIterator) GET() (*BigReal, ConstScalar) {
+  if obj.s1 == nil {
+    return nil, obj.s2
+  } else {
+    return obj.s1, obj.s2
+  }
+}
+func (obj *DenseBigRealVectorJointIterator) Clone() *DenseBigRealVectorJointIterator {
+  r := DenseBigRealVectorJointIterator{}
+  r.it1 = obj.it1.Clone()
+  r.it2 = obj.it2.CloneConstIterator()
+  r.idx = obj.idx
+  r.s1 = obj.s1
+  r.s2 = obj.s2
+  return &r
+}
+func (obj *DenseBigRealVectorJointIterator) CloneConstJointIterator() VectorConstJointIterator {
+  return obj.Clone()
+}
+func (obj *DenseBigRealVectorJointIterator) CloneJointIterator() VectorJointIterator {
+  return obj.Clone()
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseBigRealVectorJointIterator_ struct {
+  it1 *DenseBigRealVectorIterator
+  it2 *DenseBigRealVectorIterator
+  idx int
+  s1 *BigReal
+  s2 *BigReal
+}
+func (obj *DenseBigRealVectorJointIterator_) Index() int {
+  return obj.idx
+}
+func (obj *DenseBigRealVectorJointIterator_) Ok() bool {
+  return obj.s1 != nil || obj.s2 != nil
+}
+func (obj *DenseBigRealVectorJointIterator_) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GET()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GET()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  }
+}
+func (obj *DenseBigRealVectorJointIterator_) GET() (*BigReal, *BigReal) {
+  return obj.s1, obj.s2
+}
@@ -0,0 +1,12 @@
+
+#define STORE_PTR 1
+
+#define CONST_SCALAR_NAME ConstReal
+#define       SCALAR_NAME Complex
+#define       MATRIX_NAME DenseComplexMatrix
+#define       VECTOR_NAME DenseComplexVector
+
+#define CONST_SCALAR_TYPE CONST_SCALAR_NAME
+#define       SCALAR_TYPE      *SCALAR_NAME
+#define       MATRIX_TYPE      *MATRIX_NAME
+#define       VECTOR_TYPE       VECTOR_NAME
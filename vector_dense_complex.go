@@ -0,0 +1,542 @@
+/* -*- mode: go; -*-
+ *
+ * Copyright (C) 2015-2017 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+/* -------------------------------------------------------------------------- */
+package autodiff
+/* -------------------------------------------------------------------------- */
+import "fmt"
+import "bytes"
+import "bufio"
+import "encoding/json"
+import "errors"
+import "compress/gzip"
+import "sort"
+import "strconv"
+import "strings"
+import "os"
+/* -------------------------------------------------------------------------- */
+/* vector type declaration
+ * -------------------------------------------------------------------------- */
+type DenseComplexVector []*Complex
+/* constructors
+ * -------------------------------------------------------------------------- */
+// Allocate a new vector. Scalars are set to the given values.
+func NewDenseComplexVector(values []float64) DenseComplexVector {
+  v := nilDenseComplexVector(len(values))
+  for i, _ := range values {
+    v[i] = NewComplex(values[i])
+  }
+  return v
+}
+// Allocate a new vector. All scalars are set to zero.
+func NullDenseComplexVector(length int) DenseComplexVector {
+  v := nilDenseComplexVector(length)
+  if length > 0 {
+    for i := 0; i < length; i++ {
+      v[i] = NewComplex(0.0)
+    }
+  }
+  return v
+}
+// Create a empty vector without allocating memory for the scalar variables.
+func nilDenseComplexVector(length int) DenseComplexVector {
+  return make(DenseComplexVector, length)
+}
+// Convert vector type.
+func AsDenseComplexVector(v ConstVector) DenseComplexVector {
+  switch v_ := v.(type) {
+  case DenseComplexVector:
+    return v_.Clone()
+  }
+  r := NullDenseComplexVector(v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    r.AT(i).Set(v.ConstAt(i))
+  }
+  return r
+}
+/* -------------------------------------------------------------------------- */
+// Create a deep copy of the vector.
+func (v DenseComplexVector) Clone() DenseComplexVector {
+  result := make(DenseComplexVector, len(v))
+  for i, _ := range v {
+    result[i] = v[i].Clone()
+  }
+  return result
+}
+func (v DenseComplexVector) CloneVector() Vector {
+  return v.Clone()
+}
+// Copy scalars from w into this vector. The lengths of both vectors must
+// match.
+func (v DenseComplexVector) Set(w ConstVector) {
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].Set(w.ConstAt(i))
+  }
+}
+func (v DenseComplexVector) SET(w DenseComplexVector) {
+  if v.IDEM(w) {
+    return
+  }
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].SET(w.AT(i))
+  }
+}
+func (v DenseComplexVector) IDEM(w DenseComplexVector) bool {
+  if len(v) != len(w) {
+    return false
+  }
+  if len(v) == 0 {
+    return false
+  }
+  return &v[0] == &w[0]
+}
+/* const vector methods
+ * -------------------------------------------------------------------------- */
+func (v DenseComplexVector) ValueAt(i int) float64 {
+  return v[i].GetValue()
+}
+func (v DenseComplexVector) ConstAt(i int) ConstScalar {
+  return v[i]
+}
+func (v DenseComplexVector) ConstSlice(i, j int) ConstVector {
+  return v[i:j]
+}
+func (v DenseComplexVector) GetValues() []float64 {
+  s := make([]float64, v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    s[i] = v.ConstAt(i).GetValue()
+  }
+  return s
+}
+/* iterator methods
+ * -------------------------------------------------------------------------- */
+func (obj DenseComplexVector) ConstIterator() VectorConstIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseComplexVector) Iterator() VectorIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseComplexVector) JointIterator(b ConstVector) VectorJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseComplexVector) ConstJointIterator(b ConstVector) VectorConstJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseComplexVector) ITERATOR() *DenseComplexVectorIterator {
+  r := DenseComplexVectorIterator{obj, -1}
+  r.Next()
+  return &r
+}
+func (obj DenseComplexVector) JOINT_ITERATOR(b ConstVector) *DenseComplexVectorJointIterator {
+  r := DenseComplexVectorJointIterator{obj.ITERATOR(), b.ConstIterator(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+func (obj DenseComplexVector) JOINT_ITERATOR_(b DenseComplexVector) *DenseComplexVectorJointIterator_ {
+  r := DenseComplexVectorJointIterator_{obj.ITERATOR(), b.ITERATOR(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+/* -------------------------------------------------------------------------- */
+func (v DenseComplexVector) Dim() int {
+  return len(v)
+}
+func (v DenseComplexVector) At(i int) Scalar {
+  return v.AT(i)
+}
+func (v DenseComplexVector) AT(i int) *Complex {
+  return v[i]
+}
+func (v DenseComplexVector) Reset() {
+  for i := 0; i < len(v); i++ {
+    v[i].Reset()
+  }
+}
+func (v DenseComplexVector) ResetDerivatives() {
+  for i := 0; i < len(v); i++ {
+    v[i].ResetDerivatives()
+  }
+}
+func (v DenseComplexVector) ReverseOrder() {
+  n := len(v)
+  for i := 0; i < n/2; i++ {
+    v[i], v[n-1-i] = v[n-1-i], v[i]
+  }
+}
+func (v DenseComplexVector) Slice(i, j int) Vector {
+  return v[i:j]
+}
+func (v DenseComplexVector) Append(w DenseComplexVector) DenseComplexVector {
+  return append(v, w...)
+}
+func (v DenseComplexVector) AppendScalar(scalars ...Scalar) Vector {
+  for _, scalar := range scalars {
+    switch s := scalar.(type) {
+    case *Complex:
+      v = append(v, s)
+    default:
+      v = append(v, s.ConvertType(ComplexType).(*Complex))
+    }
+  }
+  return v
+}
+func (v DenseComplexVector) AppendVector(w_ Vector) Vector {
+  switch w := w_.(type) {
+  case DenseComplexVector:
+    return append(v, w...)
+  default:
+    for i := 0; i < w.Dim(); i++ {
+      v = append(v, w.At(i).ConvertType(ComplexType).(*Complex))
+    }
+    return v
+  }
+}
+func (v DenseComplexVector) Swap(i, j int) {
+  v[i], v[j] = v[j], v[i]
+}
+/* imlement ScalarContainer
+ * -------------------------------------------------------------------------- */
+func (v DenseComplexVector) Map(f func(Scalar)) {
+  for i := 0; i < len(v); i++ {
+    f( v[i])
+  }
+}
+func (v DenseComplexVector) MapSet(f func(ConstScalar) Scalar) {
+  for i := 0; i < len(v); i++ {
+    v[i].Set(f(v.ConstAt(i)))
+  }
+}
+func (v DenseComplexVector) Reduce(f func(Scalar, ConstScalar) Scalar, r Scalar) Scalar {
+  for i := 0; i < len(v); i++ {
+    r = f(r, v.ConstAt(i))
+  }
+  return r
+}
+func (v DenseComplexVector) ElementType() ScalarType {
+  return ComplexType
+}
+func (v DenseComplexVector) Variables(order int) error {
+  for i, _ := range v {
+    if err := v[i].SetVariable(i, len(v), order); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+/* permutations
+ * -------------------------------------------------------------------------- */
+func (v DenseComplexVector) Permute(pi []int) error {
+  if len(pi) != len(v) {
+    return errors.New("Permute(): permutation vector has invalid length!")
+  }
+  // permute vector
+  if !applyPermutation(pi, func(i, j int) { v[i], v[j] = v[j], v[i] }) {
+    return errors.New("Permute(): invalid permutation")
+  }
+  return nil
+}
+/* sorting
+ * -------------------------------------------------------------------------- */
+type sortDenseComplexVectorByValue DenseComplexVector
+func (v sortDenseComplexVectorByValue) Len() int { return len(v) }
+func (v sortDenseComplexVectorByValue) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+func (v sortDenseComplexVectorByValue) Less(i, j int) bool { return v[i].GetValue() < v[j].GetValue() }
+func (v DenseComplexVector) Sort(reverse bool) {
+  if reverse {
+    sort.Sort(sort.Reverse(sortDenseComplexVectorByValue(v)))
+  } else {
+    sort.Sort(sortDenseComplexVectorByValue(v))
+  }
+}
+/* type conversion
+ * -------------------------------------------------------------------------- */
+func (v DenseComplexVector) AsMatrix(n, m int) Matrix {
+  return v.ToDenseComplexMatrix(n, m)
+}
+func (v DenseComplexVector) ToDenseComplexMatrix(n, m int) *DenseComplexMatrix {
+  if n*m != len(v) {
+    panic("Matrix dimension does not fit input vector!")
+  }
+  matrix := DenseComplexMatrix{}
+  matrix.values = v
+  matrix.rows = n
+  matrix.cols = m
+  matrix.rowOffset = 0
+  matrix.rowMax = n
+  matrix.colOffset = 0
+  matrix.colMax = m
+  matrix.initTmp()
+  return &matrix
+}
+func (v DenseComplexVector) String() string {
+  var buffer bytes.Buffer
+  buffer.WriteString("[")
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(", ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  buffer.WriteString("]")
+  return buffer.String()
+}
+func (v DenseComplexVector) Table() string {
+  var buffer bytes.Buffer
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(" ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  return buffer.String()
+}
+func (v DenseComplexVector) Export(filename string) error {
+  f, err := os.Create(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  w := bufio.NewWriter(f)
+  defer w.Flush()
+  if _, err := fmt.Fprintf(w, "%s\n", v.Table()); err != nil {
+    return err
+  }
+  return nil
+}
+func (v *DenseComplexVector) Import(filename string) error {
+  var scanner *bufio.Scanner
+  // open file
+  f, err := os.Open(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  isgzip, err := isGzip(filename)
+  if err != nil {
+    return err
+  }
+  // check if file is gzipped
+  if isgzip {
+    g, err := gzip.NewReader(f)
+    if err != nil {
+      return err
+    }
+    defer g.Close()
+    scanner = bufio.NewScanner(g)
+  } else {
+    scanner = bufio.NewScanner(f)
+  }
+  // reset vector
+  *v = DenseComplexVector{}
+  for scanner.Scan() {
+    fields := strings.Fields(scanner.Text())
+    if len(fields) == 0 {
+      continue
+    }
+    if len(*v) != 0 {
+      return fmt.Errorf("invalid table")
+    }
+    for i := 0; i < len(fields); i++ {
+      value, err := strconv.ParseFloat(fields[i], 64)
+      if err != nil {
+        return fmt.Errorf("invalid table")
+      }
+      *v = append(*v, NewComplex(value))
+    }
+  }
+  return nil
+}
+/* json
+ * -------------------------------------------------------------------------- */
+func (obj DenseComplexVector) MarshalJSON() ([]byte, error) {
+  r := []*Complex{}
+  r = obj
+  return json.MarshalIndent(r, "", "  ")
+}
+func (obj *DenseComplexVector) UnmarshalJSON(data []byte) error {
+  r := []*Complex{}
+  if err := json.Unmarshal(data, &r); err != nil {
+    return err
+  }
+  *obj = nilDenseComplexVector(len(r))
+  for i := 0; i < len(r); i++ {
+    (*obj)[i] = r[i]
+  }
+  return nil
+}
+/* iterator
+ * -------------------------------------------------------------------------- */
+type DenseComplexVectorIterator struct {
+  v DenseComplexVector
+  i int
+}
+func (obj *DenseComplexVectorIterator) Get() Scalar {
+  return obj.GET()
+}
+func (obj *DenseComplexVectorIterator) GetConst() ConstScalar {
+  return obj.GET()
+}
+func (obj *DenseComplexVectorIterator) GetValue() float64 {
+  return obj.GET().GetValue()
+}
+func (obj *DenseComplexVectorIterator) GET() *Complex {
+  return obj.v[obj.i]
+}
+func (obj *DenseComplexVectorIterator) Ok() bool {
+  return obj.i < len(obj.v)
+}
+func (obj *DenseComplexVectorIterator) Next() {
+  obj.i++
+}
+func (obj *DenseComplexVectorIterator) Index() int {
+  return obj.i
+}
+func (obj *DenseComplexVectorIterator) Clone() *DenseComplexVectorIterator {
+  return &DenseComplexVectorIterator{obj.v, obj.i}
+}
+func (obj *DenseComplexVectorIterator) CloneConstIterator() VectorConstIterator {
+  return &DenseComplexVectorIterator{obj.v, obj.i}
+}
+func (obj *DenseComplexVectorIterator) CloneIterator() VectorIterator {
+  return &DenseComplexVectorIterator{obj.v, obj.i}
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseComplexVectorJointIterator struct {
+  it1 *DenseComplexVectorIterator
+  it2 VectorConstIterator
+  idx int
+  s1 *Complex
+  s2 ConstScalar
+}
+func (obj *DenseComplexVectorJointIterator) Index() int {
+  return obj.idx
+}
+func (obj *DenseComplexVectorJointIterator) Ok() bool {
+  return !(obj.s1 == nil || obj.s1.GetValue() == 0.0) ||
+         !(obj.s2 == nil || obj.s2.GetValue() == 0.0)
+}
+func (obj *DenseComplexVectorJointIterator) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GetConst()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GetConst()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  } else {
+    obj.s2 = ConstReal(0.0)
+  }
+}
+func (obj *DenseComplexVectorJointIterator) Get() (Scalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseComplexVectorJointIterator) GetConst() (ConstScalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseComplexVectorJointIterator) GetValue() (float64, float64) {
+  a, b := obj.GET()
+  return a.GetValue(), b.GetValue()
+}
+func (obj *DenseComplexVectorJointIterator) GET() (*Complex, ConstScalar) {
+  if obj.s1 == nil {
+    return nil, obj.s2
+  } else {
+    return obj.s1, obj.s2
+  }
+}
+func (obj *DenseComplexVectorJointIterator) Clone() *DenseComplexVectorJointIterator {
+  r := DenseComplexVectorJointIterator{}
+  r.it1 = obj.it1.Clone()
+  r.it2 = obj.it2.CloneConstIterator()
+  r.idx = obj.idx
+  r.s1 = obj.s1
+  r.s2 = obj.s2
+  return &r
+}
+func (obj *DenseComplexVectorJointIterator) CloneConstJointIterator() VectorConstJointIterator {
+  return obj.Clone()
+}
+func (obj *DenseComplexVectorJointIterator) CloneJointIterator() VectorJointIterator {
+  return obj.Clone()
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseComplexVectorJointIterator_ struct {
+  it1 *DenseComplexVectorIterator
+  it2 *DenseComplexVectorIterator
+  idx int
+  s1 *Complex
+  s2 *Complex
+}
+func (obj *DenseComplexVectorJointIterator_) Index() int {
+  return obj.idx
+}
+func (obj *DenseComplexVectorJointIterator_) Ok() bool {
+  return obj.s1 != nil || obj.s2 != nil
+}
+func (obj *DenseComplexVectorJointIterator_) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GET()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GET()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  }
+}
+func (obj *DenseComplexVectorJointIterator_) GET() (*Complex, *Complex) {
+  return obj.s1, obj.s2
+}
@@ -0,0 +1,12 @@
+
+#define STORE_PTR 1
+
+#define CONST_SCALAR_NAME ConstReal
+#define       SCALAR_NAME DualReal
+#define       MATRIX_NAME DenseDualRealMatrix
+#define       VECTOR_NAME DenseDualRealVector
+
+#define CONST_SCALAR_TYPE CONST_SCALAR_NAME
+#define       SCALAR_TYPE      *SCALAR_NAME
+#define       MATRIX_TYPE      *MATRIX_NAME
+#define       VECTOR_TYPE       VECTOR_NAME
@@ -0,0 +1,542 @@
+/* -*- mode: go; -*-
+ *
+ * Copyright (C) 2015-2017 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+/* -------------------------------------------------------------------------- */
+package autodiff
+/* -------------------------------------------------------------------------- */
+import "fmt"
+import "bytes"
+import "bufio"
+import "encoding/json"
+import "errors"
+import "compress/gzip"
+import "sort"
+import "strconv"
+import "strings"
+import "os"
+/* -------------------------------------------------------------------------- */
+/* vector type declaration
+ * -------------------------------------------------------------------------- */
+type DenseDualRealVector []*DualReal
+/* constructors
+ * -------------------------------------------------------------------------- */
+// Allocate a new vector. Scalars are set to the given values.
+func NewDenseDualRealVector(values []float64) DenseDualRealVector {
+  v := nilDenseDualRealVector(len(values))
+  for i, _ := range values {
+    v[i] = NewDualReal(values[i])
+  }
+  return v
+}
+// Allocate a new vector. All scalars are set to zero.
+func NullDenseDualRealVector(length int) DenseDualRealVector {
+  v := nilDenseDualRealVector(length)
+  if length > 0 {
+    for i := 0; i < length; i++ {
+      v[i] = NewDualReal(0.0)
+    }
+  }
+  return v
+}
+// Create a empty vector without allocating memory for the scalar variables.
+func nilDenseDualRealVector(length int) DenseDualRealVector {
+  return make(DenseDualRealVector, length)
+}
+// Convert vector type.
+func AsDenseDualRealVector(v ConstVector) DenseDualRealVector {
+  switch v_ := v.(type) {
+  case DenseDualRealVector:
+    return v_.Clone()
+  }
+  r := NullDenseDualRealVector(v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    r.AT(i).Set(v.ConstAt(i))
+  }
+  return r
+}
+/* -------------------------------------------------------------------------- */
+// Create a deep copy of the vector.
+func (v DenseDualRealVector) Clone() DenseDualRealVector {
+  result := make(DenseDualRealVector, len(v))
+  for i, _ := range v {
+    result[i] = v[i].Clone()
+  }
+  return result
+}
+func (v DenseDualRealVector) CloneVector() Vector {
+  return v.Clone()
+}
+// Copy scalars from w into this vector. The lengths of both vectors must
+// match.
+func (v DenseDualRealVector) Set(w ConstVector) {
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].Set(w.ConstAt(i))
+  }
+}
+func (v DenseDualRealVector) SET(w DenseDualRealVector) {
+  if v.IDEM(w) {
+    return
+  }
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].SET(w.AT(i))
+  }
+}
+func (v DenseDualRealVector) IDEM(w DenseDualRealVector) bool {
+  if len(v) != len(w) {
+    return false
+  }
+  if len(v) == 0 {
+    return false
+  }
+  return &v[0] == &w[0]
+}
+/* const vector methods
+ * -------------------------------------------------------------------------- */
+func (v DenseDualRealVector) ValueAt(i int) float64 {
+  return v[i].GetValue()
+}
+func (v DenseDualRealVector) ConstAt(i int) ConstScalar {
+  return v[i]
+}
+func (v DenseDualRealVector) ConstSlice(i, j int) ConstVector {
+  return v[i:j]
+}
+func (v DenseDualRealVector) GetValues() []float64 {
+  s := make([]float64, v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    s[i] = v.ConstAt(i).GetValue()
+  }
+  return s
+}
+/* iterator methods
+ * -------------------------------------------------------------------------- */
+func (obj DenseDualRealVector) ConstIterator() VectorConstIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseDualRealVector) Iterator() VectorIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseDualRealVector) JointIterator(b ConstVector) VectorJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseDualRealVector) ConstJointIterator(b ConstVector) VectorConstJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseDualRealVector) ITERATOR() *DenseDualRealVectorIterator {
+  r := DenseDualRealVectorIterator{obj, -1}
+  r.Next()
+  return &r
+}
+func (obj DenseDualRealVector) JOINT_ITERATOR(b ConstVector) *DenseDualRealVectorJointIterator {
+  r := DenseDualRealVectorJointIterator{obj.ITERATOR(), b.ConstIterator(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+func (obj DenseDualRealVector) JOINT_ITERATOR_(b DenseDualRealVector) *DenseDualRealVectorJointIterator_ {
+  r := DenseDualRealVectorJointIterator_{obj.ITERATOR(), b.ITERATOR(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+/* -------------------------------------------------------------------------- */
+func (v DenseDualRealVector) Dim() int {
+  return len(v)
+}
+func (v DenseDualRealVector) At(i int) Scalar {
+  return v.AT(i)
+}
+func (v DenseDualRealVector) AT(i int) *DualReal {
+  return v[i]
+}
+func (v DenseDualRealVector) Reset() {
+  for i := 0; i < len(v); i++ {
+    v[i].Reset()
+  }
+}
+func (v DenseDualRealVector) ResetDerivatives() {
+  for i := 0; i < len(v); i++ {
+    v[i].ResetDerivatives()
+  }
+}
+func (v DenseDualRealVector) ReverseOrder() {
+  n := len(v)
+  for i := 0; i < n/2; i++ {
+    v[i], v[n-1-i] = v[n-1-i], v[i]
+  }
+}
+func (v DenseDualRealVector) Slice(i, j int) Vector {
+  return v[i:j]
+}
+func (v DenseDualRealVector) Append(w DenseDualRealVector) DenseDualRealVector {
+  return append(v, w...)
+}
+func (v DenseDualRealVector) AppendScalar(scalars ...Scalar) Vector {
+  for _, scalar := range scalars {
+    switch s := scalar.(type) {
+    case *DualReal:
+      v = append(v, s)
+    default:
+      v = append(v, s.ConvertType(DualRealType).(*DualReal))
+    }
+  }
+  return v
+}
+func (v DenseDualRealVector) AppendVector(w_ Vector) Vector {
+  switch w := w_.(type) {
+  case DenseDualRealVector:
+    return append(v, w...)
+  default:
+    for i := 0; i < w.Dim(); i++ {
+      v = append(v, w.At(i).ConvertType(DualRealType).(*DualReal))
+    }
+    return v
+  }
+}
+func (v DenseDualRealVector) Swap(i, j int) {
+  v[i], v[j] = v[j], v[i]
+}
+/* imlement ScalarContainer
+ * -------------------------------------------------------------------------- */
+func (v DenseDualRealVector) Map(f func(Scalar)) {
+  for i := 0; i < len(v); i++ {
+    f( v[i])
+  }
+}
+func (v DenseDualRealVector) MapSet(f func(ConstScalar) Scalar) {
+  for i := 0; i < len(v); i++ {
+    v[i].Set(f(v.ConstAt(i)))
+  }
+}
+func (v DenseDualRealVector) Reduce(f func(Scalar, ConstScalar) Scalar, r Scalar) Scalar {
+  for i := 0; i < len(v); i++ {
+    r = f(r, v.ConstAt(i))
+  }
+  return r
+}
+func (v DenseDualRealVector) ElementType() ScalarType {
+  return DualRealType
+}
+func (v DenseDualRealVector) Variables(order int) error {
+  for i, _ := range v {
+    if err := v[i].SetVariable(i, len(v), order); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+/* permutations
+ * -------------------------------------------------------------------------- */
+func (v DenseDualRealVector) Permute(pi []int) error {
+  if len(pi) != len(v) {
+    return errors.New("Permute(): permutation vector has invalid length!")
+  }
+  // permute vector
+  if !applyPermutation(pi, func(i, j int) { v[i], v[j] = v[j], v[i] }) {
+    return errors.New("Permute(): invalid permutation")
+  }
+  return nil
+}
+/* sorting
+ * -------------------------------------------------------------------------- */
+type sortDenseDualRealVectorByValue DenseDualRealVector
+func (v sortDenseDualRealVectorByValue) Len() int { return len(v) }
+func (v sortDenseDualRealVectorByValue) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+func (v sortDenseDualRealVectorByValue) Less(i, j int) bool { return v[i].GetValue() < v[j].GetValue() }
+func (v DenseDualRealVector) Sort(reverse bool) {
+  if reverse {
+    sort.Sort(sort.Reverse(sortDenseDualRealVectorByValue(v)))
+  } else {
+    sort.Sort(sortDenseDualRealVectorByValue(v))
+  }
+}
+/* type conversion
+ * -------------------------------------------------------------------------- */
+func (v DenseDualRealVector) AsMatrix(n, m int) Matrix {
+  return v.ToDenseDualRealMatrix(n, m)
+}
+func (v DenseDualRealVector) ToDenseDualRealMatrix(n, m int) *DenseDualRealMatrix {
+  if n*m != len(v) {
+    panic("Matrix dimension does not fit input vector!")
+  }
+  matrix := DenseDualRealMatrix{}
+  matrix.values = v
+  matrix.rows = n
+  matrix.cols = m
+  matrix.rowOffset = 0
+  matrix.rowMax = n
+  matrix.colOffset = 0
+  matrix.colMax = m
+  matrix.initTmp()
+  return &matrix
+}
+func (v DenseDualRealVector) String() string {
+  var buffer bytes.Buffer
+  buffer.WriteString("[")
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(", ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  buffer.WriteString("]")
+  return buffer.String()
+}
+func (v DenseDualRealVector) Table() string {
+  var buffer bytes.Buffer
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(" ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  return buffer.String()
+}
+func (v DenseDualRealVector) Export(filename string) error {
+  f, err := os.Create(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  w := bufio.NewWriter(f)
+  defer w.Flush()
+  if _, err := fmt.Fprintf(w, "%s\n", v.Table()); err != nil {
+    return err
+  }
+  return nil
+}
+func (v *DenseDualRealVector) Import(filename string) error {
+  var scanner *bufio.Scanner
+  // open file
+  f, err := os.Open(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  isgzip, err := isGzip(filename)
+  if err != nil {
+    return err
+  }
+  // check if file is gzipped
+  if isgzip {
+    g, err := gzip.NewReader(f)
+    if err != nil {
+      return err
+    }
+    defer g.Close()
+    scanner = bufio.NewScanner(g)
+  } else {
+    scanner = bufio.NewScanner(f)
+  }
+  // reset vector
+  *v = DenseDualRealVector{}
+  for scanner.Scan() {
+    fields := strings.Fields(scanner.Text())
+    if len(fields) == 0 {
+      continue
+    }
+    if len(*v) != 0 {
+      return fmt.Errorf("invalid table")
+    }
+    for i := 0; i < len(fields); i++ {
+      value, err := strconv.ParseFloat(fields[i], 64)
+      if err != nil {
+        return fmt.Errorf("invalid table")
+      }
+      *v = append(*v, NewDualReal(value))
+    }
+  }
+  return nil
+}
+/* json
+ * -------------------------------------------------------------------------- */
+func (obj DenseDualRealVector) MarshalJSON() ([]byte, error) {
+  r := []*DualReal{}
+  r = obj
+  return json.MarshalIndent(r, "", "  ")
+}
+func (obj *DenseDualRealVector) UnmarshalJSON(data []byte) error {
+  r := []*DualReal{}
+  if err := json.Unmarshal(data, &r); err != nil {
+    return err
+  }
+  *obj = nilDenseDualRealVector(len(r))
+  for i := 0; i < len(r); i++ {
+    (*obj)[i] = r[i]
+  }
+  return nil
+}
+/* iterator
+ * -------------------------------------------------------------------------- */
+type DenseDualRealVectorIterator struct {
+  v DenseDualRealVector
+  i int
+}
+func (obj *DenseDualRealVectorIterator) Get() Scalar {
+  return obj.GET()
+}
+func (obj *DenseDualRealVectorIterator) GetConst() ConstScalar {
+  return obj.GET()
+}
+func (obj *DenseDualRealVectorIterator) GetValue() float64 {
+  return obj.GET().GetValue()
+}
+func (obj *DenseDualRealVectorIterator) GET() *DualReal {
+  return obj.v[obj.i]
+}
+func (obj *DenseDualRealVectorIterator) Ok() bool {
+  return obj.i < len(obj.v)
+}
+func (obj *DenseDualRealVectorIterator) Next() {
+  obj.i++
+}
+func (obj *DenseDualRealVectorIterator) Index() int {
+  return obj.i
+}
+func (obj *DenseDualRealVectorIterator) Clone() *DenseDualRealVectorIterator {
+  return &DenseDualRealVectorIterator{obj.v, obj.i}
+}
+func (obj *DenseDualRealVectorIterator) CloneConstIterator() VectorConstIterator {
+  return &DenseDualRealVectorIterator{obj.v, obj.i}
+}
+func (obj *DenseDualRealVectorIterator) CloneIterator() VectorIterator {
+  return &DenseDualRealVectorIterator{obj.v, obj.i}
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseDualRealVectorJointIterator struct {
+  it1 *DenseDualRealVectorIterator
+  it2 VectorConstIterator
+  idx int
+  s1 *DualReal
+  s2 ConstScalar
+}
+func (obj *DenseDualRealVectorJointIterator) Index() int {
+  return obj.idx
+}
+func (obj *DenseDualRealVectorJointIterator) Ok() bool {
+  return !(obj.s1 == nil || obj.s1.GetValue() == 0.0) ||
+         !(obj.s2 == nil || obj.s2.GetValue() == 0.0)
+}
+func (obj *DenseDualRealVectorJointIterator) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GetConst()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GetConst()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  } else {
+    obj.s2 = ConstReal(0.0)
+  }
+}
+func (obj *DenseDualRealVectorJointIterator) Get() (Scalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseDualRealVectorJointIterator) GetConst() (ConstScalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseDualRealVectorJointIterator) GetValue() (float64, float64) {
+  a, b := obj.GET()
+  return a.GetValue(), b.GetValue()
+}
+func (obj *DenseDualRealVectorJointIterator) GET() (*DualReal, ConstScalar) {
+  if obj.s1 == nil {
+    return nil, obj.s2
+  } else {
+    return obj.s1, obj.s2
+  }
+}
+func (obj *DenseDualRealVectorJointIterator) Clone() *DenseDualRealVectorJointIterator {
+  r := DenseDualRealVectorJointIterator{}
+  r.it1 = obj.it1.Clone()
+  r.it2 = obj.it2.CloneConstIterator()
+  r.idx = obj.idx
+  r.s1 = obj.s1
+  r.s2 = obj.s2
+  return &r
+}
+func (obj *DenseDualRealVectorJointIterator) CloneConstJointIterator() VectorConstJointIterator {
+  return obj.Clone()
+}
+func (obj *DenseDualRealVectorJointIterator) CloneJointIterator() VectorJointIterator {
+  return obj.Clone()
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseDualRealVectorJointIterator_ struct {
+  it1 *DenseDualRealVectorIterator
+  it2 *DenseDualRealVectorIterator
+  idx int
+  s1 *DualReal
+  s2 *DualReal
+}
+func (obj *DenseDualRealVectorJointIterator_) Index() int {
+  return obj.idx
+}
+func (obj *DenseDualRealVectorJointIterator_) Ok() bool {
+  return obj.s1 != nil || obj.s2 != nil
+}
+func (obj *DenseDualRealVectorJointIterator_) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GET()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GET()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  }
+}
+func (obj *DenseDualRealVectorJointIterator_) GET() (*DualReal, *DualReal) {
+  return obj.s1, obj.s2
+}
@@ -0,0 +1,542 @@
+/* -*- mode: go; -*-
+ *
+ * Copyright (C) 2015-2017 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+/* -------------------------------------------------------------------------- */
+package autodiff
+/* -------------------------------------------------------------------------- */
+import "fmt"
+import "bytes"
+import "bufio"
+import "encoding/json"
+import "errors"
+import "compress/gzip"
+import "sort"
+import "strconv"
+import "strings"
+import "os"
+/* -------------------------------------------------------------------------- */
+/* vector type declaration
+ * -------------------------------------------------------------------------- */
+type DenseIntervalVector []*Interval
+/* constructors
+ * -------------------------------------------------------------------------- */
+// Allocate a new vector. Scalars are set to the given values.
+func NewDenseIntervalVector(values []float64) DenseIntervalVector {
+  v := nilDenseIntervalVector(len(values))
+  for i, _ := range values {
+    v[i] = NewInterval(values[i])
+  }
+  return v
+}
+// Allocate a new vector. All scalars are set to zero.
+func NullDenseIntervalVector(length int) DenseIntervalVector {
+  v := nilDenseIntervalVector(length)
+  if length > 0 {
+    for i := 0; i < length; i++ {
+      v[i] = NewInterval(0.0)
+    }
+  }
+  return v
+}
+// Create a empty vector without allocating memory for the scalar variables.
+func nilDenseIntervalVector(length int) DenseIntervalVector {
+  return make(DenseIntervalVector, length)
+}
+// Convert vector type.
+func AsDenseIntervalVector(v ConstVector) DenseIntervalVector {
+  switch v_ := v.(type) {
+  case DenseIntervalVector:
+    return v_.Clone()
+  }
+  r := NullDenseIntervalVector(v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    r.AT(i).Set(v.ConstAt(i))
+  }
+  return r
+}
+/* -------------------------------------------------------------------------- */
+// Create a deep copy of the vector.
+func (v DenseIntervalVector) Clone() DenseIntervalVector {
+  result := make(DenseIntervalVector, len(v))
+  for i, _ := range v {
+    result[i] = v[i].Clone()
+  }
+  return result
+}
+func (v DenseIntervalVector) CloneVector() Vector {
+  return v.Clone()
+}
+// Copy scalars from w into this vector. The lengths of both vectors must
+// match.
+func (v DenseIntervalVector) Set(w ConstVector) {
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].Set(w.ConstAt(i))
+  }
+}
+func (v DenseIntervalVector) SET(w DenseIntervalVector) {
+  if v.IDEM(w) {
+    return
+  }
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].SET(w.AT(i))
+  }
+}
+func (v DenseIntervalVector) IDEM(w DenseIntervalVector) bool {
+  if len(v) != len(w) {
+    return false
+  }
+  if len(v) == 0 {
+    return false
+  }
+  return &v[0] == &w[0]
+}
+/* const vector methods
+ * -------------------------------------------------------------------------- */
+func (v DenseIntervalVector) ValueAt(i int) float64 {
+  return v[i].GetValue()
+}
+func (v DenseIntervalVector) ConstAt(i int) ConstScalar {
+  return v[i]
+}
+func (v DenseIntervalVector) ConstSlice(i, j int) ConstVector {
+  return v[i:j]
+}
+func (v DenseIntervalVector) GetValues() []float64 {
+  s := make([]float64, v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    s[i] = v.ConstAt(i).GetValue()
+  }
+  return s
+}
+/* iterator methods
+ * -------------------------------------------------------------------------- */
+func (obj DenseIntervalVector) ConstIterator() VectorConstIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseIntervalVector) Iterator() VectorIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseIntervalVector) JointIterator(b ConstVector) VectorJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseIntervalVector) ConstJointIterator(b ConstVector) VectorConstJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseIntervalVector) ITERATOR() *DenseIntervalVectorIterator {
+  r := DenseIntervalVectorIterator{obj, -1}
+  r.Next()
+  return &r
+}
+func (obj DenseIntervalVector) JOINT_ITERATOR(b ConstVector) *DenseIntervalVectorJointIterator {
+  r := DenseIntervalVectorJointIterator{obj.ITERATOR(), b.ConstIterator(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+func (obj DenseIntervalVector) JOINT_ITERATOR_(b DenseIntervalVector) *DenseIntervalVectorJointIterator_ {
+  r := DenseIntervalVectorJointIterator_{obj.ITERATOR(), b.ITERATOR(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+/* -------------------------------------------------------------------------- */
+func (v DenseIntervalVector) Dim() int {
+  return len(v)
+}
+func (v DenseIntervalVector) At(i int) Scalar {
+  return v.AT(i)
+}
+func (v DenseIntervalVector) AT(i int) *Interval {
+  return v[i]
+}
+func (v DenseIntervalVector) Reset() {
+  for i := 0; i < len(v); i++ {
+    v[i].Reset()
+  }
+}
+func (v DenseIntervalVector) ResetDerivatives() {
+  for i := 0; i < len(v); i++ {
+    v[i].ResetDerivatives()
+  }
+}
+func (v DenseIntervalVector) ReverseOrder() {
+  n := len(v)
+  for i := 0; i < n/2; i++ {
+    v[i], v[n-1-i] = v[n-1-i], v[i]
+  }
+}
+func (v DenseIntervalVector) Slice(i, j int) Vector {
+  return v[i:j]
+}
+func (v DenseIntervalVector) Append(w DenseIntervalVector) DenseIntervalVector {
+  return append(v, w...)
+}
+func (v DenseIntervalVector) AppendScalar(scalars ...Scalar) Vector {
+  for _, scalar := range scalars {
+    switch s := scalar.(type) {
+    case *Interval:
+      v = append(v, s)
+    default:
+      v = append(v, s.ConvertType(IntervalType).(*Interval))
+    }
+  }
+  return v
+}
+func (v DenseIntervalVector) AppendVector(w_ Vector) Vector {
+  switch w := w_.(type) {
+  case DenseIntervalVector:
+    return append(v, w...)
+  default:
+    for i := 0; i < w.Dim(); i++ {
+      v = append(v, w.At(i).ConvertType(IntervalType).(*Interval))
+    }
+    return v
+  }
+}
+func (v DenseIntervalVector) Swap(i, j int) {
+  v[i], v[j] = v[j], v[i]
+}
+/* imlement ScalarContainer
+ * -------------------------------------------------------------------------- */
+func (v DenseIntervalVector) Map(f func(Scalar)) {
+  for i := 0; i < len(v); i++ {
+    f( v[i])
+  }
+}
+func (v DenseIntervalVector) MapSet(f func(ConstScalar) Scalar) {
+  for i := 0; i < len(v); i++ {
+    v[i].Set(f(v.ConstAt(i)))
+  }
+}
+func (v DenseIntervalVector) Reduce(f func(Scalar, ConstScalar) Scalar, r Scalar) Scalar {
+  for i := 0; i < len(v); i++ {
+    r = f(r, v.ConstAt(i))
+  }
+  return r
+}
+func (v DenseIntervalVector) ElementType() ScalarType {
+  return IntervalType
+}
+func (v DenseIntervalVector) Variables(order int) error {
+  for i, _ := range v {
+    if err := v[i].SetVariable(i, len(v), order); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+/* permutations
+ * -------------------------------------------------------------------------- */
+func (v DenseIntervalVector) Permute(pi []int) error {
+  if len(pi) != len(v) {
+    return errors.New("Permute(): permutation vector has invalid length!")
+  }
+  // permute vector
+  if !applyPermutation(pi, func(i, j int) { v[i], v[j] = v[j], v[i] }) {
+    return errors.New("Permute(): invalid permutation")
+  }
+  return nil
+}
+/* sorting
+ * -------------------------------------------------------------------------- */
+type sortDenseIntervalVectorByValue DenseIntervalVector
+func (v sortDenseIntervalVectorByValue) Len() int { return len(v) }
+func (v sortDenseIntervalVectorByValue) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+func (v sortDenseIntervalVectorByValue) Less(i, j int) bool { return v[i].GetValue() < v[j].GetValue() }
+func (v DenseIntervalVector) Sort(reverse bool) {
+  if reverse {
+    sort.Sort(sort.Reverse(sortDenseIntervalVectorByValue(v)))
+  } else {
+    sort.Sort(sortDenseIntervalVectorByValue(v))
+  }
+}
+/* type conversion
+ * -------------------------------------------------------------------------- */
+func (v DenseIntervalVector) AsMatrix(n, m int) Matrix {
+  return v.ToDenseIntervalMatrix(n, m)
+}
+func (v DenseIntervalVector) ToDenseIntervalMatrix(n, m int) *DenseIntervalMatrix {
+  if n*m != len(v) {
+    panic("Matrix dimension does not fit input vector!")
+  }
+  matrix := DenseIntervalMatrix{}
+  matrix.values = v
+  matrix.rows = n
+  matrix.cols = m
+  matrix.rowOffset = 0
+  matrix.rowMax = n
+  matrix.colOffset = 0
+  matrix.colMax = m
+  matrix.initTmp()
+  return &matrix
+}
+func (v DenseIntervalVector) String() string {
+  var buffer bytes.Buffer
+  buffer.WriteString("[")
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(", ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  buffer.WriteString("]")
+  return buffer.String()
+}
+func (v DenseIntervalVector) Table() string {
+  var buffer bytes.Buffer
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(" ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  return buffer.String()
+}
+func (v DenseIntervalVector) Export(filename string) error {
+  f, err := os.Create(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  w := bufio.NewWriter(f)
+  defer w.Flush()
+  if _, err := fmt.Fprintf(w, "%s\n", v.Table()); err != nil {
+    return err
+  }
+  return nil
+}
+func (v *DenseIntervalVector) Import(filename string) error {
+  var scanner *bufio.Scanner
+  // open file
+  f, err := os.Open(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  isgzip, err := isGzip(filename)
+  if err != nil {
+    return err
+  }
+  // check if file is gzipped
+  if isgzip {
+    g, err := gzip.NewReader(f)
+    if err != nil {
+      return err
+    }
+    defer g.Close()
+    scanner = bufio.NewScanner(g)
+  } else {
+    scanner = bufio.NewScanner(f)
+  }
+  // reset vector
+  *v = DenseIntervalVector{}
+  for scanner.Scan() {
+    fields := strings.Fields(scanner.Text())
+    if len(fields) == 0 {
+      continue
+    }
+    if len(*v) != 0 {
+      return fmt.Errorf("invalid table")
+    }
+    for i := 0; i < len(fields); i++ {
+      value, err := strconv.ParseFloat(fields[i], 64)
+      if err != nil {
+        return fmt.Errorf("invalid table")
+      }
+      *v = append(*v, NewInterval(value))
+    }
+  }
+  return nil
+}
+/* json
+ * -------------------------------------------------------------------------- */
+func (obj DenseIntervalVector) MarshalJSON() ([]byte, error) {
+  r := []*Interval{}
+  r = obj
+  return json.MarshalIndent(r, "", "  ")
+}
+func (obj *DenseIntervalVector) UnmarshalJSON(data []byte) error {
+  r := []*Interval{}
+  if err := json.Unmarshal(data, &r); err != nil {
+    return err
+  }
+  *obj = nilDenseIntervalVector(len(r))
+  for i := 0; i < len(r); i++ {
+    (*obj)[i] = r[i]
+  }
+  return nil
+}
+/* iterator
+ * -------------------------------------------------------------------------- */
+type DenseIntervalVectorIterator struct {
+  v DenseIntervalVector
+  i int
+}
+func (obj *DenseIntervalVectorIterator) Get() Scalar {
+  return obj.GET()
+}
+func (obj *DenseIntervalVectorIterator) GetConst() ConstScalar {
+  return obj.GET()
+}
+func (obj *DenseIntervalVectorIterator) GetValue() float64 {
+  return obj.GET().GetValue()
+}
+func (obj *DenseIntervalVectorIterator) GET() *Interval {
+  return obj.v[obj.i]
+}
+func (obj *DenseIntervalVectorIterator) Ok() bool {
+  return obj.i < len(obj.v)
+}
+func (obj *DenseIntervalVectorIterator) Next() {
+  obj.i++
+}
+func (obj *DenseIntervalVectorIterator) Index() int {
+  return obj.i
+}
+func (obj *DenseIntervalVectorIterator) Clone() *DenseIntervalVectorIterator {
+  return &DenseIntervalVectorIterator{obj.v, obj.i}
+}
+func (obj *DenseIntervalVectorIterator) CloneConstIterator() VectorConstIterator {
+  return &DenseIntervalVectorIterator{obj.v, obj.i}
+}
+func (obj *DenseIntervalVectorIterator) CloneIterator() VectorIterator {
+  return &DenseIntervalVectorIterator{obj.v, obj.i}
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseIntervalVectorJointIterator struct {
+  it1 *DenseIntervalVectorIterator
+  it2 VectorConstIterator
+  idx int
+  s1 *Interval
+  s2 ConstScalar
+}
+func (obj *DenseIntervalVectorJointIterator) Index() int {
+  return obj.idx
+}
+func (obj *DenseIntervalVectorJointIterator) Ok() bool {
+  return !(obj.s1 == nil || obj.s1.GetValue() == 0.0) ||
+         !(obj.s2 == nil || obj.s2.GetValue() == 0.0)
+}
+func (obj *DenseIntervalVectorJointIterator) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GetConst()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GetConst()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  } else {
+    obj.s2 = ConstReal(0.0)
+  }
+}
+func (obj *DenseIntervalVectorJointIterator) Get() (Scalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseIntervalVectorJointIterator) GetConst() (ConstScalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseIntervalVectorJointIterator) GetValue() (float64, float64) {
+  a, b := obj.GET()
+  return a.GetValue(), b.GetValue()
+}
+func (obj *DenseIntervalVectorJointIterator) GET() (*Interval, ConstScalar) {
+  if obj.s1 == nil {
+    return nil, obj.s2
+  } else {
+    return obj.s1, obj.s2
+  }
+}
+func (obj *DenseIntervalVectorJointIterator) Clone() *DenseIntervalVectorJointIterator {
+  r := DenseIntervalVectorJointIterator{}
+  r.it1 = obj.it1.Clone()
+  r.it2 = obj.it2.CloneConstIterator()
+  r.idx = obj.idx
+  r.s1 = obj.s1
+  r.s2 = obj.s2
+  return &r
+}
+func (obj *DenseIntervalVectorJointIterator) CloneConstJointIterator() VectorConstJointIterator {
+  return obj.Clone()
+}
+func (obj *DenseIntervalVectorJointIterator) CloneJointIterator() VectorJointIterator {
+  return obj.Clone()
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseIntervalVectorJointIterator_ struct {
+  it1 *DenseIntervalVectorIterator
+  it2 *DenseIntervalVectorIterator
+  idx int
+  s1 *Interval
+  s2 *Interval
+}
+func (obj *DenseIntervalVectorJointIterator_) Index() int {
+  return obj.idx
+}
+func (obj *DenseIntervalVectorJointIterator_) Ok() bool {
+  return obj.s1 != nil || obj.s2 != nil
+}
+func (obj *DenseIntervalVectorJointIterator_) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GET()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GET()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  }
+}
+func (obj *DenseIntervalVectorJointIterator_) GET() (*Interval, *Interval) {
+  return obj.s1, obj.s2
+}
@@ -250,14 +250,8 @@ func (v DenseRealVector) Permute(pi []int) error {
     return errors.New("Permute(): permutation vector has invalid length!")
   }
   // permute vector
-  for i := 0; i < len(v); i++ {
-    if pi[i] < 0 || pi[i] >= len(v) {
-      return errors.New("Permute(): invalid permutation")
-    }
-    if i != pi[i] && pi[i] > i {
-      // permute elements
-      v[pi[i]], v[i] = v[i], v[pi[i]]
-    }
+  if !applyPermutation(pi, func(i, j int) { v[i], v[j] = v[j], v[i] }) {
+    return errors.New("Permute(): invalid permutation")
   }
   return nil
 }
@@ -0,0 +1,542 @@
+/* -*- mode: go; -*-
+ *
+ * Copyright (C) 2015-2017 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+/* -------------------------------------------------------------------------- */
+package autodiff
+/* -------------------------------------------------------------------------- */
+import "fmt"
+import "bytes"
+import "bufio"
+import "encoding/json"
+import "errors"
+import "compress/gzip"
+import "sort"
+import "strconv"
+import "strings"
+import "os"
+/* -------------------------------------------------------------------------- */
+/* vector type declaration
+ * -------------------------------------------------------------------------- */
+type DenseReverseRealVector []*ReverseReal
+/* constructors
+ * -------------------------------------------------------------------------- */
+// Allocate a new vector. Scalars are set to the given values.
+func NewDenseReverseRealVector(values []float64) DenseReverseRealVector {
+  v := nilDenseReverseRealVector(len(values))
+  for i, _ := range values {
+    v[i] = NewReverseReal(values[i])
+  }
+  return v
+}
+// Allocate a new vector. All scalars are set to zero.
+func NullDenseReverseRealVector(length int) DenseReverseRealVector {
+  v := nilDenseReverseRealVector(length)
+  if length > 0 {
+    for i := 0; i < length; i++ {
+      v[i] = NewReverseReal(0.0)
+    }
+  }
+  return v
+}
+// Create a empty vector without allocating memory for the scalar variables.
+func nilDenseReverseRealVector(length int) DenseReverseRealVector {
+  return make(DenseReverseRealVector, length)
+}
+// Convert vector type.
+func AsDenseReverseRealVector(v ConstVector) DenseReverseRealVector {
+  switch v_ := v.(type) {
+  case DenseReverseRealVector:
+    return v_.Clone()
+  }
+  r := NullDenseReverseRealVector(v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    r.AT(i).Set(v.ConstAt(i))
+  }
+  return r
+}
+/* -------------------------------------------------------------------------- */
+// Create a deep copy of the vector.
+func (v DenseReverseRealVector) Clone() DenseReverseRealVector {
+  result := make(DenseReverseRealVector, len(v))
+  for i, _ := range v {
+    result[i] = v[i].Clone()
+  }
+  return result
+}
+func (v DenseReverseRealVector) CloneVector() Vector {
+  return v.Clone()
+}
+// Copy scalars from w into this vector. The lengths of both vectors must
+// match.
+func (v DenseReverseRealVector) Set(w ConstVector) {
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].Set(w.ConstAt(i))
+  }
+}
+func (v DenseReverseRealVector) SET(w DenseReverseRealVector) {
+  if v.IDEM(w) {
+    return
+  }
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].SET(w.AT(i))
+  }
+}
+func (v DenseReverseRealVector) IDEM(w DenseReverseRealVector) bool {
+  if len(v) != len(w) {
+    return false
+  }
+  if len(v) == 0 {
+    return false
+  }
+  return &v[0] == &w[0]
+}
+/* const vector methods
+ * -------------------------------------------------------------------------- */
+func (v DenseReverseRealVector) ValueAt(i int) float64 {
+  return v[i].GetValue()
+}
+func (v DenseReverseRealVector) ConstAt(i int) ConstScalar {
+  return v[i]
+}
+func (v DenseReverseRealVector) ConstSlice(i, j int) ConstVector {
+  return v[i:j]
+}
+func (v DenseReverseRealVector) GetValues() []float64 {
+  s := make([]float64, v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    s[i] = v.ConstAt(i).GetValue()
+  }
+  return s
+}
+/* iterator methods
+ * -------------------------------------------------------------------------- */
+func (obj DenseReverseRealVector) ConstIterator() VectorConstIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseReverseRealVector) Iterator() VectorIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseReverseRealVector) JointIterator(b ConstVector) VectorJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseReverseRealVector) ConstJointIterator(b ConstVector) VectorConstJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseReverseRealVector) ITERATOR() *DenseReverseRealVectorIterator {
+  r := DenseReverseRealVectorIterator{obj, -1}
+  r.Next()
+  return &r
+}
+func (obj DenseReverseRealVector) JOINT_ITERATOR(b ConstVector) *DenseReverseRealVectorJointIterator {
+  r := DenseReverseRealVectorJointIterator{obj.ITERATOR(), b.ConstIterator(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+func (obj DenseReverseRealVector) JOINT_ITERATOR_(b DenseReverseRealVector) *DenseReverseRealVectorJointIterator_ {
+  r := DenseReverseRealVectorJointIterator_{obj.ITERATOR(), b.ITERATOR(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+/* -------------------------------------------------------------------------- */
+func (v DenseReverseRealVector) Dim() int {
+  return len(v)
+}
+func (v DenseReverseRealVector) At(i int) Scalar {
+  return v.AT(i)
+}
+func (v DenseReverseRealVector) AT(i int) *ReverseReal {
+  return v[i]
+}
+func (v DenseReverseRealVector) Reset() {
+  for i := 0; i < len(v); i++ {
+    v[i].Reset()
+  }
+}
+func (v DenseReverseRealVector) ResetDerivatives() {
+  for i := 0; i < len(v); i++ {
+    v[i].ResetDerivatives()
+  }
+}
+func (v DenseReverseRealVector) ReverseOrder() {
+  n := len(v)
+  for i := 0; i < n/2; i++ {
+    v[i], v[n-1-i] = v[n-1-i], v[i]
+  }
+}
+func (v DenseReverseRealVector) Slice(i, j int) Vector {
+  return v[i:j]
+}
+func (v DenseReverseRealVector) Append(w DenseReverseRealVector) DenseReverseRealVector {
+  return append(v, w...)
+}
+func (v DenseReverseRealVector) AppendScalar(scalars ...Scalar) Vector {
+  for _, scalar := range scalars {
+    switch s := scalar.(type) {
+    case *ReverseReal:
+      v = append(v, s)
+    default:
+      v = append(v, s.ConvertType(ReverseRealType).(*ReverseReal))
+    }
+  }
+  return v
+}
+func (v DenseReverseRealVector) AppendVector(w_ Vector) Vector {
+  switch w := w_.(type) {
+  case DenseReverseRealVector:
+    return append(v, w...)
+  default:
+    for i := 0; i < w.Dim(); i++ {
+      v = append(v, w.At(i).ConvertType(ReverseRealType).(*ReverseReal))
+    }
+    return v
+  }
+}
+func (v DenseReverseRealVector) Swap(i, j int) {
+  v[i], v[j] = v[j], v[i]
+}
+/* imlement ScalarContainer
+ * -------------------------------------------------------------------------- */
+func (v DenseReverseRealVector) Map(f func(Scalar)) {
+  for i := 0; i < len(v); i++ {
+    f( v[i])
+  }
+}
+func (v DenseReverseRealVector) MapSet(f func(ConstScalar) Scalar) {
+  for i := 0; i < len(v); i++ {
+    v[i].Set(f(v.ConstAt(i)))
+  }
+}
+func (v DenseReverseRealVector) Reduce(f func(Scalar, ConstScalar) Scalar, r Scalar) Scalar {
+  for i := 0; i < len(v); i++ {
+    r = f(r, v.ConstAt(i))
+  }
+  return r
+}
+func (v DenseReverseRealVector) ElementType() ScalarType {
+  return ReverseRealType
+}
+func (v DenseReverseRealVector) Variables(order int) error {
+  for i, _ := range v {
+    if err := v[i].SetVariable(i, len(v), order); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+/* permutations
+ * -------------------------------------------------------------------------- */
+func (v DenseReverseRealVector) Permute(pi []int) error {
+  if len(pi) != len(v) {
+    return errors.New("Permute(): permutation vector has invalid length!")
+  }
+  // permute vector
+  if !applyPermutation(pi, func(i, j int) { v[i], v[j] = v[j], v[i] }) {
+    return errors.New("Permute(): invalid permutation")
+  }
+  return nil
+}
+/* sorting
+ * -------------------------------------------------------------------------- */
+type sortDenseReverseRealVectorByValue DenseReverseRealVector
+func (v sortDenseReverseRealVectorByValue) Len() int { return len(v) }
+func (v sortDenseReverseRealVectorByValue) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+func (v sortDenseReverseRealVectorByValue) Less(i, j int) bool { return v[i].GetValue() < v[j].GetValue() }
+func (v DenseReverseRealVector) Sort(reverse bool) {
+  if reverse {
+    sort.Sort(sort.Reverse(sortDenseReverseRealVectorByValue(v)))
+  } else {
+    sort.Sort(sortDenseReverseRealVectorByValue(v))
+  }
+}
+/* type conversion
+ * -------------------------------------------------------------------------- */
+func (v DenseReverseRealVector) AsMatrix(n, m int) Matrix {
+  return v.ToDenseReverseRealMatrix(n, m)
+}
+func (v DenseReverseRealVector) ToDenseReverseRealMatrix(n, m int) *DenseReverseRealMatrix {
+  if n*m != len(v) {
+    panic("Matrix dimension does not fit input vector!")
+  }
+  matrix := DenseReverseRealMatrix{}
+  matrix.values = v
+  matrix.rows = n
+  matrix.cols = m
+  matrix.rowOffset = 0
+  matrix.rowMax = n
+  matrix.colOffset = 0
+  matrix.colMax = m
+  matrix.initTmp()
+  return &matrix
+}
+func (v DenseReverseRealVector) String() string {
+  var buffer bytes.Buffer
+  buffer.WriteString("[")
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(", ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  buffer.WriteString("]")
+  return buffer.String()
+}
+func (v DenseReverseRealVector) Table() string {
+  var buffer bytes.Buffer
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(" ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  return buffer.String()
+}
+func (v DenseReverseRealVector) Export(filename string) error {
+  f, err := os.Create(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  w := bufio.NewWriter(f)
+  defer w.Flush()
+  if _, err := fmt.Fprintf(w, "%s\n", v.Table()); err != nil {
+    return err
+  }
+  return nil
+}
+func (v *DenseReverseRealVector) Import(filename string) error {
+  var scanner *bufio.Scanner
+  // open file
+  f, err := os.Open(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  isgzip, err := isGzip(filename)
+  if err != nil {
+    return err
+  }
+  // check if file is gzipped
+  if isgzip {
+    g, err := gzip.NewReader(f)
+    if err != nil {
+      return err
+    }
+    defer g.Close()
+    scanner = bufio.NewScanner(g)
+  } else {
+    scanner = bufio.NewScanner(f)
+  }
+  // reset vector
+  *v = DenseReverseRealVector{}
+  for scanner.Scan() {
+    fields := strings.Fields(scanner.Text())
+    if len(fields) == 0 {
+      continue
+    }
+    if len(*v) != 0 {
+      return fmt.Errorf("invalid table")
+    }
+    for i := 0; i < len(fields); i++ {
+      value, err := strconv.ParseFloat(fields[i], 64)
+      if err != nil {
+        return fmt.Errorf("invalid table")
+      }
+      *v = append(*v, NewReverseReal(value))
+    }
+  }
+  return nil
+}
+/* json
+ * -------------------------------------------------------------------------- */
+func (obj DenseReverseRealVector) MarshalJSON() ([]byte, error) {
+  r := []*ReverseReal{}
+  r = obj
+  return json.MarshalIndent(r, "", "  ")
+}
+func (obj *DenseReverseRealVector) UnmarshalJSON(data []byte) error {
+  r := []*ReverseReal{}
+  if err := json.Unmarshal(data, &r); err != nil {
+    return err
+  }
+  *obj = nilDenseReverseRealVector(len(r))
+  for i := 0; i < len(r); i++ {
+    (*obj)[i] = r[i]
+  }
+  return nil
+}
+/* iterator
+ * -------------------------------------------------------------------------- */
+type DenseReverseRealVectorIterator struct {
+  v DenseReverseRealVector
+  i int
+}
+func (obj *DenseReverseRealVectorIterator) Get() Scalar {
+  return obj.GET()
+}
+func (obj *DenseReverseRealVectorIterator) GetConst() ConstScalar {
+  return obj.GET()
+}
+func (obj *DenseReverseRealVectorIterator) GetValue() float64 {
+  return obj.GET().GetValue()
+}
+func (obj *DenseReverseRealVectorIterator) GET() *ReverseReal {
+  return obj.v[obj.i]
+}
+func (obj *DenseReverseRealVectorIterator) Ok() bool {
+  return obj.i < len(obj.v)
+}
+func (obj *DenseReverseRealVectorIterator) Next() {
+  obj.i++
+}
+func (obj *DenseReverseRealVectorIterator) Index() int {
+  return obj.i
+}
+func (obj *DenseReverseRealVectorIterator) Clone() *DenseReverseRealVectorIterator {
+  return &DenseReverseRealVectorIterator{obj.v, obj.i}
+}
+func (obj *DenseReverseRealVectorIterator) CloneConstIterator() VectorConstIterator {
+  return &DenseReverseRealVectorIterator{obj.v, obj.i}
+}
+func (obj *DenseReverseRealVectorIterator) CloneIterator() VectorIterator {
+  return &DenseReverseRealVectorIterator{obj.v, obj.i}
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseReverseRealVectorJointIterator struct {
+  it1 *DenseReverseRealVectorIterator
+  it2 VectorConstIterator
+  idx int
+  s1 *ReverseReal
+  s2 ConstScalar
+}
+func (obj *DenseReverseRealVectorJointIterator) Index() int {
+  return obj.idx
+}
+func (obj *DenseReverseRealVectorJointIterator) Ok() bool {
+  return !(obj.s1 == nil || obj.s1.GetValue() == 0.0) ||
+         !(obj.s2 == nil || obj.s2.GetValue() == 0.0)
+}
+func (obj *DenseReverseRealVectorJointIterator) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GetConst()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GetConst()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  } else {
+    obj.s2 = ConstReal(0.0)
+  }
+}
+func (obj *DenseReverseRealVectorJointIterator) Get() (Scalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseReverseRealVectorJointIterator) GetConst() (ConstScalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseReverseRealVectorJointIterator) GetValue() (float64, float64) {
+  a, b := obj.GET()
+  return a.GetValue(), b.GetValue()
+}
+func (obj *DenseReverseRealVectorJointIterator) GET() (*ReverseReal, ConstScalar) {
+  if obj.s1 == nil {
+    return nil, obj.s2
+  } else {
+    return obj.s1, obj.s2
+  }
+}
+func (obj *DenseReverseRealVectorJointIterator) Clone() *DenseReverseRealVectorJointIterator {
+  r := DenseReverseRealVectorJointIterator{}
+  r.it1 = obj.it1.Clone()
+  r.it2 = obj.it2.CloneConstIterator()
+  r.idx = obj.idx
+  r.s1 = obj.s1
+  r.s2 = obj.s2
+  return &r
+}
+func (obj *DenseReverseRealVectorJointIterator) CloneConstJointIterator() VectorConstJointIterator {
+  return obj.Clone()
+}
+func (obj *DenseReverseRealVectorJointIterator) CloneJointIterator() VectorJointIterator {
+  return obj.Clone()
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseReverseRealVectorJointIterator_ struct {
+  it1 *DenseReverseRealVectorIterator
+  it2 *DenseReverseRealVectorIterator
+  idx int
+  s1 *ReverseReal
+  s2 *ReverseReal
+}
+func (obj *DenseReverseRealVectorJointIterator_) Index() int {
+  return obj.idx
+}
+func (obj *DenseReverseRealVectorJointIterator_) Ok() bool {
+  return obj.s1 != nil || obj.s2 != nil
+}
+func (obj *DenseReverseRealVectorJointIterator_) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GET()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GET()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  }
+}
+func (obj *DenseReverseRealVectorJointIterator_) GET() (*ReverseReal, *ReverseReal) {
+  return obj.s1, obj.s2
+}
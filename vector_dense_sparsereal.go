@@ -0,0 +1,542 @@
+/* -*- mode: go; -*-
+ *
+ * Copyright (C) 2015-2017 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+/* -------------------------------------------------------------------------- */
+package autodiff
+/* -------------------------------------------------------------------------- */
+import "fmt"
+import "bytes"
+import "bufio"
+import "encoding/json"
+import "errors"
+import "compress/gzip"
+import "sort"
+import "strconv"
+import "strings"
+import "os"
+/* -------------------------------------------------------------------------- */
+/* vector type declaration
+ * -------------------------------------------------------------------------- */
+type DenseSparseRealVector []*SparseReal
+/* constructors
+ * -------------------------------------------------------------------------- */
+// Allocate a new vector. Scalars are set to the given values.
+func NewDenseSparseRealVector(values []float64) DenseSparseRealVector {
+  v := nilDenseSparseRealVector(len(values))
+  for i, _ := range values {
+    v[i] = NewSparseReal(values[i])
+  }
+  return v
+}
+// Allocate a new vector. All scalars are set to zero.
+func NullDenseSparseRealVector(length int) DenseSparseRealVector {
+  v := nilDenseSparseRealVector(length)
+  if length > 0 {
+    for i := 0; i < length; i++ {
+      v[i] = NewSparseReal(0.0)
+    }
+  }
+  return v
+}
+// Create a empty vector without allocating memory for the scalar variables.
+func nilDenseSparseRealVector(length int) DenseSparseRealVector {
+  return make(DenseSparseRealVector, length)
+}
+// Convert vector type.
+func AsDenseSparseRealVector(v ConstVector) DenseSparseRealVector {
+  switch v_ := v.(type) {
+  case DenseSparseRealVector:
+    return v_.Clone()
+  }
+  r := NullDenseSparseRealVector(v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    r.AT(i).Set(v.ConstAt(i))
+  }
+  return r
+}
+/* -------------------------------------------------------------------------- */
+// Create a deep copy of the vector.
+func (v DenseSparseRealVector) Clone() DenseSparseRealVector {
+  result := make(DenseSparseRealVector, len(v))
+  for i, _ := range v {
+    result[i] = v[i].Clone()
+  }
+  return result
+}
+func (v DenseSparseRealVector) CloneVector() Vector {
+  return v.Clone()
+}
+// Copy scalars from w into this vector. The lengths of both vectors must
+// match.
+func (v DenseSparseRealVector) Set(w ConstVector) {
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].Set(w.ConstAt(i))
+  }
+}
+func (v DenseSparseRealVector) SET(w DenseSparseRealVector) {
+  if v.IDEM(w) {
+    return
+  }
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].SET(w.AT(i))
+  }
+}
+func (v DenseSparseRealVector) IDEM(w DenseSparseRealVector) bool {
+  if len(v) != len(w) {
+    return false
+  }
+  if len(v) == 0 {
+    return false
+  }
+  return &v[0] == &w[0]
+}
+/* const vector methods
+ * -------------------------------------------------------------------------- */
+func (v DenseSparseRealVector) ValueAt(i int) float64 {
+  return v[i].GetValue()
+}
+func (v DenseSparseRealVector) ConstAt(i int) ConstScalar {
+  return v[i]
+}
+func (v DenseSparseRealVector) ConstSlice(i, j int) ConstVector {
+  return v[i:j]
+}
+func (v DenseSparseRealVector) GetValues() []float64 {
+  s := make([]float64, v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    s[i] = v.ConstAt(i).GetValue()
+  }
+  return s
+}
+/* iterator methods
+ * -------------------------------------------------------------------------- */
+func (obj DenseSparseRealVector) ConstIterator() VectorConstIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseSparseRealVector) Iterator() VectorIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseSparseRealVector) JointIterator(b ConstVector) VectorJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseSparseRealVector) ConstJointIterator(b ConstVector) VectorConstJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseSparseRealVector) ITERATOR() *DenseSparseRealVectorIterator {
+  r := DenseSparseRealVectorIterator{obj, -1}
+  r.Next()
+  return &r
+}
+func (obj DenseSparseRealVector) JOINT_ITERATOR(b ConstVector) *DenseSparseRealVectorJointIterator {
+  r := DenseSparseRealVectorJointIterator{obj.ITERATOR(), b.ConstIterator(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+func (obj DenseSparseRealVector) JOINT_ITERATOR_(b DenseSparseRealVector) *DenseSparseRealVectorJointIterator_ {
+  r := DenseSparseRealVectorJointIterator_{obj.ITERATOR(), b.ITERATOR(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+/* -------------------------------------------------------------------------- */
+func (v DenseSparseRealVector) Dim() int {
+  return len(v)
+}
+func (v DenseSparseRealVector) At(i int) Scalar {
+  return v.AT(i)
+}
+func (v DenseSparseRealVector) AT(i int) *SparseReal {
+  return v[i]
+}
+func (v DenseSparseRealVector) Reset() {
+  for i := 0; i < len(v); i++ {
+    v[i].Reset()
+  }
+}
+func (v DenseSparseRealVector) ResetDerivatives() {
+  for i := 0; i < len(v); i++ {
+    v[i].ResetDerivatives()
+  }
+}
+func (v DenseSparseRealVector) ReverseOrder() {
+  n := len(v)
+  for i := 0; i < n/2; i++ {
+    v[i], v[n-1-i] = v[n-1-i], v[i]
+  }
+}
+func (v DenseSparseRealVector) Slice(i, j int) Vector {
+  return v[i:j]
+}
+func (v DenseSparseRealVector) Append(w DenseSparseRealVector) DenseSparseRealVector {
+  return append(v, w...)
+}
+func (v DenseSparseRealVector) AppendScalar(scalars ...Scalar) Vector {
+  for _, scalar := range scalars {
+    switch s := scalar.(type) {
+    case *SparseReal:
+      v = append(v, s)
+    default:
+      v = append(v, s.ConvertType(SparseRealType).(*SparseReal))
+    }
+  }
+  return v
+}
+func (v DenseSparseRealVector) AppendVector(w_ Vector) Vector {
+  switch w := w_.(type) {
+  case DenseSparseRealVector:
+    return append(v, w...)
+  default:
+    for i := 0; i < w.Dim(); i++ {
+      v = append(v, w.At(i).ConvertType(SparseRealType).(*SparseReal))
+    }
+    return v
+  }
+}
+func (v DenseSparseRealVector) Swap(i, j int) {
+  v[i], v[j] = v[j], v[i]
+}
+/* imlement ScalarContainer
+ * -------------------------------------------------------------------------- */
+func (v DenseSparseRealVector) Map(f func(Scalar)) {
+  for i := 0; i < len(v); i++ {
+    f( v[i])
+  }
+}
+func (v DenseSparseRealVector) MapSet(f func(ConstScalar) Scalar) {
+  for i := 0; i < len(v); i++ {
+    v[i].Set(f(v.ConstAt(i)))
+  }
+}
+func (v DenseSparseRealVector) Reduce(f func(Scalar, ConstScalar) Scalar, r Scalar) Scalar {
+  for i := 0; i < len(v); i++ {
+    r = f(r, v.ConstAt(i))
+  }
+  return r
+}
+func (v DenseSparseRealVector) ElementType() ScalarType {
+  return SparseRealType
+}
+func (v DenseSparseRealVector) Variables(order int) error {
+  for i, _ := range v {
+    if err := v[i].SetVariable(i, len(v), order); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+/* permutations
+ * -------------------------------------------------------------------------- */
+func (v DenseSparseRealVector) Permute(pi []int) error {
+  if len(pi) != len(v) {
+    return errors.New("Permute(): permutation vector has invalid length!")
+  }
+  // permute vector
+  if !applyPermutation(pi, func(i, j int) { v[i], v[j] = v[j], v[i] }) {
+    return errors.New("Permute(): invalid permutation")
+  }
+  return nil
+}
+/* sorting
+ * -------------------------------------------------------------------------- */
+type sortDenseSparseRealVectorByValue DenseSparseRealVector
+func (v sortDenseSparseRealVectorByValue) Len() int { return len(v) }
+func (v sortDenseSparseRealVectorByValue) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+func (v sortDenseSparseRealVectorByValue) Less(i, j int) bool { return v[i].GetValue() < v[j].GetValue() }
+func (v DenseSparseRealVector) Sort(reverse bool) {
+  if reverse {
+    sort.Sort(sort.Reverse(sortDenseSparseRealVectorByValue(v)))
+  } else {
+    sort.Sort(sortDenseSparseRealVectorByValue(v))
+  }
+}
+/* type conversion
+ * -------------------------------------------------------------------------- */
+func (v DenseSparseRealVector) AsMatrix(n, m int) Matrix {
+  return v.ToDenseSparseRealMatrix(n, m)
+}
+func (v DenseSparseRealVector) ToDenseSparseRealMatrix(n, m int) *DenseSparseRealMatrix {
+  if n*m != len(v) {
+    panic("Matrix dimension does not fit input vector!")
+  }
+  matrix := DenseSparseRealMatrix{}
+  matrix.values = v
+  matrix.rows = n
+  matrix.cols = m
+  matrix.rowOffset = 0
+  matrix.rowMax = n
+  matrix.colOffset = 0
+  matrix.colMax = m
+  matrix.initTmp()
+  return &matrix
+}
+func (v DenseSparseRealVector) String() string {
+  var buffer bytes.Buffer
+  buffer.WriteString("[")
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(", ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  buffer.WriteString("]")
+  return buffer.String()
+}
+func (v DenseSparseRealVector) Table() string {
+  var buffer bytes.Buffer
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(" ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  return buffer.String()
+}
+func (v DenseSparseRealVector) Export(filename string) error {
+  f, err := os.Create(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  w := bufio.NewWriter(f)
+  defer w.Flush()
+  if _, err := fmt.Fprintf(w, "%s\n", v.Table()); err != nil {
+    return err
+  }
+  return nil
+}
+func (v *DenseSparseRealVector) Import(filename string) error {
+  var scanner *bufio.Scanner
+  // open file
+  f, err := os.Open(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  isgzip, err := isGzip(filename)
+  if err != nil {
+    return err
+  }
+  // check if file is gzipped
+  if isgzip {
+    g, err := gzip.NewReader(f)
+    if err != nil {
+      return err
+    }
+    defer g.Close()
+    scanner = bufio.NewScanner(g)
+  } else {
+    scanner = bufio.NewScanner(f)
+  }
+  // reset vector
+  *v = DenseSparseRealVector{}
+  for scanner.Scan() {
+    fields := strings.Fields(scanner.Text())
+    if len(fields) == 0 {
+      continue
+    }
+    if len(*v) != 0 {
+      return fmt.Errorf("invalid table")
+    }
+    for i := 0; i < len(fields); i++ {
+      value, err := strconv.ParseFloat(fields[i], 64)
+      if err != nil {
+        return fmt.Errorf("invalid table")
+      }
+      *v = append(*v, NewSparseReal(value))
+    }
+  }
+  return nil
+}
+/* json
+ * -------------------------------------------------------------------------- */
+func (obj DenseSparseRealVector) MarshalJSON() ([]byte, error) {
+  r := []*SparseReal{}
+  r = obj
+  return json.MarshalIndent(r, "", "  ")
+}
+func (obj *DenseSparseRealVector) UnmarshalJSON(data []byte) error {
+  r := []*SparseReal{}
+  if err := json.Unmarshal(data, &r); err != nil {
+    return err
+  }
+  *obj = nilDenseSparseRealVector(len(r))
+  for i := 0; i < len(r); i++ {
+    (*obj)[i] = r[i]
+  }
+  return nil
+}
+/* iterator
+ * -------------------------------------------------------------------------- */
+type DenseSparseRealVectorIterator struct {
+  v DenseSparseRealVector
+  i int
+}
+func (obj *DenseSparseRealVectorIterator) Get() Scalar {
+  return obj.GET()
+}
+func (obj *DenseSparseRealVectorIterator) GetConst() ConstScalar {
+  return obj.GET()
+}
+func (obj *DenseSparseRealVectorIterator) GetValue() float64 {
+  return obj.GET().GetValue()
+}
+func (obj *DenseSparseRealVectorIterator) GET() *SparseReal {
+  return obj.v[obj.i]
+}
+func (obj *DenseSparseRealVectorIterator) Ok() bool {
+  return obj.i < len(obj.v)
+}
+func (obj *DenseSparseRealVectorIterator) Next() {
+  obj.i++
+}
+func (obj *DenseSparseRealVectorIterator) Index() int {
+  return obj.i
+}
+func (obj *DenseSparseRealVectorIterator) Clone() *DenseSparseRealVectorIterator {
+  return &DenseSparseRealVectorIterator{obj.v, obj.i}
+}
+func (obj *DenseSparseRealVectorIterator) CloneConstIterator() VectorConstIterator {
+  return &DenseSparseRealVectorIterator{obj.v, obj.i}
+}
+func (obj *DenseSparseRealVectorIterator) CloneIterator() VectorIterator {
+  return &DenseSparseRealVectorIterator{obj.v, obj.i}
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseSparseRealVectorJointIterator struct {
+  it1 *DenseSparseRealVectorIterator
+  it2 VectorConstIterator
+  idx int
+  s1 *SparseReal
+  s2 ConstScalar
+}
+func (obj *DenseSparseRealVectorJointIterator) Index() int {
+  return obj.idx
+}
+func (obj *DenseSparseRealVectorJointIterator) Ok() bool {
+  return !(obj.s1 == nil || obj.s1.GetValue() == 0.0) ||
+         !(obj.s2 == nil || obj.s2.GetValue() == 0.0)
+}
+func (obj *DenseSparseRealVectorJointIterator) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GetConst()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GetConst()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  } else {
+    obj.s2 = ConstReal(0.0)
+  }
+}
+func (obj *DenseSparseRealVectorJointIterator) Get() (Scalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseSparseRealVectorJointIterator) GetConst() (ConstScalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseSparseRealVectorJointIterator) GetValue() (float64, float64) {
+  a, b := obj.GET()
+  return a.GetValue(), b.GetValue()
+}
+func (obj *DenseSparseRealVectorJointIterator) GET() (*SparseReal, ConstScalar) {
+  if obj.s1 == nil {
+    return nil, obj.s2
+  } else {
+    return obj.s1, obj.s2
+  }
+}
+func (obj *DenseSparseRealVectorJointIterator) Clone() *DenseSparseRealVectorJointIterator {
+  r := DenseSparseRealVectorJointIterator{}
+  r.it1 = obj.it1.Clone()
+  r.it2 = obj.it2.CloneConstIterator()
+  r.idx = obj.idx
+  r.s1 = obj.s1
+  r.s2 = obj.s2
+  return &r
+}
+func (obj *DenseSparseRealVectorJointIterator) CloneConstJointIterator() VectorConstJointIterator {
+  return obj.Clone()
+}
+func (obj *DenseSparseRealVectorJointIterator) CloneJointIterator() VectorJointIterator {
+  return obj.Clone()
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseSparseRealVectorJointIterator_ struct {
+  it1 *DenseSparseRealVectorIterator
+  it2 *DenseSparseRealVectorIterator
+  idx int
+  s1 *SparseReal
+  s2 *SparseReal
+}
+func (obj *DenseSparseRealVectorJointIterator_) Index() int {
+  return obj.idx
+}
+func (obj *DenseSparseRealVectorJointIterator_) Ok() bool {
+  return obj.s1 != nil || obj.s2 != nil
+}
+func (obj *DenseSparseRealVectorJointIterator_) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GET()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GET()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  }
+}
+func (obj *DenseSparseRealVectorJointIterator_) GET() (*SparseReal, *SparseReal) {
+  return obj.s1, obj.s2
+}
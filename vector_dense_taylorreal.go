@@ -0,0 +1,542 @@
+/* -*- mode: go; -*-
+ *
+ * Copyright (C) 2015-2017 Philipp Benner
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+/* -------------------------------------------------------------------------- */
+package autodiff
+/* -------------------------------------------------------------------------- */
+import "fmt"
+import "bytes"
+import "bufio"
+import "encoding/json"
+import "errors"
+import "compress/gzip"
+import "sort"
+import "strconv"
+import "strings"
+import "os"
+/* -------------------------------------------------------------------------- */
+/* vector type declaration
+ * -------------------------------------------------------------------------- */
+type DenseTaylorRealVector []*TaylorReal
+/* constructors
+ * -------------------------------------------------------------------------- */
+// Allocate a new vector. Scalars are set to the given values.
+func NewDenseTaylorRealVector(values []float64) DenseTaylorRealVector {
+  v := nilDenseTaylorRealVector(len(values))
+  for i, _ := range values {
+    v[i] = NewTaylorReal(values[i])
+  }
+  return v
+}
+// Allocate a new vector. All scalars are set to zero.
+func NullDenseTaylorRealVector(length int) DenseTaylorRealVector {
+  v := nilDenseTaylorRealVector(length)
+  if length > 0 {
+    for i := 0; i < length; i++ {
+      v[i] = NewTaylorReal(0.0)
+    }
+  }
+  return v
+}
+// Create a empty vector without allocating memory for the scalar variables.
+func nilDenseTaylorRealVector(length int) DenseTaylorRealVector {
+  return make(DenseTaylorRealVector, length)
+}
+// Convert vector type.
+func AsDenseTaylorRealVector(v ConstVector) DenseTaylorRealVector {
+  switch v_ := v.(type) {
+  case DenseTaylorRealVector:
+    return v_.Clone()
+  }
+  r := NullDenseTaylorRealVector(v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    r.AT(i).Set(v.ConstAt(i))
+  }
+  return r
+}
+/* -------------------------------------------------------------------------- */
+// Create a deep copy of the vector.
+func (v DenseTaylorRealVector) Clone() DenseTaylorRealVector {
+  result := make(DenseTaylorRealVector, len(v))
+  for i, _ := range v {
+    result[i] = v[i].Clone()
+  }
+  return result
+}
+func (v DenseTaylorRealVector) CloneVector() Vector {
+  return v.Clone()
+}
+// Copy scalars from w into this vector. The lengths of both vectors must
+// match.
+func (v DenseTaylorRealVector) Set(w ConstVector) {
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].Set(w.ConstAt(i))
+  }
+}
+func (v DenseTaylorRealVector) SET(w DenseTaylorRealVector) {
+  if v.IDEM(w) {
+    return
+  }
+  if v.Dim() != w.Dim() {
+    panic("Set(): Vector dimensions do not match!")
+  }
+  for i := 0; i < w.Dim(); i++ {
+    v[i].SET(w.AT(i))
+  }
+}
+func (v DenseTaylorRealVector) IDEM(w DenseTaylorRealVector) bool {
+  if len(v) != len(w) {
+    return false
+  }
+  if len(v) == 0 {
+    return false
+  }
+  return &v[0] == &w[0]
+}
+/* const vector methods
+ * -------------------------------------------------------------------------- */
+func (v DenseTaylorRealVector) ValueAt(i int) float64 {
+  return v[i].GetValue()
+}
+func (v DenseTaylorRealVector) ConstAt(i int) ConstScalar {
+  return v[i]
+}
+func (v DenseTaylorRealVector) ConstSlice(i, j int) ConstVector {
+  return v[i:j]
+}
+func (v DenseTaylorRealVector) GetValues() []float64 {
+  s := make([]float64, v.Dim())
+  for i := 0; i < v.Dim(); i++ {
+    s[i] = v.ConstAt(i).GetValue()
+  }
+  return s
+}
+/* iterator methods
+ * -------------------------------------------------------------------------- */
+func (obj DenseTaylorRealVector) ConstIterator() VectorConstIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseTaylorRealVector) Iterator() VectorIterator {
+  return obj.ITERATOR()
+}
+func (obj DenseTaylorRealVector) JointIterator(b ConstVector) VectorJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseTaylorRealVector) ConstJointIterator(b ConstVector) VectorConstJointIterator {
+  return obj.JOINT_ITERATOR(b)
+}
+func (obj DenseTaylorRealVector) ITERATOR() *DenseTaylorRealVectorIterator {
+  r := DenseTaylorRealVectorIterator{obj, -1}
+  r.Next()
+  return &r
+}
+func (obj DenseTaylorRealVector) JOINT_ITERATOR(b ConstVector) *DenseTaylorRealVectorJointIterator {
+  r := DenseTaylorRealVectorJointIterator{obj.ITERATOR(), b.ConstIterator(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+func (obj DenseTaylorRealVector) JOINT_ITERATOR_(b DenseTaylorRealVector) *DenseTaylorRealVectorJointIterator_ {
+  r := DenseTaylorRealVectorJointIterator_{obj.ITERATOR(), b.ITERATOR(), -1, nil, nil}
+  r.Next()
+  return &r
+}
+/* -------------------------------------------------------------------------- */
+func (v DenseTaylorRealVector) Dim() int {
+  return len(v)
+}
+func (v DenseTaylorRealVector) At(i int) Scalar {
+  return v.AT(i)
+}
+func (v DenseTaylorRealVector) AT(i int) *TaylorReal {
+  return v[i]
+}
+func (v DenseTaylorRealVector) Reset() {
+  for i := 0; i < len(v); i++ {
+    v[i].Reset()
+  }
+}
+func (v DenseTaylorRealVector) ResetDerivatives() {
+  for i := 0; i < len(v); i++ {
+    v[i].ResetDerivatives()
+  }
+}
+func (v DenseTaylorRealVector) ReverseOrder() {
+  n := len(v)
+  for i := 0; i < n/2; i++ {
+    v[i], v[n-1-i] = v[n-1-i], v[i]
+  }
+}
+func (v DenseTaylorRealVector) Slice(i, j int) Vector {
+  return v[i:j]
+}
+func (v DenseTaylorRealVector) Append(w DenseTaylorRealVector) DenseTaylorRealVector {
+  return append(v, w...)
+}
+func (v DenseTaylorRealVector) AppendScalar(scalars ...Scalar) Vector {
+  for _, scalar := range scalars {
+    switch s := scalar.(type) {
+    case *TaylorReal:
+      v = append(v, s)
+    default:
+      v = append(v, s.ConvertType(TaylorRealType).(*TaylorReal))
+    }
+  }
+  return v
+}
+func (v DenseTaylorRealVector) AppendVector(w_ Vector) Vector {
+  switch w := w_.(type) {
+  case DenseTaylorRealVector:
+    return append(v, w...)
+  default:
+    for i := 0; i < w.Dim(); i++ {
+      v = append(v, w.At(i).ConvertType(TaylorRealType).(*TaylorReal))
+    }
+    return v
+  }
+}
+func (v DenseTaylorRealVector) Swap(i, j int) {
+  v[i], v[j] = v[j], v[i]
+}
+/* imlement ScalarContainer
+ * -------------------------------------------------------------------------- */
+func (v DenseTaylorRealVector) Map(f func(Scalar)) {
+  for i := 0; i < len(v); i++ {
+    f( v[i])
+  }
+}
+func (v DenseTaylorRealVector) MapSet(f func(ConstScalar) Scalar) {
+  for i := 0; i < len(v); i++ {
+    v[i].Set(f(v.ConstAt(i)))
+  }
+}
+func (v DenseTaylorRealVector) Reduce(f func(Scalar, ConstScalar) Scalar, r Scalar) Scalar {
+  for i := 0; i < len(v); i++ {
+    r = f(r, v.ConstAt(i))
+  }
+  return r
+}
+func (v DenseTaylorRealVector) ElementType() ScalarType {
+  return TaylorRealType
+}
+func (v DenseTaylorRealVector) Variables(order int) error {
+  for i, _ := range v {
+    if err := v[i].SetVariable(i, len(v), order); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+/* permutations
+ * -------------------------------------------------------------------------- */
+func (v DenseTaylorRealVector) Permute(pi []int) error {
+  if len(pi) != len(v) {
+    return errors.New("Permute(): permutation vector has invalid length!")
+  }
+  // permute vector
+  if !applyPermutation(pi, func(i, j int) { v[i], v[j] = v[j], v[i] }) {
+    return errors.New("Permute(): invalid permutation")
+  }
+  return nil
+}
+/* sorting
+ * -------------------------------------------------------------------------- */
+type sortDenseTaylorRealVectorByValue DenseTaylorRealVector
+func (v sortDenseTaylorRealVectorByValue) Len() int { return len(v) }
+func (v sortDenseTaylorRealVectorByValue) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+func (v sortDenseTaylorRealVectorByValue) Less(i, j int) bool { return v[i].GetValue() < v[j].GetValue() }
+func (v DenseTaylorRealVector) Sort(reverse bool) {
+  if reverse {
+    sort.Sort(sort.Reverse(sortDenseTaylorRealVectorByValue(v)))
+  } else {
+    sort.Sort(sortDenseTaylorRealVectorByValue(v))
+  }
+}
+/* type conversion
+ * -------------------------------------------------------------------------- */
+func (v DenseTaylorRealVector) AsMatrix(n, m int) Matrix {
+  return v.ToDenseTaylorRealMatrix(n, m)
+}
+func (v DenseTaylorRealVector) ToDenseTaylorRealMatrix(n, m int) *DenseTaylorRealMatrix {
+  if n*m != len(v) {
+    panic("Matrix dimension does not fit input vector!")
+  }
+  matrix := DenseTaylorRealMatrix{}
+  matrix.values = v
+  matrix.rows = n
+  matrix.cols = m
+  matrix.rowOffset = 0
+  matrix.rowMax = n
+  matrix.colOffset = 0
+  matrix.colMax = m
+  matrix.initTmp()
+  return &matrix
+}
+func (v DenseTaylorRealVector) String() string {
+  var buffer bytes.Buffer
+  buffer.WriteString("[")
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(", ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  buffer.WriteString("]")
+  return buffer.String()
+}
+func (v DenseTaylorRealVector) Table() string {
+  var buffer bytes.Buffer
+  for i, _ := range v {
+    if i != 0 {
+      buffer.WriteString(" ")
+    }
+    buffer.WriteString(v[i].String())
+  }
+  return buffer.String()
+}
+func (v DenseTaylorRealVector) Export(filename string) error {
+  f, err := os.Create(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  w := bufio.NewWriter(f)
+  defer w.Flush()
+  if _, err := fmt.Fprintf(w, "%s\n", v.Table()); err != nil {
+    return err
+  }
+  return nil
+}
+func (v *DenseTaylorRealVector) Import(filename string) error {
+  var scanner *bufio.Scanner
+  // open file
+  f, err := os.Open(filename)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  isgzip, err := isGzip(filename)
+  if err != nil {
+    return err
+  }
+  // check if file is gzipped
+  if isgzip {
+    g, err := gzip.NewReader(f)
+    if err != nil {
+      return err
+    }
+    defer g.Close()
+    scanner = bufio.NewScanner(g)
+  } else {
+    scanner = bufio.NewScanner(f)
+  }
+  // reset vector
+  *v = DenseTaylorRealVector{}
+  for scanner.Scan() {
+    fields := strings.Fields(scanner.Text())
+    if len(fields) == 0 {
+      continue
+    }
+    if len(*v) != 0 {
+      return fmt.Errorf("invalid table")
+    }
+    for i := 0; i < len(fields); i++ {
+      value, err := strconv.ParseFloat(fields[i], 64)
+      if err != nil {
+        return fmt.Errorf("invalid table")
+      }
+      *v = append(*v, NewTaylorReal(value))
+    }
+  }
+  return nil
+}
+/* json
+ * -------------------------------------------------------------------------- */
+func (obj DenseTaylorRealVector) MarshalJSON() ([]byte, error) {
+  r := []*TaylorReal{}
+  r = obj
+  return json.MarshalIndent(r, "", "  ")
+}
+func (obj *DenseTaylorRealVector) UnmarshalJSON(data []byte) error {
+  r := []*TaylorReal{}
+  if err := json.Unmarshal(data, &r); err != nil {
+    return err
+  }
+  *obj = nilDenseTaylorRealVector(len(r))
+  for i := 0; i < len(r); i++ {
+    (*obj)[i] = r[i]
+  }
+  return nil
+}
+/* iterator
+ * -------------------------------------------------------------------------- */
+type DenseTaylorRealVectorIterator struct {
+  v DenseTaylorRealVector
+  i int
+}
+func (obj *DenseTaylorRealVectorIterator) Get() Scalar {
+  return obj.GET()
+}
+func (obj *DenseTaylorRealVectorIterator) GetConst() ConstScalar {
+  return obj.GET()
+}
+func (obj *DenseTaylorRealVectorIterator) GetValue() float64 {
+  return obj.GET().GetValue()
+}
+func (obj *DenseTaylorRealVectorIterator) GET() *TaylorReal {
+  return obj.v[obj.i]
+}
+func (obj *DenseTaylorRealVectorIterator) Ok() bool {
+  return obj.i < len(obj.v)
+}
+func (obj *DenseTaylorRealVectorIterator) Next() {
+  obj.i++
+}
+func (obj *DenseTaylorRealVectorIterator) Index() int {
+  return obj.i
+}
+func (obj *DenseTaylorRealVectorIterator) Clone() *DenseTaylorRealVectorIterator {
+  return &DenseTaylorRealVectorIterator{obj.v, obj.i}
+}
+func (obj *DenseTaylorRealVectorIterator) CloneConstIterator() VectorConstIterator {
+  return &DenseTaylorRealVectorIterator{obj.v, obj.i}
+}
+func (obj *DenseTaylorRealVectorIterator) CloneIterator() VectorIterator {
+  return &DenseTaylorRealVectorIterator{obj.v, obj.i}
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseTaylorRealVectorJointIterator struct {
+  it1 *DenseTaylorRealVectorIterator
+  it2 VectorConstIterator
+  idx int
+  s1 *TaylorReal
+  s2 ConstScalar
+}
+func (obj *DenseTaylorRealVectorJointIterator) Index() int {
+  return obj.idx
+}
+func (obj *DenseTaylorRealVectorJointIterator) Ok() bool {
+  return !(obj.s1 == nil || obj.s1.GetValue() == 0.0) ||
+         !(obj.s2 == nil || obj.s2.GetValue() == 0.0)
+}
+func (obj *DenseTaylorRealVectorJointIterator) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GetConst()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GetConst()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  } else {
+    obj.s2 = ConstReal(0.0)
+  }
+}
+func (obj *DenseTaylorRealVectorJointIterator) Get() (Scalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseTaylorRealVectorJointIterator) GetConst() (ConstScalar, ConstScalar) {
+  return obj.GET()
+}
+func (obj *DenseTaylorRealVectorJointIterator) GetValue() (float64, float64) {
+  a, b := obj.GET()
+  return a.GetValue(), b.GetValue()
+}
+func (obj *DenseTaylorRealVectorJointIterator) GET() (*TaylorReal, ConstScalar) {
+  if obj.s1 == nil {
+    return nil, obj.s2
+  } else {
+    return obj.s1, obj.s2
+  }
+}
+func (obj *DenseTaylorRealVectorJointIterator) Clone() *DenseTaylorRealVectorJointIterator {
+  r := DenseTaylorRealVectorJointIterator{}
+  r.it1 = obj.it1.Clone()
+  r.it2 = obj.it2.CloneConstIterator()
+  r.idx = obj.idx
+  r.s1 = obj.s1
+  r.s2 = obj.s2
+  return &r
+}
+func (obj *DenseTaylorRealVectorJointIterator) CloneConstJointIterator() VectorConstJointIterator {
+  return obj.Clone()
+}
+func (obj *DenseTaylorRealVectorJointIterator) CloneJointIterator() VectorJointIterator {
+  return obj.Clone()
+}
+/* joint iterator
+ * -------------------------------------------------------------------------- */
+type DenseTaylorRealVectorJointIterator_ struct {
+  it1 *DenseTaylorRealVectorIterator
+  it2 *DenseTaylorRealVectorIterator
+  idx int
+  s1 *TaylorReal
+  s2 *TaylorReal
+}
+func (obj *DenseTaylorRealVectorJointIterator_) Index() int {
+  return obj.idx
+}
+func (obj *DenseTaylorRealVectorJointIterator_) Ok() bool {
+  return obj.s1 != nil || obj.s2 != nil
+}
+func (obj *DenseTaylorRealVectorJointIterator_) Next() {
+  ok1 := obj.it1.Ok()
+  ok2 := obj.it2.Ok()
+  obj.s1 = nil
+  obj.s2 = nil
+  if ok1 {
+    obj.idx = obj.it1.Index()
+    obj.s1 = obj.it1.GET()
+  }
+  if ok2 {
+    switch {
+    case obj.idx > obj.it2.Index() || !ok1:
+      obj.idx = obj.it2.Index()
+      obj.s1 = nil
+      obj.s2 = obj.it2.GET()
+    case obj.idx == obj.it2.Index():
+      obj.s2 = obj.it2.GET()
+    }
+  }
+  if obj.s1 != nil {
+    obj.it1.Next()
+  }
+  if obj.s2 != nil {
+    obj.it2.Next()
+  }
+}
+func (obj *DenseTaylorRealVectorJointIterator_) GET() (*TaylorReal, *TaylorReal) {
+  return obj.s1, obj.s2
+}